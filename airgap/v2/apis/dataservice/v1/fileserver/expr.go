@@ -0,0 +1,406 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileserver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/scanner"
+	"unicode"
+
+	"emperror.dev/errors"
+)
+
+const (
+	// FilterIn matches a field equal to one of a list of values: name in ("a", "b")
+	FilterIn FilterOperator = "in"
+	// FilterLike matches a field against a glob where * matches any text
+	// and ? a single character: name like "report-*"
+	FilterLike FilterOperator = "like"
+	// FilterExists matches a field that is set: metadata.version exists
+	FilterExists FilterOperator = "exists"
+)
+
+// MetadataFieldPrefix selects the value of a metadata key in a filter: metadata.<key>
+const MetadataFieldPrefix = "metadata."
+
+// FilterExpr is a node of a parsed filter expression, one of
+// *FilterCondition, *FilterLogical or *FilterNot.
+type FilterExpr interface {
+	fmt.Stringer
+	filterExpr()
+}
+
+// FilterCondition compares a field to values. Values are unquoted, there is
+// one value for comparisons, one or more for in and none for exists.
+type FilterCondition struct {
+	Field    string
+	Operator FilterOperator
+	Values   []string
+}
+
+// FilterLogical combines two expressions with && or ||.
+type FilterLogical struct {
+	Operator    FilterBooleanOperator
+	Left, Right FilterExpr
+}
+
+// FilterNot negates an expression.
+type FilterNot struct {
+	Expr FilterExpr
+}
+
+func (*FilterCondition) filterExpr() {}
+func (*FilterLogical) filterExpr()   {}
+func (*FilterNot) filterExpr()       {}
+
+// IsMetadata returns true if the condition is on a metadata key
+func (c *FilterCondition) IsMetadata() bool {
+	return strings.HasPrefix(c.Field, MetadataFieldPrefix)
+}
+
+// MetadataKey returns the metadata key of the condition
+func (c *FilterCondition) MetadataKey() string {
+	return strings.TrimPrefix(c.Field, MetadataFieldPrefix)
+}
+
+func (c *FilterCondition) String() string {
+	values := make([]string, 0, len(c.Values))
+	for _, value := range c.Values {
+		values = append(values, strconv.Quote(value))
+	}
+
+	switch c.Operator {
+	case FilterExists:
+		return fmt.Sprintf("%s exists", c.Field)
+	case FilterIn:
+		return fmt.Sprintf("%s in (%s)", c.Field, strings.Join(values, ", "))
+	}
+
+	return fmt.Sprintf("%s %s %s", c.Field, c.Operator, strings.Join(values, ""))
+}
+
+func (l *FilterLogical) String() string {
+	return fmt.Sprintf("(%s %s %s)", l.Left, l.Operator, l.Right)
+}
+
+func (n *FilterNot) String() string {
+	return fmt.Sprintf("!%s", n.Expr)
+}
+
+// ParseFilter parses a filter expression. A condition compares a field to a
+// value with == != > >= < <=, matches a glob with like, a list of values with
+// in (...), or tests that a field is set with exists. Conditions are combined
+// with && and ||, negated with ! and grouped with parentheses, && binds
+// tighter than ||. and, or and not can be used as keywords.
+//
+//	name like "report-*" && (metadata.version in ("1", "2") || !metadata.reviewed exists)
+//
+// Errors wrap ErrParseFailure and give the position of the problem.
+func ParseFilter(in string) (FilterExpr, error) {
+	p := &filterParser{}
+	p.init(in)
+
+	if p.tok == scanner.EOF {
+		return nil, p.errorf("empty filter")
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok != scanner.EOF {
+		return nil, p.errorf("unexpected %s", p.describe())
+	}
+
+	return expr, nil
+}
+
+// Expr returns the expression of filters chained left to right, with &&
+// binding tighter than ||.
+func (f Filters) Expr() FilterExpr {
+	var or, and FilterExpr
+
+	for _, filter := range f {
+		var cond FilterExpr = &FilterCondition{
+			Field:    unquote(filter.Left),
+			Operator: filter.Operator,
+			Values:   []string{unquote(filter.Right)},
+		}
+
+		if and == nil {
+			and = cond
+		} else {
+			and = &FilterLogical{Operator: FilterAnd, Left: and, Right: cond}
+		}
+
+		if filter.NextFilterOperator != FilterAnd {
+			or = logical(FilterOr, or, and)
+			and = nil
+		}
+	}
+
+	return logical(FilterOr, or, and)
+}
+
+func logical(operator FilterBooleanOperator, left, right FilterExpr) FilterExpr {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	}
+	return &FilterLogical{Operator: operator, Left: left, Right: right}
+}
+
+func unquote(in string) string {
+	if out, err := strconv.Unquote(in); err == nil {
+		return out
+	}
+	return in
+}
+
+// symbolToken is the token of operators such as == or &&
+const symbolToken = -100
+
+type filterParser struct {
+	s scanner.Scanner
+
+	tok  rune
+	text string
+	pos  int
+
+	err error
+}
+
+func (p *filterParser) init(in string) {
+	p.s.Init(strings.NewReader(in))
+	p.s.Mode = scanner.ScanIdents | scanner.ScanFloats | scanner.ScanStrings | scanner.ScanRawStrings
+	p.s.IsIdentRune = func(ch rune, i int) bool {
+		return ch == '_' || unicode.IsLetter(ch) ||
+			(i > 0 && (unicode.IsDigit(ch) || ch == '.' || ch == '-'))
+	}
+	p.s.Error = func(s *scanner.Scanner, msg string) {
+		if p.err == nil {
+			p.err = errors.Wrapf(ErrParseFailure, "%s at position %d", msg, s.Pos().Offset)
+		}
+	}
+	p.next()
+}
+
+func (p *filterParser) next() {
+	p.tok = p.s.Scan()
+	p.text = p.s.TokenText()
+	p.pos = p.s.Position.Offset
+
+	switch p.tok {
+	case '=', '!', '<', '>', '&', '|':
+		peek := p.s.Peek()
+		if (peek == '=' && p.tok != '&' && p.tok != '|') ||
+			(peek == p.tok && (p.tok == '&' || p.tok == '|')) {
+			p.s.Next()
+			p.text += string(peek)
+		}
+		p.tok = symbolToken
+	}
+}
+
+func (p *filterParser) errorf(format string, args ...interface{}) error {
+	if p.err != nil {
+		return p.err
+	}
+
+	return errors.Wrapf(ErrParseFailure, "%s at position %d", fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *filterParser) describe() string {
+	if p.tok == scanner.EOF {
+		return "end of filter"
+	}
+	return strconv.Quote(p.text)
+}
+
+// accept consumes the current token if it is one of words, keywords are
+// not case sensitive.
+func (p *filterParser) accept(words ...string) bool {
+	for _, word := range words {
+		if (p.tok == scanner.Ident && strings.EqualFold(p.text, word)) ||
+			(p.tok != scanner.Ident && p.text == word) {
+			p.next()
+			return true
+		}
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (FilterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("||", "or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &FilterLogical{Operator: FilterOr, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (FilterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("&&", "and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &FilterLogical{Operator: FilterAnd, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (FilterExpr, error) {
+	if p.accept("!", "not") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &FilterNot{Expr: expr}, nil
+	}
+
+	if p.accept("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.accept(")") {
+			return nil, p.errorf("expected ) but found %s", p.describe())
+		}
+		return expr, nil
+	}
+
+	return p.parseCondition()
+}
+
+var comparisonOperators = []FilterOperator{
+	FilterEqual, FilterNotEqual,
+	FilterGreaterThan, FilterGreaterThanEqual,
+	FilterLessThan, FilterLessThanEqual,
+}
+
+func (p *filterParser) parseCondition() (FilterExpr, error) {
+	if p.tok != scanner.Ident {
+		return nil, p.errorf("expected a field but found %s", p.describe())
+	}
+
+	cond := &FilterCondition{Field: p.text}
+	if cond.IsMetadata() && cond.MetadataKey() == "" {
+		return nil, p.errorf("metadata key is missing")
+	}
+	p.next()
+
+	switch {
+	case p.accept(string(FilterExists)):
+		cond.Operator = FilterExists
+		return cond, nil
+	case p.accept(string(FilterIn)):
+		cond.Operator = FilterIn
+		return cond, p.parseList(cond)
+	case p.accept(string(FilterLike)):
+		cond.Operator = FilterLike
+	default:
+		for _, op := range comparisonOperators {
+			if p.accept(string(op)) {
+				cond.Operator = op
+				break
+			}
+		}
+	}
+
+	if cond.Operator == "" {
+		return nil, p.errorf("expected an operator after %s but found %s", cond.Field, p.describe())
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	cond.Values = []string{value}
+
+	return cond, nil
+}
+
+func (p *filterParser) parseList(cond *FilterCondition) error {
+	if !p.accept("(") {
+		return p.errorf("expected ( after in but found %s", p.describe())
+	}
+
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return err
+		}
+		cond.Values = append(cond.Values, value)
+
+		if p.accept(")") {
+			return nil
+		}
+
+		if !p.accept(",") {
+			return p.errorf("expected , or ) but found %s", p.describe())
+		}
+	}
+}
+
+func (p *filterParser) parseValue() (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+
+	switch p.tok {
+	case scanner.String, scanner.RawString:
+		value, err := strconv.Unquote(p.text)
+		if err != nil {
+			return "", p.errorf("invalid string %s", p.text)
+		}
+		p.next()
+		return value, nil
+	case scanner.Int, scanner.Float, scanner.Ident:
+		value := p.text
+		p.next()
+		return value, nil
+	case '-':
+		p.next()
+		if p.tok != scanner.Int && p.tok != scanner.Float {
+			return "", p.errorf("expected a number but found %s", p.describe())
+		}
+		value := "-" + p.text
+		p.next()
+		return value, nil
+	}
+
+	return "", p.errorf("expected a value but found %s", p.describe())
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileserver
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("filter expressions", func() {
+	DescribeTable("should parse",
+		func(test, expected string) {
+			expr, err := ParseFilter(test)
+			Expect(err).To(Succeed())
+			Expect(expr.String()).To(Equal(expected))
+		},
+		Entry("comparison", `source == "redhat"`, `source == "redhat"`),
+		Entry("comparison operators", `a != 1 && b > 2 && c >= 3 && d < 4 && e <= 5`,
+			`((((a != "1" && b > "2") && c >= "3") && d < "4") && e <= "5")`),
+		Entry("number", `size >= 10`, `size >= "10"`),
+		Entry("negative number", `size > -1`, `size > "-1"`),
+		Entry("float", `ratio < 0.5`, `ratio < "0.5"`),
+		Entry("identifier value", `sourceType == report`, `sourceType == "report"`),
+		Entry("raw string", "name == `a\"b`", `name == "a\"b"`),
+		Entry("metadata", `metadata.cluster-id == "abc"`, `metadata.cluster-id == "abc"`),
+		Entry("like", `name like "report-*"`, `name like "report-*"`),
+
+		// precedence and associativity
+		Entry("and binds tighter", `a == 1 || b == 2 && c == 3`, `(a == "1" || (b == "2" && c == "3"))`),
+		Entry("and binds tighter with keywords", `a == 1 or b == 2 and c == 3`, `(a == "1" || (b == "2" && c == "3"))`),
+		Entry("and before or", `a == 1 and b == 2 or c == 3`, `((a == "1" && b == "2") || c == "3")`),
+		Entry("or is left associative", `a == 1 || b == 2 || c == 3`, `((a == "1" || b == "2") || c == "3")`),
+		Entry("and is left associative", `a == 1 && b == 2 && c == 3`, `((a == "1" && b == "2") && c == "3")`),
+		Entry("keywords", `a == 1 AND not b == 2 or c == 3`, `((a == "1" && !b == "2") || c == "3")`),
+		Entry("mixed keywords and symbols", `a == 1 Or b == 2 && NOT c == 3`, `(a == "1" || (b == "2" && !c == "3"))`),
+
+		// parentheses
+		Entry("parentheses", `(a == 1 || b == 2) && c == 3`, `((a == "1" || b == "2") && c == "3")`),
+		Entry("redundant parentheses", `((a == 1))`, `a == "1"`),
+		Entry("nested parentheses", `a == 1 && (b == 2 || (c == 3 && (d == 4 || e == 5)))`,
+			`(a == "1" && (b == "2" || (c == "3" && (d == "4" || e == "5"))))`),
+		Entry("parentheses on the left", `((a == 1 || b == 2) && c == 3) || d == 4`,
+			`(((a == "1" || b == "2") && c == "3") || d == "4")`),
+
+		// not binds to the next condition or group
+		Entry("not binds tighter than and", `not a == 1 and b == 2`, `(!a == "1" && b == "2")`),
+		Entry("not binds tighter than or", `!a == 1 || b == 2`, `(!a == "1" || b == "2")`),
+		Entry("not of a group", `!(a == 1 || b == 2) && c == 3`, `(!(a == "1" || b == "2") && c == "3")`),
+		Entry("not on the right", `a == 1 && !b == 2`, `(a == "1" && !b == "2")`),
+		Entry("double not", `not !a exists`, `!!a exists`),
+
+		// in lists
+		Entry("in", `sourceType in ("report", usage)`, `sourceType in ("report", "usage")`),
+		Entry("in with one value", `source in ("redhat")`, `source in ("redhat")`),
+		Entry("in with numbers", `size in (1, -2, 3.5)`, `size in ("1", "-2", "3.5")`),
+		Entry("in with separators quoted", `name in ("a,b", "c)", "(d")`, `name in ("a,b", "c)", "(d")`),
+		Entry("in with escapes", `name in ("a\"b", "c\\d", "e\tf")`, `name in ("a\"b", "c\\d", "e\tf")`),
+		Entry("in with raw strings", "name in (`a\\b`, `\"c\"`)", `name in ("a\\b", "\"c\"")`),
+		Entry("in keyword", `metadata.version IN ("1", "2")`, `metadata.version in ("1", "2")`),
+
+		// exists
+		Entry("exists", `!metadata.reviewed exists`, `!metadata.reviewed exists`),
+		Entry("exists keyword", `deletedAt EXISTS`, `deletedAt exists`),
+		Entry("exists combined", `metadata.a exists && b == 1 || not metadata.c exists`,
+			`((metadata.a exists && b == "1") || !metadata.c exists)`),
+	)
+
+	DescribeTable("should unquote the values",
+		func(test string, values []string) {
+			expr, err := ParseFilter(test)
+			Expect(err).To(Succeed())
+			Expect(expr).To(BeAssignableToTypeOf(&FilterCondition{}))
+			Expect(expr.(*FilterCondition).Values).To(Equal(values))
+		},
+		Entry("string", `a == "x y"`, []string{"x y"}),
+		Entry("escaped quote", `a == "x\"y"`, []string{`x"y`}),
+		Entry("escaped backslash", `a == "x\\y"`, []string{`x\y`}),
+		Entry("unicode escape", `a == "\u00e9"`, []string{"é"}),
+		Entry("raw string keeps backslashes", "a == `x\\y`", []string{`x\y`}),
+		Entry("in list", `a in ("x", y, 1, "a,b")`, []string{"x", "y", "1", "a,b"}),
+		Entry("exists has no value", `a exists`, []string(nil)),
+	)
+
+	DescribeTable("should fail to parse",
+		func(test, message string) {
+			_, err := ParseFilter(test)
+			Expect(err).To(MatchError(ErrParseFailure))
+			Expect(err.Error()).To(ContainSubstring(message))
+		},
+		Entry("empty", ``, "empty filter"),
+		Entry("blank", `   `, "empty filter"),
+		Entry("missing value", `a ==`, "expected a value but found end of filter at position 4"),
+		Entry("missing operator", `a "b"`, `expected an operator after a but found "\"b\"" at position 2`),
+		Entry("missing field", `== 1`, `expected a field but found "==" at position 0`),
+		Entry("unclosed parenthesis", `(a == 1`, "expected ) but found end of filter at position 7"),
+		Entry("unopened parenthesis", `a == 1)`, `unexpected ")" at position 6`),
+		Entry("empty parentheses", `()`, `expected a field but found ")" at position 1`),
+		Entry("missing boolean operator", `a == 1 b == 2`, `unexpected "b" at position 7`),
+		Entry("trailing operator", `a == 1 &&`, "expected a field but found end of filter at position 9"),
+		Entry("leading operator", `|| a == 1`, `expected a field but found "||" at position 0`),
+		Entry("not alone", `not`, "expected a field but found end of filter at position 3"),
+		Entry("not before an operator", `a == 1 && not || b == 2`, `expected a field but found "||" at position 14`),
+		Entry("single &", `a == 1 & b == 2`, `unexpected "&" at position 7`),
+		Entry("empty list", `a in ()`, `expected a value but found ")" at position 6`),
+		Entry("list without parentheses", `a in "x"`, `expected ( after in but found "\"x\"" at position 5`),
+		Entry("list without separator", `a in ("x" "y")`, `expected , or ) but found "\"y\"" at position 10`),
+		Entry("list trailing separator", `a in ("x",)`, `expected a value but found ")" at position 10`),
+		Entry("unclosed list", `a in ("x"`, "expected , or ) but found end of filter at position 9"),
+		Entry("single quotes", `a == 'x'`, `expected a value but found "'" at position 5`),
+		Entry("unterminated string", `a == "x`, "literal not terminated at position"),
+		Entry("minus without a number", `a == -x`, `expected a number but found "x" at position 6`),
+		Entry("missing metadata key", `metadata. == 1`, "metadata key is missing at position 0"),
+		Entry("quote in a metadata key", `metadata.a'b == 1`, `expected an operator after metadata.a but found "'" at position 10`),
+		Entry("semicolon in a field", `a;b == 1`, `expected an operator after a but found ";" at position 1`),
+		Entry("exists with a value", `a exists "x"`, `unexpected "\"x\"" at position 9`),
+	)
+
+	It("should convert flat filters", func() {
+		fs := Filters{}
+		Expect(fs.UnmarshalText([]byte(`a == b || c == d && e == f`))).To(Succeed())
+		Expect(fs.Expr().String()).To(Equal(`(a == "b" || (c == "d" && e == "f"))`))
+	})
+})
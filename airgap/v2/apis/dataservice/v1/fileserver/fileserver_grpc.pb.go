@@ -19,13 +19,32 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	FileServer_ListFiles_FullMethodName          = "/dataservice.v1.fileserver.FileServer/ListFiles"
-	FileServer_GetFile_FullMethodName            = "/dataservice.v1.fileserver.FileServer/GetFile"
-	FileServer_UploadFile_FullMethodName         = "/dataservice.v1.fileserver.FileServer/UploadFile"
-	FileServer_UpdateFileMetadata_FullMethodName = "/dataservice.v1.fileserver.FileServer/UpdateFileMetadata"
-	FileServer_DownloadFile_FullMethodName       = "/dataservice.v1.fileserver.FileServer/DownloadFile"
-	FileServer_DeleteFile_FullMethodName         = "/dataservice.v1.fileserver.FileServer/DeleteFile"
-	FileServer_CleanTombstones_FullMethodName    = "/dataservice.v1.fileserver.FileServer/CleanTombstones"
+	FileServer_ListFiles_FullMethodName             = "/dataservice.v1.fileserver.FileServer/ListFiles"
+	FileServer_GetFile_FullMethodName               = "/dataservice.v1.fileserver.FileServer/GetFile"
+	FileServer_ListFileVersions_FullMethodName      = "/dataservice.v1.fileserver.FileServer/ListFileVersions"
+	FileServer_WatchFiles_FullMethodName            = "/dataservice.v1.fileserver.FileServer/WatchFiles"
+	FileServer_UploadFile_FullMethodName            = "/dataservice.v1.fileserver.FileServer/UploadFile"
+	FileServer_StartUpload_FullMethodName           = "/dataservice.v1.fileserver.FileServer/StartUpload"
+	FileServer_PutChunk_FullMethodName              = "/dataservice.v1.fileserver.FileServer/PutChunk"
+	FileServer_GetUploadStatus_FullMethodName       = "/dataservice.v1.fileserver.FileServer/GetUploadStatus"
+	FileServer_FinalizeUpload_FullMethodName        = "/dataservice.v1.fileserver.FileServer/FinalizeUpload"
+	FileServer_AbortUpload_FullMethodName           = "/dataservice.v1.fileserver.FileServer/AbortUpload"
+	FileServer_UpdateFileMetadata_FullMethodName    = "/dataservice.v1.fileserver.FileServer/UpdateFileMetadata"
+	FileServer_DownloadFile_FullMethodName          = "/dataservice.v1.fileserver.FileServer/DownloadFile"
+	FileServer_DeleteFile_FullMethodName            = "/dataservice.v1.fileserver.FileServer/DeleteFile"
+	FileServer_BatchGet_FullMethodName              = "/dataservice.v1.fileserver.FileServer/BatchGet"
+	FileServer_BatchUpdateMetadata_FullMethodName   = "/dataservice.v1.fileserver.FileServer/BatchUpdateMetadata"
+	FileServer_BatchDelete_FullMethodName           = "/dataservice.v1.fileserver.FileServer/BatchDelete"
+	FileServer_CleanTombstones_FullMethodName       = "/dataservice.v1.fileserver.FileServer/CleanTombstones"
+	FileServer_ApplyRetention_FullMethodName        = "/dataservice.v1.fileserver.FileServer/ApplyRetention"
+	FileServer_RotateEncryptionKeys_FullMethodName  = "/dataservice.v1.fileserver.FileServer/RotateEncryptionKeys"
+	FileServer_ListAuditEvents_FullMethodName       = "/dataservice.v1.fileserver.FileServer/ListAuditEvents"
+	FileServer_VerifyAuditLog_FullMethodName        = "/dataservice.v1.fileserver.FileServer/VerifyAuditLog"
+	FileServer_BackupDatabase_FullMethodName        = "/dataservice.v1.fileserver.FileServer/BackupDatabase"
+	FileServer_RestoreDatabase_FullMethodName       = "/dataservice.v1.fileserver.FileServer/RestoreDatabase"
+	FileServer_ExportFiles_FullMethodName           = "/dataservice.v1.fileserver.FileServer/ExportFiles"
+	FileServer_ImportAcknowledgement_FullMethodName = "/dataservice.v1.fileserver.FileServer/ImportAcknowledgement"
+	FileServer_VerifyDatabase_FullMethodName        = "/dataservice.v1.fileserver.FileServer/VerifyDatabase"
 )
 
 // FileServerClient is the client API for FileServer service.
@@ -35,11 +54,63 @@ type FileServerClient interface {
 	// Lists files.
 	ListFiles(ctx context.Context, in *ListFilesRequest, opts ...grpc.CallOption) (*ListFilesResponse, error)
 	GetFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (*GetFileResponse, error)
+	// Lists the versions kept of a file, newest first.
+	ListFileVersions(ctx context.Context, in *ListFileVersionsRequest, opts ...grpc.CallOption) (*ListFileVersionsResponse, error)
+	// Streams the files created, updated and deleted after a revision that
+	// match a filter. The first response holds the revision the watch starts
+	// from, a client resumes from the revision of the last response received.
+	// The stream also serves as server-sent events on GET /v1/files/watch.
+	WatchFiles(ctx context.Context, in *WatchFilesRequest, opts ...grpc.CallOption) (FileServer_WatchFilesClient, error)
+	// Uploads a file, the info then the content. Clients without gRPC post
+	// a multipart/form-data upload on POST /v1/files instead.
 	UploadFile(ctx context.Context, opts ...grpc.CallOption) (FileServer_UploadFileClient, error)
+	// Starts a resumable upload, the content is then sent with PutChunk and
+	// saved as a file by FinalizeUpload.
+	StartUpload(ctx context.Context, in *StartUploadRequest, opts ...grpc.CallOption) (*StartUploadResponse, error)
+	// Stores a chunk of a resumable upload at an offset.
+	PutChunk(ctx context.Context, in *PutChunkRequest, opts ...grpc.CallOption) (*PutChunkResponse, error)
+	// Returns the offset committed for a resumable upload, to resume after a failure.
+	GetUploadStatus(ctx context.Context, in *GetUploadStatusRequest, opts ...grpc.CallOption) (*GetUploadStatusResponse, error)
+	// Verifies the content of a resumable upload and saves the file.
+	FinalizeUpload(ctx context.Context, in *FinalizeUploadRequest, opts ...grpc.CallOption) (*FinalizeUploadResponse, error)
+	// Discards a resumable upload.
+	AbortUpload(ctx context.Context, in *AbortUploadRequest, opts ...grpc.CallOption) (*AbortUploadResponse, error)
 	UpdateFileMetadata(ctx context.Context, in *UpdateFileMetadataRequest, opts ...grpc.CallOption) (*UpdateFileMetadataResponse, error)
 	DownloadFile(ctx context.Context, in *DownloadFileRequest, opts ...grpc.CallOption) (FileServer_DownloadFileClient, error)
 	DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*DeleteFileResponse, error)
+	// Gets the files selected by ids or a filter.
+	BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error)
+	// Updates the metadata of the files selected by ids or a filter in a
+	// single transaction, nothing is changed when it fails for a file.
+	BatchUpdateMetadata(ctx context.Context, in *BatchUpdateMetadataRequest, opts ...grpc.CallOption) (*BatchUpdateMetadataResponse, error)
+	// Deletes the files selected by ids or a filter in a single transaction,
+	// nothing is deleted when it fails for a file.
+	BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error)
 	CleanTombstones(ctx context.Context, in *CleanTombstonesRequest, opts ...grpc.CallOption) (*CleanTombstonesResponse, error)
+	// Deletes the files that have outlived their retention rule, or with
+	// dry_run only lists them.
+	ApplyRetention(ctx context.Context, in *ApplyRetentionRequest, opts ...grpc.CallOption) (*ApplyRetentionResponse, error)
+	// Wraps the data keys of the stored content with the primary key
+	// encryption key, the content is not rewritten.
+	RotateEncryptionKeys(ctx context.Context, in *RotateEncryptionKeysRequest, opts ...grpc.CallOption) (*RotateEncryptionKeysResponse, error)
+	// Lists the audit log of calls that changed or downloaded files, newest first.
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
+	// Checks the hash chain of the audit log to detect events that were changed or removed.
+	VerifyAuditLog(ctx context.Context, in *VerifyAuditLogRequest, opts ...grpc.CallOption) (*VerifyAuditLogResponse, error)
+	// Streams a consistent, checksummed archive of the files, their metadata,
+	// versions and tombstones, and the audit log.
+	BackupDatabase(ctx context.Context, in *BackupDatabaseRequest, opts ...grpc.CallOption) (FileServer_BackupDatabaseClient, error)
+	// Restores an archive streamed by BackupDatabase into an empty data
+	// service, then verifies its integrity.
+	RestoreDatabase(ctx context.Context, opts ...grpc.CallOption) (FileServer_RestoreDatabaseClient, error)
+	// Streams a signed bundle of the files matching a filter, to be carried
+	// across an air gap and imported on a connected network.
+	ExportFiles(ctx context.Context, in *ExportFilesRequest, opts ...grpc.CallOption) (FileServer_ExportFilesClient, error)
+	// Marks the files delivered by the import of a bundle, read from the
+	// acknowledgement written by the import.
+	ImportAcknowledgement(ctx context.Context, in *ImportAcknowledgementRequest, opts ...grpc.CallOption) (*ImportAcknowledgementResponse, error)
+	// Checks the content of every file against its checksum and the audit log chain.
+	VerifyDatabase(ctx context.Context, in *VerifyDatabaseRequest, opts ...grpc.CallOption) (*VerifyDatabaseResponse, error)
 }
 
 type fileServerClient struct {
@@ -68,8 +139,49 @@ func (c *fileServerClient) GetFile(ctx context.Context, in *GetFileRequest, opts
 	return out, nil
 }
 
+func (c *fileServerClient) ListFileVersions(ctx context.Context, in *ListFileVersionsRequest, opts ...grpc.CallOption) (*ListFileVersionsResponse, error) {
+	out := new(ListFileVersionsResponse)
+	err := c.cc.Invoke(ctx, FileServer_ListFileVersions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServerClient) WatchFiles(ctx context.Context, in *WatchFilesRequest, opts ...grpc.CallOption) (FileServer_WatchFilesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileServer_ServiceDesc.Streams[0], FileServer_WatchFiles_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileServerWatchFilesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FileServer_WatchFilesClient interface {
+	Recv() (*WatchFilesResponse, error)
+	grpc.ClientStream
+}
+
+type fileServerWatchFilesClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileServerWatchFilesClient) Recv() (*WatchFilesResponse, error) {
+	m := new(WatchFilesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *fileServerClient) UploadFile(ctx context.Context, opts ...grpc.CallOption) (FileServer_UploadFileClient, error) {
-	stream, err := c.cc.NewStream(ctx, &FileServer_ServiceDesc.Streams[0], FileServer_UploadFile_FullMethodName, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileServer_ServiceDesc.Streams[1], FileServer_UploadFile_FullMethodName, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -102,6 +214,51 @@ func (x *fileServerUploadFileClient) CloseAndRecv() (*UploadFileResponse, error)
 	return m, nil
 }
 
+func (c *fileServerClient) StartUpload(ctx context.Context, in *StartUploadRequest, opts ...grpc.CallOption) (*StartUploadResponse, error) {
+	out := new(StartUploadResponse)
+	err := c.cc.Invoke(ctx, FileServer_StartUpload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServerClient) PutChunk(ctx context.Context, in *PutChunkRequest, opts ...grpc.CallOption) (*PutChunkResponse, error) {
+	out := new(PutChunkResponse)
+	err := c.cc.Invoke(ctx, FileServer_PutChunk_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServerClient) GetUploadStatus(ctx context.Context, in *GetUploadStatusRequest, opts ...grpc.CallOption) (*GetUploadStatusResponse, error) {
+	out := new(GetUploadStatusResponse)
+	err := c.cc.Invoke(ctx, FileServer_GetUploadStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServerClient) FinalizeUpload(ctx context.Context, in *FinalizeUploadRequest, opts ...grpc.CallOption) (*FinalizeUploadResponse, error) {
+	out := new(FinalizeUploadResponse)
+	err := c.cc.Invoke(ctx, FileServer_FinalizeUpload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServerClient) AbortUpload(ctx context.Context, in *AbortUploadRequest, opts ...grpc.CallOption) (*AbortUploadResponse, error) {
+	out := new(AbortUploadResponse)
+	err := c.cc.Invoke(ctx, FileServer_AbortUpload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *fileServerClient) UpdateFileMetadata(ctx context.Context, in *UpdateFileMetadataRequest, opts ...grpc.CallOption) (*UpdateFileMetadataResponse, error) {
 	out := new(UpdateFileMetadataResponse)
 	err := c.cc.Invoke(ctx, FileServer_UpdateFileMetadata_FullMethodName, in, out, opts...)
@@ -112,7 +269,7 @@ func (c *fileServerClient) UpdateFileMetadata(ctx context.Context, in *UpdateFil
 }
 
 func (c *fileServerClient) DownloadFile(ctx context.Context, in *DownloadFileRequest, opts ...grpc.CallOption) (FileServer_DownloadFileClient, error) {
-	stream, err := c.cc.NewStream(ctx, &FileServer_ServiceDesc.Streams[1], FileServer_DownloadFile_FullMethodName, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileServer_ServiceDesc.Streams[2], FileServer_DownloadFile_FullMethodName, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -152,6 +309,33 @@ func (c *fileServerClient) DeleteFile(ctx context.Context, in *DeleteFileRequest
 	return out, nil
 }
 
+func (c *fileServerClient) BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error) {
+	out := new(BatchGetResponse)
+	err := c.cc.Invoke(ctx, FileServer_BatchGet_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServerClient) BatchUpdateMetadata(ctx context.Context, in *BatchUpdateMetadataRequest, opts ...grpc.CallOption) (*BatchUpdateMetadataResponse, error) {
+	out := new(BatchUpdateMetadataResponse)
+	err := c.cc.Invoke(ctx, FileServer_BatchUpdateMetadata_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServerClient) BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error) {
+	out := new(BatchDeleteResponse)
+	err := c.cc.Invoke(ctx, FileServer_BatchDelete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *fileServerClient) CleanTombstones(ctx context.Context, in *CleanTombstonesRequest, opts ...grpc.CallOption) (*CleanTombstonesResponse, error) {
 	out := new(CleanTombstonesResponse)
 	err := c.cc.Invoke(ctx, FileServer_CleanTombstones_FullMethodName, in, out, opts...)
@@ -161,6 +345,158 @@ func (c *fileServerClient) CleanTombstones(ctx context.Context, in *CleanTombsto
 	return out, nil
 }
 
+func (c *fileServerClient) ApplyRetention(ctx context.Context, in *ApplyRetentionRequest, opts ...grpc.CallOption) (*ApplyRetentionResponse, error) {
+	out := new(ApplyRetentionResponse)
+	err := c.cc.Invoke(ctx, FileServer_ApplyRetention_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServerClient) RotateEncryptionKeys(ctx context.Context, in *RotateEncryptionKeysRequest, opts ...grpc.CallOption) (*RotateEncryptionKeysResponse, error) {
+	out := new(RotateEncryptionKeysResponse)
+	err := c.cc.Invoke(ctx, FileServer_RotateEncryptionKeys_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServerClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, FileServer_ListAuditEvents_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServerClient) VerifyAuditLog(ctx context.Context, in *VerifyAuditLogRequest, opts ...grpc.CallOption) (*VerifyAuditLogResponse, error) {
+	out := new(VerifyAuditLogResponse)
+	err := c.cc.Invoke(ctx, FileServer_VerifyAuditLog_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServerClient) BackupDatabase(ctx context.Context, in *BackupDatabaseRequest, opts ...grpc.CallOption) (FileServer_BackupDatabaseClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileServer_ServiceDesc.Streams[3], FileServer_BackupDatabase_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileServerBackupDatabaseClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FileServer_BackupDatabaseClient interface {
+	Recv() (*BackupDatabaseResponse, error)
+	grpc.ClientStream
+}
+
+type fileServerBackupDatabaseClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileServerBackupDatabaseClient) Recv() (*BackupDatabaseResponse, error) {
+	m := new(BackupDatabaseResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileServerClient) RestoreDatabase(ctx context.Context, opts ...grpc.CallOption) (FileServer_RestoreDatabaseClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileServer_ServiceDesc.Streams[4], FileServer_RestoreDatabase_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileServerRestoreDatabaseClient{stream}
+	return x, nil
+}
+
+type FileServer_RestoreDatabaseClient interface {
+	Send(*RestoreDatabaseRequest) error
+	CloseAndRecv() (*RestoreDatabaseResponse, error)
+	grpc.ClientStream
+}
+
+type fileServerRestoreDatabaseClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileServerRestoreDatabaseClient) Send(m *RestoreDatabaseRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *fileServerRestoreDatabaseClient) CloseAndRecv() (*RestoreDatabaseResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(RestoreDatabaseResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileServerClient) ExportFiles(ctx context.Context, in *ExportFilesRequest, opts ...grpc.CallOption) (FileServer_ExportFilesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileServer_ServiceDesc.Streams[5], FileServer_ExportFiles_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileServerExportFilesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FileServer_ExportFilesClient interface {
+	Recv() (*ExportFilesResponse, error)
+	grpc.ClientStream
+}
+
+type fileServerExportFilesClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileServerExportFilesClient) Recv() (*ExportFilesResponse, error) {
+	m := new(ExportFilesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileServerClient) ImportAcknowledgement(ctx context.Context, in *ImportAcknowledgementRequest, opts ...grpc.CallOption) (*ImportAcknowledgementResponse, error) {
+	out := new(ImportAcknowledgementResponse)
+	err := c.cc.Invoke(ctx, FileServer_ImportAcknowledgement_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServerClient) VerifyDatabase(ctx context.Context, in *VerifyDatabaseRequest, opts ...grpc.CallOption) (*VerifyDatabaseResponse, error) {
+	out := new(VerifyDatabaseResponse)
+	err := c.cc.Invoke(ctx, FileServer_VerifyDatabase_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FileServerServer is the server API for FileServer service.
 // All implementations must embed UnimplementedFileServerServer
 // for forward compatibility
@@ -168,11 +504,63 @@ type FileServerServer interface {
 	// Lists files.
 	ListFiles(context.Context, *ListFilesRequest) (*ListFilesResponse, error)
 	GetFile(context.Context, *GetFileRequest) (*GetFileResponse, error)
+	// Lists the versions kept of a file, newest first.
+	ListFileVersions(context.Context, *ListFileVersionsRequest) (*ListFileVersionsResponse, error)
+	// Streams the files created, updated and deleted after a revision that
+	// match a filter. The first response holds the revision the watch starts
+	// from, a client resumes from the revision of the last response received.
+	// The stream also serves as server-sent events on GET /v1/files/watch.
+	WatchFiles(*WatchFilesRequest, FileServer_WatchFilesServer) error
+	// Uploads a file, the info then the content. Clients without gRPC post
+	// a multipart/form-data upload on POST /v1/files instead.
 	UploadFile(FileServer_UploadFileServer) error
+	// Starts a resumable upload, the content is then sent with PutChunk and
+	// saved as a file by FinalizeUpload.
+	StartUpload(context.Context, *StartUploadRequest) (*StartUploadResponse, error)
+	// Stores a chunk of a resumable upload at an offset.
+	PutChunk(context.Context, *PutChunkRequest) (*PutChunkResponse, error)
+	// Returns the offset committed for a resumable upload, to resume after a failure.
+	GetUploadStatus(context.Context, *GetUploadStatusRequest) (*GetUploadStatusResponse, error)
+	// Verifies the content of a resumable upload and saves the file.
+	FinalizeUpload(context.Context, *FinalizeUploadRequest) (*FinalizeUploadResponse, error)
+	// Discards a resumable upload.
+	AbortUpload(context.Context, *AbortUploadRequest) (*AbortUploadResponse, error)
 	UpdateFileMetadata(context.Context, *UpdateFileMetadataRequest) (*UpdateFileMetadataResponse, error)
 	DownloadFile(*DownloadFileRequest, FileServer_DownloadFileServer) error
 	DeleteFile(context.Context, *DeleteFileRequest) (*DeleteFileResponse, error)
+	// Gets the files selected by ids or a filter.
+	BatchGet(context.Context, *BatchGetRequest) (*BatchGetResponse, error)
+	// Updates the metadata of the files selected by ids or a filter in a
+	// single transaction, nothing is changed when it fails for a file.
+	BatchUpdateMetadata(context.Context, *BatchUpdateMetadataRequest) (*BatchUpdateMetadataResponse, error)
+	// Deletes the files selected by ids or a filter in a single transaction,
+	// nothing is deleted when it fails for a file.
+	BatchDelete(context.Context, *BatchDeleteRequest) (*BatchDeleteResponse, error)
 	CleanTombstones(context.Context, *CleanTombstonesRequest) (*CleanTombstonesResponse, error)
+	// Deletes the files that have outlived their retention rule, or with
+	// dry_run only lists them.
+	ApplyRetention(context.Context, *ApplyRetentionRequest) (*ApplyRetentionResponse, error)
+	// Wraps the data keys of the stored content with the primary key
+	// encryption key, the content is not rewritten.
+	RotateEncryptionKeys(context.Context, *RotateEncryptionKeysRequest) (*RotateEncryptionKeysResponse, error)
+	// Lists the audit log of calls that changed or downloaded files, newest first.
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
+	// Checks the hash chain of the audit log to detect events that were changed or removed.
+	VerifyAuditLog(context.Context, *VerifyAuditLogRequest) (*VerifyAuditLogResponse, error)
+	// Streams a consistent, checksummed archive of the files, their metadata,
+	// versions and tombstones, and the audit log.
+	BackupDatabase(*BackupDatabaseRequest, FileServer_BackupDatabaseServer) error
+	// Restores an archive streamed by BackupDatabase into an empty data
+	// service, then verifies its integrity.
+	RestoreDatabase(FileServer_RestoreDatabaseServer) error
+	// Streams a signed bundle of the files matching a filter, to be carried
+	// across an air gap and imported on a connected network.
+	ExportFiles(*ExportFilesRequest, FileServer_ExportFilesServer) error
+	// Marks the files delivered by the import of a bundle, read from the
+	// acknowledgement written by the import.
+	ImportAcknowledgement(context.Context, *ImportAcknowledgementRequest) (*ImportAcknowledgementResponse, error)
+	// Checks the content of every file against its checksum and the audit log chain.
+	VerifyDatabase(context.Context, *VerifyDatabaseRequest) (*VerifyDatabaseResponse, error)
 	mustEmbedUnimplementedFileServerServer()
 }
 
@@ -186,9 +574,30 @@ func (UnimplementedFileServerServer) ListFiles(context.Context, *ListFilesReques
 func (UnimplementedFileServerServer) GetFile(context.Context, *GetFileRequest) (*GetFileResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetFile not implemented")
 }
+func (UnimplementedFileServerServer) ListFileVersions(context.Context, *ListFileVersionsRequest) (*ListFileVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFileVersions not implemented")
+}
+func (UnimplementedFileServerServer) WatchFiles(*WatchFilesRequest, FileServer_WatchFilesServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchFiles not implemented")
+}
 func (UnimplementedFileServerServer) UploadFile(FileServer_UploadFileServer) error {
 	return status.Errorf(codes.Unimplemented, "method UploadFile not implemented")
 }
+func (UnimplementedFileServerServer) StartUpload(context.Context, *StartUploadRequest) (*StartUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartUpload not implemented")
+}
+func (UnimplementedFileServerServer) PutChunk(context.Context, *PutChunkRequest) (*PutChunkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutChunk not implemented")
+}
+func (UnimplementedFileServerServer) GetUploadStatus(context.Context, *GetUploadStatusRequest) (*GetUploadStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUploadStatus not implemented")
+}
+func (UnimplementedFileServerServer) FinalizeUpload(context.Context, *FinalizeUploadRequest) (*FinalizeUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinalizeUpload not implemented")
+}
+func (UnimplementedFileServerServer) AbortUpload(context.Context, *AbortUploadRequest) (*AbortUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AbortUpload not implemented")
+}
 func (UnimplementedFileServerServer) UpdateFileMetadata(context.Context, *UpdateFileMetadataRequest) (*UpdateFileMetadataResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateFileMetadata not implemented")
 }
@@ -198,9 +607,45 @@ func (UnimplementedFileServerServer) DownloadFile(*DownloadFileRequest, FileServ
 func (UnimplementedFileServerServer) DeleteFile(context.Context, *DeleteFileRequest) (*DeleteFileResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteFile not implemented")
 }
+func (UnimplementedFileServerServer) BatchGet(context.Context, *BatchGetRequest) (*BatchGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGet not implemented")
+}
+func (UnimplementedFileServerServer) BatchUpdateMetadata(context.Context, *BatchUpdateMetadataRequest) (*BatchUpdateMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchUpdateMetadata not implemented")
+}
+func (UnimplementedFileServerServer) BatchDelete(context.Context, *BatchDeleteRequest) (*BatchDeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDelete not implemented")
+}
 func (UnimplementedFileServerServer) CleanTombstones(context.Context, *CleanTombstonesRequest) (*CleanTombstonesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CleanTombstones not implemented")
 }
+func (UnimplementedFileServerServer) ApplyRetention(context.Context, *ApplyRetentionRequest) (*ApplyRetentionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyRetention not implemented")
+}
+func (UnimplementedFileServerServer) RotateEncryptionKeys(context.Context, *RotateEncryptionKeysRequest) (*RotateEncryptionKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateEncryptionKeys not implemented")
+}
+func (UnimplementedFileServerServer) ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
+func (UnimplementedFileServerServer) VerifyAuditLog(context.Context, *VerifyAuditLogRequest) (*VerifyAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyAuditLog not implemented")
+}
+func (UnimplementedFileServerServer) BackupDatabase(*BackupDatabaseRequest, FileServer_BackupDatabaseServer) error {
+	return status.Errorf(codes.Unimplemented, "method BackupDatabase not implemented")
+}
+func (UnimplementedFileServerServer) RestoreDatabase(FileServer_RestoreDatabaseServer) error {
+	return status.Errorf(codes.Unimplemented, "method RestoreDatabase not implemented")
+}
+func (UnimplementedFileServerServer) ExportFiles(*ExportFilesRequest, FileServer_ExportFilesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportFiles not implemented")
+}
+func (UnimplementedFileServerServer) ImportAcknowledgement(context.Context, *ImportAcknowledgementRequest) (*ImportAcknowledgementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportAcknowledgement not implemented")
+}
+func (UnimplementedFileServerServer) VerifyDatabase(context.Context, *VerifyDatabaseRequest) (*VerifyDatabaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyDatabase not implemented")
+}
 func (UnimplementedFileServerServer) mustEmbedUnimplementedFileServerServer() {}
 
 // UnsafeFileServerServer may be embedded to opt out of forward compatibility for this service.
@@ -250,6 +695,45 @@ func _FileServer_GetFile_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileServer_ListFileVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFileVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServerServer).ListFileVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileServer_ListFileVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServerServer).ListFileVersions(ctx, req.(*ListFileVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileServer_WatchFiles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchFilesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileServerServer).WatchFiles(m, &fileServerWatchFilesServer{stream})
+}
+
+type FileServer_WatchFilesServer interface {
+	Send(*WatchFilesResponse) error
+	grpc.ServerStream
+}
+
+type fileServerWatchFilesServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileServerWatchFilesServer) Send(m *WatchFilesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _FileServer_UploadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(FileServerServer).UploadFile(&fileServerUploadFileServer{stream})
 }
@@ -276,6 +760,96 @@ func (x *fileServerUploadFileServer) Recv() (*UploadFileRequest, error) {
 	return m, nil
 }
 
+func _FileServer_StartUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServerServer).StartUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileServer_StartUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServerServer).StartUpload(ctx, req.(*StartUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileServer_PutChunk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutChunkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServerServer).PutChunk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileServer_PutChunk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServerServer).PutChunk(ctx, req.(*PutChunkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileServer_GetUploadStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUploadStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServerServer).GetUploadStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileServer_GetUploadStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServerServer).GetUploadStatus(ctx, req.(*GetUploadStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileServer_FinalizeUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinalizeUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServerServer).FinalizeUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileServer_FinalizeUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServerServer).FinalizeUpload(ctx, req.(*FinalizeUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileServer_AbortUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AbortUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServerServer).AbortUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileServer_AbortUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServerServer).AbortUpload(ctx, req.(*AbortUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FileServer_UpdateFileMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateFileMetadataRequest)
 	if err := dec(in); err != nil {
@@ -333,6 +907,60 @@ func _FileServer_DeleteFile_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileServer_BatchGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServerServer).BatchGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileServer_BatchGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServerServer).BatchGet(ctx, req.(*BatchGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileServer_BatchUpdateMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpdateMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServerServer).BatchUpdateMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileServer_BatchUpdateMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServerServer).BatchUpdateMetadata(ctx, req.(*BatchUpdateMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileServer_BatchDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServerServer).BatchDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileServer_BatchDelete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServerServer).BatchDelete(ctx, req.(*BatchDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FileServer_CleanTombstones_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CleanTombstonesRequest)
 	if err := dec(in); err != nil {
@@ -351,6 +979,182 @@ func _FileServer_CleanTombstones_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FileServer_ApplyRetention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyRetentionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServerServer).ApplyRetention(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileServer_ApplyRetention_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServerServer).ApplyRetention(ctx, req.(*ApplyRetentionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileServer_RotateEncryptionKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateEncryptionKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServerServer).RotateEncryptionKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileServer_RotateEncryptionKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServerServer).RotateEncryptionKeys(ctx, req.(*RotateEncryptionKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileServer_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServerServer).ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileServer_ListAuditEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServerServer).ListAuditEvents(ctx, req.(*ListAuditEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileServer_VerifyAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyAuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServerServer).VerifyAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileServer_VerifyAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServerServer).VerifyAuditLog(ctx, req.(*VerifyAuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileServer_BackupDatabase_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BackupDatabaseRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileServerServer).BackupDatabase(m, &fileServerBackupDatabaseServer{stream})
+}
+
+type FileServer_BackupDatabaseServer interface {
+	Send(*BackupDatabaseResponse) error
+	grpc.ServerStream
+}
+
+type fileServerBackupDatabaseServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileServerBackupDatabaseServer) Send(m *BackupDatabaseResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FileServer_RestoreDatabase_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileServerServer).RestoreDatabase(&fileServerRestoreDatabaseServer{stream})
+}
+
+type FileServer_RestoreDatabaseServer interface {
+	SendAndClose(*RestoreDatabaseResponse) error
+	Recv() (*RestoreDatabaseRequest, error)
+	grpc.ServerStream
+}
+
+type fileServerRestoreDatabaseServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileServerRestoreDatabaseServer) SendAndClose(m *RestoreDatabaseResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *fileServerRestoreDatabaseServer) Recv() (*RestoreDatabaseRequest, error) {
+	m := new(RestoreDatabaseRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _FileServer_ExportFiles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportFilesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileServerServer).ExportFiles(m, &fileServerExportFilesServer{stream})
+}
+
+type FileServer_ExportFilesServer interface {
+	Send(*ExportFilesResponse) error
+	grpc.ServerStream
+}
+
+type fileServerExportFilesServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileServerExportFilesServer) Send(m *ExportFilesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FileServer_ImportAcknowledgement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportAcknowledgementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServerServer).ImportAcknowledgement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileServer_ImportAcknowledgement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServerServer).ImportAcknowledgement(ctx, req.(*ImportAcknowledgementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileServer_VerifyDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyDatabaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServerServer).VerifyDatabase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileServer_VerifyDatabase_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServerServer).VerifyDatabase(ctx, req.(*VerifyDatabaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // FileServer_ServiceDesc is the grpc.ServiceDesc for FileServer service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -366,6 +1170,30 @@ var FileServer_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetFile",
 			Handler:    _FileServer_GetFile_Handler,
 		},
+		{
+			MethodName: "ListFileVersions",
+			Handler:    _FileServer_ListFileVersions_Handler,
+		},
+		{
+			MethodName: "StartUpload",
+			Handler:    _FileServer_StartUpload_Handler,
+		},
+		{
+			MethodName: "PutChunk",
+			Handler:    _FileServer_PutChunk_Handler,
+		},
+		{
+			MethodName: "GetUploadStatus",
+			Handler:    _FileServer_GetUploadStatus_Handler,
+		},
+		{
+			MethodName: "FinalizeUpload",
+			Handler:    _FileServer_FinalizeUpload_Handler,
+		},
+		{
+			MethodName: "AbortUpload",
+			Handler:    _FileServer_AbortUpload_Handler,
+		},
 		{
 			MethodName: "UpdateFileMetadata",
 			Handler:    _FileServer_UpdateFileMetadata_Handler,
@@ -374,12 +1202,53 @@ var FileServer_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteFile",
 			Handler:    _FileServer_DeleteFile_Handler,
 		},
+		{
+			MethodName: "BatchGet",
+			Handler:    _FileServer_BatchGet_Handler,
+		},
+		{
+			MethodName: "BatchUpdateMetadata",
+			Handler:    _FileServer_BatchUpdateMetadata_Handler,
+		},
+		{
+			MethodName: "BatchDelete",
+			Handler:    _FileServer_BatchDelete_Handler,
+		},
 		{
 			MethodName: "CleanTombstones",
 			Handler:    _FileServer_CleanTombstones_Handler,
 		},
+		{
+			MethodName: "ApplyRetention",
+			Handler:    _FileServer_ApplyRetention_Handler,
+		},
+		{
+			MethodName: "RotateEncryptionKeys",
+			Handler:    _FileServer_RotateEncryptionKeys_Handler,
+		},
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _FileServer_ListAuditEvents_Handler,
+		},
+		{
+			MethodName: "VerifyAuditLog",
+			Handler:    _FileServer_VerifyAuditLog_Handler,
+		},
+		{
+			MethodName: "ImportAcknowledgement",
+			Handler:    _FileServer_ImportAcknowledgement_Handler,
+		},
+		{
+			MethodName: "VerifyDatabase",
+			Handler:    _FileServer_VerifyDatabase_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchFiles",
+			Handler:       _FileServer_WatchFiles_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "UploadFile",
 			Handler:       _FileServer_UploadFile_Handler,
@@ -390,6 +1259,21 @@ var FileServer_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _FileServer_DownloadFile_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "BackupDatabase",
+			Handler:       _FileServer_BackupDatabase_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "RestoreDatabase",
+			Handler:       _FileServer_RestoreDatabase_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportFiles",
+			Handler:       _FileServer_ExportFiles_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "dataservice/v1/fileserver/fileserver.proto",
 }
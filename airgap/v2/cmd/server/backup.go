@@ -0,0 +1,227 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// adminClient connects to the grpc API of a running data service
+type adminClient struct {
+	api       string
+	tokenFile string
+}
+
+func (a *adminClient) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&a.api, "api", "a", "127.0.0.1:8003", "address of the grpc API of the data service")
+	cmd.Flags().StringVar(&a.tokenFile, "token-file", "", "file holding the bearer token sent to the data service, such as a service account token")
+}
+
+func (a *adminClient) connect(ctx context.Context) (fileserver.FileServerClient, context.Context, io.Closer, error) {
+	if a.tokenFile != "" {
+		token, err := os.ReadFile(a.tokenFile)
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "failed to read the token file")
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	conn, err := grpc.DialContext(ctx, a.api, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to connect to the data service")
+	}
+
+	return fileserver.NewFileServerClient(conn), ctx, conn, nil
+}
+
+func newBackupCmd() *cobra.Command {
+	var (
+		client adminClient
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Backs up the data service to an archive",
+		Long: `Writes a consistent, checksummed archive of the files, their metadata, versions and tombstones,
+and the audit log of a running data service. The sha256 checksum of the archive is written next to it.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, ctx, closer, err := client.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer closer.Close()
+
+			stream, err := c.BackupDatabase(ctx, &fileserver.BackupDatabaseRequest{})
+			if err != nil {
+				return errors.Wrap(err, "failed to start the backup")
+			}
+
+			f, err := os.Create(output)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			defer f.Close()
+
+			h := sha256.New()
+			w := io.MultiWriter(f, h)
+
+			var result *fileserver.BackupDatabaseResult
+			for result == nil {
+				res, err := stream.Recv()
+				if err == io.EOF {
+					return errors.New("the backup ended before it was complete")
+				}
+				if err != nil {
+					return errors.Wrap(err, "backup failed")
+				}
+
+				if _, err := w.Write(res.GetChunkData()); err != nil {
+					return errors.WithStack(err)
+				}
+				result = res.GetResult()
+			}
+
+			if err := f.Close(); err != nil {
+				return errors.WithStack(err)
+			}
+
+			checksum := fmt.Sprintf("%x", h.Sum(nil))
+			if checksum != result.Checksum {
+				return errors.Errorf("archive checksum %s does not match %s", checksum, result.Checksum)
+			}
+
+			err = os.WriteFile(output+".sha256", []byte(fmt.Sprintf("%s  %s\n", checksum, output)), 0600)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			log.Info("backup complete", "output", output, "checksum", checksum,
+				"files", result.Manifest.Files, "tombstones", result.Manifest.Tombstones,
+				"versions", result.Manifest.Versions, "auditEvents", result.Manifest.AuditEvents)
+			return nil
+		},
+	}
+
+	client.addFlags(cmd)
+	cmd.Flags().StringVarP(&output, "output", "o", "dataservice-backup.tar.gz", "file the archive is written to")
+
+	return cmd
+}
+
+func newRestoreCmd() *cobra.Command {
+	var (
+		client   adminClient
+		input    string
+		checksum string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restores an archive into an empty data service",
+		Long: `Restores an archive written by backup into a running data service without files,
+such as a new node, then verifies the content of every file and the audit log.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if checksum == "" {
+				// written by backup next to the archive
+				if data, err := os.ReadFile(input + ".sha256"); err == nil {
+					checksum = strings.Fields(string(data) + " ")[0]
+				}
+			}
+
+			f, err := os.Open(input)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			defer f.Close()
+
+			c, ctx, closer, err := client.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer closer.Close()
+
+			stream, err := c.RestoreDatabase(ctx)
+			if err != nil {
+				return errors.Wrap(err, "failed to start the restore")
+			}
+
+			if checksum != "" {
+				err := stream.Send(&fileserver.RestoreDatabaseRequest{
+					Data: &fileserver.RestoreDatabaseRequest_Checksum{Checksum: checksum},
+				})
+				if err != nil {
+					return errors.Wrap(err, "restore failed")
+				}
+			}
+
+			chunk := make([]byte, 64*1024)
+			for {
+				n, err := f.Read(chunk)
+				if n > 0 {
+					sendErr := stream.Send(&fileserver.RestoreDatabaseRequest{
+						Data: &fileserver.RestoreDatabaseRequest_ChunkData{ChunkData: chunk[:n]},
+					})
+					if sendErr != nil {
+						// the error of the call is returned by CloseAndRecv
+						break
+					}
+				}
+
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return errors.WithStack(err)
+				}
+			}
+
+			res, err := stream.CloseAndRecv()
+			if err != nil {
+				return errors.Wrap(err, "restore failed")
+			}
+
+			log.Info("restore complete", "input", input,
+				"files", res.Manifest.Files, "tombstones", res.Manifest.Tombstones,
+				"versions", res.Manifest.Versions, "auditEvents", res.Manifest.AuditEvents)
+
+			if !res.Verification.Valid {
+				return errors.Errorf("restored data service failed verification, invalid files %v, first invalid audit event %q",
+					res.Verification.InvalidFileIds, res.Verification.FirstInvalidAuditEventId)
+			}
+
+			log.Info("verification complete", "files", res.Verification.FilesVerified, "auditEvents", res.Verification.AuditEventsVerified)
+			return nil
+		},
+	}
+
+	client.addFlags(cmd)
+	cmd.Flags().StringVarP(&input, "input", "i", "dataservice-backup.tar.gz", "archive written by backup")
+	cmd.Flags().StringVar(&checksum, "checksum", "", "sha256 checksum of the archive, read from the .sha256 file next to it by default")
+
+	return cmd
+}
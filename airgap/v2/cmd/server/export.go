@@ -0,0 +1,156 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+
+	"emperror.dev/errors"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/bundle"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+)
+
+func newExportCmd() *cobra.Command {
+	var (
+		client adminClient
+		output string
+		filter string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Exports files to a signed bundle carried across the air gap",
+		Long: `Writes the files matching a filter, by default the files not yet delivered, to a bundle signed
+by the data service. The bundle is imported on a connected network by the import command of the reporter,
+whose acknowledgement is then imported back with the acknowledge command.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, ctx, closer, err := client.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer closer.Close()
+
+			stream, err := c.ExportFiles(ctx, &fileserver.ExportFilesRequest{Filter: filter})
+			if err != nil {
+				return errors.Wrap(err, "failed to start the export")
+			}
+
+			f, err := os.Create(output)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			defer f.Close()
+
+			h := sha256.New()
+			w := io.MultiWriter(f, h)
+
+			var result *fileserver.ExportFilesResult
+			for result == nil {
+				res, err := stream.Recv()
+				if err == io.EOF {
+					return errors.New("the export ended before it was complete")
+				}
+				if err != nil {
+					return errors.Wrap(err, "export failed")
+				}
+
+				if _, err := w.Write(res.GetChunkData()); err != nil {
+					return errors.WithStack(err)
+				}
+				result = res.GetResult()
+			}
+
+			if err := f.Close(); err != nil {
+				return errors.WithStack(err)
+			}
+
+			checksum := fmt.Sprintf("%x", h.Sum(nil))
+			if checksum != result.Checksum {
+				return errors.Errorf("bundle checksum %s does not match %s", checksum, result.Checksum)
+			}
+
+			log.Info("export complete", "output", output, "bundle", result.BundleId, "files", result.Files, "checksum", checksum)
+			return nil
+		},
+	}
+
+	client.addFlags(cmd)
+	cmd.Flags().StringVarP(&output, "output", "o", "dataservice-export.tar", "file the bundle is written to")
+	cmd.Flags().StringVar(&filter, "filter", bundle.UndeliveredFilter, "files exported, in the syntax of the ListFiles filter, all the files when empty")
+
+	return cmd
+}
+
+func newAcknowledgeCmd() *cobra.Command {
+	var (
+		client          adminClient
+		input           string
+		deleteDelivered bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "acknowledge",
+		Short: "Marks the files of an exported bundle delivered",
+		Long: `Imports the acknowledgement written by the import of a bundle on a connected network, marking
+the files uploaded as delivered so they are not exported again. Files whose content changed since the
+export are left as they are.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(input)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			c, ctx, closer, err := client.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer closer.Close()
+
+			res, err := c.ImportAcknowledgement(ctx, &fileserver.ImportAcknowledgementRequest{
+				Acknowledgement: data,
+				DeleteDelivered: deleteDelivered,
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to import the acknowledgement")
+			}
+
+			failed := 0
+			for _, result := range res.Results {
+				if codes.Code(result.Code) != codes.OK {
+					failed++
+					log.Info("file not acknowledged", "id", result.Id, "code", codes.Code(result.Code).String(), "message", result.Message)
+				}
+			}
+
+			log.Info("acknowledgement imported", "input", input, "files", len(res.Results)-failed, "failed", failed)
+
+			if failed != 0 {
+				return errors.Errorf("%d files were not acknowledged", failed)
+			}
+			return nil
+		},
+	}
+
+	client.addFlags(cmd)
+	cmd.Flags().StringVarP(&input, "input", "i", "dataservice-export.tar.ack.json", "acknowledgement written by the import of the bundle")
+	cmd.Flags().BoolVar(&deleteDelivered, "delete", false, "delete the files delivered once marked, they are kept until the tombstones are cleaned")
+
+	return cmd
+}
@@ -15,23 +15,33 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
+	"github.com/prometheus/client_golang/prometheus"
 	server "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/internal/server"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/bundle"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/dqlite"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/envelope"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/scheduler"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/tracing"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 	k8sapiflag "k8s.io/component-base/cli/flag"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
 var log logr.Logger
@@ -41,6 +51,8 @@ func main() {
 		api            string
 		gatewayApi     string
 		db             string
+		dbDriver       string
+		dbDSN          string
 		join           []string
 		dir            string
 		verbose        bool
@@ -52,6 +64,38 @@ func main() {
 		tlsKey         string
 		minVersion     string
 		cipherSuites   []string
+		blobStore      string
+		blobDir        string
+		s3Endpoint     string
+		s3Bucket       string
+		s3Prefix       string
+		s3Region       string
+		s3AccessKey    string
+		s3SecretKey    string
+		s3Insecure     bool
+		chunkSize      int
+		uploadExpiry   time.Duration
+		eventRetention time.Duration
+		watchPoll      time.Duration
+		maxVersions    int
+		pageTokenFile  string
+		quotaMaxBytes  string
+		quotaMaxFiles  int64
+		sourceMaxBytes string
+		sourceMaxFiles int64
+		authEnabled    bool
+		authAudiences  []string
+		authAdmins     []string
+		authUploaders  []string
+		authReaders    []string
+		compression    string
+		compressMin    int
+		keyDir         string
+		primaryKey     string
+		bundleKey      string
+		otlpEndpoint   string
+		otlpInsecure   bool
+		sampleRatio    float64
 	)
 
 	cmd := &cobra.Command{
@@ -67,8 +111,36 @@ func main() {
 				}
 			}
 
+			if endpoint := viper.GetString("otlp-endpoint"); endpoint != "" {
+				shutdown, err := tracing.Start(context.Background(), tracing.Config{
+					Endpoint:    endpoint,
+					Insecure:    viper.GetBool("otlp-insecure"),
+					SampleRatio: viper.GetFloat64("trace-sample-ratio"),
+				})
+				if err != nil {
+					log.Error(err, "failed to start tracing")
+					return err
+				}
+
+				defer func() {
+					ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+					defer cancel()
+					if err := shutdown(ctx); err != nil {
+						log.Error(err, "failed to flush traces")
+					}
+				}()
+			}
+
 			j := viper.GetStringSlice("join")
 
+			driver := viper.GetString("db-driver")
+			if driver == "" {
+				driver = dqlite.DefaultDriver
+			}
+			if driver == dqlite.DriverDqlite && viper.GetString("db") == "" {
+				return fmt.Errorf("required flag \"db\" not set, the %s driver needs a replication address", driver)
+			}
+
 			tlsVersion, err := k8sapiflag.TLSVersion(minVersion)
 			if err != nil {
 				log.Error(err, "TLS version invalid")
@@ -81,18 +153,107 @@ func main() {
 				os.Exit(1)
 			}
 
+			blobType := viper.GetString("blob-store")
+			localBlobs := blobType == blobstore.TypeLocal || blobType == ""
+			if driver == dqlite.DriverDqlite && localBlobs && len(j) != 0 {
+				err := fmt.Errorf("%w, use --blob-store %s", dqlite.ErrLocalBlobStore, blobstore.TypeS3)
+				log.Error(err, "invalid blob store")
+				return err
+			}
+
+			blobDir := viper.GetString("blob-dir")
+			if blobDir == "" {
+				blobDir = filepath.Join(viper.GetString("dir"), "blobs")
+			}
+
+			blobs, err := blobstore.New(context.Background(), blobstore.Config{
+				Type: blobType,
+				Dir:  blobDir,
+				S3: blobstore.S3Config{
+					Endpoint:        viper.GetString("s3-endpoint"),
+					Bucket:          viper.GetString("s3-bucket"),
+					Prefix:          viper.GetString("s3-prefix"),
+					Region:          viper.GetString("s3-region"),
+					AccessKeyID:     viper.GetString("s3-access-key"),
+					SecretAccessKey: viper.GetString("s3-secret-key"),
+					Insecure:        viper.GetBool("s3-insecure"),
+				},
+			})
+			if err != nil {
+				log.Error(err, "failed to create blob store")
+				return err
+			}
+
+			var retention []database.RetentionRule
+			if err := viper.UnmarshalKey("retention", &retention); err != nil {
+				log.Error(err, "failed to read retention rules")
+				return err
+			}
+
+			if err := database.ValidateRetentionRules(retention); err != nil {
+				log.Error(err, "invalid retention rules")
+				return err
+			}
+
+			quotas, err := quotasFromConfig()
+			if err != nil {
+				log.Error(err, "invalid quotas")
+				return err
+			}
+
+			compression := database.CompressionPolicy{
+				Algorithm: viper.GetString("compression"),
+				MinSize:   viper.GetInt("compression-min-size"),
+			}
+			if err := compression.Validate(); err != nil {
+				log.Error(err, "invalid compression")
+				return err
+			}
+
+			pageTokenKey, err := readPageTokenKey()
+			if err != nil {
+				log.Error(err, "failed to read the page token key")
+				return err
+			}
+			if driver == dqlite.DriverDqlite && len(pageTokenKey) == 0 && len(j) != 0 {
+				log.Error(dqlite.ErrNoPageTokenKey, "invalid page token key")
+				return dqlite.ErrNoPageTokenKey
+			}
+
+			var keyring *envelope.Keyring
+			if dir := viper.GetString("encryption-key-dir"); dir != "" {
+				keyring, err = envelope.LoadKeyring(dir, viper.GetString("encryption-key-id"))
+				if err != nil {
+					log.Error(err, "failed to load the key encryption keys")
+					return err
+				}
+				log.Info("content encryption enabled", "primaryKey", keyring.Primary(), "keys", keyring.KeyIDs())
+			}
+
 			cfg := &dqlite.DatabaseConfig{
-				Name:         "airgap",
-				Dir:          viper.GetString("dir"),
-				Url:          viper.GetString("db"),
-				Join:         &j,
-				Verbose:      viper.GetBool("verbose"),
-				Log:          log,
-				CACert:       caCert,
-				TLSCert:      tlsCert,
-				TLSKey:       tlsKey,
-				CipherSuites: tlsCipherSuites,
-				MinVersion:   tlsVersion,
+				Driver:         driver,
+				DSN:            viper.GetString("db-dsn"),
+				Name:           "airgap",
+				Dir:            viper.GetString("dir"),
+				Url:            viper.GetString("db"),
+				Join:           &j,
+				Verbose:        viper.GetBool("verbose"),
+				Log:            log,
+				CACert:         caCert,
+				TLSCert:        tlsCert,
+				TLSKey:         tlsKey,
+				CipherSuites:   tlsCipherSuites,
+				MinVersion:     tlsVersion,
+				BlobStore:      blobs,
+				LocalBlobStore: localBlobs,
+				UploadExpiry:   viper.GetDuration("upload-expiry"),
+				EventRetention: viper.GetDuration("event-retention"),
+				MaxVersions:    viper.GetInt("max-file-versions"),
+				PageTokenKey:   pageTokenKey,
+				RetentionRules: retention,
+				Quotas:         quotas,
+				Compression:    compression,
+				Keyring:        keyring,
 			}
 
 			cleanAfter := viper.GetDuration("cleanAfter")
@@ -104,12 +265,17 @@ func main() {
 
 			defer cfg.Close()
 
+			// metrics of the server, the scheduler and the database node
+			registry := prometheus.NewRegistry()
+			registry.MustRegister(cfg.Collector())
+
 			sched := &scheduler.SchedulerConfig{
 				Log:            log,
 				Fs:             fs,
 				DB:             cfg,
 				CleanAfter:     viper.GetString("cleanAfter"),
 				CronExpression: viper.GetString("cronExpression"),
+				Registerer:     registry,
 			}
 
 			// Attempt migration and start scheduler
@@ -118,10 +284,45 @@ func main() {
 				return err
 			}
 
-			ctx, cancel := context.WithCancel(context.Background())
+			bs := &server.Server{
+				Log:               log,
+				FileStore:         fs,
+				APIEndpoint:       api,
+				GatewayEndpoint:   gatewayApi,
+				ChunkSize:         chunkSize,
+				Registry:          registry,
+				WatchPollInterval: viper.GetDuration("watch-poll-interval"),
+			}
 
-			bs := &server.Server{Log: log, FileStore: fs, APIEndpoint: api, GatewayEndpoint: gatewayApi}
+			if path := viper.GetString("bundle-signing-key"); path != "" {
+				bs.BundleSigningKey, err = bundle.LoadPrivateKey(path)
+				if err != nil {
+					log.Error(err, "failed to load the bundle signing key")
+					return err
+				}
+			}
+
+			if viper.GetBool("auth-token-review") {
+				restConfig, err := ctrlconfig.GetConfig()
+				if err != nil {
+					log.Error(err, "failed to get kubernetes config")
+					return err
+				}
+
+				bs.TokenValidator, err = server.NewTokenReviewValidator(restConfig, viper.GetStringSlice("auth-audiences"))
+				if err != nil {
+					log.Error(err, "failed to create token validator")
+					return err
+				}
+
+				bs.RoleBindings = server.NewRoleBindings(map[server.Role][]string{
+					server.RoleAdmin:    viper.GetStringSlice("auth-admins"),
+					server.RoleUploader: viper.GetStringSlice("auth-uploaders"),
+					server.RoleReader:   viper.GetStringSlice("auth-readers"),
+				})
+			}
 
+			ctx, cancel := context.WithCancel(context.Background())
 			stopCh := (&shutdownHandler{log: log}).SetupSignalHandler()
 
 			var group errgroup.Group
@@ -150,7 +351,9 @@ func main() {
 	flags.StringVarP(&api, "api", "a", "127.0.0.1:8003", "address used to expose the grpc API")
 	flags.StringVarP(&gatewayApi, "gw", "g", "127.0.0.1:8007", "address used to expose the grpc gateway API")
 
-	flags.StringVarP(&db, "db", "d", "", "address used for internal database replication")
+	flags.StringVarP(&db, "db", "d", "", "address used for internal database replication, required by the dqlite driver")
+	flags.StringVar(&dbDriver, "db-driver", "", "database storing the files, dqlite, sqlite or postgres, defaults to dqlite on linux and sqlite elsewhere")
+	flags.StringVar(&dbDSN, "db-dsn", "", "connection string of the postgres database, such as host=db user=airgap dbname=airgap sslmode=verify-full")
 
 	flags.StringSliceVarP(&join, "join", "j", nil, "database addresses of existing nodes")
 	flags.StringVarP(&dir, "dir", "D", "/tmp/dqlite", "data directory")
@@ -163,6 +366,42 @@ func main() {
 	flags.StringVar(&cleanAfter, "cleanAfter", "-1440h", "clean files older than x seconds/minutes/hours, default 1440i.e. 60 days")
 	flags.StringVar(&cronExpression, "cronExpression", "*/10 * * * *", "cron expression for scheduler, default cron will run every day 12:00 AM")
 
+	flags.StringVar(&blobStore, "blob-store", blobstore.TypeLocal, "backend used to store file content, local or s3, a dqlite cluster of several nodes requires s3")
+	flags.StringVar(&blobDir, "blob-dir", "", "directory used by the local blob store, defaults to a blobs folder in the data directory")
+	flags.StringVar(&s3Endpoint, "s3-endpoint", "", "host:port of the S3 compatible service used by the s3 blob store")
+	flags.StringVar(&s3Bucket, "s3-bucket", "", "bucket used by the s3 blob store")
+	flags.StringVar(&s3Prefix, "s3-prefix", "", "object name prefix used by the s3 blob store")
+	flags.StringVar(&s3Region, "s3-region", "", "region of the s3 bucket")
+	flags.StringVar(&s3AccessKey, "s3-access-key", "", "access key for the s3 blob store")
+	flags.StringVar(&s3SecretKey, "s3-secret-key", "", "secret key for the s3 blob store")
+	flags.BoolVar(&s3Insecure, "s3-insecure", false, "connect to the s3 blob store without TLS")
+
+	flags.DurationVar(&eventRetention, "event-retention", 24*time.Hour, "time file events are kept for watchers to resume from, 0 keeps them forever")
+	flags.DurationVar(&watchPoll, "watch-poll-interval", time.Second, "interval at which watchers read the file changes made through the other nodes")
+	flags.DurationVar(&uploadExpiry, "upload-expiry", 72*time.Hour, "time after which an idle resumable upload is discarded, 0 keeps them until finalized")
+	flags.IntVar(&maxVersions, "max-file-versions", 10, "number of versions kept for each file, 0 keeps them all")
+	flags.StringVar(&pageTokenFile, "page-token-key-file", "", "file of the mounted secret holding the key signing list page tokens, shared by all replicas, the AIRGAP_PAGE_TOKEN_KEY environment variable is used when empty, a random key is used when both are empty and there is a single node")
+	flags.StringVar(&quotaMaxBytes, "quota-max-bytes", "", "maximum size of the content stored for all files, such as 50Gi, unlimited when empty")
+	flags.Int64Var(&quotaMaxFiles, "quota-max-files", 0, "maximum number of files stored, 0 is unlimited")
+	flags.StringVar(&sourceMaxBytes, "quota-source-max-bytes", "", "maximum size of the content stored for the files of each source, unless set for the source in quotaSources of the config file")
+	flags.Int64Var(&sourceMaxFiles, "quota-source-max-files", 0, "maximum number of files stored for each source, unless set for the source in quotaSources of the config file")
+	flags.StringVar(&compression, "compression", database.CompressionZstd, "compression of the stored content, none, gzip or zstd, content already compressed is stored as is")
+	flags.IntVar(&compressMin, "compression-min-size", 512, "size in bytes under which content is not compressed, up to 65536")
+	flags.StringVar(&keyDir, "encryption-key-dir", "", "directory of the mounted secret holding the key encryption keys, one 32 byte key per file named by its id, content is not encrypted when empty")
+	flags.StringVar(&primaryKey, "encryption-key-id", "", "id of the key wrapping new data keys, required when there are several keys, data keys wrapped by another key are rewrapped by the scheduler")
+	flags.StringVar(&bundleKey, "bundle-signing-key", "", "PEM file of the ed25519 private key signing the bundles of exported files, files can not be exported when empty")
+	flags.IntVar(&chunkSize, "chunk-size", 32*1024, "size in bytes of the chunks sent when streaming a download")
+
+	flags.BoolVar(&authEnabled, "auth-token-review", false, "authenticate callers with a kubernetes TokenReview of their bearer token")
+	flags.StringSliceVar(&authAudiences, "auth-audiences", nil, "audiences the bearer tokens must be issued for")
+	flags.StringSliceVar(&authAdmins, "auth-admins", nil, "users granted the admin role, groups are prefixed with group:")
+	flags.StringSliceVar(&authUploaders, "auth-uploaders", nil, "users granted the uploader role, groups are prefixed with group:")
+	flags.StringSliceVar(&authReaders, "auth-readers", nil, "users granted the reader role, groups are prefixed with group:")
+
+	flags.StringVar(&otlpEndpoint, "otlp-endpoint", "", "host:port of the OTLP gRPC collector receiving the traces, tracing is disabled when empty")
+	flags.BoolVar(&otlpInsecure, "otlp-insecure", false, "connect to the OTLP collector without TLS")
+	flags.Float64Var(&sampleRatio, "trace-sample-ratio", 0.1, "fraction of the traces started by the server that are sampled, traces started by callers follow their decision")
+
 	flags.StringVar(&minVersion, "tls-min-version", "VersionTLS12", "Minimum TLS version supported. Value must match version names from https://golang.org/pkg/crypto/tls/#pkg-constants.")
 	flags.StringSliceVar(&cipherSuites,
 		"tls-cipher-suites",
@@ -174,8 +413,13 @@ func main() {
 			"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"},
 		"Comma-separated list of cipher suites for the server. Values are from tls package constants (https://golang.org/pkg/crypto/tls/#pkg-constants). If omitted, a subset will be used")
 
-	cmd.MarkFlagRequired("db")
+	cmd.AddCommand(newBackupCmd(), newRestoreCmd(), newExportCmd(), newAcknowledgeCmd())
 
+	// flags can also be set by AIRGAP_ environment variables, such as
+	// AIRGAP_S3_SECRET_KEY, to keep secrets out of the command line
+	viper.SetEnvPrefix("airgap")
+	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	viper.AutomaticEnv()
 	viper.BindPFlags(flags)
 
 	if err := cmd.Execute(); err != nil {
@@ -183,6 +427,26 @@ func main() {
 	}
 }
 
+// readPageTokenKey returns the key of --page-token-key-file, or of the
+// AIRGAP_PAGE_TOKEN_KEY environment variable. The key is not a flag so it is
+// not visible in the command line of the process.
+func readPageTokenKey() ([]byte, error) {
+	if path := viper.GetString("page-token-key-file"); path != "" {
+		key, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		key = bytes.TrimSpace(key)
+		if len(key) == 0 {
+			return nil, fmt.Errorf("page token key file %s is empty", path)
+		}
+		return key, nil
+	}
+
+	return []byte(viper.GetString("page-token-key")), nil
+}
+
 var onlyOneSignalHandler = make(chan struct{})
 var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
 
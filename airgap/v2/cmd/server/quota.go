@@ -0,0 +1,80 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/pkg/errors"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// sourceQuota is the quota of a source in the config file, bytes are a
+// quantity such as 10Gi
+type sourceQuota struct {
+	MaxBytes string `mapstructure:"maxBytes"`
+	MaxFiles int64  `mapstructure:"maxFiles"`
+}
+
+// quotasFromConfig reads the global and default source quotas from the flags
+// and the quotas of each source from the quotaSources key of the config file:
+//
+//	quotaSources:
+//	  dataReporter:
+//	    maxBytes: 10Gi
+//	    maxFiles: 10000
+func quotasFromConfig() (database.Quotas, error) {
+	quotas := database.Quotas{Sources: map[string]database.QuotaLimits{}}
+
+	var err error
+	quotas.Global, err = quotaLimits(viper.GetString("quota-max-bytes"), viper.GetInt64("quota-max-files"))
+	if err != nil {
+		return quotas, errors.Wrap(err, "invalid quota-max-bytes")
+	}
+
+	quotas.DefaultSource, err = quotaLimits(viper.GetString("quota-source-max-bytes"), viper.GetInt64("quota-source-max-files"))
+	if err != nil {
+		return quotas, errors.Wrap(err, "invalid quota-source-max-bytes")
+	}
+
+	sources := map[string]sourceQuota{}
+	if err := viper.UnmarshalKey("quotaSources", &sources); err != nil {
+		return quotas, errors.Wrap(err, "invalid quotaSources")
+	}
+
+	for source, quota := range sources {
+		quotas.Sources[source], err = quotaLimits(quota.MaxBytes, quota.MaxFiles)
+		if err != nil {
+			return quotas, errors.Wrapf(err, "invalid quota of source %s", source)
+		}
+	}
+
+	return quotas, nil
+}
+
+func quotaLimits(maxBytes string, maxFiles int64) (database.QuotaLimits, error) {
+	limits := database.QuotaLimits{MaxFiles: maxFiles}
+	if maxBytes == "" {
+		return limits, nil
+	}
+
+	quantity, err := resource.ParseQuantity(maxBytes)
+	if err != nil {
+		return limits, err
+	}
+
+	limits.MaxBytes = quantity.Value()
+	return limits, nil
+}
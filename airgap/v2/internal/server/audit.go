@@ -0,0 +1,163 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	dataservicev1 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newAuditEvent starts the audit event of a call to method by the caller of ctx
+func newAuditEvent(ctx context.Context, method string) *modelsv2.AuditEvent {
+	event := &modelsv2.AuditEvent{Method: method}
+
+	if caller := callerFromContext(ctx); caller != nil {
+		event.Username = caller.Username
+
+		if len(caller.Groups) != 0 {
+			groups, _ := json.Marshal(caller.Groups)
+			event.Groups = string(groups)
+		}
+	}
+
+	return event
+}
+
+// setAuditFile records the file a call applies to
+func setAuditFile(event *modelsv2.AuditEvent, file *modelsv2.StoredFile) {
+	event.FileID = file.ID
+	event.Name = file.Name
+	event.Source = file.Source
+	event.SourceType = file.SourceType
+	event.ChecksumBefore = file.File.Checksum
+	event.ChecksumAfter = file.File.Checksum
+}
+
+func setAuditDetails(event *modelsv2.AuditEvent, details map[string]string) {
+	data, _ := json.Marshal(details)
+	event.Details = string(data)
+}
+
+// audit appends the events to the audit log for a call that is not recorded
+// in the transaction of a change, such as a download. Changes are recorded in
+// their own transaction with database.WithAuditEvent.
+func (fs *FileServer) audit(events ...*modelsv2.AuditEvent) error {
+	// recorded even when the caller went away
+	if err := fs.FileStore.AppendAuditEvent(context.Background(), events...); err != nil {
+		fs.Log.Error(err, "failed to record audit event", "method", events[0].Method, "events", len(events))
+		return status.Errorf(codes.Internal, "failed to record audit event %s=%s", "err", err)
+	}
+
+	return nil
+}
+
+// auditPrepare wraps prepare to record the file an upload saves, the
+// checksum of the content it replaces is read in the transaction saving it.
+func auditPrepare(event *modelsv2.AuditEvent, prepare database.PrepareUpload) database.PrepareUpload {
+	return func(content *modelsv2.StoredFileContent) (*modelsv2.StoredFile, error) {
+		file, err := prepare(content)
+		if err != nil {
+			return nil, err
+		}
+
+		event.Name = file.Name
+		event.Source = file.Source
+		event.SourceType = file.SourceType
+		event.ChecksumAfter = content.Checksum
+
+		return file, nil
+	}
+}
+
+func (fs *FileServer) ListAuditEvents(ctx context.Context, req *fileserver.ListAuditEventsRequest) (*fileserver.ListAuditEventsResponse, error) {
+	pageSize := 100
+	opts := []database.ListOption{}
+
+	if req.PageSize != 0 {
+		pageSize = int(req.PageSize)
+	}
+
+	opts = append(opts, database.Paginate(req.PageToken, pageSize))
+
+	if req.Filter != "" {
+		expr, err := fileserver.ParseFilter(req.Filter)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Filter is formatted incorrectly. Error: %s", err)
+		}
+		opts = append(opts, database.ApplyFilter(expr))
+	}
+
+	events, pageToken, err := fs.FileStore.ListAuditEvents(ctx, opts...)
+	if err != nil {
+		return nil, listError(err, "failed to list audit events")
+	}
+
+	responseEvents := make([]*dataservicev1.AuditEvent, 0, len(events))
+
+	for i := range events {
+		event, err := modelsv2.AuditEventToProto(&events[i])
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert audit event %s=%d %s=%s", "id", events[i].ID, "err", err)
+		}
+
+		responseEvents = append(responseEvents, event)
+	}
+
+	return &fileserver.ListAuditEventsResponse{
+		Events:        responseEvents,
+		NextPageToken: pageToken,
+		PageSize:      int32(pageSize),
+	}, nil
+}
+
+func (fs *FileServer) VerifyAuditLog(ctx context.Context, _ *fileserver.VerifyAuditLogRequest) (*fileserver.VerifyAuditLogResponse, error) {
+	verified, firstInvalid, err := fs.FileStore.VerifyAuditLog(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to verify audit log %s=%s", "err", err)
+	}
+
+	res := &fileserver.VerifyAuditLogResponse{
+		EventsVerified: uint64(verified),
+		Valid:          firstInvalid == 0,
+	}
+
+	if firstInvalid != 0 {
+		fs.Log.Info("audit log chain is broken", "id", firstInvalid)
+		res.FirstInvalidId = fmt.Sprintf("%d", firstInvalid)
+	}
+
+	return res, nil
+}
+
+func setAuditFileID(event *modelsv2.AuditEvent, id string) {
+	event.FileID, _ = modelsv2.ConvertStrToUint(id)
+}
+
+// auditDownload records a download of file, including partial ones
+func (fs *FileServer) auditDownload(ctx context.Context, file *modelsv2.StoredFile, bytesSent int64) error {
+	event := newAuditEvent(ctx, "DownloadFile")
+	setAuditFile(event, file)
+	setAuditDetails(event, map[string]string{"bytesSent": strconv.FormatInt(bytesSent, 10)})
+	return fs.audit(event)
+}
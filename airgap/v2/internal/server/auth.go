@@ -0,0 +1,314 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Role is the access granted to a caller, each role includes the ones before it.
+type Role int
+
+const (
+	RoleNone Role = iota
+	// RoleReader lists and downloads files
+	RoleReader
+	// RoleUploader uploads, updates and soft deletes files
+	RoleUploader
+	// RoleAdmin permanently deletes files and cleans tombstones
+	RoleAdmin
+)
+
+const (
+	ErrUnauthenticated = errors.Sentinel("caller is not authenticated")
+	ErrUnknownRole     = errors.Sentinel("unknown role")
+)
+
+var roleNames = map[Role]string{
+	RoleNone:     "none",
+	RoleReader:   "reader",
+	RoleUploader: "uploader",
+	RoleAdmin:    "admin",
+}
+
+func (r Role) String() string {
+	return roleNames[r]
+}
+
+func ParseRole(name string) (Role, error) {
+	for role, roleName := range roleNames {
+		if roleName == strings.ToLower(name) {
+			return role, nil
+		}
+	}
+	return RoleNone, errors.WithDetails(ErrUnknownRole, "role", name)
+}
+
+// UserInfo identifies the owner of a token.
+type UserInfo struct {
+	Username string
+	Groups   []string
+}
+
+// TokenValidator returns the user a bearer token belongs to, or
+// ErrUnauthenticated if the token is not valid.
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (*UserInfo, error)
+}
+
+// groupPrefix marks a group in the subjects of NewRoleBindings
+const groupPrefix = "group:"
+
+// RoleBindings grants roles to users and groups. A caller has the highest
+// role granted to its username or one of its groups.
+type RoleBindings struct {
+	Users  map[string]Role
+	Groups map[string]Role
+}
+
+// NewRoleBindings grants each role to a list of subjects, a subject is a
+// username such as system:serviceaccount:<namespace>:<name>, or a group
+// prefixed with group:
+func NewRoleBindings(subjects map[Role][]string) RoleBindings {
+	bindings := RoleBindings{Users: map[string]Role{}, Groups: map[string]Role{}}
+
+	for role, names := range subjects {
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+
+			bindings.grant(role, name)
+		}
+	}
+
+	return bindings
+}
+
+func (b RoleBindings) grant(role Role, subject string) {
+	bound, key := b.Users, subject
+	if strings.HasPrefix(subject, groupPrefix) {
+		bound, key = b.Groups, strings.TrimPrefix(subject, groupPrefix)
+	}
+
+	if role > bound[key] {
+		bound[key] = role
+	}
+}
+
+func (b RoleBindings) RoleFor(user *UserInfo) Role {
+	role := b.Users[user.Username]
+
+	for _, group := range user.Groups {
+		if groupRole := b.Groups[group]; groupRole > role {
+			role = groupRole
+		}
+	}
+
+	return role
+}
+
+// Caller is an authenticated caller of the API.
+type Caller struct {
+	UserInfo
+	Role Role
+}
+
+type callerKey struct{}
+
+// callerFromContext returns the caller authenticated for a request, nil if
+// authentication is disabled.
+func callerFromContext(ctx context.Context) *Caller {
+	caller, _ := ctx.Value(callerKey{}).(*Caller)
+	return caller
+}
+
+// publicMethods are served without authentication, for health probes
+var publicMethods = map[string]bool{
+	"/grpc.health.v1.Health/Check": true,
+	"/grpc.health.v1.Health/Watch": true,
+}
+
+// requiredRole returns the role needed to call a method, req is nil for
+// streams. Methods that are not listed are restricted to admins.
+func requiredRole(method string, req interface{}) Role {
+	switch method {
+	case fileserver.FileServer_ListFiles_FullMethodName,
+		fileserver.FileServer_GetFile_FullMethodName,
+		fileserver.FileServer_ListFileVersions_FullMethodName,
+		fileserver.FileServer_DownloadFile_FullMethodName,
+		fileserver.FileServer_WatchFiles_FullMethodName,
+		fileserver.FileServer_BatchGet_FullMethodName,
+		fileserver.FileServer_ExportFiles_FullMethodName:
+		return RoleReader
+	case fileserver.FileServer_UploadFile_FullMethodName,
+		fileserver.FileServer_StartUpload_FullMethodName,
+		fileserver.FileServer_PutChunk_FullMethodName,
+		fileserver.FileServer_GetUploadStatus_FullMethodName,
+		fileserver.FileServer_FinalizeUpload_FullMethodName,
+		fileserver.FileServer_AbortUpload_FullMethodName,
+		fileserver.FileServer_UpdateFileMetadata_FullMethodName,
+		fileserver.FileServer_BatchUpdateMetadata_FullMethodName,
+		fileserver.FileServer_ImportAcknowledgement_FullMethodName:
+		return RoleUploader
+	case fileserver.FileServer_DeleteFile_FullMethodName:
+		if deleteReq, ok := req.(*fileserver.DeleteFileRequest); ok && !deleteReq.GetPermanent() {
+			return RoleUploader
+		}
+		return RoleAdmin
+	case fileserver.FileServer_BatchDelete_FullMethodName:
+		if deleteReq, ok := req.(*fileserver.BatchDeleteRequest); ok && !deleteReq.GetPermanent() {
+			return RoleUploader
+		}
+		return RoleAdmin
+	}
+
+	return RoleAdmin
+}
+
+// authorizer authenticates callers by their bearer token and checks they
+// have the role required by the method called.
+type authorizer struct {
+	log       logr.Logger
+	validator TokenValidator
+	bindings  RoleBindings
+}
+
+func (a *authorizer) authenticate(ctx context.Context, token string) (*Caller, error) {
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "bearer token is missing")
+	}
+
+	user, err := a.validator.ValidateToken(ctx, token)
+	if err != nil {
+		a.log.V(2).Info("token validation failed", "err", err.Error())
+
+		if errors.Is(err, ErrUnauthenticated) {
+			return nil, status.Error(codes.Unauthenticated, ErrUnauthenticated.Error())
+		}
+		return nil, status.Errorf(codes.Unavailable, "failed to validate token: %v", err)
+	}
+
+	return &Caller{UserInfo: *user, Role: a.bindings.RoleFor(user)}, nil
+}
+
+func (a *authorizer) authorize(ctx context.Context, method string, req interface{}) (context.Context, error) {
+	if publicMethods[method] {
+		return ctx, nil
+	}
+
+	caller, err := a.authenticate(ctx, tokenFromMetadata(ctx))
+	if err != nil {
+		return ctx, err
+	}
+
+	if required := requiredRole(method, req); caller.Role < required {
+		a.log.Info("permission denied", "user", caller.Username, "role", caller.Role.String(), "method", method, "required", required.String())
+		return ctx, status.Errorf(codes.PermissionDenied, "%s role required", required)
+	}
+
+	return context.WithValue(ctx, callerKey{}, caller), nil
+}
+
+func (a *authorizer) UnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	ctx, err := a.authorize(ctx, info.FullMethod, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+func (a *authorizer) StreamInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, err := a.authorize(stream.Context(), info.FullMethod, nil)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+}
+
+// HandlerFunc checks the caller of an http route has the role required,
+// for routes served by the gateway outside of the gRPC API.
+func (a *authorizer) HandlerFunc(required Role, handler runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		caller, err := a.authenticate(r.Context(), bearerToken(r.Header.Get("Authorization")))
+		if err != nil {
+			code := http.StatusUnauthorized
+			if status.Code(err) == codes.Unavailable {
+				code = http.StatusServiceUnavailable
+			}
+			http.Error(w, status.Convert(err).Message(), code)
+			return
+		}
+
+		if caller.Role < required {
+			http.Error(w, required.String()+" role required", http.StatusForbidden)
+			return
+		}
+
+		handler(w, r.WithContext(context.WithValue(r.Context(), callerKey{}, caller)), pathParams)
+	}
+}
+
+// contextStream replaces the context of a stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func tokenFromMetadata(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if token := bearerToken(value); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+func bearerToken(header string) string {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
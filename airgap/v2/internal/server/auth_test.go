@@ -0,0 +1,264 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	dataservicev1 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("auth", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		srv    *testServer
+		store  database.StoredFileStore
+		client fileserver.FileServerClient
+		fileID string
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+		DeferCleanup(cancel)
+
+		srv = newTestServer(testServerOptions{Auth: true})
+		store, client = srv.Store, srv.Client
+
+		var err error
+		fileID, err = store.Save(ctx, &modelsv2.StoredFile{
+			Name:       "auth.txt",
+			Source:     "redhat-marketplace",
+			SourceType: "report",
+			File:       modelsv2.StoredFileContent{Content: []byte("auth")},
+		})
+		Expect(err).To(Succeed())
+	})
+
+	as := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+
+	list := func(ctx context.Context) error {
+		_, err := client.ListFiles(ctx, &fileserver.ListFilesRequest{})
+		return err
+	}
+
+	deleteFile := func(ctx context.Context, permanent bool) error {
+		_, err := client.DeleteFile(ctx, &fileserver.DeleteFileRequest{Id: fileID, Permanent: permanent})
+		return err
+	}
+
+	It("should reject callers without a valid token", func() {
+		Expect(status.Code(list(ctx))).To(Equal(codes.Unauthenticated))
+		Expect(status.Code(list(as("unknown-token")))).To(Equal(codes.Unauthenticated))
+		Expect(status.Code(list(as("nobody-token")))).To(Equal(codes.PermissionDenied))
+	})
+
+	It("should serve health checks without a token", func() {
+		_, err := grpc_health_v1.NewHealthClient(srv.Conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		Expect(err).To(Succeed())
+	})
+
+	It("should let readers read", func() {
+		reader := as("reader-token")
+		Expect(list(reader)).To(Succeed())
+
+		stream, err := client.DownloadFile(reader, &fileserver.DownloadFileRequest{Id: fileID})
+		Expect(err).To(Succeed())
+		_, err = stream.Recv()
+		Expect(err).To(Succeed())
+
+		Expect(status.Code(deleteFile(reader, false))).To(Equal(codes.PermissionDenied))
+
+		upload, err := client.UploadFile(reader)
+		Expect(err).To(Succeed())
+		_, err = upload.CloseAndRecv()
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	})
+
+	It("should let uploaders upload and soft delete", func() {
+		uploader := as("uploader-token")
+		Expect(list(uploader)).To(Succeed())
+
+		_, err := client.StartUpload(uploader, &fileserver.StartUploadRequest{
+			Info: &dataservicev1.FileInfo{Name: "upload.txt", Source: "redhat-marketplace", SourceType: "report"},
+		})
+		Expect(err).To(Succeed())
+
+		Expect(status.Code(deleteFile(uploader, true))).To(Equal(codes.PermissionDenied))
+		Expect(deleteFile(uploader, false)).To(Succeed())
+
+		_, err = client.CleanTombstones(uploader, &fileserver.CleanTombstonesRequest{})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	})
+
+	It("should let admins permanently delete and clean tombstones", func() {
+		admin := as("admin-token")
+		Expect(deleteFile(admin, true)).To(Succeed())
+
+		_, err := client.CleanTombstones(admin, &fileserver.CleanTombstonesRequest{})
+		Expect(err).To(Succeed())
+
+		_, err = client.ListAuditEvents(as("uploader-token"), &fileserver.ListAuditEventsRequest{})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+
+		res, err := client.ListAuditEvents(admin, &fileserver.ListAuditEventsRequest{})
+		Expect(err).To(Succeed())
+		Expect(res.Events).To(HaveLen(2))
+		Expect(res.Events[1].Method).To(Equal("DeleteFile"))
+		Expect(res.Events[1].Username).To(Equal("jane"))
+		Expect(res.Events[1].Groups).To(ConsistOf("system:authenticated", "rhm-admins"))
+	})
+
+	It("should authorize the http download", func() {
+		download := func(token string) int {
+			req, err := http.NewRequest(http.MethodPost, srv.URL+"/v1/file/"+fileID+"/download", nil)
+			Expect(err).To(Succeed())
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).To(Succeed())
+			resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		Expect(download("")).To(Equal(http.StatusUnauthorized))
+		Expect(download("nobody-token")).To(Equal(http.StatusForbidden))
+		Expect(download("reader-token")).To(Equal(http.StatusOK))
+	})
+
+	It("should authorize the http upload", func() {
+		upload := func(token string) int {
+			body := &strings.Builder{}
+			mw := multipart.NewWriter(body)
+			Expect(mw.WriteField("source", "redhat-marketplace")).To(Succeed())
+			Expect(mw.WriteField("sourceType", "report")).To(Succeed())
+			part, err := mw.CreateFormFile("file", "upload.txt")
+			Expect(err).To(Succeed())
+			_, err = part.Write([]byte("upload"))
+			Expect(err).To(Succeed())
+			Expect(mw.Close()).To(Succeed())
+
+			req, err := http.NewRequest(http.MethodPost, srv.URL+"/v1/files", strings.NewReader(body.String()))
+			Expect(err).To(Succeed())
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+			sum := sha256.Sum256([]byte("upload"))
+			req.Header.Set("Digest", "sha-256="+base64.StdEncoding.EncodeToString(sum[:]))
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).To(Succeed())
+			resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		Expect(upload("reader-token")).To(Equal(http.StatusForbidden))
+		Expect(upload("uploader-token")).To(Equal(http.StatusOK))
+
+		events, _, err := store.ListAuditEvents(ctx)
+		Expect(err).To(Succeed())
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Username).To(Equal("system:serviceaccount:rhm:reporter"))
+	})
+
+	It("should parse roles", func() {
+		role, err := ParseRole("Uploader")
+		Expect(err).To(Succeed())
+		Expect(role).To(Equal(RoleUploader))
+
+		_, err = ParseRole("owner")
+		Expect(err).To(MatchError(ErrUnknownRole))
+	})
+})
+
+var _ = Describe("TokenReviewValidator", func() {
+	var (
+		clientset *fake.Clientset
+		reviews   int
+		sut       *TokenReviewValidator
+		ctx       = context.Background()
+	)
+
+	BeforeEach(func() {
+		reviews = 0
+		clientset = fake.NewSimpleClientset()
+		clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+			reviews++
+			review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+			Expect(review.Spec.Audiences).To(ConsistOf("rhm-data-service"))
+
+			if strings.HasPrefix(review.Spec.Token, "valid") {
+				review.Status = authenticationv1.TokenReviewStatus{
+					Authenticated: true,
+					User: authenticationv1.UserInfo{
+						Username: "system:serviceaccount:rhm:reporter",
+						Groups:   []string{"system:serviceaccounts"},
+					},
+				}
+			} else {
+				review.Status = authenticationv1.TokenReviewStatus{Error: "invalid token"}
+			}
+			return true, review, nil
+		})
+
+		sut = &TokenReviewValidator{
+			Client:    clientset.AuthenticationV1().TokenReviews(),
+			Audiences: []string{"rhm-data-service"},
+			CacheTTL:  time.Minute,
+		}
+	})
+
+	It("should return the user of a valid token and cache the review", func() {
+		user, err := sut.ValidateToken(ctx, "valid-token")
+		Expect(err).To(Succeed())
+		Expect(user.Username).To(Equal("system:serviceaccount:rhm:reporter"))
+		Expect(user.Groups).To(ConsistOf("system:serviceaccounts"))
+
+		_, err = sut.ValidateToken(ctx, "valid-token")
+		Expect(err).To(Succeed())
+		Expect(reviews).To(Equal(1))
+	})
+
+	It("should reject an invalid token", func() {
+		_, err := sut.ValidateToken(ctx, "expired-token")
+		Expect(err).To(MatchError(ErrUnauthenticated))
+
+		_, err = sut.ValidateToken(ctx, "expired-token")
+		Expect(err).To(MatchError(ErrUnauthenticated))
+		Expect(reviews).To(Equal(2))
+	})
+})
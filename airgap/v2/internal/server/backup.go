@@ -0,0 +1,243 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// chunkWriter sends the archive written to it as chunks of a stream
+type chunkWriter struct {
+	send      func(chunk []byte) error
+	chunkSize int
+	buf       []byte
+	progress  *progress
+}
+
+func newChunkWriter(send func(chunk []byte) error, chunkSize int, progress *progress) *chunkWriter {
+	return &chunkWriter{
+		send:      send,
+		chunkSize: chunkSize,
+		buf:       make([]byte, 0, chunkSize),
+		progress:  progress,
+	}
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		n := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+
+		if len(w.buf) == cap(w.buf) {
+			if err := w.Flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (w *chunkWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	if err := w.send(w.buf); err != nil {
+		return err
+	}
+
+	w.progress.Add(len(w.buf))
+	w.buf = make([]byte, 0, w.chunkSize)
+	return nil
+}
+
+func (fs *FileServer) BackupDatabase(_ *fileserver.BackupDatabaseRequest, stream fileserver.FileServer_BackupDatabaseServer) error {
+	ctx := stream.Context()
+	h := sha256.New()
+	w := newChunkWriter(func(chunk []byte) error {
+		return stream.Send(&fileserver.BackupDatabaseResponse{
+			Data: &fileserver.BackupDatabaseResponse_ChunkData{ChunkData: chunk},
+		})
+	}, fs.chunkSize(), newProgress(fs.Log, "backup progress"))
+
+	manifest, err := fs.FileStore.Backup(ctx, io.MultiWriter(w, h))
+	if err == nil {
+		err = w.Flush()
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to back up the database %s=%s", "err", err)
+	}
+
+	checksum := fmt.Sprintf("%x", h.Sum(nil))
+	fs.Log.Info("backup complete", "files", manifest.Files, "bytes", w.progress.Total(), "checksum", checksum, "missingBlobs", len(manifest.MissingBlobs))
+
+	event := newAuditEvent(ctx, "BackupDatabase")
+	setAuditDetails(event, map[string]string{"files": strconv.FormatInt(manifest.Files, 10), "checksum": checksum})
+	if err := fs.audit(event); err != nil {
+		return err
+	}
+
+	return stream.Send(&fileserver.BackupDatabaseResponse{
+		Data: &fileserver.BackupDatabaseResponse_Result{Result: &fileserver.BackupDatabaseResult{
+			Manifest: backupManifestToProto(manifest),
+			Checksum: checksum,
+		}},
+	})
+}
+
+// restoreReader reads the archive chunks of the stream
+type restoreReader struct {
+	stream   fileserver.FileServer_RestoreDatabaseServer
+	checksum string
+	buf      []byte
+}
+
+func (r *restoreReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+
+		if checksum := req.GetChecksum(); checksum != "" {
+			r.checksum = checksum
+		}
+
+		r.buf = req.GetChunkData()
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (fs *FileServer) RestoreDatabase(stream fileserver.FileServer_RestoreDatabaseServer) error {
+	ctx := stream.Context()
+
+	// the archive is spooled so its checksum is verified before restoring
+	spool, err := os.CreateTemp("", "restore-*.tar.gz")
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to create restore file %s=%s", "err", err)
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	h := sha256.New()
+	r := &restoreReader{stream: stream}
+
+	size, err := io.Copy(io.MultiWriter(spool, h), r)
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	if err != nil {
+		return status.Errorf(codes.Unknown, "failed to receive the archive %s=%s", "err", err)
+	}
+
+	checksum := fmt.Sprintf("%x", h.Sum(nil))
+	if r.checksum != "" && r.checksum != checksum {
+		return status.Errorf(codes.DataLoss, "archive checksum %s does not match %s", checksum, r.checksum)
+	}
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return status.Errorf(codes.Internal, "failed to read restore file %s=%s", "err", err)
+	}
+
+	manifest, err := fs.FileStore.Restore(ctx, spool)
+	switch {
+	case errors.Is(err, database.ErrRestoreNotEmpty):
+		return status.Errorf(codes.FailedPrecondition, "%s", err)
+	case errors.Is(err, database.ErrInvalidBackup):
+		return status.Errorf(codes.InvalidArgument, "%s", err)
+	case err != nil:
+		return status.Errorf(codes.Internal, "failed to restore the database %s=%s", "err", err)
+	}
+
+	fs.Log.Info("restore complete", "files", manifest.Files, "bytes", size, "checksum", checksum)
+
+	event := newAuditEvent(ctx, "RestoreDatabase")
+	setAuditDetails(event, map[string]string{"files": strconv.FormatInt(manifest.Files, 10), "checksum": checksum})
+	if err := fs.audit(event); err != nil {
+		return err
+	}
+
+	verification, err := fs.VerifyDatabase(ctx, &fileserver.VerifyDatabaseRequest{})
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(&fileserver.RestoreDatabaseResponse{
+		Manifest:     backupManifestToProto(manifest),
+		Verification: verification,
+	})
+}
+
+func (fs *FileServer) VerifyDatabase(ctx context.Context, _ *fileserver.VerifyDatabaseRequest) (*fileserver.VerifyDatabaseResponse, error) {
+	report, err := fs.FileStore.VerifyIntegrity(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to verify the database %s=%s", "err", err)
+	}
+
+	res := &fileserver.VerifyDatabaseResponse{
+		Valid:               report.Valid(),
+		FilesVerified:       uint64(report.FilesVerified),
+		AuditEventsVerified: uint64(report.AuditEventsVerified),
+	}
+
+	for _, id := range report.InvalidFiles {
+		res.InvalidFileIds = append(res.InvalidFileIds, fmt.Sprintf("%d", id))
+	}
+
+	if report.FirstInvalidAuditEvent != 0 {
+		res.FirstInvalidAuditEventId = fmt.Sprintf("%d", report.FirstInvalidAuditEvent)
+	}
+
+	if !res.Valid {
+		fs.Log.Info("database integrity check failed", "invalidFiles", res.InvalidFileIds, "firstInvalidAuditEvent", res.FirstInvalidAuditEventId)
+	}
+
+	return res, nil
+}
+
+func backupManifestToProto(manifest *database.BackupManifest) *fileserver.BackupManifest {
+	return &fileserver.BackupManifest{
+		FormatVersion: uint32(manifest.FormatVersion),
+		CreatedAt:     timestamppb.New(manifest.CreatedAt),
+		Files:         uint64(manifest.Files),
+		Tombstones:    uint64(manifest.Tombstones),
+		Versions:      uint64(manifest.Versions),
+		AuditEvents:   uint64(manifest.AuditEvents),
+		Blobs:         uint64(manifest.Blobs),
+		Entries:       manifest.Entries,
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Describe("backup", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+
+	// serve starts a file server on an empty store
+	serve := func() (database.StoredFileStore, fileserver.FileServerClient) {
+		srv := newTestServer(testServerOptions{Server: func(s *Server) { s.ChunkSize = 100 }})
+		return srv.Store, srv.Client
+	}
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+		DeferCleanup(cancel)
+	})
+
+	backup := func(client fileserver.FileServerClient) ([]byte, *fileserver.BackupDatabaseResult) {
+		stream, err := client.BackupDatabase(ctx, &fileserver.BackupDatabaseRequest{})
+		Expect(err).To(Succeed())
+
+		var archive bytes.Buffer
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).To(Succeed())
+			Expect(len(res.GetChunkData())).To(BeNumerically("<=", 100))
+
+			archive.Write(res.GetChunkData())
+			if result := res.GetResult(); result != nil {
+				return archive.Bytes(), result
+			}
+		}
+
+		Fail("backup ended without a result")
+		return nil, nil
+	}
+
+	restore := func(client fileserver.FileServerClient, archive []byte, checksum string) (*fileserver.RestoreDatabaseResponse, error) {
+		stream, err := client.RestoreDatabase(ctx)
+		Expect(err).To(Succeed())
+
+		Expect(stream.Send(&fileserver.RestoreDatabaseRequest{
+			Data: &fileserver.RestoreDatabaseRequest_Checksum{Checksum: checksum},
+		})).To(Succeed())
+
+		for len(archive) > 0 {
+			n := 1000
+			if n > len(archive) {
+				n = len(archive)
+			}
+			Expect(stream.Send(&fileserver.RestoreDatabaseRequest{
+				Data: &fileserver.RestoreDatabaseRequest_ChunkData{ChunkData: archive[:n]},
+			})).To(Succeed())
+			archive = archive[n:]
+		}
+
+		return stream.CloseAndRecv()
+	}
+
+	It("should back up and restore into an empty data service", func() {
+		store, client := serve()
+		id, err := store.Save(ctx, &modelsv2.StoredFile{
+			Name:       "backup.txt",
+			Source:     "redhat-marketplace",
+			SourceType: "report",
+			File:       modelsv2.StoredFileContent{Content: []byte("backup")},
+		})
+		Expect(err).To(Succeed())
+
+		archive, result := backup(client)
+		Expect(result.Checksum).To(Equal(fmt.Sprintf("%x", sha256.Sum256(archive))))
+		Expect(result.Manifest.Files).To(Equal(uint64(1)))
+
+		_, err = restore(client, archive, result.Checksum)
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+
+		restored, emptyClient := serve()
+		_, err = restore(emptyClient, archive, "bad checksum")
+		Expect(status.Code(err)).To(Equal(codes.DataLoss))
+
+		res, err := restore(emptyClient, archive, result.Checksum)
+		Expect(err).To(Succeed())
+		Expect(res.Manifest.Files).To(Equal(uint64(1)))
+		Expect(res.Verification.Valid).To(BeTrue())
+		Expect(res.Verification.FilesVerified).To(Equal(uint64(1)))
+
+		file, err := restored.Download(ctx, id)
+		Expect(err).To(Succeed())
+		Expect(string(file.File.Content)).To(Equal("backup"))
+
+		events, _, err := restored.ListAuditEvents(ctx)
+		Expect(err).To(Succeed())
+		// the backup is recorded once the archive is complete, so it is not in it
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Method).To(Equal("RestoreDatabase"))
+
+		verify, err := emptyClient.VerifyDatabase(ctx, &fileserver.VerifyDatabaseRequest{})
+		Expect(err).To(Succeed())
+		Expect(verify.Valid).To(BeTrue())
+		Expect(verify.AuditEventsVerified).To(Equal(uint64(1)))
+	})
+})
@@ -0,0 +1,270 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBatchSize bounds the files a batch operation applies to
+const maxBatchSize = 1000
+
+// errBatchFailed rolls back a batch failing for a file
+const errBatchFailed = errors.Sentinel("batch failed")
+
+func (fs *FileServer) BatchGet(ctx context.Context, req *fileserver.BatchGetRequest) (*fileserver.BatchGetResponse, error) {
+	ids, err := fs.batchFiles(ctx, req.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*fileserver.BatchResult, 0, len(ids))
+	for _, id := range ids {
+		file, err := getBatchFile(ctx, fs.FileStore, id)
+		results = append(results, batchResult(id, file, err))
+	}
+
+	return &fileserver.BatchGetResponse{Results: results}, nil
+}
+
+func (fs *FileServer) BatchUpdateMetadata(ctx context.Context, req *fileserver.BatchUpdateMetadataRequest) (*fileserver.BatchUpdateMetadataResponse, error) {
+	if len(req.Metadata) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "metadata is required")
+	}
+
+	ids, err := fs.batchFiles(ctx, req.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*fileserver.BatchResult
+
+	err = fs.FileStore.Batch(ctx, req.DryRun, func(store database.StoredFileStore) error {
+		results = nil
+
+		for _, id := range ids {
+			file, err := getBatchFile(ctx, store, id)
+			if err == nil {
+				event := newAuditEvent(ctx, "BatchUpdateMetadata")
+				mergeMetadata(file, req.Metadata)
+				setAuditFile(event, file)
+				_, err = store.Save(database.WithAuditEvent(ctx, event), file)
+			}
+
+			results = append(results, batchResult(id, file, err))
+		}
+
+		return batchOutcome(results)
+	})
+
+	committed, err := fs.endBatch(results, req.DryRun, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileserver.BatchUpdateMetadataResponse{Results: results, Committed: committed}, nil
+}
+
+func (fs *FileServer) BatchDelete(ctx context.Context, req *fileserver.BatchDeleteRequest) (*fileserver.BatchDeleteResponse, error) {
+	ids, err := fs.batchFiles(ctx, req.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*fileserver.BatchResult
+
+	err = fs.FileStore.Batch(ctx, req.DryRun, func(store database.StoredFileStore) error {
+		results = nil
+
+		for _, id := range ids {
+			file, err := getBatchFile(ctx, store, id)
+			if err == nil {
+				event := newAuditEvent(ctx, "BatchDelete")
+				setAuditFile(event, file)
+				if req.Permanent {
+					event.ChecksumAfter = ""
+				}
+				setAuditDetails(event, map[string]string{"permanent": strconv.FormatBool(req.Permanent)})
+				err = store.Delete(database.WithAuditEvent(ctx, event), id, req.Permanent)
+			}
+
+			// the tombstone, a file removed for good is returned as it was
+			if err == nil && !req.Permanent {
+				file, err = getBatchFile(ctx, store, id)
+			}
+
+			results = append(results, batchResult(id, file, err))
+		}
+
+		return batchOutcome(results)
+	})
+
+	committed, err := fs.endBatch(results, req.DryRun, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileserver.BatchDeleteResponse{Results: results, Committed: committed}, nil
+}
+
+// batchFiles returns the ids of the files selected, without duplicates
+func (fs *FileServer) batchFiles(ctx context.Context, selector *fileserver.BatchSelector) ([]string, error) {
+	switch {
+	case selector == nil || (len(selector.Ids) == 0 && selector.Filter == ""):
+		return nil, status.Error(codes.InvalidArgument, "ids or a filter are required")
+	case len(selector.Ids) != 0 && selector.Filter != "":
+		return nil, status.Error(codes.InvalidArgument, "ids and filter can not be used together")
+	case len(selector.Ids) > maxBatchSize:
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d files can be selected", maxBatchSize)
+	}
+
+	if len(selector.Ids) != 0 {
+		ids := make([]string, 0, len(selector.Ids))
+		seen := map[string]bool{}
+		for _, id := range selector.Ids {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	expr, err := fileserver.ParseFilter(selector.Filter)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Filter is formatted incorrectly. Error: %s", err)
+	}
+
+	opts := []database.ListOption{database.ApplyFilter(expr)}
+	if selector.IncludeDeleted {
+		opts = append(opts, database.ShowDeleted())
+	}
+
+	ids := []string{}
+	pageToken := ""
+	for {
+		files, next, err := fs.FileStore.List(ctx, append(opts, database.Paginate(pageToken, 100))...)
+		if err != nil {
+			return nil, listError(err, "failed to list files")
+		}
+
+		for i := range files {
+			ids = append(ids, strconv.FormatUint(uint64(files[i].ID), 10))
+		}
+
+		if len(ids) > maxBatchSize {
+			return nil, status.Errorf(codes.FailedPrecondition, "the filter selects more than %d files", maxBatchSize)
+		}
+
+		if next == "" {
+			return ids, nil
+		}
+		pageToken = next
+	}
+}
+
+// getBatchFile returns the file with id, or a NotFound status
+func getBatchFile(ctx context.Context, store database.StoredFileStore, id string) (*modelsv2.StoredFile, error) {
+	if _, err := modelsv2.ConvertStrToUint(id); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid id %q", id)
+	}
+
+	file, err := store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.ID == 0 {
+		return nil, status.Errorf(codes.NotFound, "file %s not found", id)
+	}
+
+	return file, nil
+}
+
+// batchResult returns the result for file, or for the error of the operation
+func batchResult(id string, file *modelsv2.StoredFile, err error) *fileserver.BatchResult {
+	res := &fileserver.BatchResult{Id: id}
+
+	if err == nil {
+		res.File, err = modelsv2.StoredFileToProto(file)
+	}
+
+	if err != nil {
+		st := status.Convert(batchError(err))
+		res.Code = int32(st.Code())
+		res.Message = st.Message()
+		res.File = nil
+	}
+
+	return res
+}
+
+// batchError keeps the status of the errors for a file and maps the others
+func batchError(err error) error {
+	if quotaErr := (&database.QuotaExceededError{}); errors.As(err, &quotaErr) {
+		return quotaError(quotaErr)
+	}
+
+	var statusErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &statusErr) {
+		return statusErr.GRPCStatus().Err()
+	}
+
+	if errors.Is(err, database.ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+// batchOutcome rolls the batch back when it failed for a file
+func batchOutcome(results []*fileserver.BatchResult) error {
+	for _, res := range results {
+		if res.Code != int32(codes.OK) {
+			return errBatchFailed
+		}
+	}
+	return nil
+}
+
+// endBatch returns whether the batch was committed. The results of the files
+// rolled back because the batch failed for another file are aborted.
+func (fs *FileServer) endBatch(results []*fileserver.BatchResult, dryRun bool, err error) (bool, error) {
+	if errors.Is(err, errBatchFailed) {
+		for _, res := range results {
+			if res.Code == int32(codes.OK) {
+				res.Code = int32(codes.Aborted)
+				res.Message = "rolled back, the batch failed for another file"
+				res.File = nil
+			}
+		}
+		return false, nil
+	}
+
+	if err != nil {
+		fs.Log.Error(err, "batch failed")
+		return false, status.Errorf(codes.Internal, "batch failed: %s", err)
+	}
+
+	return !dryRun, nil
+}
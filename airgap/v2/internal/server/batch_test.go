@@ -0,0 +1,216 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Describe("batch", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		store  database.StoredFileStore
+		client fileserver.FileServerClient
+
+		reportID, eventsID string
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+		DeferCleanup(func() { cancel() })
+
+		srv := newTestServer(testServerOptions{})
+		store, client = srv.Store, srv.Client
+
+		save := func(name, sourceType string) string {
+			id, err := store.Save(ctx, &modelsv2.StoredFile{
+				Name:       name,
+				Source:     "redhat-marketplace",
+				SourceType: sourceType,
+				File:       modelsv2.StoredFileContent{Content: []byte(name)},
+				Metadata:   []modelsv2.StoredFileMetadata{{Key: "cluster", Value: "a"}},
+			})
+			Expect(err).To(Succeed())
+			return id
+		}
+
+		reportID = save("report.tar.gz", "report")
+		eventsID = save("events.json", "eventjson")
+	})
+
+	codesOf := func(results []*fileserver.BatchResult) []codes.Code {
+		var all []codes.Code
+		for _, res := range results {
+			all = append(all, codes.Code(res.Code))
+		}
+		return all
+	}
+
+	metadata := func(id string) map[string]string {
+		res, err := client.GetFile(ctx, &fileserver.GetFileRequest{IdLookup: &fileserver.GetFileRequest_Id{Id: id}})
+		Expect(err).To(Succeed())
+		return res.Info.Metadata
+	}
+
+	It("should get files by id with a result for each", func() {
+		res, err := client.BatchGet(ctx, &fileserver.BatchGetRequest{
+			Selector: &fileserver.BatchSelector{Ids: []string{reportID, "1000", eventsID, reportID}},
+		})
+		Expect(err).To(Succeed())
+		Expect(codesOf(res.Results)).To(Equal([]codes.Code{codes.OK, codes.NotFound, codes.OK}))
+		Expect(res.Results[0].File.Name).To(Equal("report.tar.gz"))
+		Expect(res.Results[1].File).To(BeNil())
+		Expect(res.Results[2].File.Name).To(Equal("events.json"))
+	})
+
+	It("should update the metadata of the files matching a filter", func() {
+		res, err := client.BatchUpdateMetadata(ctx, &fileserver.BatchUpdateMetadataRequest{
+			Selector: &fileserver.BatchSelector{Filter: `metadata.cluster == "a"`},
+			Metadata: map[string]string{"reviewed": "true"},
+		})
+		Expect(err).To(Succeed())
+		Expect(res.Committed).To(BeTrue())
+		Expect(codesOf(res.Results)).To(Equal([]codes.Code{codes.OK, codes.OK}))
+		Expect(res.Results[0].File.Metadata).To(HaveKeyWithValue("reviewed", "true"))
+
+		Expect(metadata(reportID)).To(Equal(map[string]string{"cluster": "a", "reviewed": "true"}))
+		Expect(metadata(eventsID)).To(Equal(map[string]string{"cluster": "a", "reviewed": "true"}))
+
+		events, _, err := store.ListAuditEvents(ctx)
+		Expect(err).To(Succeed())
+		Expect(events).To(HaveLen(2))
+		Expect(events[0].Method).To(Equal("BatchUpdateMetadata"))
+	})
+
+	It("should not update any file when it fails for one", func() {
+		res, err := client.BatchUpdateMetadata(ctx, &fileserver.BatchUpdateMetadataRequest{
+			Selector: &fileserver.BatchSelector{Ids: []string{reportID, "1000"}},
+			Metadata: map[string]string{"reviewed": "true"},
+		})
+		Expect(err).To(Succeed())
+		Expect(res.Committed).To(BeFalse())
+		Expect(codesOf(res.Results)).To(Equal([]codes.Code{codes.Aborted, codes.NotFound}))
+
+		Expect(metadata(reportID)).ToNot(HaveKey("reviewed"))
+	})
+
+	It("should only return the files as they would be on a dry run", func() {
+		res, err := client.BatchUpdateMetadata(ctx, &fileserver.BatchUpdateMetadataRequest{
+			Selector: &fileserver.BatchSelector{Ids: []string{reportID}},
+			Metadata: map[string]string{"cluster": "b"},
+			DryRun:   true,
+		})
+		Expect(err).To(Succeed())
+		Expect(res.Committed).To(BeFalse())
+		Expect(res.Results[0].File.Metadata).To(HaveKeyWithValue("cluster", "b"))
+		Expect(metadata(reportID)).To(HaveKeyWithValue("cluster", "a"))
+
+		deleted, err := client.BatchDelete(ctx, &fileserver.BatchDeleteRequest{
+			Selector:  &fileserver.BatchSelector{Filter: `sourceType == "report"`},
+			Permanent: true,
+			DryRun:    true,
+		})
+		Expect(err).To(Succeed())
+		Expect(deleted.Committed).To(BeFalse())
+		Expect(codesOf(deleted.Results)).To(Equal([]codes.Code{codes.OK}))
+
+		file, rc, err := store.Open(ctx, reportID)
+		Expect(err).To(Succeed())
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		Expect(err).To(Succeed())
+		Expect(string(content)).To(Equal(file.Name))
+	})
+
+	It("should delete the files selected", func() {
+		res, err := client.BatchDelete(ctx, &fileserver.BatchDeleteRequest{
+			Selector: &fileserver.BatchSelector{Ids: []string{reportID, eventsID}},
+		})
+		Expect(err).To(Succeed())
+		Expect(res.Committed).To(BeTrue())
+		Expect(res.Results[0].File.DeletedAt).ToNot(BeNil())
+
+		list, err := client.ListFiles(ctx, &fileserver.ListFilesRequest{})
+		Expect(err).To(Succeed())
+		Expect(list.Files).To(BeEmpty())
+
+		By("removing the tombstones for good")
+		res, err = client.BatchDelete(ctx, &fileserver.BatchDeleteRequest{
+			Selector:  &fileserver.BatchSelector{Filter: `name like "*"`, IncludeDeleted: true},
+			Permanent: true,
+		})
+		Expect(err).To(Succeed())
+		Expect(res.Committed).To(BeTrue())
+		Expect(res.Results).To(HaveLen(2))
+
+		list, err = client.ListFiles(ctx, &fileserver.ListFilesRequest{IncludeDeleted: true})
+		Expect(err).To(Succeed())
+		Expect(list.Files).To(BeEmpty())
+	})
+
+	It("should reject invalid selectors", func() {
+		for _, selector := range []*fileserver.BatchSelector{
+			nil,
+			{Ids: []string{reportID}, Filter: `name == "x"`},
+			{Filter: `name ==`},
+		} {
+			_, err := client.BatchGet(ctx, &fileserver.BatchGetRequest{Selector: selector})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument), "selector %v", selector)
+		}
+
+		_, err := client.BatchUpdateMetadata(ctx, &fileserver.BatchUpdateMetadataRequest{
+			Selector: &fileserver.BatchSelector{Ids: []string{reportID}},
+		})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+
+	It("should serve the batch routes on the gateway", func() {
+		mux := runtime.NewServeMux()
+		Expect(fileserver.RegisterFileServerHandlerClient(ctx, mux, client)).To(Succeed())
+		srv := httptest.NewServer(mux)
+		defer srv.Close()
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"selector": map[string]interface{}{"ids": []string{eventsID}},
+		})
+		res, err := http.Post(srv.URL+"/v1/files/batch/get", "application/json", bytes.NewReader(body))
+		Expect(err).To(Succeed())
+		defer res.Body.Close()
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+
+		got := &fileserver.BatchGetResponse{}
+		data, err := io.ReadAll(res.Body)
+		Expect(err).To(Succeed())
+		Expect((&runtime.JSONPb{}).Unmarshal(data, got)).To(Succeed())
+		Expect(got.Results).To(HaveLen(1))
+		Expect(got.Results[0].File.Name).To(Equal("events.json"))
+	})
+})
@@ -0,0 +1,244 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/bundle"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxExportFiles bounds the files of a bundle, their manifest is held in memory
+const maxExportFiles = 10000
+
+func (fs *FileServer) ExportFiles(req *fileserver.ExportFilesRequest, stream fileserver.FileServer_ExportFilesServer) error {
+	ctx := stream.Context()
+
+	if fs.BundleSigningKey == nil {
+		return status.Error(codes.FailedPrecondition, "files can not be exported without a bundle signing key")
+	}
+
+	files, err := fs.exportedFiles(ctx, req.Filter)
+	if err != nil {
+		return err
+	}
+
+	manifest := bundle.NewManifest(req.Filter, files)
+
+	h := sha256.New()
+	w := newChunkWriter(func(chunk []byte) error {
+		return stream.Send(&fileserver.ExportFilesResponse{
+			Data: &fileserver.ExportFilesResponse_ChunkData{ChunkData: chunk},
+		})
+	}, fs.chunkSize(), newProgress(fs.Log, "export progress"))
+
+	err = fs.writeBundle(ctx, io.MultiWriter(w, h), manifest)
+	if err == nil {
+		err = w.Flush()
+	}
+
+	var statusErr interface{ GRPCStatus() *status.Status }
+	switch {
+	case err == nil:
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.As(err, &statusErr):
+		return statusErr.GRPCStatus().Err()
+	default:
+		return status.Errorf(codes.Internal, "failed to export files %s=%s", "err", err)
+	}
+
+	checksum := fmt.Sprintf("%x", h.Sum(nil))
+	fs.Log.Info("export complete", "bundle", manifest.ID, "files", len(files), "bytes", w.progress.Total(), "checksum", checksum)
+
+	events := make([]*modelsv2.AuditEvent, 0, len(manifest.Files))
+	for i := range manifest.Files {
+		file := &manifest.Files[i]
+		event := newAuditEvent(ctx, "ExportFiles")
+		setAuditFileID(event, file.ID)
+		event.Name, event.Source, event.SourceType = file.Name, file.Source, file.SourceType
+		event.ChecksumBefore = file.Checksum
+		setAuditDetails(event, map[string]string{"bundleId": manifest.ID, "version": strconv.FormatUint(file.Version, 10)})
+		events = append(events, event)
+	}
+
+	// the files of a bundle are all recorded exported or none is
+	if err := fs.audit(events...); err != nil {
+		return err
+	}
+
+	return stream.Send(&fileserver.ExportFilesResponse{
+		Data: &fileserver.ExportFilesResponse_Result{Result: &fileserver.ExportFilesResult{
+			BundleId: manifest.ID,
+			Files:    int32(len(files)),
+			Checksum: checksum,
+		}},
+	})
+}
+
+// exportedFiles returns the files matching filter, oldest first
+func (fs *FileServer) exportedFiles(ctx context.Context, filter string) ([]bundle.File, error) {
+	opts := []database.ListOption{database.OrderBy("created_at asc")}
+
+	if filter != "" {
+		expr, err := fileserver.ParseFilter(filter)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Filter is formatted incorrectly. Error: %s", err)
+		}
+		opts = append(opts, database.ApplyFilter(expr))
+	}
+
+	files := []bundle.File{}
+	pageToken := ""
+	for {
+		page, next, err := fs.FileStore.List(ctx, append(opts, database.Paginate(pageToken, 100))...)
+		if err != nil {
+			return nil, listError(err, "failed to list files")
+		}
+
+		for i := range page {
+			files = append(files, bundleFile(&page[i]))
+		}
+
+		if len(files) > maxExportFiles {
+			return nil, status.Errorf(codes.FailedPrecondition, "the filter selects more than %d files", maxExportFiles)
+		}
+
+		if next == "" {
+			return files, nil
+		}
+		pageToken = next
+	}
+}
+
+func bundleFile(file *modelsv2.StoredFile) bundle.File {
+	metadata := map[string]string{}
+	for _, m := range file.Metadata {
+		metadata[m.Key] = m.Value
+	}
+
+	return bundle.File{
+		ID:         strconv.FormatUint(uint64(file.ID), 10),
+		Version:    file.Version,
+		Name:       file.Name,
+		Source:     file.Source,
+		SourceType: file.SourceType,
+		MimeType:   file.File.MimeType,
+		Metadata:   metadata,
+		Size:       int64(file.File.Size),
+		Checksum:   file.File.Checksum,
+	}
+}
+
+// writeBundle writes the bundle of the files of manifest to w, reading the
+// version of each file listed in case it changed since
+func (fs *FileServer) writeBundle(ctx context.Context, w io.Writer, manifest *bundle.Manifest) error {
+	bw, err := bundle.NewWriter(w, fs.BundleSigningKey, manifest)
+	if err != nil {
+		return err
+	}
+
+	for i := range manifest.Files {
+		file := &manifest.Files[i]
+
+		_, rc, err := fs.FileStore.OpenVersion(ctx, file.ID, file.Version)
+		if errors.Is(err, database.ErrNotFound) {
+			return status.Errorf(codes.Aborted, "file %s was deleted during the export", file.ID)
+		}
+		if err != nil {
+			return err
+		}
+
+		err = bw.WriteFile(&contextReader{ctx: ctx, r: rc})
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return bw.Close()
+}
+
+func (fs *FileServer) ImportAcknowledgement(ctx context.Context, req *fileserver.ImportAcknowledgementRequest) (*fileserver.ImportAcknowledgementResponse, error) {
+	ack, err := bundle.ParseAcknowledgement(req.Acknowledgement)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res := &fileserver.ImportAcknowledgementResponse{}
+
+	for i := range ack.Files {
+		delivery := &ack.Files[i]
+		if !delivery.Delivered() {
+			continue
+		}
+
+		file, err := fs.acknowledge(ctx, ack, delivery, req.DeleteDelivered)
+		res.Results = append(res.Results, batchResult(delivery.ID, file, err))
+	}
+
+	return res, nil
+}
+
+// acknowledge marks the file of delivery delivered, unless its content
+// changed since the export
+func (fs *FileServer) acknowledge(ctx context.Context, ack *bundle.Acknowledgement, delivery *bundle.Delivery, deleteDelivered bool) (*modelsv2.StoredFile, error) {
+	file, err := getBatchFile(ctx, fs.FileStore, delivery.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.DeletedAt.Valid {
+		return nil, status.Errorf(codes.NotFound, "file %s was deleted", delivery.ID)
+	}
+
+	if file.File.Checksum != delivery.Checksum {
+		return nil, status.Errorf(codes.FailedPrecondition, "the content of file %s changed since it was exported", delivery.ID)
+	}
+
+	mergeMetadata(file, ack.Metadata(delivery))
+
+	event := newAuditEvent(ctx, "ImportAcknowledgement")
+	setAuditFile(event, file)
+	setAuditDetails(event, map[string]string{"bundleId": ack.BundleID, "uploadId": delivery.UploadID})
+
+	if _, err := fs.FileStore.Save(database.WithAuditEvent(ctx, event), file); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save file %s=%s %s=%s", "id", delivery.ID, "err", err)
+	}
+
+	if !deleteDelivered {
+		return file, nil
+	}
+
+	event = newAuditEvent(ctx, "DeleteFile")
+	setAuditFile(event, file)
+	setAuditDetails(event, map[string]string{"permanent": "false", "bundleId": ack.BundleID})
+
+	if err := fs.FileStore.Delete(database.WithAuditEvent(ctx, event), delivery.ID, false); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete file %s=%s %s=%s", "id", delivery.ID, "err", err)
+	}
+
+	return getBatchFile(ctx, fs.FileStore, delivery.ID)
+}
@@ -0,0 +1,205 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/bundle"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// marketplaceStub records the files uploaded by an import
+type marketplaceStub struct {
+	uploaded []string
+}
+
+func (m *marketplaceStub) UploadFile(_ context.Context, fileName string, r io.Reader) (string, error) {
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return "", err
+	}
+	m.uploaded = append(m.uploaded, fileName)
+	return fmt.Sprintf("request-%d", len(m.uploaded)), nil
+}
+
+var _ = Describe("export", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		store  database.StoredFileStore
+		fs     *FileServer
+		client fileserver.FileServerClient
+		public ed25519.PublicKey
+
+		ids []string
+	)
+
+	save := func(name, content string, metadata ...modelsv2.StoredFileMetadata) string {
+		id, err := store.Save(ctx, &modelsv2.StoredFile{
+			Name:       name,
+			Source:     "redhat-marketplace",
+			SourceType: "report",
+			File:       modelsv2.StoredFileContent{Content: []byte(content)},
+			Metadata:   metadata,
+		})
+		Expect(err).To(Succeed())
+		return id
+	}
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+		DeferCleanup(func() { cancel() })
+
+		var (
+			private ed25519.PrivateKey
+			err     error
+		)
+		public, private, err = ed25519.GenerateKey(rand.Reader)
+		Expect(err).To(Succeed())
+
+		srv := newTestServer(testServerOptions{
+			Server: func(s *Server) {
+				s.BundleSigningKey = private
+				s.ChunkSize = 1024
+			},
+		})
+		store, fs, client = srv.Store, srv.FS, srv.Client
+
+		ids = []string{
+			save("report-1.tar.gz", "report one"),
+			save("report-2.tar.gz", string(bytes.Repeat([]byte("report two "), 1024))),
+			save("report-0.tar.gz", "delivered", modelsv2.StoredFileMetadata{Key: bundle.MetadataDelivered, Value: "true"}),
+		}
+	})
+
+	export := func(filter string) (*bytes.Buffer, *fileserver.ExportFilesResult, error) {
+		stream, err := client.ExportFiles(ctx, &fileserver.ExportFilesRequest{Filter: filter})
+		Expect(err).To(Succeed())
+
+		buf := &bytes.Buffer{}
+		for {
+			res, err := stream.Recv()
+			if err != nil {
+				return nil, nil, err
+			}
+			buf.Write(res.GetChunkData())
+			if result := res.GetResult(); result != nil {
+				return buf, result, nil
+			}
+		}
+	}
+
+	acknowledge := func(ack *bundle.Acknowledgement, deleteDelivered bool) []*fileserver.BatchResult {
+		data, err := json.Marshal(ack)
+		Expect(err).To(Succeed())
+
+		res, err := client.ImportAcknowledgement(ctx, &fileserver.ImportAcknowledgementRequest{
+			Acknowledgement: data,
+			DeleteDelivered: deleteDelivered,
+		})
+		Expect(err).To(Succeed())
+		return res.Results
+	}
+
+	It("should export, import and acknowledge the files not delivered", func() {
+		data, result, err := export(bundle.UndeliveredFilter)
+		Expect(err).To(Succeed())
+		Expect(result.Files).To(BeEquivalentTo(2))
+		Expect(result.Checksum).To(Equal(fmt.Sprintf("%x", sha256.Sum256(data.Bytes()))))
+
+		r, err := bundle.NewReader(data, public)
+		Expect(err).To(Succeed())
+		Expect(r.Manifest.ID).To(Equal(result.BundleId))
+		Expect(r.Manifest.Files[0].ID).To(Equal(ids[0]))
+
+		uploader := &marketplaceStub{}
+		ack, err := bundle.Import(ctx, r, uploader, nil)
+		Expect(err).To(Succeed())
+		Expect(uploader.uploaded).To(Equal([]string{"report-1.tar.gz", "report-2.tar.gz"}))
+
+		results := acknowledge(ack, false)
+		Expect(results).To(HaveLen(2))
+		for _, res := range results {
+			Expect(codes.Code(res.Code)).To(Equal(codes.OK), res.Message)
+			Expect(res.File.Metadata).To(HaveKeyWithValue(bundle.MetadataDelivered, "true"))
+			Expect(res.File.Metadata).To(HaveKeyWithValue(bundle.MetadataBundleID, result.BundleId))
+		}
+		Expect(results[0].File.Metadata).To(HaveKeyWithValue(bundle.MetadataUploadID, "request-1"))
+
+		By("exporting nothing once delivered")
+		_, result, err = export(bundle.UndeliveredFilter)
+		Expect(err).To(Succeed())
+		Expect(result.Files).To(BeZero())
+
+		events, _, err := store.ListAuditEvents(ctx)
+		Expect(err).To(Succeed())
+		methods := []string{}
+		for _, event := range events {
+			methods = append(methods, event.Method)
+		}
+		Expect(methods).To(ConsistOf("ExportFiles", "ExportFiles", "ImportAcknowledgement", "ImportAcknowledgement"))
+	})
+
+	It("should not acknowledge files changed since the export", func() {
+		data, _, err := export("")
+		Expect(err).To(Succeed())
+
+		r, err := bundle.NewReader(data, public)
+		Expect(err).To(Succeed())
+		Expect(r.Manifest.Files).To(HaveLen(3))
+
+		ack, err := bundle.Import(ctx, r, &marketplaceStub{}, nil)
+		Expect(err).To(Succeed())
+		ack.Files[2].Error = "rejected"
+
+		save("report-1.tar.gz", "report one, again")
+
+		results := acknowledge(ack, true)
+		Expect(results).To(HaveLen(2))
+		Expect(codes.Code(results[0].Code)).To(Equal(codes.FailedPrecondition))
+		Expect(codes.Code(results[1].Code)).To(Equal(codes.OK))
+		Expect(results[1].File.DeletedAt).ToNot(BeNil())
+
+		file, err := store.Get(ctx, ids[2])
+		Expect(err).To(Succeed())
+		Expect(file.DeletedAt.Valid).To(BeFalse())
+	})
+
+	It("should not export without a signing key or with an invalid filter", func() {
+		_, _, err := export(`name ==`)
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+		fs.BundleSigningKey = nil
+		_, _, err = export("")
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+
+		_, err = client.ImportAcknowledgement(ctx, &fileserver.ImportAcknowledgementRequest{Acknowledgement: []byte("{}")})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+})
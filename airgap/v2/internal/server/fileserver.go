@@ -15,11 +15,13 @@
 package server
 
 import (
-	"bytes"
+	"bufio"
 	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"emperror.dev/errors"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
@@ -46,91 +48,131 @@ const (
 )
 
 func (fs *FileServer) UploadFile(stream fileserver.FileServer_UploadFileServer) error {
-	var bs []byte
-	var finfo *dataservicev1.FileInfo
+	progress := newProgress(fs.Log, "upload progress")
+	progress.counter = fs.transferred(directionUpload)
+
+	reader := &uploadReader{
+		stream:   stream,
+		progress: progress,
+		checkInfo: func(info *dataservicev1.FileInfo) error {
+			return fs.checkQuota(stream.Context(), info.Source, int64(info.Size))
+		},
+	}
 
-	for {
-		req, err := stream.Recv()
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
+	var file *modelsv2.StoredFile
 
-			fs.Log.Error(err, "Oops, something went wrong!")
-			return status.Errorf(
-				codes.Unknown,
-				"Error while processing stream, details: %v",
-				err,
+	prepare := fs.prepareFile(func() *dataservicev1.FileInfo { return reader.info }, reader.checksum)
+	event := newAuditEvent(stream.Context(), "UploadFile")
+
+	// Stream the content to storage, the file is only saved once all of it is
+	// received and verified, a failed upload discards the content
+	id, err := fs.FileStore.Upload(database.WithAuditEvent(stream.Context(), event), reader, auditPrepare(event, func(content *modelsv2.StoredFileContent) (*modelsv2.StoredFile, error) {
+		var err error
+		file, err = prepare(content)
+		return file, err
+	}))
+
+	if err != nil {
+		return uploadError(err)
+	}
+
+	// Prepare response on save and close stream
+	res := &fileserver.UploadFileResponse{
+		Id:            id,
+		Size:          uint32(file.File.Size),
+		BytesReceived: uint64(reader.progress.Total()),
+	}
+
+	return stream.SendAndClose(res)
+}
+
+// prepareFile returns the callback building the file described by the info
+// of an upload, once its content is verified against the checksum provided.
+// checksum returns the checksum of the content in the declared algorithm
+// given its sha256 checksum.
+func (fs *FileServer) prepareFile(
+	info func() *dataservicev1.FileInfo,
+	checksum func(sha256sum string) string,
+) database.PrepareUpload {
+	return func(content *modelsv2.StoredFileContent) (*modelsv2.StoredFile, error) {
+		finfo := info()
+
+		if finfo == nil {
+			fs.Log.Error(ErrFileInfoMissing, "file info is nil")
+			return nil, status.Errorf(
+				codes.FailedPrecondition,
+				ErrFileInfoMissing.Error(),
 			)
 		}
 
-		if b := req.GetChunkData(); b != nil {
-			if bs == nil {
-				bs = b
-			} else {
-				bs = append(bs, b...)
-			}
+		if content.Size == 0 {
+			fs.Log.Error(ErrFileContentMissing, "file content is missing")
+			return nil, status.Errorf(
+				codes.FailedPrecondition,
+				ErrFileContentMissing.Error(),
+			)
 		}
 
-		if req.GetInfo() != nil {
-			finfo = req.GetInfo()
+		fs.Log.V(2).Info("Stream end", "total bytes received", content.Size)
+
+		calculated := checksum(content.Checksum)
+		if !strings.EqualFold(calculated, finfo.Checksum) {
+			err := errors.WithDetails(ErrFileChecksumIncorrect,
+				"algorithm", checksumAlgorithm(finfo),
+				"checksumProvided", finfo.Checksum,
+				"checksumCalculated", calculated)
+			fs.Log.Error(err, "checksum failure")
+
+			return nil, status.Errorf(
+				codes.DataLoss,
+				"Err: %s Details: %v+",
+				err.Error(),
+				errors.GetDetails(err),
+			)
 		}
-	}
 
-	if finfo == nil {
-		fs.Log.Error(ErrFileInfoMissing, "file info is nil")
-		return status.Errorf(
-			codes.FailedPrecondition,
-			ErrFileInfoMissing.Error(),
-		)
-	}
+		file, err := modelsv2.StoredFileFromProto(finfo)
+		if err != nil {
+			return nil, err
+		}
 
-	if len(bs) == 0 {
-		fs.Log.Error(ErrFileContentMissing, "file content is missing")
-		return status.Errorf(
-			codes.FailedPrecondition,
-			ErrFileContentMissing.Error(),
-		)
+		file.File.MimeType = finfo.MimeType
+		return file, nil
 	}
+}
 
-	fs.Log.V(2).Info("Stream end", "total bytes received", len(bs))
-	file, err := modelsv2.StoredFileFromProto(finfo)
-
-	if err != nil {
-		return err
+// uploadError keeps the status of errors raised by the stream or while
+// preparing the file, and reports any other failure as a storage error.
+func uploadError(err error) error {
+	if quotaErr := (&database.QuotaExceededError{}); errors.As(err, &quotaErr) {
+		return quotaError(quotaErr)
 	}
 
-	file.File.Content = bs
-	file.File.MimeType = finfo.MimeType
-
-	// Attempt to save file in database
-	id, err := fs.FileStore.Save(stream.Context(), file)
-	if err != nil {
-		return status.Errorf(
-			codes.Unknown,
-			fmt.Sprintf("Failed to save file in database: %v", err),
-		)
+	var statusErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &statusErr) {
+		return statusErr.GRPCStatus().Err()
 	}
 
-	if file.File.Checksum != finfo.Checksum {
-		err := errors.WithDetails(ErrFileChecksumIncorrect, "checksumProvided", finfo.Checksum, "checksumCalculated", file.File.Checksum)
-		fs.Log.Error(err, "checksum failure")
-
-		return status.Errorf(
-			codes.DataLoss,
-			"Err: %s Details: %v+",
-			err.Error(),
-			errors.GetDetails(err),
-		)
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
 	}
 
-	// Prepare response on save and close stream
-	res := &fileserver.UploadFileResponse{
-		Id:   id,
-		Size: uint32(file.File.Size),
+	return status.Errorf(
+		codes.Unknown,
+		fmt.Sprintf("Failed to save file in database: %v", err),
+	)
+}
+
+// listError reports an invalid filter, order or page token as an invalid
+// argument and any other failure as an internal error.
+func listError(err error, msg string) error {
+	for _, invalid := range []error{database.ErrInvalidFilter, database.ErrInvalidOrderBy, database.ErrInvalidPageToken} {
+		if errors.Is(err, invalid) {
+			return status.Errorf(codes.InvalidArgument, "%s. Error: %s", msg, err)
+		}
 	}
 
-	return stream.SendAndClose(res)
+	return status.Errorf(codes.Internal, "%s", msg)
 }
 
 func (fs *FileServer) ListFiles(ctx context.Context, req *fileserver.ListFilesRequest) (*fileserver.ListFilesResponse, error) {
@@ -148,12 +190,20 @@ func (fs *FileServer) ListFiles(ctx context.Context, req *fileserver.ListFilesRe
 	}
 
 	if req.Filter != "" {
-		filters := fileserver.Filters{}
-		err := filters.UnmarshalText([]byte(req.Filter))
+		expr, err := fileserver.ParseFilter(req.Filter)
 		if err != nil {
 			return nil, status.Errorf(codes.InvalidArgument, "Filter is formatted incorrectly. Error: %s", err)
 		}
-		opts = append(opts, database.ApplyFilters(filters))
+		opts = append(opts, database.ApplyFilter(expr))
+	}
+
+	if req.OrderBy != "" {
+		opts = append(opts, database.OrderBy(req.OrderBy))
+	}
+
+	var totalSize int64
+	if req.IncludeTotalSize {
+		opts = append(opts, database.CountTotal(&totalSize))
 	}
 
 	responseFiles := []*dataservicev1.FileInfo{}
@@ -161,7 +211,7 @@ func (fs *FileServer) ListFiles(ctx context.Context, req *fileserver.ListFilesRe
 	files, pageToken, err := fs.FileStore.List(ctx, opts...)
 
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to list files")
+		return nil, listError(err, "failed to list files")
 	}
 
 	errs := []error{}
@@ -190,6 +240,7 @@ func (fs *FileServer) ListFiles(ctx context.Context, req *fileserver.ListFilesRe
 		Files:         responseFiles,
 		NextPageToken: pageToken,
 		PageSize:      int32(pageSize),
+		TotalSize:     totalSize,
 	}, nil
 }
 
@@ -232,6 +283,18 @@ func (fs *FileServer) GetFile(ctx context.Context, req *fileserver.GetFileReques
 		return nil, status.Errorf(codes.NotFound, "not found")
 	}
 
+	if req.Version != 0 {
+		file, err = fs.FileStore.GetVersion(ctx, fmt.Sprintf("%d", file.ID), req.Version)
+
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "version not found %s=%d", "version", req.Version)
+		}
+
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get file version %s=%d %s=%s", "version", req.Version, "err", err)
+		}
+	}
+
 	info, err := modelsv2.StoredFileToProto(file)
 
 	if err != nil {
@@ -243,6 +306,31 @@ func (fs *FileServer) GetFile(ctx context.Context, req *fileserver.GetFileReques
 	}, nil
 }
 
+func (fs *FileServer) ListFileVersions(ctx context.Context, req *fileserver.ListFileVersionsRequest) (*fileserver.ListFileVersionsResponse, error) {
+	files, err := fs.FileStore.ListVersions(ctx, req.Id)
+
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "not found %s=%s", "id", req.Id)
+	}
+
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list file versions %s=%s %s=%s", "id", req.Id, "err", err)
+	}
+
+	versions := make([]*dataservicev1.FileInfo, 0, len(files))
+
+	for i := range files {
+		info, err := modelsv2.StoredFileToProto(&files[i])
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert file %s=%s %s=%s", "id", req.Id, "err", err)
+		}
+
+		versions = append(versions, info)
+	}
+
+	return &fileserver.ListFileVersionsResponse{Versions: versions}, nil
+}
+
 func (fs *FileServer) UpdateFileMetadata(
 	ctx context.Context,
 	req *fileserver.UpdateFileMetadataRequest,
@@ -257,27 +345,15 @@ func (fs *FileServer) UpdateFileMetadata(
 		return nil, status.Errorf(codes.Internal, "failed to get file %s=%s %s=%s", "id", req.Id, "err", err)
 	}
 
-	metadata := req.Metadata
+	mergeMetadata(file, req.Metadata)
 
-	for _, md := range file.Metadata {
-		if _, ok := metadata[md.Key]; !ok {
-			metadata[md.Key] = md.Value
-		}
-	}
-
-	fileMetadata := []modelsv2.StoredFileMetadata{}
+	event := newAuditEvent(ctx, "UpdateFileMetadata")
+	setAuditFile(event, file)
 
-	for key, value := range metadata {
-		fileMetadata = append(fileMetadata,
-			modelsv2.StoredFileMetadata{
-				Key:   key,
-				Value: value,
-			})
+	_, err = fs.FileStore.Save(database.WithAuditEvent(ctx, event), file)
+	if quotaErr := (&database.QuotaExceededError{}); errors.As(err, &quotaErr) {
+		return nil, quotaError(quotaErr)
 	}
-
-	file.Metadata = fileMetadata
-
-	_, err = fs.FileStore.Save(ctx, file)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to save file %s=%s %s=%s", "id", req.Id, "err", err)
 	}
@@ -292,47 +368,125 @@ func (fs *FileServer) UpdateFileMetadata(
 	}, nil
 }
 
-const chunkSize = 1024
+// mergeMetadata sets metadata on file, keeping its other keys
+func mergeMetadata(file *modelsv2.StoredFile, metadata map[string]string) {
+	merged := make(map[string]string, len(metadata)+len(file.Metadata))
+	for key, value := range metadata {
+		merged[key] = value
+	}
+
+	for _, md := range file.Metadata {
+		if _, ok := merged[md.Key]; !ok {
+			merged[md.Key] = md.Value
+		}
+	}
+
+	fileMetadata := []modelsv2.StoredFileMetadata{}
+
+	for key, value := range merged {
+		fileMetadata = append(fileMetadata,
+			modelsv2.StoredFileMetadata{
+				Key:   key,
+				Value: value,
+			})
+	}
+
+	file.Metadata = fileMetadata
+}
 
-func (fs *FileServer) DownloadFile(req *fileserver.DownloadFileRequest, stream fileserver.FileServer_DownloadFileServer) error {
-	file, err := fs.FileStore.Download(stream.Context(), req.Id)
+func (fs *FileServer) DownloadFile(req *fileserver.DownloadFileRequest, stream fileserver.FileServer_DownloadFileServer) (err error) {
+	ctx := stream.Context()
+
+	file, rc, err := fs.FileStore.OpenVersion(ctx, req.Id, req.Version)
+	if errors.Is(err, database.ErrNotFound) {
+		return status.Errorf(codes.NotFound, "not found %s=%s %s=%d", "id", req.Id, "version", req.Version)
+	}
 	if err != nil {
 		return status.Errorf(
 			codes.InvalidArgument,
 			fmt.Sprintf("Failed to fetch file from database due to: %v", err),
 		)
 	}
+	defer rc.Close()
 
-	buf := file.File.Content
-	var chunk []byte
+	reader := &contextReader{ctx: ctx, r: rc}
+	progress := newProgress(fs.Log, "download progress")
+	progress.counter = fs.transferred(directionDownload)
+	totalSize := uint64(file.File.Size)
 
-	for len(buf) != 0 {
-		if len(buf) >= chunkSize {
-			chunk, buf = buf[:chunkSize], buf[chunkSize:]
-		} else {
-			chunk, buf = buf[:], buf[len(buf):]
+	// any part of the content sent is a download
+	defer func() {
+		if auditErr := fs.auditDownload(ctx, file, progress.Total()); err == nil {
+			err = auditErr
 		}
+	}()
+
+	for {
+		// a new buffer for every message, sent messages must not be modified
+		chunk := make([]byte, fs.chunkSize())
+		n, err := io.ReadFull(reader, chunk)
+
+		if n > 0 {
+			progress.Add(n)
+
+			// File chunk response
+			res := &fileserver.DownloadFileResponse{
+				ChunkData: chunk[:n],
+				BytesSent: uint64(progress.Total()),
+				TotalSize: totalSize,
+			}
 
-		// File chunk response
-		res := &fileserver.DownloadFileResponse{
-			ChunkData: chunk,
+			// Send file chunks
+			if sendErr := stream.Send(res); sendErr != nil {
+				return status.Errorf(
+					codes.Unknown,
+					fmt.Sprintf("Error while sending response %v ", sendErr),
+				)
+			}
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return status.FromContextError(err).Err()
 		}
 
-		// Send file chunks
-		err = stream.Send(res)
 		if err != nil {
 			return status.Errorf(
 				codes.Unknown,
-				fmt.Sprintf("Error while sending response %v ", err),
+				fmt.Sprintf("Error while reading file %v ", err),
 			)
 		}
 	}
 
+	fs.Log.V(2).Info("download complete", "id", req.Id, "bytes", progress.Total())
 	return nil
 }
 
+func (fs *FileServer) chunkSize() int {
+	if fs.ChunkSize <= 0 {
+		return defaultChunkSize
+	}
+	return fs.ChunkSize
+}
+
 func (fs *FileServer) DeleteFile(ctx context.Context, req *fileserver.DeleteFileRequest) (*fileserver.DeleteFileResponse, error) {
-	err := fs.FileStore.Delete(ctx, req.Id, req.Permanent)
+	file, err := fs.FileStore.Get(ctx, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get file %s=%s %s=%s", "id", req.Id, "err", err)
+	}
+
+	event := newAuditEvent(ctx, "DeleteFile")
+	setAuditFile(event, file)
+	setAuditFileID(event, req.Id)
+	if req.Permanent {
+		event.ChecksumAfter = ""
+	}
+	setAuditDetails(event, map[string]string{"permanent": strconv.FormatBool(req.Permanent)})
+
+	err = fs.FileStore.Delete(database.WithAuditEvent(ctx, event), req.Id, req.Permanent)
 
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to delete file %s=%s %s=%s", "id", req.Id, "err", err)
@@ -342,7 +496,8 @@ func (fs *FileServer) DeleteFile(ctx context.Context, req *fileserver.DeleteFile
 }
 
 func (fs *FileServer) CleanTombstones(ctx context.Context, _ *fileserver.CleanTombstonesRequest) (*fileserver.CleanTombstonesResponse, error) {
-	rowsAffects, err := fs.FileStore.CleanTombstones(ctx)
+	event := newAuditEvent(ctx, "CleanTombstones")
+	rowsAffects, err := fs.FileStore.CleanTombstones(database.WithAuditEvent(ctx, event))
 
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to clean tombstones %s=%s", "err", err)
@@ -351,8 +506,75 @@ func (fs *FileServer) CleanTombstones(ctx context.Context, _ *fileserver.CleanTo
 	return &fileserver.CleanTombstonesResponse{TombstonesCleaned: int32(rowsAffects)}, nil
 }
 
+func (fs *FileServer) ApplyRetention(ctx context.Context, req *fileserver.ApplyRetentionRequest) (*fileserver.ApplyRetentionResponse, error) {
+	event := newAuditEvent(ctx, "ApplyRetention")
+	expired, err := fs.FileStore.ApplyRetention(database.WithAuditEvent(ctx, event), req.DryRun)
+	if errors.Is(err, database.ErrInvalidRetentionRule) {
+		return nil, status.Errorf(codes.FailedPrecondition, "retention rules are invalid: %s", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to apply retention rules: %s", err)
+	}
+
+	files := make([]*fileserver.ExpiredFile, 0, len(expired))
+	for i := range expired {
+		file, err := modelsv2.StoredFileToProto(&expired[i].File)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert file: %s", err)
+		}
+
+		files = append(files, &fileserver.ExpiredFile{File: file, Rule: expired[i].Rule})
+	}
+
+	return &fileserver.ApplyRetentionResponse{Files: files, DryRun: req.DryRun}, nil
+}
+
+func (fs *FileServer) RotateEncryptionKeys(ctx context.Context, _ *fileserver.RotateEncryptionKeysRequest) (*fileserver.RotateEncryptionKeysResponse, error) {
+	event := newAuditEvent(ctx, "RotateEncryptionKeys")
+	rotation, err := fs.FileStore.RotateKeys(database.WithAuditEvent(ctx, event))
+	if errors.Is(err, database.ErrNotEncrypted) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to rotate data keys: %s", err)
+	}
+
+	return &fileserver.RotateEncryptionKeysResponse{
+		DataKeysRotated: rotation.Rotated,
+		SkippedBlobKeys: rotation.Skipped,
+	}, nil
+}
+
 func (fs *FileServer) RegisterHTTPRoutes(mux *runtime.ServeMux) error {
-	return mux.HandlePath("POST", "/v1/file/{id}/download", fs.httpDownloadFile)
+	download := runtime.HandlerFunc(fs.httpDownloadFile)
+	upload := runtime.HandlerFunc(fs.httpUploadFile)
+	watch := runtime.HandlerFunc(fs.httpWatchFiles)
+
+	metrics := fs.httpMetrics()
+	metricsHandler := runtime.HandlerFunc(func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		metrics.ServeHTTP(w, r)
+	})
+
+	if auth := fs.authorizer(); auth != nil {
+		download = auth.HandlerFunc(RoleReader, download)
+		upload = auth.HandlerFunc(RoleUploader, upload)
+		watch = auth.HandlerFunc(RoleReader, watch)
+		metricsHandler = auth.HandlerFunc(RoleReader, metricsHandler)
+	}
+
+	if err := mux.HandlePath("GET", "/metrics", metricsHandler); err != nil {
+		return err
+	}
+
+	if err := mux.HandlePath("GET", "/v1/files/watch", watch); err != nil {
+		return err
+	}
+
+	if err := mux.HandlePath("POST", "/v1/files", upload); err != nil {
+		return err
+	}
+
+	return mux.HandlePath("POST", "/v1/file/{id}/download", download)
 }
 
 func (fs *FileServer) httpDownloadFile(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
@@ -366,20 +588,45 @@ func (fs *FileServer) httpDownloadFile(w http.ResponseWriter, r *http.Request, p
 		return
 	}
 
-	data, err := fs.FileStore.Download(ctx, id)
+	var version uint64
+	if v := r.URL.Query().Get("version"); v != "" {
+		var err error
+		version, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid version %s", v), http.StatusBadRequest)
+			return
+		}
+	}
+
+	file, rc, err := fs.FileStore.OpenVersion(ctx, id, version)
+
+	if errors.Is(err, database.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
 
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	defer rc.Close()
 
-	if data == nil {
-		http.NotFound(w, r)
-		return
+	// sniff the content type without reading the whole file
+	content := bufio.NewReaderSize(rc, 512)
+	head, _ := content.Peek(512)
+
+	w.Header().Add("Content-Type", http.DetectContentType(head))
+	if digest, err := encodeDigest(file.File.Checksum); err == nil {
+		w.Header().Add("Digest", digest)
+	}
+	w.Header().Add("Content-Length", strconv.Itoa(file.File.Size))
+
+	n, err := io.Copy(w, &contextReader{ctx: ctx, r: content})
+	fs.transferred(directionDownload).Add(float64(n))
+	if err != nil {
+		fs.Log.Error(err, "failed to stream file", "id", id)
 	}
 
-	w.Header().Add("Content-Type", http.DetectContentType(data.File.Content))
-	w.Header().Add("Digest", fmt.Sprintf("sha-256=%s", data.File.Checksum))
-	io.Copy(w, bytes.NewReader(data.File.Content))
-	return
+	// the response is already sent, a failure to record it can only be logged
+	_ = fs.auditDownload(ctx, file, n)
 }
@@ -0,0 +1,690 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	dataservicev1 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Describe("FileServer", func() {
+	const uploadChunkSize = 64 * 1024
+
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		srv    *testServer
+		store  database.StoredFileStore
+		fs     *FileServer
+		client fileserver.FileServerClient
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), 2*time.Minute)
+		DeferCleanup(cancel)
+
+		srv = newTestServer(testServerOptions{})
+		store, fs, client = srv.Store, srv.FS, srv.Client
+	})
+
+	blobCount := func() int {
+		count := 0
+		filepath.Walk(srv.BlobDir, func(_ string, info os.FileInfo, err error) error {
+			if err == nil && info.Mode().IsRegular() {
+				count++
+			}
+			return nil
+		})
+		return count
+	}
+
+	fileInfo := func(checksum string) *fileserver.UploadFileRequest {
+		return &fileserver.UploadFileRequest{
+			Data: &fileserver.UploadFileRequest_Info{
+				Info: &dataservicev1.FileInfo{
+					Name:       "large.bin",
+					Source:     "redhat-marketplace",
+					SourceType: "report",
+					Checksum:   checksum,
+					MimeType:   "application/octet-stream",
+				},
+			},
+		}
+	}
+
+	// upload sends size bytes built from a repeated random chunk and returns its checksum
+	upload := func(ctx context.Context, size int) (*fileserver.UploadFileResponse, string, error) {
+		chunk := make([]byte, uploadChunkSize)
+		_, err := rand.Read(chunk)
+		Expect(err).To(Succeed())
+
+		h := sha256.New()
+		for sent := 0; sent < size; sent += len(chunk) {
+			h.Write(chunk)
+		}
+		checksum := fmt.Sprintf("%x", h.Sum(nil))
+
+		stream, err := client.UploadFile(ctx)
+		Expect(err).To(Succeed())
+		Expect(stream.Send(fileInfo(checksum))).To(Succeed())
+
+		for sent := 0; sent < size; sent += len(chunk) {
+			err := stream.Send(&fileserver.UploadFileRequest{
+				Data: &fileserver.UploadFileRequest_ChunkData{ChunkData: chunk},
+			})
+			if err != nil {
+				break
+			}
+		}
+
+		res, err := stream.CloseAndRecv()
+		return res, checksum, err
+	}
+
+	// checksumDigest returns the Digest header of a download
+	checksumDigest := func(checksum string) string {
+		sum, err := hex.DecodeString(checksum)
+		Expect(err).To(Succeed())
+		return "sha-256=" + base64.StdEncoding.EncodeToString(sum)
+	}
+
+	// peakHeap samples the heap in use until stop is closed
+	peakHeap := func(stop <-chan struct{}) <-chan uint64 {
+		result := make(chan uint64, 1)
+		go func() {
+			var peak uint64
+			var stats goruntime.MemStats
+			ticker := time.NewTicker(5 * time.Millisecond)
+			defer ticker.Stop()
+
+			for {
+				goruntime.ReadMemStats(&stats)
+				if stats.HeapInuse > peak {
+					peak = stats.HeapInuse
+				}
+
+				select {
+				case <-stop:
+					result <- peak
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+		return result
+	}
+
+	It("should stream a large file without holding it in memory", func() {
+		const size = 128 * 1024 * 1024
+		const maxHeapGrowth = 48 * 1024 * 1024
+
+		goruntime.GC()
+		var stats goruntime.MemStats
+		goruntime.ReadMemStats(&stats)
+		baseline := stats.HeapInuse
+
+		stop := make(chan struct{})
+		peak := peakHeap(stop)
+
+		res, checksum, err := upload(ctx, size)
+		Expect(err).To(Succeed())
+		Expect(res.Size).To(Equal(uint32(size)))
+		Expect(res.BytesReceived).To(Equal(uint64(size)))
+
+		stream, err := client.DownloadFile(ctx, &fileserver.DownloadFileRequest{Id: res.Id})
+		Expect(err).To(Succeed())
+
+		h := sha256.New()
+		var last *fileserver.DownloadFileResponse
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).To(Succeed())
+			Expect(len(chunk.ChunkData)).To(BeNumerically("<=", defaultChunkSize))
+			h.Write(chunk.ChunkData)
+			last = chunk
+		}
+
+		close(stop)
+		Expect(<-peak - baseline).To(BeNumerically("<", maxHeapGrowth))
+
+		Expect(fmt.Sprintf("%x", h.Sum(nil))).To(Equal(checksum))
+		Expect(last.BytesSent).To(Equal(uint64(size)))
+		Expect(last.TotalSize).To(Equal(uint64(size)))
+		Expect(blobCount()).To(Equal(1))
+	})
+
+	It("should send chunks of the configured size", func() {
+		fs.ChunkSize = 1000
+
+		res, _, err := upload(ctx, uploadChunkSize)
+		Expect(err).To(Succeed())
+
+		stream, err := client.DownloadFile(ctx, &fileserver.DownloadFileRequest{Id: res.Id})
+		Expect(err).To(Succeed())
+
+		chunks := 0
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).To(Succeed())
+			Expect(len(chunk.ChunkData)).To(BeNumerically("<=", 1000))
+			chunks++
+		}
+		Expect(chunks).To(Equal((uploadChunkSize + 999) / 1000))
+	})
+
+	It("should discard the content of a cancelled upload", func() {
+		uploadCtx, cancelUpload := context.WithCancel(ctx)
+
+		stream, err := client.UploadFile(uploadCtx)
+		Expect(err).To(Succeed())
+		Expect(stream.Send(fileInfo(""))).To(Succeed())
+
+		chunk := make([]byte, uploadChunkSize)
+		for i := 0; i < 16; i++ {
+			Expect(stream.Send(&fileserver.UploadFileRequest{
+				Data: &fileserver.UploadFileRequest_ChunkData{ChunkData: chunk},
+			})).To(Succeed())
+		}
+
+		cancelUpload()
+		_, err = stream.CloseAndRecv()
+		Expect(status.Code(err)).To(Equal(codes.Canceled))
+
+		Eventually(blobCount).Should(BeZero())
+		Consistently(func() int {
+			files, _, err := store.List(ctx)
+			Expect(err).To(Succeed())
+			return len(files)
+		}).Should(BeZero())
+	})
+
+	Context("checksum", func() {
+		content := []byte("checksum content")
+
+		send := func(info *dataservicev1.FileInfo, infoFirst bool) (*fileserver.UploadFileResponse, error) {
+			stream, err := client.UploadFile(ctx)
+			Expect(err).To(Succeed())
+
+			infoReq := &fileserver.UploadFileRequest{Data: &fileserver.UploadFileRequest_Info{Info: info}}
+			chunkReq := &fileserver.UploadFileRequest{Data: &fileserver.UploadFileRequest_ChunkData{ChunkData: content}}
+
+			if infoFirst {
+				Expect(stream.Send(infoReq)).To(Succeed())
+				Expect(stream.Send(chunkReq)).To(Succeed())
+			} else {
+				Expect(stream.Send(chunkReq)).To(Succeed())
+				Expect(stream.Send(infoReq)).To(Succeed())
+			}
+
+			return stream.CloseAndRecv()
+		}
+
+		info := func(algorithm, checksum string) *dataservicev1.FileInfo {
+			return &dataservicev1.FileInfo{
+				Name:              "checksum.txt",
+				Source:            "redhat-marketplace",
+				SourceType:        "report",
+				Checksum:          checksum,
+				ChecksumAlgorithm: algorithm,
+			}
+		}
+
+		fileCount := func() int {
+			files, _, err := store.List(ctx)
+			Expect(err).To(Succeed())
+			return len(files)
+		}
+
+		It("should not save a file with an incorrect checksum", func() {
+			_, err := send(info("", fmt.Sprintf("%x", sha256.Sum256([]byte("other content")))), true)
+			Expect(status.Code(err)).To(Equal(codes.DataLoss))
+			Expect(fileCount()).To(BeZero())
+			Expect(blobCount()).To(BeZero())
+		})
+
+		It("should not replace a file with an incorrect checksum", func() {
+			res, err := send(info("", fmt.Sprintf("%x", sha256.Sum256(content))), true)
+			Expect(err).To(Succeed())
+
+			_, err = send(info("", "bad"), true)
+			Expect(status.Code(err)).To(Equal(codes.DataLoss))
+
+			_, rc, err := store.Open(ctx, res.Id)
+			Expect(err).To(Succeed())
+			defer rc.Close()
+			data, _ := io.ReadAll(rc)
+			Expect(data).To(Equal(content))
+			Expect(blobCount()).To(Equal(1))
+		})
+
+		It("should verify a declared checksum algorithm", func() {
+			res, err := send(info("SHA-512", fmt.Sprintf("%X", sha512.Sum512(content))), true)
+			Expect(err).To(Succeed())
+
+			file, err := store.Get(ctx, res.Id)
+			Expect(err).To(Succeed())
+			Expect(file.File.Checksum).To(Equal(fmt.Sprintf("%x", sha256.Sum256(content))))
+
+			_, err = send(info("sha384", fmt.Sprintf("%x", sha512.Sum512(content))), true)
+			Expect(status.Code(err)).To(Equal(codes.DataLoss))
+		})
+
+		It("should accept sha256 info after the content", func() {
+			_, err := send(info("sha256", fmt.Sprintf("%x", sha256.Sum256(content))), false)
+			Expect(err).To(Succeed())
+		})
+
+		It("should reject an unsupported or late checksum algorithm", func() {
+			_, err := send(info("md4", "abc"), true)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			_, err = send(info("sha512", fmt.Sprintf("%x", sha512.Sum512(content))), false)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			Expect(fileCount()).To(BeZero())
+			Expect(blobCount()).To(BeZero())
+		})
+	})
+
+	Context("resumable upload", func() {
+		content := []byte("resumable upload content")
+
+		start := func(checksum string) string {
+			res, err := client.StartUpload(ctx, &fileserver.StartUploadRequest{
+				Info: &dataservicev1.FileInfo{
+					Name:       "resumable.txt",
+					Source:     "redhat-marketplace",
+					SourceType: "report",
+					Checksum:   checksum,
+					Metadata:   map[string]string{"version": "1"},
+				},
+				TotalSize: uint64(len(content)),
+			})
+			Expect(err).To(Succeed())
+			Expect(res.CommittedOffset).To(BeZero())
+			return res.SessionId
+		}
+
+		put := func(session string, offset int, data []byte) (*fileserver.PutChunkResponse, error) {
+			return client.PutChunk(ctx, &fileserver.PutChunkRequest{
+				SessionId: session,
+				Offset:    uint64(offset),
+				ChunkData: data,
+			})
+		}
+
+		It("should resume from the committed offset and finalize", func() {
+			session := start(fmt.Sprintf("%x", sha256.Sum256(content)))
+
+			res, err := put(session, 0, content[:10])
+			Expect(err).To(Succeed())
+			Expect(res.CommittedOffset).To(Equal(uint64(10)))
+
+			By("sending past the committed offset")
+			_, err = put(session, 15, content[15:])
+			Expect(status.Code(err)).To(Equal(codes.OutOfRange))
+
+			By("resending a chunk after a lost response")
+			res, err = put(session, 5, content[5:15])
+			Expect(err).To(Succeed())
+			Expect(res.CommittedOffset).To(Equal(uint64(15)))
+
+			_, err = client.FinalizeUpload(ctx, &fileserver.FinalizeUploadRequest{SessionId: session})
+			Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+
+			uploadStatus, err := client.GetUploadStatus(ctx, &fileserver.GetUploadStatusRequest{SessionId: session})
+			Expect(err).To(Succeed())
+			Expect(uploadStatus.CommittedOffset).To(Equal(uint64(15)))
+			Expect(uploadStatus.TotalSize).To(Equal(uint64(len(content))))
+
+			_, err = put(session, int(uploadStatus.CommittedOffset), content[uploadStatus.CommittedOffset:])
+			Expect(err).To(Succeed())
+
+			finalized, err := client.FinalizeUpload(ctx, &fileserver.FinalizeUploadRequest{SessionId: session})
+			Expect(err).To(Succeed())
+			Expect(finalized.BytesReceived).To(Equal(uint64(len(content))))
+
+			file, rc, err := store.Open(ctx, finalized.Id)
+			Expect(err).To(Succeed())
+			defer rc.Close()
+			data, _ := io.ReadAll(rc)
+			Expect(data).To(Equal(content))
+			Expect(file.Name).To(Equal("resumable.txt"))
+			Expect(file.Metadata).To(HaveLen(1))
+			Expect(blobCount()).To(Equal(1))
+
+			uploadStatus, err = client.GetUploadStatus(ctx, &fileserver.GetUploadStatusRequest{SessionId: session})
+			Expect(err).To(Succeed())
+			Expect(uploadStatus.FileId).To(Equal(finalized.Id))
+		})
+
+		It("should discard an upload with an incorrect checksum", func() {
+			session := start("bad")
+
+			_, err := put(session, 0, content)
+			Expect(err).To(Succeed())
+
+			_, err = client.FinalizeUpload(ctx, &fileserver.FinalizeUploadRequest{SessionId: session})
+			Expect(status.Code(err)).To(Equal(codes.DataLoss))
+
+			_, err = client.GetUploadStatus(ctx, &fileserver.GetUploadStatusRequest{SessionId: session})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+			Expect(blobCount()).To(BeZero())
+		})
+
+		It("should abort an upload", func() {
+			session := start("")
+
+			_, err := put(session, 0, content[:10])
+			Expect(err).To(Succeed())
+			Expect(blobCount()).To(Equal(1))
+
+			_, err = client.AbortUpload(ctx, &fileserver.AbortUploadRequest{SessionId: session})
+			Expect(err).To(Succeed())
+			Expect(blobCount()).To(BeZero())
+
+			_, err = put(session, 10, content[10:])
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+	})
+
+	Context("versions", func() {
+		It("should list, get and download previous versions", func() {
+			first, checksum1, err := upload(ctx, 64*1024)
+			Expect(err).To(Succeed())
+			_, checksum2, err := upload(ctx, 128*1024)
+			Expect(err).To(Succeed())
+
+			versions, err := client.ListFileVersions(ctx, &fileserver.ListFileVersionsRequest{Id: first.Id})
+			Expect(err).To(Succeed())
+			Expect(versions.Versions).To(HaveLen(2))
+			Expect(versions.Versions[0].Version).To(Equal(uint64(2)))
+			Expect(versions.Versions[0].Checksum).To(Equal(checksum2))
+			Expect(versions.Versions[1].Checksum).To(Equal(checksum1))
+
+			latest, err := client.GetFile(ctx, &fileserver.GetFileRequest{IdLookup: &fileserver.GetFileRequest_Id{Id: first.Id}})
+			Expect(err).To(Succeed())
+			Expect(latest.Info.Version).To(Equal(uint64(2)))
+			Expect(latest.Info.Checksum).To(Equal(checksum2))
+
+			previous, err := client.GetFile(ctx, &fileserver.GetFileRequest{
+				IdLookup: &fileserver.GetFileRequest_Id{Id: first.Id},
+				Version:  1,
+			})
+			Expect(err).To(Succeed())
+			Expect(previous.Info.Version).To(Equal(uint64(1)))
+			Expect(previous.Info.Checksum).To(Equal(checksum1))
+			Expect(previous.Info.Size).To(Equal(uint32(64 * 1024)))
+
+			_, err = client.GetFile(ctx, &fileserver.GetFileRequest{
+				IdLookup: &fileserver.GetFileRequest_Id{Id: first.Id},
+				Version:  3,
+			})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+
+			stream, err := client.DownloadFile(ctx, &fileserver.DownloadFileRequest{Id: first.Id, Version: 1})
+			Expect(err).To(Succeed())
+			h := sha256.New()
+			for {
+				res, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				Expect(err).To(Succeed())
+				h.Write(res.ChunkData)
+			}
+			Expect(fmt.Sprintf("%x", h.Sum(nil))).To(Equal(checksum1))
+
+			stream, err = client.DownloadFile(ctx, &fileserver.DownloadFileRequest{Id: first.Id, Version: 9})
+			Expect(err).To(Succeed())
+			_, err = stream.Recv()
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+
+			resp, err := http.Post(srv.URL+"/v1/file/"+first.Id+"/download?version=1", "", nil)
+			Expect(err).To(Succeed())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(resp.Header.Get("Digest")).To(Equal(checksumDigest(checksum1)))
+
+			resp, err = http.Post(srv.URL+"/v1/file/"+first.Id+"/download?version=9", "", nil)
+			Expect(err).To(Succeed())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Context("audit log", func() {
+		methods := func(events []*dataservicev1.AuditEvent) []string {
+			names := []string{}
+			for _, event := range events {
+				names = append(names, event.Method)
+			}
+			return names
+		}
+
+		It("should record every change and download", func() {
+			first, checksum1, err := upload(ctx, 64*1024)
+			Expect(err).To(Succeed())
+			_, checksum2, err := upload(ctx, 128*1024)
+			Expect(err).To(Succeed())
+
+			_, err = client.UpdateFileMetadata(ctx, &fileserver.UpdateFileMetadataRequest{
+				Id:       first.Id,
+				Metadata: map[string]string{"version": "2"},
+			})
+			Expect(err).To(Succeed())
+
+			stream, err := client.DownloadFile(ctx, &fileserver.DownloadFileRequest{Id: first.Id})
+			Expect(err).To(Succeed())
+			for {
+				_, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				Expect(err).To(Succeed())
+			}
+
+			_, err = client.DeleteFile(ctx, &fileserver.DeleteFileRequest{Id: first.Id, Permanent: true})
+			Expect(err).To(Succeed())
+			_, err = client.CleanTombstones(ctx, &fileserver.CleanTombstonesRequest{})
+			Expect(err).To(Succeed())
+
+			res, err := client.ListAuditEvents(ctx, &fileserver.ListAuditEventsRequest{})
+			Expect(err).To(Succeed())
+			Expect(methods(res.Events)).To(Equal([]string{
+				"CleanTombstones", "DeleteFile", "DownloadFile", "UpdateFileMetadata", "UploadFile", "UploadFile",
+			}))
+
+			created, replaced := res.Events[5], res.Events[4]
+			Expect(created.FileId).To(Equal(first.Id))
+			Expect(created.FileKey.Name).To(Equal("large.bin"))
+			Expect(created.ChecksumBefore).To(BeEmpty())
+			Expect(created.ChecksumAfter).To(Equal(checksum1))
+			Expect(replaced.ChecksumBefore).To(Equal(checksum1))
+			Expect(replaced.ChecksumAfter).To(Equal(checksum2))
+
+			Expect(res.Events[3].ChecksumAfter).To(Equal(checksum2))
+			Expect(res.Events[2].Details).To(HaveKeyWithValue("bytesSent", "131072"))
+
+			deleted := res.Events[1]
+			Expect(deleted.ChecksumBefore).To(Equal(checksum2))
+			Expect(deleted.ChecksumAfter).To(BeEmpty())
+			Expect(deleted.Details).To(HaveKeyWithValue("permanent", "true"))
+
+			for i := 0; i < len(res.Events)-1; i++ {
+				Expect(res.Events[i].PreviousHash).To(Equal(res.Events[i+1].Hash))
+			}
+
+			res, err = client.ListAuditEvents(ctx, &fileserver.ListAuditEventsRequest{Filter: `method == "UploadFile"`})
+			Expect(err).To(Succeed())
+			Expect(methods(res.Events)).To(Equal([]string{"UploadFile", "UploadFile"}))
+
+			verify, err := client.VerifyAuditLog(ctx, &fileserver.VerifyAuditLogRequest{})
+			Expect(err).To(Succeed())
+			Expect(verify.Valid).To(BeTrue())
+			Expect(verify.EventsVerified).To(Equal(uint64(6)))
+		})
+
+		It("should not record a failed upload", func() {
+			_, _, err := upload(ctx, 64*1024)
+			Expect(err).To(Succeed())
+
+			stream, err := client.UploadFile(ctx)
+			Expect(err).To(Succeed())
+			Expect(stream.Send(fileInfo("bad checksum"))).To(Succeed())
+			Expect(stream.Send(&fileserver.UploadFileRequest{
+				Data: &fileserver.UploadFileRequest_ChunkData{ChunkData: []byte("content")},
+			})).To(Succeed())
+			_, err = stream.CloseAndRecv()
+			Expect(status.Code(err)).To(Equal(codes.DataLoss))
+
+			res, err := client.ListAuditEvents(ctx, &fileserver.ListAuditEventsRequest{})
+			Expect(err).To(Succeed())
+			Expect(methods(res.Events)).To(Equal([]string{"UploadFile"}))
+		})
+	})
+
+	It("should list files matching a filter", func() {
+		_, _, err := upload(ctx, 1024)
+		Expect(err).To(Succeed())
+
+		res, err := client.ListFiles(ctx, &fileserver.ListFilesRequest{Filter: `name like "large.*" && (size > 1000 || name in ("other"))`})
+		Expect(err).To(Succeed())
+		Expect(res.Files).To(HaveLen(1))
+
+		res, err = client.ListFiles(ctx, &fileserver.ListFilesRequest{Filter: `!metadata.version exists && name == "other"`})
+		Expect(err).To(Succeed())
+		Expect(res.Files).To(BeEmpty())
+
+		_, err = client.ListFiles(ctx, &fileserver.ListFilesRequest{Filter: `name == `})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+		_, err = client.ListFiles(ctx, &fileserver.ListFilesRequest{Filter: `owner == "jane"`})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+
+	It("should page through files in order", func() {
+		for _, name := range []string{"c.txt", "a.txt", "d.txt", "b.txt"} {
+			_, err := store.Save(ctx, &modelsv2.StoredFile{
+				Name:       name,
+				Source:     "redhat-marketplace",
+				SourceType: "report",
+				File:       modelsv2.StoredFileContent{Content: []byte(name)},
+			})
+			Expect(err).To(Succeed())
+		}
+
+		req := &fileserver.ListFilesRequest{PageSize: 3, OrderBy: "name", IncludeTotalSize: true}
+		res, err := client.ListFiles(ctx, req)
+		Expect(err).To(Succeed())
+		Expect(res.TotalSize).To(Equal(int64(4)))
+		Expect(res.Files).To(HaveLen(3))
+		Expect(res.Files[0].Name).To(Equal("a.txt"))
+		Expect(res.NextPageToken).ToNot(BeEmpty())
+
+		req.PageToken = res.NextPageToken
+		res, err = client.ListFiles(ctx, req)
+		Expect(err).To(Succeed())
+		Expect(res.Files).To(HaveLen(1))
+		Expect(res.Files[0].Name).To(Equal("d.txt"))
+		Expect(res.NextPageToken).To(BeEmpty())
+
+		req.OrderBy = "name desc"
+		_, err = client.ListFiles(ctx, req)
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+		_, err = client.ListFiles(ctx, &fileserver.ListFilesRequest{OrderBy: "metadata.foo"})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+
+	It("should reject an upload without file info", func() {
+		stream, err := client.UploadFile(ctx)
+		Expect(err).To(Succeed())
+		Expect(stream.Send(&fileserver.UploadFileRequest{
+			Data: &fileserver.UploadFileRequest_ChunkData{ChunkData: []byte("data")},
+		})).To(Succeed())
+
+		_, err = stream.CloseAndRecv()
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(blobCount()).To(BeZero())
+	})
+
+	It("should stop a cancelled download", func() {
+		res, _, err := upload(ctx, 4*1024*1024)
+		Expect(err).To(Succeed())
+
+		downloadCtx, cancelDownload := context.WithCancel(ctx)
+		stream, err := client.DownloadFile(downloadCtx, &fileserver.DownloadFileRequest{Id: res.Id})
+		Expect(err).To(Succeed())
+
+		_, err = stream.Recv()
+		Expect(err).To(Succeed())
+
+		cancelDownload()
+		Eventually(func() codes.Code {
+			_, err := stream.Recv()
+			return status.Code(err)
+		}).Should(Equal(codes.Canceled))
+	})
+
+	It("should stream the http download", func() {
+		res, checksum, err := upload(ctx, 1024*1024)
+		Expect(err).To(Succeed())
+
+		resp, err := http.Post(srv.URL+"/v1/file/"+res.Id+"/download", "", nil)
+		Expect(err).To(Succeed())
+		defer resp.Body.Close()
+
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.ContentLength).To(Equal(int64(1024 * 1024)))
+		Expect(resp.Header.Get("Digest")).To(Equal(checksumDigest(checksum)))
+
+		h := sha256.New()
+		_, err = io.Copy(h, resp.Body)
+		Expect(err).To(Succeed())
+		Expect(fmt.Sprintf("%x", h.Sum(nil))).To(Equal(checksum))
+
+		resp, err = http.Post(srv.URL+"/v1/file/999/download", "", nil)
+		Expect(err).To(Succeed())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+	})
+})
@@ -0,0 +1,180 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const metricsNamespace = "airgap"
+
+var (
+	storageBytesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "storage", "bytes"),
+		"Bytes stored by the files of a source, including the versions and tombstones kept.",
+		[]string{"source"}, nil)
+	storageFilesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "storage", "files"),
+		"Number of files of a source that are not deleted.",
+		[]string{"source"}, nil)
+	quotaBytesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "quota", "bytes"),
+		"Maximum bytes stored by the files of a source, the global quota when source is empty.",
+		[]string{"source"}, nil)
+	quotaFilesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "quota", "files"),
+		"Maximum number of files of a source, the global quota when source is empty.",
+		[]string{"source"}, nil)
+)
+
+// usageCollector reads the storage usage and quotas of the store when the
+// metrics are scraped. The totals of all sources have an empty source label,
+// unlimited quotas are not reported.
+type usageCollector struct {
+	log   logr.Logger
+	store database.StoredFileStore
+}
+
+func (c *usageCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- storageBytesDesc
+	ch <- storageFilesDesc
+	ch <- quotaBytesDesc
+	ch <- quotaFilesDesc
+}
+
+func (c *usageCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	usage, err := c.store.Usage(ctx)
+	if err != nil {
+		c.log.Error(err, "failed to read storage usage")
+		ch <- prometheus.NewInvalidMetric(storageBytesDesc, err)
+		return
+	}
+
+	collect := func(source string, used database.Usage, limits database.QuotaLimits) {
+		ch <- prometheus.MustNewConstMetric(storageBytesDesc, prometheus.GaugeValue, float64(used.Bytes), source)
+		ch <- prometheus.MustNewConstMetric(storageFilesDesc, prometheus.GaugeValue, float64(used.Files), source)
+
+		if limits.MaxBytes > 0 {
+			ch <- prometheus.MustNewConstMetric(quotaBytesDesc, prometheus.GaugeValue, float64(limits.MaxBytes), source)
+		}
+		if limits.MaxFiles > 0 {
+			ch <- prometheus.MustNewConstMetric(quotaFilesDesc, prometheus.GaugeValue, float64(limits.MaxFiles), source)
+		}
+	}
+
+	collect("", usage.Usage, usage.Limits)
+	for source, sourceUsage := range usage.Sources {
+		collect(source, sourceUsage.Usage, sourceUsage.Limits)
+	}
+}
+
+// serverMetrics are the metrics of the calls served
+type serverMetrics struct {
+	rpcDuration *prometheus.HistogramVec
+	transferred *prometheus.CounterVec
+}
+
+func newServerMetrics() *serverMetrics {
+	return &serverMetrics{
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "rpc",
+			Name:      "duration_seconds",
+			Help:      "Duration of the gRPC calls by method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		transferred: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "transferred_bytes_total",
+			Help:      "Bytes of file content uploaded and downloaded.",
+		}, []string{"direction"}),
+	}
+}
+
+const (
+	directionUpload   = "upload"
+	directionDownload = "download"
+)
+
+// metricsRegistry returns the registry of the metrics served on /metrics,
+// registering the metrics of the server once
+func (frs *Server) metricsRegistry() *prometheus.Registry {
+	frs.metricsOnce.Do(func() {
+		if frs.Registry == nil {
+			frs.Registry = prometheus.NewRegistry()
+		}
+
+		frs.metrics = newServerMetrics()
+		frs.Registry.MustRegister(
+			&usageCollector{log: frs.Log.WithName("metrics"), store: frs.FileStore},
+			frs.metrics.rpcDuration,
+			frs.metrics.transferred,
+		)
+	})
+	return frs.Registry
+}
+
+// transferred returns the counter of the bytes moved in a direction
+func (frs *Server) transferred(direction string) prometheus.Counter {
+	frs.metricsRegistry()
+	return frs.metrics.transferred.WithLabelValues(direction)
+}
+
+func (frs *Server) observeRPC(method string, start time.Time, err error) {
+	frs.metricsRegistry()
+	frs.metrics.rpcDuration.WithLabelValues(method, status.Code(err).String()).Observe(time.Since(start).Seconds())
+}
+
+// unaryMetrics records the duration and status of unary calls
+func (frs *Server) unaryMetrics(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	start := time.Now()
+	res, err := handler(ctx, req)
+	frs.observeRPC(info.FullMethod, start, err)
+	return res, err
+}
+
+// streamMetrics records the duration and status of streaming calls
+func (frs *Server) streamMetrics(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	frs.observeRPC(info.FullMethod, start, err)
+	return err
+}
+
+func (fs *FileServer) httpMetrics() http.Handler {
+	return promhttp.HandlerFor(fs.metricsRegistry(), promhttp.HandlerOpts{})
+}
@@ -0,0 +1,187 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// collector is an OTLP trace collector keeping the spans exported to it
+type collector struct {
+	collectortrace.UnimplementedTraceServiceServer
+
+	mu    sync.Mutex
+	spans []*tracev1.Span
+}
+
+func (c *collector) Export(_ context.Context, req *collectortrace.ExportTraceServiceRequest) (*collectortrace.ExportTraceServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, resourceSpans := range req.ResourceSpans {
+		for _, scopeSpans := range resourceSpans.ScopeSpans {
+			c.spans = append(c.spans, scopeSpans.Spans...)
+		}
+	}
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+func (c *collector) exported() []*tracev1.Span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*tracev1.Span(nil), c.spans...)
+}
+
+var _ = Describe("observability", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		traced bool
+		store  database.StoredFileStore
+		fs     *FileServer
+		client fileserver.FileServerClient
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+		DeferCleanup(func() { cancel() })
+		traced = false
+	})
+
+	// serve starts the file server with its interceptors, once the tracer
+	// provider of the test is set up
+	JustBeforeEach(func() {
+		srv := newTestServer(testServerOptions{
+			Server:       func(s *Server) { s.ChunkSize = 4 },
+			Interceptors: true,
+			Tracing:      traced,
+		})
+		store, fs, client = srv.Store, srv.FS, srv.Client
+	})
+
+	save := func(content string) string {
+		id, err := store.Save(ctx, &modelsv2.StoredFile{
+			Name:       "metrics.txt",
+			Source:     "redhat-marketplace",
+			SourceType: "report",
+			File:       modelsv2.StoredFileContent{Content: []byte(content)},
+		})
+		Expect(err).To(Succeed())
+		return id
+	}
+
+	It("should export the call durations and bytes transferred", func() {
+		id := save("0123456789")
+
+		stream, err := client.DownloadFile(ctx, &fileserver.DownloadFileRequest{Id: id})
+		Expect(err).To(Succeed())
+		for {
+			_, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).To(Succeed())
+		}
+
+		_, err = client.GetFile(ctx, &fileserver.GetFileRequest{IdLookup: &fileserver.GetFileRequest_Id{Id: "missing"}})
+		Expect(status.Code(err)).NotTo(Equal(codes.OK))
+
+		rec := httptest.NewRecorder()
+		fs.httpMetrics().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		Expect(rec.Body.String()).To(And(
+			ContainSubstring(`airgap_rpc_duration_seconds_count{code="OK",method="/dataservice.v1.fileserver.FileServer/DownloadFile"} 1`),
+			MatchRegexp(`airgap_rpc_duration_seconds_count{code="[A-Za-z]+",method="/dataservice.v1.fileserver.FileServer/GetFile"} 1`),
+			ContainSubstring(`airgap_transferred_bytes_total{direction="download"} 10`),
+			ContainSubstring(`airgap_storage_files{source=""} 1`),
+		))
+	})
+
+	Context("with tracing", func() {
+		var (
+			traces   *collector
+			shutdown func(context.Context) error
+		)
+
+		BeforeEach(func() {
+			traces = &collector{}
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).To(Succeed())
+
+			collectorSrv := grpc.NewServer()
+			collectortrace.RegisterTraceServiceServer(collectorSrv, traces)
+			go collectorSrv.Serve(lis)
+
+			shutdown, err = tracing.Start(ctx, tracing.Config{Endpoint: lis.Addr().String(), Insecure: true, SampleRatio: 1})
+			Expect(err).To(Succeed())
+			traced = true
+
+			DeferCleanup(func() {
+				otel.SetTracerProvider(trace.NewNoopTracerProvider())
+				collectorSrv.Stop()
+			})
+		})
+
+		It("should export the spans of a call and its queries in one trace", func() {
+			id := save("traced")
+
+			_, err := client.GetFile(ctx, &fileserver.GetFileRequest{IdLookup: &fileserver.GetFileRequest_Id{Id: id}})
+			Expect(err).To(Succeed())
+			Expect(shutdown(ctx)).To(Succeed())
+
+			spans := traces.exported()
+
+			var call *tracev1.Span
+			for _, span := range spans {
+				if span.Name == "dataservice.v1.fileserver.FileServer/GetFile" {
+					call = span
+				}
+			}
+			Expect(call).NotTo(BeNil())
+			Expect(call.Kind).To(Equal(tracev1.Span_SPAN_KIND_SERVER))
+
+			// the file saved outside of the call is not traced
+			Expect(spans).To(ContainElement(And(
+				HaveField("Name", "gorm.query"),
+				HaveField("TraceId", Equal(call.TraceId)),
+				HaveField("ParentSpanId", Equal(call.SpanId)),
+			)))
+			for _, span := range spans {
+				Expect(span.TraceId).To(Equal(call.TraceId))
+			}
+		})
+	})
+})
@@ -0,0 +1,276 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	dataservicev1 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	ErrInvalidUploadForm = errors.Sentinel("invalid upload form")
+	ErrInvalidDigest     = errors.Sentinel("invalid Digest header")
+)
+
+const (
+	// uploadFormFile is the form field of the file content
+	uploadFormFile = "file"
+	// maxFormFieldSize bounds the size of the other form fields
+	maxFormFieldSize = 64 * 1024
+)
+
+// httpUploadFile serves POST /v1/files, a multipart/form-data upload for
+// clients without gRPC such as curl:
+//
+//	curl -H "Digest: sha-256=$(openssl dgst -sha256 -binary report.tar.gz | base64)" \
+//	  -F source=redhat-marketplace -F sourceType=report -F metadata=cluster=a \
+//	  -F file=@report.tar.gz https://airgap/v1/files
+//
+// The fields describing the file are sent before the file part, whose content
+// is streamed to storage. The checksum is set by the Digest header or by the
+// checksum and checksumAlgorithm fields, and verified as for UploadFile.
+func (fs *FileServer) httpUploadFile(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	ctx := r.Context()
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("multipart/form-data expected: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	info, size, part, err := readUploadForm(mr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer part.Close()
+
+	if err := setDigest(info, r.Header.Values("Digest")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h, err := fs.checkUpload(ctx, info, size)
+	if err != nil {
+		httpUploadError(w, err)
+		return
+	}
+
+	progress := newProgress(fs.Log, "upload progress")
+	progress.counter = fs.transferred(directionUpload)
+
+	var reader io.Reader = &progressReader{r: &contextReader{ctx: ctx, r: part}, progress: progress}
+	if h != nil {
+		reader = io.TeeReader(reader, h)
+	}
+
+	var file *modelsv2.StoredFile
+
+	prepare := fs.prepareFile(func() *dataservicev1.FileInfo { return info }, hashChecksum(h))
+	event := newAuditEvent(ctx, "UploadFile")
+
+	id, err := fs.FileStore.Upload(database.WithAuditEvent(ctx, event), reader, auditPrepare(event, func(content *modelsv2.StoredFileContent) (*modelsv2.StoredFile, error) {
+		var err error
+		file, err = prepare(content)
+		return file, err
+	}))
+
+	if err != nil {
+		httpUploadError(w, uploadError(err))
+		return
+	}
+
+	data, err := protojson.Marshal(&fileserver.UploadFileResponse{
+		Id:            id,
+		Size:          uint32(file.File.Size),
+		BytesReceived: uint64(progress.Total()),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// httpUploadError writes the status of err, content not matching its
+// checksum is a bad request rather than the server error of DataLoss.
+func httpUploadError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+
+	code := runtime.HTTPStatusFromCode(st.Code())
+	if st.Code() == codes.DataLoss {
+		code = http.StatusBadRequest
+	}
+
+	http.Error(w, st.Message(), code)
+}
+
+// readUploadForm reads the fields of an upload form up to the file part,
+// returned to stream its content. size is the optional size field, only
+// used to check the quota before the content is sent.
+func readUploadForm(mr *multipart.Reader) (info *dataservicev1.FileInfo, size int64, file *multipart.Part, err error) {
+	info = &dataservicev1.FileInfo{Metadata: map[string]string{}}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, 0, nil, ErrFileContentMissing
+		}
+		if err != nil {
+			return nil, 0, nil, errors.Wrap(ErrInvalidUploadForm, err.Error())
+		}
+
+		field := part.FormName()
+		if field == uploadFormFile {
+			if info.Name == "" {
+				info.Name = part.FileName()
+			}
+			if info.MimeType == "" {
+				info.MimeType = part.Header.Get("Content-Type")
+			}
+			return info, size, part, nil
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxFormFieldSize+1))
+		part.Close()
+		if err != nil {
+			return nil, 0, nil, errors.Wrap(ErrInvalidUploadForm, err.Error())
+		}
+		if len(value) > maxFormFieldSize {
+			return nil, 0, nil, errors.Wrapf(ErrInvalidUploadForm, "field %s is larger than %d bytes", field, maxFormFieldSize)
+		}
+
+		if err := setFormField(info, &size, field, string(value)); err != nil {
+			return nil, 0, nil, err
+		}
+	}
+}
+
+// setFormField sets the file info field of an upload form, metadata is sent
+// as key=value and may be repeated.
+func setFormField(info *dataservicev1.FileInfo, size *int64, field, value string) error {
+	switch field {
+	case "name":
+		info.Name = value
+	case "source":
+		info.Source = value
+	case "sourceType":
+		info.SourceType = value
+	case "mimeType":
+		info.MimeType = value
+	case "checksum":
+		info.Checksum = value
+	case "checksumAlgorithm":
+		info.ChecksumAlgorithm = value
+	case "size":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 {
+			return errors.Wrapf(ErrInvalidUploadForm, "invalid size %q", value)
+		}
+		*size = n
+	case "metadata":
+		key, val, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return errors.Wrapf(ErrInvalidUploadForm, "metadata %q is not key=value", value)
+		}
+		info.Metadata[key] = val
+	default:
+		return errors.Wrapf(ErrInvalidUploadForm, "unknown field %s", field)
+	}
+	return nil
+}
+
+// setDigest sets the checksum of info from the Digest header, such as
+// sha-256=<base64>. The first digest in an algorithm of checksumAlgorithms
+// is verified and the others are ignored. A checksum field sent as well
+// must match it.
+func setDigest(info *dataservicev1.FileInfo, header []string) error {
+	if len(header) == 0 {
+		if info.Checksum == "" {
+			return errors.Wrap(ErrInvalidDigest, "a Digest header or a checksum field is required")
+		}
+		return nil
+	}
+
+	for _, digests := range header {
+		for _, digest := range strings.Split(digests, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(digest), "=")
+
+			algorithm := checksumAlgorithm(&dataservicev1.FileInfo{ChecksumAlgorithm: name})
+			newHash, ok := checksumAlgorithms[algorithm]
+			if !ok {
+				continue
+			}
+
+			checksum, err := decodeDigest(value, newHash().Size())
+			if err != nil {
+				return errors.Wrapf(ErrInvalidDigest, "invalid %s digest", name)
+			}
+
+			if info.Checksum != "" && (checksumAlgorithm(info) != algorithm || !strings.EqualFold(info.Checksum, checksum)) {
+				return errors.Wrap(ErrInvalidDigest, "the checksum field does not match the header")
+			}
+
+			info.Checksum = checksum
+			info.ChecksumAlgorithm = algorithm
+			return nil
+		}
+	}
+
+	return errors.Wrap(ErrInvalidDigest, "no supported digest algorithm, use sha-256, sha-384 or sha-512")
+}
+
+// encodeDigest returns the Digest header of the hex sha256 checksum of a
+// download, sha-256=<base64> as RFC 3230.
+func encodeDigest(checksum string) (string, error) {
+	sum, err := hex.DecodeString(checksum)
+	if err != nil {
+		return "", err
+	}
+
+	return "sha-256=" + base64.StdEncoding.EncodeToString(sum), nil
+}
+
+// decodeDigest returns the hex checksum of a digest of size bytes, encoded in
+// base64 as RFC 3230.
+func decodeDigest(value string, size int) (string, error) {
+	sum, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+	if len(sum) != size {
+		return "", errors.Errorf("digest is %d bytes, expected %d", len(sum), size)
+	}
+
+	return hex.EncodeToString(sum), nil
+}
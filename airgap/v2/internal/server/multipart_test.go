@@ -0,0 +1,172 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+)
+
+var _ = Describe("multipart upload", func() {
+	var (
+		ctx     context.Context
+		cancel  context.CancelFunc
+		store   database.StoredFileStore
+		url     string
+		content = []byte(strings.Repeat("cluster-1,usage,42\n", 4096))
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+		DeferCleanup(func() { cancel() })
+
+		srv := newTestServer(testServerOptions{})
+		store, url = srv.Store, srv.URL
+	})
+
+	// post sends the fields, then the content as the file part unless nil
+	post := func(digest string, fields [][2]string, content []byte) (int, string) {
+		body := &bytes.Buffer{}
+		mw := multipart.NewWriter(body)
+		for _, field := range fields {
+			Expect(mw.WriteField(field[0], field[1])).To(Succeed())
+		}
+		if content != nil {
+			part, err := mw.CreateFormFile("file", "usage.csv")
+			Expect(err).To(Succeed())
+			_, err = part.Write(content)
+			Expect(err).To(Succeed())
+		}
+		Expect(mw.Close()).To(Succeed())
+
+		req, err := http.NewRequest(http.MethodPost, url+"/v1/files", body)
+		Expect(err).To(Succeed())
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		if digest != "" {
+			req.Header.Set("Digest", digest)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).To(Succeed())
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		Expect(err).To(Succeed())
+		return resp.StatusCode, string(data)
+	}
+
+	fields := [][2]string{
+		{"source", "redhat-marketplace"},
+		{"sourceType", "usage"},
+		{"metadata", "cluster=a"},
+		{"metadata", "env=prod"},
+	}
+
+	sha256Digest := func(content []byte) string {
+		sum := sha256.Sum256(content)
+		return "sha-256=" + base64.StdEncoding.EncodeToString(sum[:])
+	}
+
+	It("should upload a file with the Digest header", func() {
+		code, body := post(sha256Digest(content), fields, content)
+		Expect(code).To(Equal(http.StatusOK), body)
+
+		res := map[string]interface{}{}
+		Expect(json.Unmarshal([]byte(body), &res)).To(Succeed())
+		Expect(res).To(HaveKeyWithValue("size", BeNumerically("==", len(content))))
+
+		file, rc, err := store.Open(ctx, res["id"].(string))
+		Expect(err).To(Succeed())
+		stored, err := io.ReadAll(rc)
+		rc.Close()
+		Expect(err).To(Succeed())
+		Expect(stored).To(Equal(content))
+
+		Expect(file.Name).To(Equal("usage.csv"))
+		Expect(file.SourceType).To(Equal("usage"))
+		Expect(file.File.MimeType).To(Equal("application/octet-stream"))
+		Expect(file.Metadata).To(HaveLen(2))
+
+		events, _, err := store.ListAuditEvents(ctx)
+		Expect(err).To(Succeed())
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Method).To(Equal("UploadFile"))
+		Expect(events[0].ChecksumAfter).To(Equal(file.File.Checksum))
+	})
+
+	It("should verify the other algorithms", func() {
+		sum := sha512.Sum512(content)
+		code, body := post("md5=abc, sha-512="+base64.StdEncoding.EncodeToString(sum[:]), append(fields, [2]string{"name", "usage-a.csv"}), content)
+		Expect(code).To(Equal(http.StatusOK), body)
+
+		files, _, err := store.List(ctx)
+		Expect(err).To(Succeed())
+		Expect(files).To(HaveLen(1))
+		Expect(files[0].Name).To(Equal("usage-a.csv"))
+
+		By("accepting the checksum field without the header")
+		code, body = post("", append(fields, [2]string{"checksum", fmt.Sprintf("%x", sha256.Sum256(content))}), content)
+		Expect(code).To(Equal(http.StatusOK), body)
+	})
+
+	It("should not save content not matching its digest", func() {
+		code, body := post(sha256Digest([]byte("other")), fields, content)
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(ContainSubstring(ErrFileChecksumIncorrect.Error()))
+
+		files, _, err := store.List(ctx)
+		Expect(err).To(Succeed())
+		Expect(files).To(BeEmpty())
+	})
+
+	It("should reject invalid uploads", func() {
+		hexDigest := fmt.Sprintf("sha-256=%x", sha256.Sum256(content))
+
+		for digest, fields := range map[string][][2]string{
+			"":                    fields,
+			"md5=abc":             fields,
+			"sha-256=abc":         fields,
+			hexDigest:             fields,
+			sha256Digest(content): append(fields, [2]string{"owner", "jane"}),
+			sha256Digest(nil):     append(fields, [2]string{"checksum", "abc"}),
+		} {
+			code, body := post(digest, fields, content)
+			Expect(code).To(Equal(http.StatusBadRequest), "%s %v: %s", digest, fields, body)
+		}
+
+		code, body := post(sha256Digest(content), fields, nil)
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(ContainSubstring(ErrFileContentMissing.Error()))
+
+		resp, err := http.Post(url+"/v1/files", "application/json", strings.NewReader("{}"))
+		Expect(err).To(Succeed())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+})
@@ -0,0 +1,69 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// checkQuota rejects an upload for source before its content is sent when
+// storing bytes more would exceed a quota
+func (fs *FileServer) checkQuota(ctx context.Context, source string, bytes int64) error {
+	// the content of a file is never empty
+	if bytes < 1 {
+		bytes = 1
+	}
+
+	err := fs.FileStore.CheckQuota(ctx, source, bytes)
+	if err == nil {
+		return nil
+	}
+
+	if quotaErr := (&database.QuotaExceededError{}); errors.As(err, &quotaErr) {
+		return quotaError(quotaErr)
+	}
+
+	return status.Errorf(codes.Internal, "failed to check quota: %s", err)
+}
+
+// quotaError reports an exceeded quota as ResourceExhausted, with the quota
+// exceeded as a QuotaFailure detail
+func quotaError(err *database.QuotaExceededError) error {
+	subject := "global"
+	if err.Source != "" {
+		subject = fmt.Sprintf("source:%s", err.Source)
+	}
+
+	st := status.New(codes.ResourceExhausted, err.Error())
+	detailed, detailsErr := st.WithDetails(&errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{{
+			Subject: subject,
+			Description: fmt.Sprintf("%d %s used of %d, %d requested",
+				err.Used, err.Resource, err.Limit, err.Requested),
+		}},
+	})
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
@@ -0,0 +1,130 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	dataservicev1 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Describe("quotas", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		fs     *FileServer
+		client fileserver.FileServerClient
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+		DeferCleanup(func() { cancel() })
+
+		srv := newTestServer(testServerOptions{
+			Store: database.FileStoreConfig{
+				Quotas: database.Quotas{
+					Global:  database.QuotaLimits{MaxFiles: 10},
+					Sources: map[string]database.QuotaLimits{"small": {MaxBytes: 10}},
+				},
+			},
+		})
+		fs, client = srv.FS, srv.Client
+	})
+
+	upload := func(name string, content []byte) error {
+		stream, err := client.UploadFile(ctx)
+		Expect(err).To(Succeed())
+
+		err = stream.Send(&fileserver.UploadFileRequest{
+			Data: &fileserver.UploadFileRequest_Info{Info: &dataservicev1.FileInfo{
+				Name:       name,
+				Source:     "small",
+				SourceType: "report",
+				Checksum:   fmt.Sprintf("%x", sha256.Sum256(content)),
+			}},
+		})
+		if err == nil {
+			// a rejected upload fails the send, the status is returned by CloseAndRecv
+			stream.Send(&fileserver.UploadFileRequest{
+				Data: &fileserver.UploadFileRequest_ChunkData{ChunkData: content},
+			})
+		}
+
+		_, err = stream.CloseAndRecv()
+		return err
+	}
+
+	violation := func(err error) *errdetails.QuotaFailure_Violation {
+		st := status.Convert(err)
+		Expect(st.Code()).To(Equal(codes.ResourceExhausted), "unexpected error %v", err)
+
+		for _, detail := range st.Details() {
+			if failure, ok := detail.(*errdetails.QuotaFailure); ok {
+				Expect(failure.Violations).To(HaveLen(1))
+				return failure.Violations[0]
+			}
+		}
+
+		Fail("the error has no quota failure detail")
+		return nil
+	}
+
+	It("should reject uploads over the quota of their source", func() {
+		Expect(upload("first", []byte("12345678"))).To(Succeed())
+
+		err := upload("second", []byte("12345"))
+		Expect(violation(err).Subject).To(Equal("source:small"))
+		Expect(violation(err).Description).To(Equal("8 bytes used of 10, 5 requested"))
+
+		_, err = client.StartUpload(ctx, &fileserver.StartUploadRequest{
+			Info:      &dataservicev1.FileInfo{Name: "third", Source: "small", SourceType: "report"},
+			TotalSize: 3,
+		})
+		Expect(violation(err).Subject).To(Equal("source:small"))
+
+		By("rejecting an upload before its content once the quota is used")
+		Expect(upload("fourth", []byte("12"))).To(Succeed())
+		err = upload("fifth", []byte("1"))
+		Expect(violation(err).Description).To(Equal("10 bytes used of 10, 1 requested"))
+	})
+
+	It("should export the storage usage and quotas", func() {
+		Expect(upload("first", []byte("12345678"))).To(Succeed())
+
+		rec := httptest.NewRecorder()
+		fs.httpMetrics().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		Expect(rec.Body.String()).To(And(
+			ContainSubstring(`airgap_storage_bytes{source=""} 8`),
+			ContainSubstring(`airgap_storage_bytes{source="small"} 8`),
+			ContainSubstring(`airgap_storage_files{source="small"} 1`),
+			ContainSubstring(`airgap_quota_files{source=""} 10`),
+			ContainSubstring(`airgap_quota_bytes{source="small"} 10`),
+		))
+	})
+})
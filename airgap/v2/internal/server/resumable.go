@@ -0,0 +1,170 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"emperror.dev/errors"
+	dataservicev1 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (fs *FileServer) StartUpload(ctx context.Context, req *fileserver.StartUploadRequest) (*fileserver.StartUploadResponse, error) {
+	if req.Info == nil {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			ErrFileInfoMissing.Error(),
+		)
+	}
+
+	if _, err := fs.checkUpload(ctx, req.Info, int64(req.TotalSize)); err != nil {
+		return nil, err
+	}
+
+	session, err := modelsv2.UploadSessionFromProto(req.Info, int64(req.TotalSize))
+	if err != nil {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			fmt.Sprintf("invalid file info: %v", err),
+		)
+	}
+
+	id, err := fs.FileStore.StartUpload(ctx, session)
+	if err != nil {
+		return nil, uploadSessionError(err)
+	}
+
+	fs.Log.V(2).Info("upload started", "session", id, "name", session.Name, "size", session.TotalSize)
+
+	return &fileserver.StartUploadResponse{SessionId: id}, nil
+}
+
+func (fs *FileServer) PutChunk(ctx context.Context, req *fileserver.PutChunkRequest) (*fileserver.PutChunkResponse, error) {
+	committed, err := fs.FileStore.PutUploadChunk(ctx, req.SessionId, int64(req.Offset), bytes.NewReader(req.ChunkData))
+	if err != nil {
+		return nil, uploadSessionError(err)
+	}
+
+	fs.transferred(directionUpload).Add(float64(len(req.ChunkData)))
+
+	return &fileserver.PutChunkResponse{CommittedOffset: uint64(committed)}, nil
+}
+
+func (fs *FileServer) GetUploadStatus(ctx context.Context, req *fileserver.GetUploadStatusRequest) (*fileserver.GetUploadStatusResponse, error) {
+	session, err := fs.FileStore.GetUpload(ctx, req.SessionId)
+	if err != nil {
+		return nil, uploadSessionError(err)
+	}
+
+	res := &fileserver.GetUploadStatusResponse{
+		SessionId:       session.ID,
+		CommittedOffset: uint64(session.CommittedOffset),
+		TotalSize:       uint64(session.TotalSize),
+	}
+
+	if session.FileID != 0 {
+		res.FileId = fmt.Sprintf("%d", session.FileID)
+	}
+
+	return res, nil
+}
+
+func (fs *FileServer) FinalizeUpload(ctx context.Context, req *fileserver.FinalizeUploadRequest) (*fileserver.FinalizeUploadResponse, error) {
+	session, rc, err := fs.FileStore.OpenUpload(ctx, req.SessionId)
+	if err != nil {
+		return nil, uploadSessionError(err)
+	}
+	defer rc.Close()
+
+	finfo, err := modelsv2.UploadSessionToProto(session)
+	if err != nil {
+		return nil, uploadSessionError(err)
+	}
+
+	h, err := newChecksumHash(finfo)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader = &contextReader{ctx: ctx, r: rc}
+	if h != nil {
+		reader = io.TeeReader(reader, h)
+	}
+
+	event := newAuditEvent(ctx, "FinalizeUpload")
+	prepare := auditPrepare(event, fs.prepareFile(func() *dataservicev1.FileInfo { return finfo }, hashChecksum(h)))
+
+	id, err := fs.FileStore.FinishUpload(database.WithAuditEvent(ctx, event), session.ID, reader, prepare)
+	if err != nil {
+		err = uploadSessionError(err)
+
+		// sending the chunks again would not fix the content
+		if status.Code(err) == codes.DataLoss {
+			if abortErr := fs.FileStore.AbortUpload(context.Background(), session.ID); abortErr != nil {
+				fs.Log.Error(abortErr, "failed to abort upload", "session", session.ID)
+			}
+		}
+
+		return nil, err
+	}
+
+	fs.Log.V(2).Info("upload finalized", "session", session.ID, "id", id, "bytes", session.CommittedOffset)
+
+	return &fileserver.FinalizeUploadResponse{
+		Id:            id,
+		BytesReceived: uint64(session.CommittedOffset),
+	}, nil
+}
+
+func (fs *FileServer) AbortUpload(ctx context.Context, req *fileserver.AbortUploadRequest) (*fileserver.AbortUploadResponse, error) {
+	if err := fs.FileStore.AbortUpload(ctx, req.SessionId); err != nil {
+		return nil, uploadSessionError(err)
+	}
+
+	return &fileserver.AbortUploadResponse{}, nil
+}
+
+// uploadSessionError maps the errors of upload sessions to status codes a
+// client can act on, an Aborted or OutOfRange chunk is resent from the
+// committed offset.
+func uploadSessionError(err error) error {
+	var code codes.Code
+
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, database.ErrUploadOffset):
+		code = codes.OutOfRange
+	case errors.Is(err, database.ErrUploadConflict):
+		code = codes.Aborted
+	case errors.Is(err, database.ErrUploadFinished),
+		errors.Is(err, database.ErrUploadIncomplete):
+		code = codes.FailedPrecondition
+	case errors.Is(err, database.ErrInvalidInput):
+		code = codes.InvalidArgument
+	default:
+		return uploadError(err)
+	}
+
+	return status.Errorf(code, "%v Details: %v", err, errors.GetDetails(err))
+}
@@ -16,13 +16,19 @@ package server
 
 import (
 	"context"
+	"crypto/ed25519"
 	"net"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
@@ -37,9 +43,34 @@ type Server struct {
 	APIEndpoint     string
 	GatewayEndpoint string
 
+	// ChunkSize is the size in bytes of the chunks streamed to clients
+	ChunkSize int
+
+	// WatchPollInterval is how often watchers read the changes made through
+	// the other nodes, the changes made through this server are sent at once.
+	// It is 1s when 0.
+	WatchPollInterval time.Duration
+
+	// TokenValidator authenticates callers by their bearer token, the API is
+	// not authenticated when it is nil
+	TokenValidator TokenValidator
+	// RoleBindings grants roles to authenticated callers
+	RoleBindings RoleBindings
+
+	// BundleSigningKey signs the bundles of ExportFiles, files can not be
+	// exported when it is nil
+	BundleSigningKey ed25519.PrivateKey
+
+	// Registry holds the metrics served on /metrics by the gateway, the
+	// metrics of the server are added to it, a registry is created when nil
+	Registry *prometheus.Registry
+
 	Health                  *health.Server
 	APIListenerProvider     func(addr string) (net.Listener, error)
 	GatewayListenerProvider func(addr string) (net.Listener, error)
+
+	metricsOnce sync.Once
+	metrics     *serverMetrics
 }
 
 func (frs *Server) Start(ctx context.Context) error {
@@ -69,7 +100,7 @@ func (frs *Server) Start(ctx context.Context) error {
 			return err
 		}
 
-		grpcServer := grpc.NewServer()
+		grpcServer := grpc.NewServer(frs.serverOptions()...)
 
 		fileserver.RegisterFileServerServer(grpcServer, fs)
 		grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
@@ -86,7 +117,12 @@ func (frs *Server) Start(ctx context.Context) error {
 		}
 
 		mux := runtime.NewServeMux()
-		opts := []grpc.DialOption{grpc.WithInsecure()}
+		// the trace of a gateway request continues in the gRPC server
+		opts := []grpc.DialOption{
+			grpc.WithInsecure(),
+			grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),
+			grpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor()),
+		}
 
 		err = fileserver.RegisterFileServerHandlerFromEndpoint(ctx, mux, frs.APIEndpoint, opts)
 		if err != nil {
@@ -99,9 +135,39 @@ func (frs *Server) Start(ctx context.Context) error {
 		}
 
 		// Start HTTP server (and proxy calls to gRPC server endpoint)
-		return http.Serve(lis, mux)
+		return http.Serve(lis, otelhttp.NewHandler(mux, "gateway"))
 	})
 
 	// wait
 	return group.Wait()
 }
+
+func (frs *Server) authorizer() *authorizer {
+	if frs.TokenValidator == nil {
+		return nil
+	}
+
+	return &authorizer{
+		log:       frs.Log.WithName("auth"),
+		validator: frs.TokenValidator,
+		bindings:  frs.RoleBindings,
+	}
+}
+
+// serverOptions returns the interceptors of the gRPC server
+func (frs *Server) serverOptions() []grpc.ServerOption {
+	// spans are only exported when a tracer provider is set up, the
+	// metrics include the calls rejected by the authorizer
+	unary := []grpc.UnaryServerInterceptor{otelgrpc.UnaryServerInterceptor(), frs.unaryMetrics}
+	stream := []grpc.StreamServerInterceptor{otelgrpc.StreamServerInterceptor(), frs.streamMetrics}
+
+	if auth := frs.authorizer(); auth != nil {
+		unary = append(unary, auth.UnaryInterceptor)
+		stream = append(stream, auth.StreamInterceptor)
+	}
+
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+}
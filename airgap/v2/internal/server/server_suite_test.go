@@ -0,0 +1,185 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestServer(t *testing.T) {
+	logger := zap.New(zap.WriteTo(GinkgoWriter))
+	logf.SetLogger(logger)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Server Suite")
+}
+
+// fakeValidator accepts the tokens it knows
+type fakeValidator map[string]*UserInfo
+
+func (f fakeValidator) ValidateToken(_ context.Context, token string) (*UserInfo, error) {
+	user, ok := f[token]
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	return user, nil
+}
+
+// testUsers are the tokens accepted by a test server with Auth
+var testUsers = fakeValidator{
+	"reader-token":   {Username: "system:serviceaccount:rhm:reader"},
+	"uploader-token": {Username: "system:serviceaccount:rhm:reporter"},
+	"admin-token":    {Username: "jane", Groups: []string{"system:authenticated", "rhm-admins"}},
+	"nobody-token":   {Username: "nobody", Groups: []string{"system:authenticated"}},
+}
+
+// testRoleBindings are the roles of testUsers
+var testRoleBindings = map[Role][]string{
+	RoleReader:   {"system:serviceaccount:rhm:reader"},
+	RoleUploader: {"system:serviceaccount:rhm:reporter"},
+	RoleAdmin:    {"group:rhm-admins"},
+}
+
+// testServerOptions configures the file server started by newTestServer
+type testServerOptions struct {
+	// Store configures the file store, its blob store is set by newTestServer
+	Store database.FileStoreConfig
+	// Server sets the fields of the server before it is started
+	Server func(*Server)
+	// Wrap wraps the file store served, such as to fail some of its calls
+	Wrap func(database.StoredFileStore) database.StoredFileStore
+	// Interceptors serves gRPC with the interceptors of the file server
+	Interceptors bool
+	// Auth authenticates the tokens of testUsers and authorizes them with
+	// testRoleBindings, gRPC is served with the interceptors
+	Auth bool
+	// Tracing traces the queries of the database
+	Tracing bool
+}
+
+// testServer is a file server on a sqlite database and a local blob store in
+// temporary directories, served over an in-memory gRPC connection and HTTP
+type testServer struct {
+	DB     *gorm.DB
+	Blobs  blobstore.BlobStore
+	Store  database.StoredFileStore
+	FS     *FileServer
+	Client fileserver.FileServerClient
+	Conn   *grpc.ClientConn
+	// URL is the address of the HTTP routes
+	URL string
+	// BlobDir is the directory of the local blob store
+	BlobDir string
+}
+
+// newTestServer starts a file server, it is stopped once the test ends
+func newTestServer(opts testServerOptions) *testServer {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(GinkgoT().TempDir(), "server.gorm.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	Expect(err).To(Succeed())
+	DeferCleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	if opts.Tracing {
+		Expect(db.Use(database.Tracing{})).To(Succeed())
+	}
+
+	blobDir := GinkgoT().TempDir()
+	blobs, err := blobstore.NewLocal(blobDir)
+	Expect(err).To(Succeed())
+	Expect(database.Migrate(db, blobs)).To(Succeed())
+
+	config := opts.Store
+	config.BlobStore = blobs
+	store, closer := database.New(db, config)
+	DeferCleanup(func() { Expect(closer.Close()).To(Succeed()) })
+
+	if opts.Wrap != nil {
+		store = opts.Wrap(store)
+	}
+
+	fs := &FileServer{Server: &Server{Log: logf.Log.WithName("fileserver"), FileStore: store}}
+	if opts.Auth {
+		fs.TokenValidator = testUsers
+		fs.RoleBindings = NewRoleBindings(testRoleBindings)
+	}
+	if opts.Server != nil {
+		opts.Server(fs.Server)
+	}
+
+	var serverOpts []grpc.ServerOption
+	if opts.Interceptors || opts.Auth {
+		serverOpts = fs.serverOptions()
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	grpcSrv := grpc.NewServer(serverOpts...)
+	fileserver.RegisterFileServerServer(grpcSrv, fs)
+	grpc_health_v1.RegisterHealthServer(grpcSrv, health.NewServer())
+	go grpcSrv.Serve(listener)
+	DeferCleanup(grpcSrv.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	Expect(err).To(Succeed())
+	DeferCleanup(conn.Close)
+
+	mux := runtime.NewServeMux()
+	Expect(fs.RegisterHTTPRoutes(mux)).To(Succeed())
+	httpSrv := httptest.NewServer(mux)
+	DeferCleanup(func() {
+		httpSrv.CloseClientConnections()
+		httpSrv.Close()
+	})
+
+	return &testServer{
+		DB:      db,
+		Blobs:   blobs,
+		BlobDir: blobDir,
+		Store:   store,
+		FS:      fs,
+		Client:  fileserver.NewFileServerClient(conn),
+		Conn:    conn,
+		URL:     httpSrv.URL,
+	}
+}
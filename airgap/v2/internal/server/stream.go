@@ -0,0 +1,235 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	dataservicev1 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultChunkSize = 32 * 1024
+
+	// progressInterval is how many bytes are transferred between progress logs
+	progressInterval = 8 * 1024 * 1024
+)
+
+const defaultChecksumAlgorithm = "sha256"
+
+// checksumAlgorithms a client may declare for the checksum of an upload
+var checksumAlgorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha384": sha512.New384,
+	"sha512": sha512.New,
+}
+
+// uploadReader exposes the chunks of an upload stream as an io.Reader, so the
+// content can be streamed to storage without holding it in memory. The file
+// info may be sent at any point of the stream and is kept aside, unless it
+// declares a checksum algorithm other than sha256 that has to hash the
+// content from the start.
+type uploadReader struct {
+	stream   fileserver.FileServer_UploadFileServer
+	info     *dataservicev1.FileInfo
+	buf      []byte
+	progress *progress
+	hash     hash.Hash
+
+	// checkInfo rejects the upload once its file info is received
+	checkInfo func(info *dataservicev1.FileInfo) error
+}
+
+func (u *uploadReader) Read(p []byte) (int, error) {
+	for len(u.buf) == 0 {
+		if err := u.stream.Context().Err(); err != nil {
+			return 0, err
+		}
+
+		req, err := u.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+
+		if info := req.GetInfo(); info != nil {
+			if err := u.setInfo(info); err != nil {
+				return 0, err
+			}
+		}
+
+		u.buf = req.GetChunkData()
+	}
+
+	n := copy(p, u.buf)
+	u.buf = u.buf[n:]
+	u.progress.Add(n)
+
+	if u.hash != nil {
+		u.hash.Write(p[:n])
+	}
+
+	return n, nil
+}
+
+func (u *uploadReader) setInfo(info *dataservicev1.FileInfo) error {
+	u.info = info
+
+	if u.checkInfo != nil {
+		if err := u.checkInfo(info); err != nil {
+			return err
+		}
+	}
+
+	h, err := newChecksumHash(info)
+	if err != nil || h == nil {
+		return err
+	}
+
+	if u.progress.Total() != 0 {
+		return status.Errorf(
+			codes.InvalidArgument,
+			"file info must be sent before the content to use checksum algorithm %q", info.ChecksumAlgorithm,
+		)
+	}
+
+	u.hash = h
+	return nil
+}
+
+// checksum returns the checksum of the content read in the algorithm declared
+// by the client, sha256 is calculated by the store and returned as is.
+func (u *uploadReader) checksum(sha256sum string) string {
+	return hashChecksum(u.hash)(sha256sum)
+}
+
+// hashChecksum returns the checksum of h, or the sha256 checksum when h is nil.
+func hashChecksum(h hash.Hash) func(sha256sum string) string {
+	return func(sha256sum string) string {
+		if h == nil {
+			return sha256sum
+		}
+		return fmt.Sprintf("%x", h.Sum(nil))
+	}
+}
+
+// checkUpload rejects an upload of size bytes described by info before its
+// content is sent, an unknown checksum algorithm or a size over quota. It
+// returns the hash of the checksum algorithm as newChecksumHash.
+func (fs *FileServer) checkUpload(ctx context.Context, info *dataservicev1.FileInfo, size int64) (hash.Hash, error) {
+	h, err := newChecksumHash(info)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fs.checkQuota(ctx, info.Source, size); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// newChecksumHash returns the hash of the checksum algorithm declared in info,
+// or nil for sha256 that is always calculated by the store.
+func newChecksumHash(info *dataservicev1.FileInfo) (hash.Hash, error) {
+	algorithm := checksumAlgorithm(info)
+	if algorithm == defaultChecksumAlgorithm {
+		return nil, nil
+	}
+
+	newHash, ok := checksumAlgorithms[algorithm]
+	if !ok {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"unsupported checksum algorithm %q", info.ChecksumAlgorithm,
+		)
+	}
+
+	return newHash(), nil
+}
+
+// checksumAlgorithm normalizes the declared algorithm, sha-256 and SHA256 are both sha256
+func checksumAlgorithm(info *dataservicev1.FileInfo) string {
+	algorithm := strings.ToLower(strings.ReplaceAll(info.ChecksumAlgorithm, "-", ""))
+	if algorithm == "" {
+		return defaultChecksumAlgorithm
+	}
+	return algorithm
+}
+
+// progress counts the bytes transferred and logs them periodically. The
+// bytes are also added to the counter when set.
+type progress struct {
+	log     logr.Logger
+	msg     string
+	total   int64
+	next    int64
+	counter prometheus.Counter
+}
+
+func newProgress(log logr.Logger, msg string) *progress {
+	return &progress{log: log, msg: msg, next: progressInterval}
+}
+
+func (p *progress) Add(n int) {
+	p.total += int64(n)
+	if p.counter != nil {
+		p.counter.Add(float64(n))
+	}
+
+	if p.total >= p.next {
+		p.log.V(2).Info(p.msg, "bytes", p.total)
+		p.next = p.total + progressInterval
+	}
+}
+
+func (p *progress) Total() int64 {
+	return p.total
+}
+
+// contextReader stops reading once the context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// progressReader adds the bytes read to progress.
+type progressReader struct {
+	r        io.Reader
+	progress *progress
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.progress.Add(n)
+	return n, err
+}
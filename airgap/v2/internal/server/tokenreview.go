@@ -0,0 +1,131 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	authenticationv1client "k8s.io/client-go/kubernetes/typed/authentication/v1"
+	"k8s.io/client-go/rest"
+)
+
+// tokenCacheSize bounds the reviews kept, expired reviews are dropped once it is reached
+const tokenCacheSize = 1024
+
+// TokenReviewValidator validates tokens with the Kubernetes TokenReview API.
+// Reviews are cached for CacheTTL so chunked uploads don't review the token
+// on every request.
+type TokenReviewValidator struct {
+	Client    authenticationv1client.TokenReviewInterface
+	Audiences []string
+	CacheTTL  time.Duration
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]tokenReview
+}
+
+type tokenReview struct {
+	user    *UserInfo
+	expires time.Time
+}
+
+var _ TokenValidator = &TokenReviewValidator{}
+
+func NewTokenReviewValidator(config *rest.Config, audiences []string) (*TokenReviewValidator, error) {
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create kubernetes client")
+	}
+
+	return &TokenReviewValidator{
+		Client:    client.AuthenticationV1().TokenReviews(),
+		Audiences: audiences,
+		CacheTTL:  time.Minute,
+	}, nil
+}
+
+func (v *TokenReviewValidator) ValidateToken(ctx context.Context, token string) (*UserInfo, error) {
+	key := sha256.Sum256([]byte(token))
+
+	if user := v.cached(key); user != nil {
+		return user, nil
+	}
+
+	review, err := v.Client.Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: v.Audiences,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create token review")
+	}
+
+	if !review.Status.Authenticated {
+		return nil, errors.WithDetails(ErrUnauthenticated, "reason", review.Status.Error)
+	}
+
+	user := &UserInfo{
+		Username: review.Status.User.Username,
+		Groups:   review.Status.User.Groups,
+	}
+
+	v.store(key, user)
+	return user, nil
+}
+
+func (v *TokenReviewValidator) cached(key [sha256.Size]byte) *UserInfo {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	review, ok := v.cache[key]
+	if !ok || time.Now().After(review.expires) {
+		return nil
+	}
+	return review.user
+}
+
+func (v *TokenReviewValidator) store(key [sha256.Size]byte, user *UserInfo) {
+	if v.CacheTTL <= 0 {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.cache == nil {
+		v.cache = map[[sha256.Size]byte]tokenReview{}
+	}
+
+	now := time.Now()
+	if len(v.cache) >= tokenCacheSize {
+		for k, review := range v.cache {
+			if now.After(review.expires) {
+				delete(v.cache, k)
+			}
+		}
+	}
+
+	if len(v.cache) < tokenCacheSize {
+		v.cache[key] = tokenReview{user: user, expires: now.Add(v.CacheTTL)}
+	}
+}
@@ -0,0 +1,317 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	dataservicev1 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultWatchPollInterval = time.Second
+
+	// maxWatchRetryInterval bounds the wait between reads of the changes
+	// while the database is unavailable, such as during a leader election
+	maxWatchRetryInterval = 30 * time.Second
+
+	watchBatchSize = 100
+
+	// sseKeepAliveInterval is how often an idle event stream sends a comment
+	// so proxies do not close it
+	sseKeepAliveInterval = 15 * time.Second
+)
+
+var fileEventTypes = map[modelsv2.FileEventType]fileserver.FileEvent_Type{
+	modelsv2.FileEventCreated: fileserver.FileEvent_CREATED,
+	modelsv2.FileEventUpdated: fileserver.FileEvent_UPDATED,
+	modelsv2.FileEventDeleted: fileserver.FileEvent_DELETED,
+}
+
+func (fs *FileServer) WatchFiles(req *fileserver.WatchFilesRequest, stream fileserver.FileServer_WatchFilesServer) error {
+	return fs.watchFiles(stream.Context(), req, stream.Send)
+}
+
+// watchFiles sends the changes to files after the revision of req until ctx is
+// done. The changes are read from the database once a change is committed by
+// this server, and every poll interval for the changes committed through the
+// other nodes. The watch outlives the database being unavailable, it resumes
+// from the last revision sent once a new leader is elected.
+func (fs *FileServer) watchFiles(ctx context.Context, req *fileserver.WatchFilesRequest, send func(*fileserver.WatchFilesResponse) error) error {
+	opts := []database.ListOption{}
+	if req.Filter != "" {
+		expr, err := fileserver.ParseFilter(req.Filter)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "Filter is formatted incorrectly. Error: %s", err)
+		}
+		opts = append(opts, database.ApplyFilter(expr))
+	}
+
+	poll := fs.WatchPollInterval
+	if poll <= 0 {
+		poll = defaultWatchPollInterval
+	}
+
+	var (
+		revision = req.Revision
+		retry    = poll
+		started  bool
+	)
+
+	for {
+		// taken before reading, so a change committed meanwhile is not missed
+		changed := fs.FileStore.Changed()
+
+		changes, next, err := fs.FileStore.FileEvents(ctx, revision, watchBatchSize, opts...)
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+
+			if err := watchError(err); err != nil {
+				return err
+			}
+
+			fs.Log.Error(err, "failed to read file events, retrying", "revision", revision, "retryIn", retry)
+			if err := sleep(ctx, retry); err != nil {
+				return status.FromContextError(err).Err()
+			}
+
+			retry *= 2
+			if retry > maxWatchRetryInterval {
+				retry = maxWatchRetryInterval
+			}
+			continue
+		}
+		retry = poll
+
+		if !started || next != revision {
+			res, err := watchResponse(changes, next)
+			if err != nil {
+				return err
+			}
+
+			if err := send(res); err != nil {
+				return err
+			}
+			started = true
+		}
+
+		// more changes may be waiting
+		if next != revision {
+			revision = next
+			continue
+		}
+
+		timer := time.NewTimer(poll)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return status.FromContextError(ctx.Err()).Err()
+		case <-changed:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// watchError maps the errors the watch can not recover from to status codes,
+// it returns nil for the errors worth retrying.
+func watchError(err error) error {
+	switch {
+	case errors.Is(err, database.ErrInvalidFilter), errors.Is(err, database.ErrInvalidRevision):
+		return status.Errorf(codes.InvalidArgument, "%s", err)
+	case errors.Is(err, database.ErrRevisionCompacted):
+		return status.Errorf(codes.OutOfRange, "%s, list the files again and watch from a new revision", err)
+	}
+
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func watchResponse(changes []database.FileChange, revision string) (*fileserver.WatchFilesResponse, error) {
+	res := &fileserver.WatchFilesResponse{
+		Events:   make([]*fileserver.FileEvent, 0, len(changes)),
+		Revision: revision,
+	}
+
+	for i := range changes {
+		event, err := fileEventToProto(&changes[i])
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert file event: %s", err)
+		}
+		res.Events = append(res.Events, event)
+	}
+
+	return res, nil
+}
+
+func fileEventToProto(change *database.FileChange) (*fileserver.FileEvent, error) {
+	event := &fileserver.FileEvent{
+		Type:     fileEventTypes[change.Event.Type],
+		Revision: change.Revision,
+		Time:     timestamppb.New(change.Event.CreatedAt),
+	}
+
+	if change.File == nil {
+		event.File = &dataservicev1.FileInfo{
+			Id:         fmt.Sprintf("%d", change.Event.FileID),
+			Name:       change.Event.Name,
+			Source:     change.Event.Source,
+			SourceType: change.Event.SourceType,
+			Version:    change.Event.Version,
+		}
+		return event, nil
+	}
+
+	file, err := modelsv2.StoredFileToProto(change.File)
+	if err != nil {
+		return nil, err
+	}
+	event.File = file
+
+	return event, nil
+}
+
+// httpWatchFiles serves WatchFiles as server-sent events. Each change is an
+// event named after its type, with the FileEvent as data and its revision as
+// id, so an EventSource reconnecting resumes after the last change received
+// with the Last-Event-ID header. A revision event is sent when the revision
+// moves past changes not matching the filter.
+func (fs *FileServer) httpWatchFiles(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	req := &fileserver.WatchFilesRequest{
+		Filter:   r.URL.Query().Get("filter"),
+		Revision: r.URL.Query().Get("revision"),
+	}
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		req.Revision = lastEventID
+	}
+
+	var (
+		mu      sync.Mutex
+		started bool
+	)
+
+	write := func(format string, args ...interface{}) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !started {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("X-Accel-Buffering", "no")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+
+	marshaler := protojson.MarshalOptions{EmitUnpopulated: true}
+
+	send := func(res *fileserver.WatchFilesResponse) error {
+		id := ""
+		for _, event := range res.Events {
+			data, err := marshaler.Marshal(event)
+			if err != nil {
+				return err
+			}
+
+			if err := write("id: %s\nevent: %s\ndata: %s\n\n", event.Revision, strings.ToLower(event.Type.String()), data); err != nil {
+				return err
+			}
+			id = event.Revision
+		}
+
+		if id == res.Revision {
+			return nil
+		}
+
+		return write("id: %s\nevent: revision\ndata: %s\n\n", res.Revision, strconv.Quote(res.Revision))
+	}
+
+	go func() {
+		ticker := time.NewTicker(sseKeepAliveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := write(": keepalive\n\n"); err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	err := fs.watchFiles(ctx, req, send)
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+
+	mu.Lock()
+	wasStarted := started
+	mu.Unlock()
+
+	st := status.Convert(err)
+	if !wasStarted {
+		http.Error(w, st.Message(), runtime.HTTPStatusFromCode(st.Code()))
+		return
+	}
+
+	write("event: error\ndata: %s\n\n", strconv.Quote(st.Message()))
+}
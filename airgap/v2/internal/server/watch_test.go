@@ -0,0 +1,283 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// unavailableStore fails reading the file events while down is set, as a
+// database electing a new leader
+type unavailableStore struct {
+	database.StoredFileStore
+	down  atomic.Bool
+	reads atomic.Int32
+}
+
+func (s *unavailableStore) FileEvents(ctx context.Context, revision string, limit int, opts ...database.ListOption) ([]database.FileChange, string, error) {
+	s.reads.Add(1)
+	if s.down.Load() {
+		return nil, "", errors.New("no leader")
+	}
+	return s.StoredFileStore.FileEvents(ctx, revision, limit, opts...)
+}
+
+var _ = Describe("watch", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		srv    *testServer
+		store  *unavailableStore
+		client fileserver.FileServerClient
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+		DeferCleanup(func() { cancel() })
+
+		store = &unavailableStore{}
+		srv = newTestServer(testServerOptions{
+			Server: func(s *Server) { s.WatchPollInterval = 50 * time.Millisecond },
+			Wrap: func(files database.StoredFileStore) database.StoredFileStore {
+				store.StoredFileStore = files
+				return store
+			},
+		})
+		client = srv.Client
+	})
+
+	save := func(store database.StoredFileStore, name, sourceType string) string {
+		id, err := store.Save(ctx, &modelsv2.StoredFile{
+			Name:       name,
+			Source:     "redhat-marketplace",
+			SourceType: sourceType,
+			File:       modelsv2.StoredFileContent{Content: []byte(name)},
+		})
+		Expect(err).To(Succeed())
+		return id
+	}
+
+	// watch starts a watch and returns the revision it starts from
+	watch := func(req *fileserver.WatchFilesRequest) (fileserver.FileServer_WatchFilesClient, string) {
+		stream, err := client.WatchFiles(ctx, req)
+		Expect(err).To(Succeed())
+
+		res, err := stream.Recv()
+		Expect(err).To(Succeed())
+		Expect(res.Events).To(BeEmpty())
+		return stream, res.Revision
+	}
+
+	// next receives the next changes, skipping the responses only moving the revision
+	next := func(stream fileserver.FileServer_WatchFilesClient) *fileserver.WatchFilesResponse {
+		for {
+			res, err := stream.Recv()
+			Expect(err).To(Succeed())
+			if len(res.Events) != 0 {
+				return res
+			}
+		}
+	}
+
+	It("should stream the changes to files", func() {
+		stream, _ := watch(&fileserver.WatchFilesRequest{})
+
+		id := save(store, "report.tar.gz", "report")
+		res := next(stream)
+		Expect(res.Events).To(HaveLen(1))
+		Expect(res.Events[0].Type).To(Equal(fileserver.FileEvent_CREATED))
+		Expect(res.Events[0].File.Id).To(Equal(id))
+		Expect(res.Events[0].File.Name).To(Equal("report.tar.gz"))
+		Expect(res.Revision).To(Equal(res.Events[0].Revision))
+
+		_, err := client.DeleteFile(ctx, &fileserver.DeleteFileRequest{Id: id, Permanent: true})
+		Expect(err).To(Succeed())
+		res = next(stream)
+		Expect(res.Events).To(HaveLen(1))
+		Expect(res.Events[0].Type).To(Equal(fileserver.FileEvent_DELETED))
+		Expect(res.Events[0].File.Id).To(Equal(id))
+		Expect(res.Events[0].File.Name).To(Equal("report.tar.gz"))
+	})
+
+	It("should only stream the changes to files matching the filter", func() {
+		stream, _ := watch(&fileserver.WatchFilesRequest{Filter: `sourceType == "report"`})
+
+		save(store, "events.json", "eventjson")
+		save(store, "report.tar.gz", "report")
+
+		res := next(stream)
+		Expect(res.Events).To(HaveLen(1))
+		Expect(res.Events[0].File.Name).To(Equal("report.tar.gz"))
+	})
+
+	It("should resume from a revision", func() {
+		stream, revision := watch(&fileserver.WatchFilesRequest{})
+		save(store, "first.json", "eventjson")
+		first := next(stream)
+
+		By("missing changes while disconnected")
+		save(store, "second.json", "eventjson")
+		save(store, "third.json", "eventjson")
+
+		stream, err := client.WatchFiles(ctx, &fileserver.WatchFilesRequest{Revision: first.Revision})
+		Expect(err).To(Succeed())
+		res := next(stream)
+		Expect(res.Events).To(HaveLen(2))
+		Expect(res.Events[0].File.Name).To(Equal("second.json"))
+		Expect(res.Events[1].File.Name).To(Equal("third.json"))
+
+		stream, err = client.WatchFiles(ctx, &fileserver.WatchFilesRequest{Revision: revision})
+		Expect(err).To(Succeed())
+		Expect(next(stream).Events).To(HaveLen(3))
+	})
+
+	It("should send the changes made through another node", func() {
+		stream, _ := watch(&fileserver.WatchFilesRequest{})
+
+		// the other node shares the database but does not signal this server
+		other, _ := database.New(srv.DB, database.FileStoreConfig{BlobStore: srv.Blobs})
+		save(other, "report.tar.gz", "report")
+
+		res := next(stream)
+		Expect(res.Events).To(HaveLen(1))
+		Expect(res.Events[0].File.Name).To(Equal("report.tar.gz"))
+	})
+
+	It("should keep watching while the database is unavailable", func() {
+		stream, _ := watch(&fileserver.WatchFilesRequest{})
+
+		store.down.Store(true)
+		reads := store.reads.Load()
+		Eventually(store.reads.Load).Should(BeNumerically(">", reads+1))
+
+		// saved while the watch can not read the changes
+		save(store, "report.tar.gz", "report")
+		store.down.Store(false)
+
+		res := next(stream)
+		Expect(res.Events).To(HaveLen(1))
+		Expect(res.Events[0].File.Name).To(Equal("report.tar.gz"))
+	})
+
+	It("should reject invalid requests", func() {
+		save(store, "report.tar.gz", "report")
+
+		for req, code := range map[*fileserver.WatchFilesRequest]codes.Code{
+			{Revision: "100"}:                 codes.OutOfRange,
+			{Revision: "revision"}:            codes.InvalidArgument,
+			{Filter: `unknown == "report"`}:   codes.InvalidArgument,
+			{Filter: `sourceType == "report`}: codes.InvalidArgument,
+		} {
+			stream, err := client.WatchFiles(ctx, req)
+			Expect(err).To(Succeed())
+			_, err = stream.Recv()
+			Expect(status.Code(err)).To(Equal(code), "request %v", req)
+		}
+	})
+
+	Context("as server-sent events", func() {
+		var httpSrv *httptest.Server
+
+		BeforeEach(func() {
+			mux := runtime.NewServeMux()
+			Expect(fileserver.RegisterFileServerHandlerClient(ctx, mux, client)).To(Succeed())
+			Expect(srv.FS.RegisterHTTPRoutes(mux)).To(Succeed())
+			httpSrv = httptest.NewServer(mux)
+		})
+
+		AfterEach(func() {
+			httpSrv.CloseClientConnections()
+			httpSrv.Close()
+		})
+
+		get := func(query url.Values, lastEventID string) *http.Response {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpSrv.URL+"/v1/files/watch?"+query.Encode(), nil)
+			Expect(err).To(Succeed())
+			if lastEventID != "" {
+				req.Header.Set("Last-Event-ID", lastEventID)
+			}
+
+			res, err := http.DefaultClient.Do(req)
+			Expect(err).To(Succeed())
+			return res
+		}
+
+		// readEvent returns the fields of the next event
+		readEvent := func(r *bufio.Reader) map[string]string {
+			fields := map[string]string{}
+			for {
+				line, err := r.ReadString('\n')
+				Expect(err).To(Succeed())
+
+				line = strings.TrimSuffix(line, "\n")
+				if line == "" && len(fields) > 0 {
+					return fields
+				}
+
+				if name, value, ok := strings.Cut(line, ": "); ok && name != "" {
+					fields[name] = value
+				}
+			}
+		}
+
+		It("should stream the changes as events", func() {
+			res := get(url.Values{"filter": {`sourceType == "report"`}}, "")
+			defer res.Body.Close()
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(res.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+
+			r := bufio.NewReader(res.Body)
+			start := readEvent(r)
+			Expect(start["event"]).To(Equal("revision"))
+
+			id := save(store, "report.tar.gz", "report")
+
+			event := readEvent(r)
+			Expect(event["event"]).To(Equal("created"))
+			Expect(event["id"]).ToNot(Equal(start["id"]))
+			Expect(event["data"]).To(ContainSubstring(`"id":"` + id + `"`))
+
+			By("resuming from the last event id")
+			save(store, "report.tar.gz", "report")
+
+			resumed := get(url.Values{"revision": {start["id"]}}, event["id"])
+			defer resumed.Body.Close()
+			Expect(readEvent(bufio.NewReader(resumed.Body))["event"]).To(Equal("updated"))
+		})
+
+		It("should return the status of a rejected watch", func() {
+			res := get(url.Values{"revision": {"100"}}, "")
+			defer res.Body.Close()
+			Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
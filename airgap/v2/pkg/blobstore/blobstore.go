@@ -0,0 +1,70 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"context"
+	"io"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+// BlobStore holds file content outside of the database. The database only
+// keeps the key returned by NewKey as a reference to the blob.
+type BlobStore interface {
+	// Put stores the content of r under key and returns the number of bytes written.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Get returns a reader for the blob stored under key. Callers must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+const (
+	ErrNotFound    = errors.Sentinel("blob not found")
+	ErrInvalidKey  = errors.Sentinel("invalid blob key")
+	ErrUnknownType = errors.Sentinel("unknown blob store type")
+)
+
+const (
+	TypeLocal = "local"
+	TypeS3    = "s3"
+)
+
+type Config struct {
+	// Type of backend to use, local or s3
+	Type string
+	// Dir is the root directory of the local backend
+	Dir string
+	S3  S3Config
+}
+
+// New creates the backend described by the config.
+func New(ctx context.Context, cfg Config) (BlobStore, error) {
+	switch cfg.Type {
+	case TypeLocal, "":
+		return NewLocal(cfg.Dir)
+	case TypeS3:
+		return NewS3(ctx, cfg.S3)
+	}
+
+	return nil, errors.WithDetails(ErrUnknownType, "type", cfg.Type)
+}
+
+// NewKey returns a new unique blob key.
+func NewKey() string {
+	return uuid.NewString()
+}
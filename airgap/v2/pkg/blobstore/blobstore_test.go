@@ -0,0 +1,159 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("blobstore", func() {
+	var (
+		ctx = context.Background()
+		sut BlobStore
+	)
+
+	behavesLikeABlobStore := func() {
+		It("should put, get and delete", func() {
+			key := NewKey()
+			n, err := sut.Put(ctx, key, strings.NewReader("hello world"))
+			Expect(err).To(Succeed())
+			Expect(n).To(Equal(int64(11)))
+
+			rc, err := sut.Get(ctx, key)
+			Expect(err).To(Succeed())
+			data, err := io.ReadAll(rc)
+			Expect(err).To(Succeed())
+			Expect(rc.Close()).To(Succeed())
+			Expect(string(data)).To(Equal("hello world"))
+
+			Expect(sut.Delete(ctx, key)).To(Succeed())
+			_, err = sut.Get(ctx, key)
+			Expect(err).To(MatchError(ErrNotFound))
+
+			Expect(sut.Delete(ctx, key)).To(Succeed())
+		})
+
+		It("should overwrite an existing key", func() {
+			key := NewKey()
+			_, err := sut.Put(ctx, key, strings.NewReader("first"))
+			Expect(err).To(Succeed())
+			_, err = sut.Put(ctx, key, strings.NewReader("second"))
+			Expect(err).To(Succeed())
+
+			rc, err := sut.Get(ctx, key)
+			Expect(err).To(Succeed())
+			defer rc.Close()
+			data, _ := io.ReadAll(rc)
+			Expect(string(data)).To(Equal("second"))
+		})
+
+		It("should store blobs larger than a single part", func() {
+			content := make([]byte, s3PartSize+1024)
+			_, err := rand.Read(content)
+			Expect(err).To(Succeed())
+
+			key := NewKey()
+			n, err := sut.Put(ctx, key, bytes.NewReader(content))
+			Expect(err).To(Succeed())
+			Expect(n).To(Equal(int64(len(content))))
+
+			rc, err := sut.Get(ctx, key)
+			Expect(err).To(Succeed())
+			defer rc.Close()
+			data, _ := io.ReadAll(rc)
+			Expect(bytes.Equal(data, content)).To(BeTrue())
+		})
+	}
+
+	Context("local", func() {
+		BeforeEach(func() {
+			var err error
+			sut, err = New(ctx, Config{Type: TypeLocal, Dir: GinkgoT().TempDir()})
+			Expect(err).To(Succeed())
+		})
+
+		behavesLikeABlobStore()
+
+		It("should reject keys that escape the directory", func() {
+			_, err := sut.Put(ctx, "../../etc/passwd", strings.NewReader("x"))
+			Expect(err).To(MatchError(ErrInvalidKey))
+		})
+
+		It("should not leave a blob behind when the context is cancelled", func() {
+			cctx, cancel := context.WithCancel(ctx)
+			cancel()
+
+			key := NewKey()
+			_, err := sut.Put(cctx, key, strings.NewReader("data"))
+			Expect(err).To(MatchError(context.Canceled))
+
+			_, err = sut.Get(ctx, key)
+			Expect(err).To(MatchError(ErrNotFound))
+		})
+	})
+
+	Context("s3", func() {
+		var (
+			fake   *fakeS3
+			server *httptest.Server
+		)
+
+		BeforeEach(func() {
+			fake = newFakeS3()
+			server = httptest.NewServer(fake)
+
+			var err error
+			sut, err = New(ctx, Config{
+				Type: TypeS3,
+				S3: S3Config{
+					Endpoint:        strings.TrimPrefix(server.URL, "http://"),
+					Bucket:          "airgap",
+					Prefix:          "files",
+					AccessKeyID:     "access",
+					SecretAccessKey: "secret",
+					Insecure:        true,
+				},
+			})
+			Expect(err).To(Succeed())
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		behavesLikeABlobStore()
+
+		It("should keep objects under the prefix", func() {
+			key := NewKey()
+			_, err := sut.Put(ctx, key, strings.NewReader("data"))
+			Expect(err).To(Succeed())
+			Expect(fake.buckets["airgap"]).To(HaveKey("files/" + key))
+			Expect(fake.objectCount("airgap")).To(Equal(1))
+		})
+	})
+
+	It("should fail on an unknown type", func() {
+		_, err := New(ctx, Config{Type: "nfs"})
+		Expect(err).To(MatchError(ErrUnknownType))
+	})
+})
@@ -0,0 +1,214 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeS3 is a minimal in memory stand-in for an S3 compatible service such
+// as MinIO. It understands just enough of the protocol for the minio client:
+// buckets, objects and multipart uploads. Requests are not authenticated.
+type fakeS3 struct {
+	mu      sync.Mutex
+	buckets map[string]map[string][]byte
+	uploads map[string]map[int][]byte
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		buckets: map[string]map[string][]byte{},
+		uploads: map[string]map[int][]byte{},
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	bucket := parts[0]
+	key := ""
+	if len(parts) == 2 {
+		key = parts[1]
+	}
+	query := r.URL.Query()
+
+	if key == "" {
+		f.serveBucket(w, r, bucket)
+		return
+	}
+
+	objects, ok := f.buckets[bucket]
+	if !ok {
+		writeS3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		id := uuid.NewString()
+		f.uploads[id] = map[int][]byte{}
+		writeXML(w, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			Bucket   string
+			Key      string
+			UploadId string
+		}{Bucket: bucket, Key: key, UploadId: id})
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		upload, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		part, _ := strconv.Atoi(query.Get("partNumber"))
+		body := readBody(r)
+		upload[part] = body
+		w.Header().Set("ETag", etag(body))
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		upload, ok := f.uploads[query.Get("uploadId")]
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		numbers := []int{}
+		for n := range upload {
+			numbers = append(numbers, n)
+		}
+		sort.Ints(numbers)
+		buf := &bytes.Buffer{}
+		for _, n := range numbers {
+			buf.Write(upload[n])
+		}
+		delete(f.uploads, query.Get("uploadId"))
+		objects[key] = buf.Bytes()
+		writeXML(w, struct {
+			XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+			Bucket  string
+			Key     string
+			ETag    string
+		}{Bucket: bucket, Key: key, ETag: etag(objects[key])})
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(f.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		body := readBody(r)
+		objects[key] = body
+		w.Header().Set("ETag", etag(body))
+	case r.Method == http.MethodGet, r.Method == http.MethodHead:
+		body, ok := objects[key]
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("ETag", etag(body))
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		if r.Method == http.MethodGet {
+			w.Write(body)
+		}
+	case r.Method == http.MethodDelete:
+		delete(objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (f *fakeS3) serveBucket(w http.ResponseWriter, r *http.Request, bucket string) {
+	_, ok := f.buckets[bucket]
+
+	switch r.Method {
+	case http.MethodHead:
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case http.MethodPut:
+		if !ok {
+			f.buckets[bucket] = map[string][]byte{}
+		}
+	case http.MethodGet:
+		writeXML(w, struct {
+			XMLName xml.Name `xml:"LocationConstraint"`
+		}{})
+	default:
+		writeS3Error(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (f *fakeS3) objectCount(bucket string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.buckets[bucket])
+}
+
+// readBody decodes the aws-chunked encoding used by signed streaming uploads.
+func readBody(r *http.Request) []byte {
+	body, _ := io.ReadAll(r.Body)
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return body
+	}
+
+	out := &bytes.Buffer{}
+	for len(body) > 0 {
+		i := bytes.Index(body, []byte("\r\n"))
+		if i < 0 {
+			break
+		}
+		header := string(body[:i])
+		if j := strings.Index(header, ";"); j >= 0 {
+			header = header[:j]
+		}
+		size, err := strconv.ParseInt(header, 16, 64)
+		if err != nil || size == 0 {
+			break
+		}
+		body = body[i+2:]
+		out.Write(body[:size])
+		body = body[size+2:]
+	}
+	return out.Bytes()
+}
+
+func etag(body []byte) string {
+	return fmt.Sprintf(`"%x"`, md5.Sum(body))
+}
+
+func writeXML(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(v)
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string
+	}{Code: code})
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+type localStore struct {
+	dir string
+}
+
+// NewLocal returns a BlobStore that keeps each blob as a file under dir.
+// Blobs are sharded into sub directories by the first two characters of the key.
+func NewLocal(dir string) (BlobStore, error) {
+	if dir == "" {
+		return nil, errors.New("blob store directory is required")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "can't create %s", dir)
+	}
+
+	return &localStore{dir: dir}, nil
+}
+
+func (s *localStore) path(key string) (string, error) {
+	if len(key) < 3 || strings.ContainsAny(key, `/\.`) {
+		return "", errors.WithDetails(ErrInvalidKey, "key", key)
+	}
+
+	return filepath.Join(s.dir, key[:2], key), nil
+}
+
+func (s *localStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	// write to a temp file first so a partial blob is never visible under key
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-"+key)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, &contextReader{ctx: ctx, r: r})
+	if err != nil {
+		tmp.Close()
+		return n, errors.WithStack(err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return n, errors.WithStack(err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return n, errors.WithStack(err)
+	}
+
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return n, errors.WithStack(err)
+	}
+
+	return n, nil
+}
+
+func (s *localStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.WithDetails(ErrNotFound, "key", key)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return f, nil
+}
+
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(p)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// contextReader stops a copy once the context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blobstore
+
+import (
+	"context"
+	"io"
+	"path"
+
+	"emperror.dev/errors"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+type S3Config struct {
+	// Endpoint is the host:port of the S3 compatible service
+	Endpoint        string
+	Bucket          string
+	Prefix          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Insecure        bool
+}
+
+// s3PartSize bounds the memory used while streaming a blob of unknown size.
+const s3PartSize = 16 * 1024 * 1024
+
+type s3Store struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3 returns a BlobStore backed by an S3 compatible object store.
+// The bucket is created if it does not exist.
+func NewS3(ctx context.Context, cfg S3Config) (BlobStore, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("s3 endpoint and bucket are required")
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: !cfg.Insecure,
+		Region: region,
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to check bucket", "bucket", cfg.Bucket)
+	}
+
+	if !exists {
+		err = client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{Region: region})
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to create bucket", "bucket", cfg.Bucket)
+		}
+	}
+
+	return &s3Store{client: client, bucket: cfg.Bucket, prefix: cfg.Prefix}, nil
+}
+
+func (s *s3Store) object(key string) string {
+	return path.Join(s.prefix, key)
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	info, err := s.client.PutObject(ctx, s.bucket, s.object(key), r, -1, minio.PutObjectOptions{
+		PartSize:    s3PartSize,
+		ContentType: "application/octet-stream",
+	})
+	if err != nil {
+		return 0, errors.WrapIfWithDetails(err, "failed to put object", "key", key)
+	}
+
+	return info.Size, nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	// GetObject is lazy, stat first so a missing blob is reported here
+	_, err := s.client.StatObject(ctx, s.bucket, s.object(key), minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, errors.WithDetails(ErrNotFound, "key", key)
+		}
+		return nil, errors.WrapIfWithDetails(err, "failed to stat object", "key", key)
+	}
+
+	obj, err := s.client.GetObject(ctx, s.bucket, s.object(key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to get object", "key", key)
+	}
+
+	return obj, nil
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	err := s.client.RemoveObject(ctx, s.bucket, s.object(key), minio.RemoveObjectOptions{})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to remove object", "key", key)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Metadata set on the files of the data service once delivered
+const (
+	MetadataDelivered   = "delivered"
+	MetadataDeliveredAt = "deliveredAt"
+	MetadataUploadID    = "uploadId"
+	MetadataBundleID    = "bundleId"
+)
+
+// UndeliveredFilter selects the files not yet delivered, the default files
+// of an export
+const UndeliveredFilter = `not metadata.delivered == "true"`
+
+// Acknowledgement records the delivery of the files of a bundle, it is
+// imported back into the data service to mark the files delivered
+type Acknowledgement struct {
+	BundleID  string     `json:"bundleId"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Files     []Delivery `json:"files"`
+}
+
+// Delivery is the outcome of the upload of a file of a bundle
+type Delivery struct {
+	ID       string `json:"id"`
+	Version  uint64 `json:"version"`
+	Name     string `json:"name"`
+	Checksum string `json:"checksum"`
+	UploadID string `json:"uploadId,omitempty"`
+	// Error is set when the file failed to upload
+	Error string `json:"error,omitempty"`
+}
+
+// Delivered returns true if the file was uploaded
+func (d *Delivery) Delivered() bool {
+	return d.Error == ""
+}
+
+// Metadata returns the metadata marking the file of d delivered
+func (a *Acknowledgement) Metadata(d *Delivery) map[string]string {
+	metadata := map[string]string{
+		MetadataDelivered:   "true",
+		MetadataDeliveredAt: a.CreatedAt.UTC().Format(time.RFC3339),
+		MetadataBundleID:    a.BundleID,
+	}
+	if d.UploadID != "" {
+		metadata[MetadataUploadID] = d.UploadID
+	}
+	return metadata
+}
+
+// ParseAcknowledgement parses an acknowledgement written by Import
+func ParseAcknowledgement(data []byte) (*Acknowledgement, error) {
+	ack := &Acknowledgement{}
+	if err := json.Unmarshal(data, ack); err != nil {
+		return nil, errors.Wrap(err, "invalid acknowledgement")
+	}
+	if ack.BundleID == "" {
+		return nil, errors.New("invalid acknowledgement, the bundle id is missing")
+	}
+	return ack, nil
+}
+
+// Uploader uploads the content of a file, as the uploaders of the reporter
+type Uploader interface {
+	UploadFile(ctx context.Context, fileName string, reader io.Reader) (id string, err error)
+}
+
+// Import uploads each file of the bundle read by r once its content is
+// verified and returns the acknowledgement of the uploads. The files
+// delivered by previous, the acknowledgement of an earlier import of the same
+// bundle, are not uploaded again. A file failing to upload is recorded with
+// its error and the import goes on, while a bundle failing verification stops
+// it with the acknowledgement of the files delivered so far.
+func Import(ctx context.Context, r *Reader, uploader Uploader, previous *Acknowledgement) (*Acknowledgement, error) {
+	ack := &Acknowledgement{BundleID: r.Manifest.ID, CreatedAt: time.Now().UTC()}
+
+	delivered := map[string]Delivery{}
+	if previous != nil && previous.BundleID == r.Manifest.ID {
+		for _, d := range previous.Files {
+			if d.Delivered() {
+				delivered[d.ID] = d
+			}
+		}
+	}
+
+	for {
+		file, content, err := r.Next()
+		if err == io.EOF {
+			return ack, nil
+		}
+		if err != nil {
+			return ack, err
+		}
+
+		if d, ok := delivered[file.ID]; ok && d.Checksum == file.Checksum {
+			ack.Files = append(ack.Files, d)
+			continue
+		}
+
+		// the content is verified before any of it is uploaded
+		data := bytes.NewBuffer(make([]byte, 0, file.Size))
+		if _, err := io.Copy(data, content); err != nil {
+			return ack, err
+		}
+
+		d := Delivery{ID: file.ID, Version: file.Version, Name: file.Name, Checksum: file.Checksum}
+
+		d.UploadID, err = uploader.UploadFile(ctx, file.Name, data)
+		if err != nil {
+			d.Error = err.Error()
+		}
+
+		ack.Files = append(ack.Files, d)
+
+		if err := ctx.Err(); err != nil {
+			return ack, err
+		}
+	}
+}
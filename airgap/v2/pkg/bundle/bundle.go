@@ -0,0 +1,305 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bundle carries files of the data service across an air gap. A
+// bundle is a tar archive of a manifest indexing the files, the ed25519
+// signature of the manifest, then the content of each file in manifest
+// order, verified against the sha256 checksums of the manifest.
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	ErrInvalidBundle    = errors.Sentinel("invalid bundle")
+	ErrInvalidSignature = errors.Sentinel("bundle signature is invalid")
+	ErrChecksumMismatch = errors.Sentinel("file content does not match the manifest")
+)
+
+// Version of the bundle format
+const Version = 1
+
+const (
+	manifestPath  = "manifest.json"
+	signaturePath = "manifest.json.sig"
+	filesDir      = "files"
+
+	// maxManifestSize bounds the size of the manifest read before its
+	// signature is verified
+	maxManifestSize = 64 * 1024 * 1024
+)
+
+// Manifest indexes the files of a bundle
+type Manifest struct {
+	Version   int       `json:"version"`
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Filter selected the files exported
+	Filter string `json:"filter,omitempty"`
+	Files  []File `json:"files"`
+}
+
+// NewManifest returns the manifest of a new bundle of files
+func NewManifest(filter string, files []File) *Manifest {
+	return &Manifest{
+		Version:   Version,
+		ID:        uuid.NewString(),
+		CreatedAt: time.Now().UTC(),
+		Filter:    filter,
+		Files:     files,
+	}
+}
+
+// File is a file of the data service in a bundle
+type File struct {
+	ID         string            `json:"id"`
+	Version    uint64            `json:"version"`
+	Name       string            `json:"name"`
+	Source     string            `json:"source"`
+	SourceType string            `json:"sourceType"`
+	MimeType   string            `json:"mimeType,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Size       int64             `json:"size"`
+	// Checksum is the sha256 checksum of the content
+	Checksum string `json:"checksum"`
+	// Path of the content in the archive
+	Path string `json:"path"`
+}
+
+// Writer writes a bundle, the content of the files of its manifest is then
+// written in order with WriteFile.
+type Writer struct {
+	tw       *tar.Writer
+	manifest *Manifest
+	next     int
+}
+
+// NewWriter writes the manifest, signed with key, to w. It sets the path of
+// the files of the manifest.
+func NewWriter(w io.Writer, key ed25519.PrivateKey, manifest *Manifest) (*Writer, error) {
+	for i := range manifest.Files {
+		file := &manifest.Files[i]
+		file.Path = path.Join(filesDir, file.ID, safeName(file.Name))
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	bw := &Writer{tw: tar.NewWriter(w), manifest: manifest}
+
+	if err := bw.writeEntry(manifestPath, data); err != nil {
+		return nil, err
+	}
+
+	if err := bw.writeEntry(signaturePath, ed25519.Sign(key, data)); err != nil {
+		return nil, err
+	}
+
+	return bw, nil
+}
+
+// safeName keeps the base name of a file, without the separators of a path
+func safeName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == ".." || name == "/" {
+		return "content"
+	}
+	return name
+}
+
+func (w *Writer) writeEntry(name string, data []byte) error {
+	if err := w.tw.WriteHeader(w.header(name, int64(len(data)))); err != nil {
+		return errors.WithStack(err)
+	}
+	_, err := w.tw.Write(data)
+	return errors.WithStack(err)
+}
+
+func (w *Writer) header(name string, size int64) *tar.Header {
+	return &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0600,
+		ModTime:  w.manifest.CreatedAt,
+		Format:   tar.FormatPAX,
+	}
+}
+
+// WriteFile writes the content of the next file of the manifest read from r,
+// it fails when the content does not match the size and checksum of the file.
+func (w *Writer) WriteFile(r io.Reader) error {
+	if w.next == len(w.manifest.Files) {
+		return errors.New("every file of the manifest is written")
+	}
+
+	file := &w.manifest.Files[w.next]
+	w.next++
+
+	if err := w.tw.WriteHeader(w.header(file.Path, file.Size)); err != nil {
+		return errors.WithStack(err)
+	}
+
+	h := sha256.New()
+	n, err := io.Copy(w.tw, io.TeeReader(r, h))
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to write file", "id", file.ID)
+	}
+
+	if n != file.Size || fmt.Sprintf("%x", h.Sum(nil)) != file.Checksum {
+		return errors.WithDetails(ErrChecksumMismatch, "id", file.ID, "size", n)
+	}
+
+	return nil
+}
+
+// Close ends the bundle once every file is written, it does not close the
+// underlying writer.
+func (w *Writer) Close() error {
+	if w.next != len(w.manifest.Files) {
+		return errors.Errorf("%d files of the manifest are not written", len(w.manifest.Files)-w.next)
+	}
+	return errors.WithStack(w.tw.Close())
+}
+
+// Reader reads a bundle whose manifest signature is verified
+type Reader struct {
+	Manifest *Manifest
+
+	tr   *tar.Reader
+	next int
+}
+
+// NewReader reads the manifest of the bundle read by r and verifies its
+// signature with key.
+func NewReader(r io.Reader, key ed25519.PublicKey) (*Reader, error) {
+	tr := tar.NewReader(r)
+
+	data, err := readEntry(tr, manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := readEntry(tr, signaturePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ed25519.Verify(key, data, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, errors.Wrap(ErrInvalidBundle, err.Error())
+	}
+
+	if manifest.Version != Version {
+		return nil, errors.WithDetails(errors.Wrap(ErrInvalidBundle, "unsupported version"), "version", manifest.Version)
+	}
+
+	return &Reader{Manifest: manifest, tr: tr}, nil
+}
+
+func readEntry(tr *tar.Reader, name string) ([]byte, error) {
+	hdr, err := tr.Next()
+	if err == io.EOF {
+		return nil, errors.Wrapf(ErrInvalidBundle, "%s is missing", name)
+	}
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidBundle, err.Error())
+	}
+
+	if hdr.Name != name {
+		return nil, errors.Wrapf(ErrInvalidBundle, "%s expected, found %s", name, hdr.Name)
+	}
+
+	if hdr.Size > maxManifestSize {
+		return nil, errors.Wrapf(ErrInvalidBundle, "%s is larger than %d bytes", name, maxManifestSize)
+	}
+
+	data := bytes.NewBuffer(make([]byte, 0, hdr.Size))
+	if _, err := io.Copy(data, tr); err != nil {
+		return nil, errors.Wrap(ErrInvalidBundle, err.Error())
+	}
+
+	return data.Bytes(), nil
+}
+
+// Next returns the next file of the bundle and a reader of its content,
+// failing with ErrChecksumMismatch at the end of content not matching the
+// manifest. It returns io.EOF after the last file.
+func (r *Reader) Next() (*File, io.Reader, error) {
+	hdr, err := r.tr.Next()
+
+	if r.next == len(r.Manifest.Files) {
+		if err == io.EOF {
+			return nil, nil, io.EOF
+		}
+		if err != nil {
+			return nil, nil, errors.Wrap(ErrInvalidBundle, err.Error())
+		}
+		return nil, nil, errors.Wrapf(ErrInvalidBundle, "%s is not in the manifest", hdr.Name)
+	}
+
+	file := &r.Manifest.Files[r.next]
+	r.next++
+
+	if err == io.EOF {
+		return nil, nil, errors.WithDetails(errors.Wrap(ErrInvalidBundle, "file is missing"), "id", file.ID)
+	}
+	if err != nil {
+		return nil, nil, errors.Wrap(ErrInvalidBundle, err.Error())
+	}
+
+	if hdr.Name != file.Path || hdr.Size != file.Size {
+		return nil, nil, errors.WithDetails(errors.Wrapf(ErrInvalidBundle, "%s expected, found %s", file.Path, hdr.Name), "id", file.ID)
+	}
+
+	return file, &verifyingReader{r: r.tr, h: sha256.New(), file: file}, nil
+}
+
+// verifyingReader checks the checksum of the content once it is read
+type verifyingReader struct {
+	r    io.Reader
+	h    hash.Hash
+	file *File
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	v.h.Write(p[:n])
+
+	if err == io.EOF && fmt.Sprintf("%x", v.h.Sum(nil)) != v.file.Checksum {
+		return n, errors.WithDetails(ErrChecksumMismatch, "id", v.file.ID, "name", v.file.Name)
+	}
+
+	return n, err
+}
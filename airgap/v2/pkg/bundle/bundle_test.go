@@ -0,0 +1,186 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strings"
+
+	"emperror.dev/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeUploader records the uploads, failing the files in fail
+type fakeUploader struct {
+	uploaded map[string]string
+	fail     map[string]bool
+}
+
+func (f *fakeUploader) UploadFile(_ context.Context, fileName string, r io.Reader) (string, error) {
+	if f.fail[fileName] {
+		return "", errors.New("service unavailable")
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	f.uploaded[fileName] = string(data)
+	return "upload-" + fileName, nil
+}
+
+var _ = Describe("bundle", func() {
+	var (
+		public   ed25519.PublicKey
+		private  ed25519.PrivateKey
+		contents = map[string]string{"1": "report one", "2": strings.Repeat("report two", 1024)}
+		manifest *Manifest
+	)
+
+	newFile := func(id, name string) File {
+		return File{
+			ID:         id,
+			Version:    1,
+			Name:       name,
+			Source:     "redhat-marketplace",
+			SourceType: "report",
+			Size:       int64(len(contents[id])),
+			Checksum:   fmt.Sprintf("%x", sha256.Sum256([]byte(contents[id]))),
+		}
+	}
+
+	write := func(manifest *Manifest) *bytes.Buffer {
+		buf := &bytes.Buffer{}
+		w, err := NewWriter(buf, private, manifest)
+		Expect(err).To(Succeed())
+		for _, file := range manifest.Files {
+			Expect(w.WriteFile(strings.NewReader(contents[file.ID]))).To(Succeed())
+		}
+		Expect(w.Close()).To(Succeed())
+		return buf
+	}
+
+	BeforeEach(func() {
+		var err error
+		public, private, err = ed25519.GenerateKey(rand.Reader)
+		Expect(err).To(Succeed())
+
+		manifest = NewManifest(UndeliveredFilter, []File{newFile("1", "report-1.tar.gz"), newFile("2", "../report-2.tar.gz")})
+	})
+
+	It("should read the files written", func() {
+		r, err := NewReader(write(manifest), public)
+		Expect(err).To(Succeed())
+		Expect(r.Manifest.ID).To(Equal(manifest.ID))
+		Expect(r.Manifest.Filter).To(Equal(UndeliveredFilter))
+
+		for _, id := range []string{"1", "2"} {
+			file, content, err := r.Next()
+			Expect(err).To(Succeed())
+			Expect(file.ID).To(Equal(id))
+			Expect(file.Path).To(HavePrefix("files/" + id + "/report-"))
+
+			data, err := io.ReadAll(content)
+			Expect(err).To(Succeed())
+			Expect(string(data)).To(Equal(contents[id]))
+		}
+
+		_, _, err = r.Next()
+		Expect(err).To(Equal(io.EOF))
+	})
+
+	It("should not write content not matching the manifest", func() {
+		w, err := NewWriter(io.Discard, private, manifest)
+		Expect(err).To(Succeed())
+		Expect(w.WriteFile(strings.NewReader("report 1"))).To(MatchError(ErrChecksumMismatch))
+	})
+
+	It("should reject a bundle signed with another key", func() {
+		other, _, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).To(Succeed())
+
+		_, err = NewReader(write(manifest), other)
+		Expect(err).To(MatchError(ErrInvalidSignature))
+	})
+
+	It("should detect content changed after signing", func() {
+		data := write(manifest).Bytes()
+		i := bytes.Index(data, []byte(contents["1"]))
+		Expect(i).To(BeNumerically(">", 0))
+		data[i] = 'R'
+
+		r, err := NewReader(bytes.NewReader(data), public)
+		Expect(err).To(Succeed())
+
+		_, content, err := r.Next()
+		Expect(err).To(Succeed())
+		_, err = io.ReadAll(content)
+		Expect(err).To(MatchError(ErrChecksumMismatch))
+	})
+
+	It("should upload the files and acknowledge them", func() {
+		uploader := &fakeUploader{uploaded: map[string]string{}, fail: map[string]bool{"../report-2.tar.gz": true}}
+
+		r, err := NewReader(write(manifest), public)
+		Expect(err).To(Succeed())
+		ack, err := Import(context.Background(), r, uploader, nil)
+		Expect(err).To(Succeed())
+
+		Expect(ack.BundleID).To(Equal(manifest.ID))
+		Expect(ack.Files).To(HaveLen(2))
+		Expect(ack.Files[0].Delivered()).To(BeTrue())
+		Expect(ack.Files[0].UploadID).To(Equal("upload-report-1.tar.gz"))
+		Expect(ack.Files[1].Delivered()).To(BeFalse())
+		Expect(ack.Metadata(&ack.Files[0])).To(HaveKeyWithValue(MetadataDelivered, "true"))
+
+		By("only uploading the files not delivered when imported again")
+		uploader = &fakeUploader{uploaded: map[string]string{}}
+		r, err = NewReader(write(manifest), public)
+		Expect(err).To(Succeed())
+		ack, err = Import(context.Background(), r, uploader, ack)
+		Expect(err).To(Succeed())
+
+		Expect(uploader.uploaded).To(Equal(map[string]string{"../report-2.tar.gz": contents["2"]}))
+		Expect(ack.Files[0].UploadID).To(Equal("upload-report-1.tar.gz"))
+		Expect(ack.Files[1].Delivered()).To(BeTrue())
+	})
+
+	It("should parse PEM keys", func() {
+		der, err := x509.MarshalPKCS8PrivateKey(private)
+		Expect(err).To(Succeed())
+		parsed, err := ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+		Expect(err).To(Succeed())
+		Expect(parsed.Equal(private)).To(BeTrue())
+
+		der, err = x509.MarshalPKIXPublicKey(public)
+		Expect(err).To(Succeed())
+		parsedPublic, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+		Expect(err).To(Succeed())
+		Expect(parsedPublic.Equal(public)).To(BeTrue())
+
+		_, err = ParsePublicKey([]byte("not a key"))
+		Expect(err).To(MatchError(ErrInvalidKey))
+	})
+})
@@ -0,0 +1,84 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+
+	"emperror.dev/errors"
+)
+
+const ErrInvalidKey = errors.Sentinel("invalid bundle signing key")
+
+// LoadPrivateKey reads the ed25519 key signing bundles from a PKCS #8 PEM
+// file, as written by openssl genpkey -algorithm ed25519
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to read the bundle signing key", "path", path)
+	}
+	return ParsePrivateKey(data)
+}
+
+// ParsePrivateKey parses an ed25519 private key in PKCS #8 PEM
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Wrap(ErrInvalidKey, "no PEM block")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidKey, err.Error())
+	}
+
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.Wrapf(ErrInvalidKey, "%T is not an ed25519 key", key)
+	}
+	return private, nil
+}
+
+// LoadPublicKey reads the ed25519 key verifying bundles from a PKIX PEM
+// file, as written by openssl pkey -pubout
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to read the bundle verification key", "path", path)
+	}
+	return ParsePublicKey(data)
+}
+
+// ParsePublicKey parses an ed25519 public key in PKIX PEM
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Wrap(ErrInvalidKey, "no PEM block")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidKey, err.Error())
+	}
+
+	public, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.Wrapf(ErrInvalidKey, "%T is not an ed25519 key", key)
+	}
+	return public, nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+)
+
+// maxAuditAttempts is how many times an append is tried when another one
+// took the end of the chain first
+const maxAuditAttempts = 5
+
+type auditEventKey struct{}
+
+// WithAuditEvent returns a copy of ctx whose event is appended to the audit
+// log in the transaction saving or deleting a file with it, the event is
+// only recorded when the change is committed.
+func WithAuditEvent(ctx context.Context, event *modelsv2.AuditEvent) context.Context {
+	return context.WithValue(ctx, auditEventKey{}, event)
+}
+
+// auditChange appends the audit event of ctx, if any, for the change to the
+// file with id made in tx. checksumBefore is the checksum of its content read
+// in tx before the change.
+func auditChange(ctx context.Context, tx *gorm.DB, id uint, checksumBefore string) error {
+	event, _ := ctx.Value(auditEventKey{}).(*modelsv2.AuditEvent)
+	if event == nil {
+		return nil
+	}
+
+	if event.FileID == 0 {
+		event.FileID = id
+	}
+	event.ChecksumBefore = checksumBefore
+
+	return appendAuditEvent(tx, event)
+}
+
+// auditOperation appends the audit event of ctx, if any, for an operation
+// on many files made in tx, with the details of its outcome.
+func auditOperation(ctx context.Context, tx *gorm.DB, details map[string]string) error {
+	event, _ := ctx.Value(auditEventKey{}).(*modelsv2.AuditEvent)
+	if event == nil {
+		return nil
+	}
+
+	data, _ := json.Marshal(details)
+	event.Details = string(data)
+
+	return appendAuditEvent(tx, event)
+}
+
+// AppendAuditEvent adds the events to the end of the audit log, chained to
+// the last event. They are all recorded or none is.
+func (d *fileStore) AppendAuditEvent(ctx context.Context, events ...*modelsv2.AuditEvent) error {
+	for _, event := range events {
+		if event == nil {
+			return errors.Wrap(ErrInvalidInput, "event is nil")
+		}
+	}
+
+	return d.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, event := range events {
+			if err := appendAuditEvent(tx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// appendAuditEvent adds event to the end of the audit log in tx. The previous
+// hash is unique, an append racing with another one, from this node or
+// another, fails and is tried again on the new end of the chain.
+func appendAuditEvent(tx *gorm.DB, event *modelsv2.AuditEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+	event.CreatedAt = event.CreatedAt.Truncate(time.Microsecond)
+
+	var err error
+
+	for attempt := 0; attempt < maxAuditAttempts; attempt++ {
+		// a savepoint, the failed insert must not abort the transaction
+		err = tx.Transaction(func(tx *gorm.DB) error {
+			last := modelsv2.AuditEvent{}
+			err := tx.Order("id desc").Limit(1).Find(&last).Error
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			event.ID = 0
+			event.PreviousHash = last.Hash
+			event.Hash = event.ComputeHash()
+
+			return errors.WithStack(tx.Create(event).Error)
+		})
+
+		if !isUniqueViolation(err) {
+			return err
+		}
+	}
+
+	return errors.WrapIfWithDetails(err, "failed to append audit event", "attempts", maxAuditAttempts)
+}
+
+// isUniqueViolation reports whether err is raised by a unique constraint,
+// sqlite and dqlite only report it in the message
+func isUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+
+	msg := err.Error()
+	return strings.Contains(msg, "UNIQUE constraint failed") || // sqlite, dqlite
+		strings.Contains(msg, "SQLSTATE 23505") // postgres
+}
+
+// ListAuditEvents returns a page of the audit log, newest first. Filters
+// apply to the fields of the events.
+func (d *fileStore) ListAuditEvents(ctx context.Context, opts ...ListOption) (events []modelsv2.AuditEvent, nextPageToken string, err error) {
+	listOpts := (&ListOptions{}).ApplyOptions(opts)
+	listOpts.ShowDeleted = false
+	listOpts.schemas = auditEventSchemas
+
+	query, order, cursorQuery, err := d.startPage(d.WithContext(ctx).Model(&modelsv2.AuditEvent{}), auditEvent, "id desc", listOpts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = query.
+		Scopes(listOpts.scopes()...).
+		Find(&events).Error
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(events) == listOpts.Pagination.PageSize+1 {
+		events = events[:len(events)-1]
+
+		last := &events[len(events)-1]
+		nextPageToken, err = d.nextPageToken(order, cursorQuery, last, last.ID)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	return events, nextPageToken, nil
+}
+
+// VerifyAuditLog walks the audit log in order and checks the hash of every
+// event. It returns the number of events verified and the id of the first
+// event breaking the chain, 0 if the log is intact.
+func (d *fileStore) VerifyAuditLog(ctx context.Context) (int64, uint, error) {
+	var (
+		verified     int64
+		firstInvalid uint
+		previousHash string
+		events       []modelsv2.AuditEvent
+	)
+
+	result := d.WithContext(ctx).
+		FindInBatches(&events, 100, func(_ *gorm.DB, _ int) error {
+			for i := range events {
+				event := &events[i]
+				if event.PreviousHash != previousHash || event.Hash != event.ComputeHash() {
+					firstInvalid = event.ID
+					return errAuditChainBroken
+				}
+
+				previousHash = event.Hash
+				verified++
+			}
+			return nil
+		})
+
+	if err := result.Error; err != nil && !errors.Is(err, errAuditChainBroken) {
+		return verified, 0, errors.WithStack(err)
+	}
+
+	return verified, firstInvalid, nil
+}
+
+// errAuditChainBroken stops the walk of the audit log at the first invalid event
+const errAuditChainBroken = errors.Sentinel("audit chain broken")
@@ -0,0 +1,235 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("audit log", func() {
+	var (
+		db  *gorm.DB
+		sut StoredFileStore
+		ctx = context.Background()
+	)
+
+	BeforeEach(func() {
+		var blobs blobstore.BlobStore
+		db, blobs = openTestStore("audit.gorm.db")
+		sut = newTestStore(db, FileStoreConfig{BlobStore: blobs})
+
+		for _, method := range []string{"UploadFile", "UpdateFileMetadata", "DownloadFile", "DeleteFile"} {
+			Expect(sut.AppendAuditEvent(ctx, &modelsv2.AuditEvent{
+				Username:      "system:serviceaccount:rhm:reporter",
+				Method:        method,
+				FileID:        1,
+				Name:          "report.tar.gz",
+				ChecksumAfter: "abc",
+			})).To(Succeed())
+		}
+	})
+
+	It("should chain events", func() {
+		events := []modelsv2.AuditEvent{}
+		Expect(db.Order("id").Find(&events).Error).To(Succeed())
+		Expect(events).To(HaveLen(4))
+
+		Expect(events[0].PreviousHash).To(BeEmpty())
+		for i := 1; i < len(events); i++ {
+			Expect(events[i].PreviousHash).To(Equal(events[i-1].Hash))
+			Expect(events[i].Hash).To(Equal(events[i].ComputeHash()))
+		}
+
+		verified, invalid, err := sut.VerifyAuditLog(ctx)
+		Expect(err).To(Succeed())
+		Expect(verified).To(Equal(int64(4)))
+		Expect(invalid).To(BeZero())
+	})
+
+	It("should keep the chain linear with concurrent appends", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				Expect(sut.AppendAuditEvent(ctx, &modelsv2.AuditEvent{Method: "DownloadFile"})).To(Succeed())
+			}()
+		}
+		wg.Wait()
+
+		verified, invalid, err := sut.VerifyAuditLog(ctx)
+		Expect(err).To(Succeed())
+		Expect(verified).To(Equal(int64(14)))
+		Expect(invalid).To(BeZero())
+	})
+
+	It("should keep the chain linear with appends from stores sharing the database", func() {
+		other := newTestStore(db, FileStoreConfig{})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			store := sut
+			if i%2 == 0 {
+				store = other
+			}
+
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				Expect(store.AppendAuditEvent(ctx, &modelsv2.AuditEvent{Method: "DownloadFile"})).To(Succeed())
+			}()
+		}
+		wg.Wait()
+
+		verified, invalid, err := sut.VerifyAuditLog(ctx)
+		Expect(err).To(Succeed())
+		Expect(verified).To(Equal(int64(14)))
+		Expect(invalid).To(BeZero())
+	})
+
+	It("should retry an append when another one took the end of the chain", func() {
+		events := []modelsv2.AuditEvent{}
+		Expect(db.Order("id").Find(&events).Error).To(Succeed())
+
+		// another node appended after the last event was read
+		stale := true
+		Expect(db.Callback().Query().After("gorm:query").Register("test:stale", func(tx *gorm.DB) {
+			if last, ok := tx.Statement.Dest.(*modelsv2.AuditEvent); ok && stale {
+				stale = false
+				*last = events[2]
+			}
+		})).To(Succeed())
+
+		Expect(sut.AppendAuditEvent(ctx, &modelsv2.AuditEvent{Method: "DownloadFile"})).To(Succeed())
+		Expect(stale).To(BeFalse())
+
+		events = nil
+		Expect(db.Order("id").Find(&events).Error).To(Succeed())
+		Expect(events).To(HaveLen(5))
+		Expect(events[4].PreviousHash).To(Equal(events[3].Hash))
+	})
+
+	It("should append the event of a change in its transaction", func() {
+		file := &modelsv2.StoredFile{
+			Name:       "audited",
+			Source:     "test",
+			SourceType: "test",
+			File:       modelsv2.StoredFileContent{Content: []byte("audited")},
+		}
+
+		id, err := sut.Save(WithAuditEvent(ctx, &modelsv2.AuditEvent{Method: "UploadFile"}), file)
+		Expect(err).To(Succeed())
+
+		events, _, err := sut.ListAuditEvents(ctx, Paginate("", 1))
+		Expect(err).To(Succeed())
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Method).To(Equal("UploadFile"))
+		Expect(fmt.Sprintf("%d", events[0].FileID)).To(Equal(id))
+
+		Expect(sut.Batch(ctx, true, func(store StoredFileStore) error {
+			return store.Delete(WithAuditEvent(ctx, &modelsv2.AuditEvent{Method: "BatchDelete"}), id, false)
+		})).To(Succeed())
+
+		events, _, err = sut.ListAuditEvents(ctx, Paginate("", 1))
+		Expect(err).To(Succeed())
+		Expect(events[0].Method).To(Equal("UploadFile"), "the event is rolled back with the change")
+
+		verified, invalid, err := sut.VerifyAuditLog(ctx)
+		Expect(err).To(Succeed())
+		Expect(verified).To(Equal(int64(5)))
+		Expect(invalid).To(BeZero())
+	})
+
+	It("should not update or delete events", func() {
+		event := &modelsv2.AuditEvent{}
+		Expect(db.First(event).Error).To(Succeed())
+
+		Expect(db.Model(event).Update("username", "someone-else").Error).To(MatchError(modelsv2.ErrAuditAppendOnly))
+		Expect(db.Delete(event).Error).To(MatchError(modelsv2.ErrAuditAppendOnly))
+	})
+
+	It("should detect a changed event", func() {
+		Expect(db.Exec("UPDATE audit_events SET username = ? WHERE id = ?", "someone-else", 2).Error).To(Succeed())
+
+		verified, invalid, err := sut.VerifyAuditLog(ctx)
+		Expect(err).To(Succeed())
+		Expect(verified).To(Equal(int64(1)))
+		Expect(invalid).To(Equal(uint(2)))
+	})
+
+	It("should detect a removed event", func() {
+		Expect(db.Exec("DELETE FROM audit_events WHERE id = ?", 3).Error).To(Succeed())
+
+		_, invalid, err := sut.VerifyAuditLog(ctx)
+		Expect(err).To(Succeed())
+		Expect(invalid).To(Equal(uint(4)))
+	})
+
+	It("should list events newest first with filters", func() {
+		events, next, err := sut.ListAuditEvents(ctx, Paginate("", 2))
+		Expect(err).To(Succeed())
+		Expect(next).ToNot(BeEmpty())
+		Expect(events).To(HaveLen(2))
+		Expect(events[0].Method).To(Equal("DeleteFile"))
+		Expect(events[1].Method).To(Equal("DownloadFile"))
+
+		events, _, err = sut.ListAuditEvents(ctx, Paginate(next, 2))
+		Expect(err).To(Succeed())
+		Expect(events).To(HaveLen(2))
+		Expect(events[0].Method).To(Equal("UpdateFileMetadata"))
+		Expect(events[1].Method).To(Equal("UploadFile"))
+
+		filters := fileserver.Filters{}
+		Expect(filters.UnmarshalText([]byte(`method == "UpdateFileMetadata"`))).To(Succeed())
+
+		events, next, err = sut.ListAuditEvents(ctx, ApplyFilters(filters))
+		Expect(err).To(Succeed())
+		Expect(next).To(BeEmpty())
+		Expect(events).To(HaveLen(1))
+		Expect(events[0].Method).To(Equal("UpdateFileMetadata"))
+	})
+
+	It("should record changes in their transaction", func() {
+		id := saveTestFile(ctx, sut, testFile("report.tar.gz", "first"))
+		first, err := sut.Get(ctx, id)
+		Expect(err).To(Succeed())
+
+		replaced := testFile("report.tar.gz", "second")
+		Expect(sut.Save(WithAuditEvent(ctx, &modelsv2.AuditEvent{Method: "UploadFile"}), replaced)).Error().To(Succeed())
+		Expect(sut.Delete(ctx, id, false)).To(Succeed())
+
+		_, err = sut.CleanTombstones(WithAuditEvent(ctx, &modelsv2.AuditEvent{Method: "CleanTombstones"}))
+		Expect(err).To(Succeed())
+
+		events, _, err := sut.ListAuditEvents(ctx, Paginate("", 2))
+		Expect(err).To(Succeed())
+		Expect(events).To(HaveLen(2))
+		Expect(events[0].Method).To(Equal("CleanTombstones"))
+		Expect(events[0].Details).To(Equal(`{"tombstonesCleaned":"1"}`))
+		Expect(events[1].Method).To(Equal("UploadFile"))
+		Expect(events[1].ChecksumBefore).To(Equal(first.File.Checksum))
+	})
+})
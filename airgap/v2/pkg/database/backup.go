@@ -0,0 +1,519 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	ErrInvalidBackup    = errors.Sentinel("invalid backup archive")
+	ErrRestoreNotEmpty  = errors.Sentinel("restore requires an empty data service")
+	backupFormatVersion = 2
+
+	backupManifestEntry    = "manifest.json"
+	backupFilesEntry       = "records/stored_files.jsonl"
+	backupAuditEventsEntry = "records/audit_events.jsonl"
+	backupBlobsEntry       = "records/stored_blobs.jsonl"
+	backupBlobPrefix       = "blobs/"
+)
+
+// BackupManifest describes a backup archive. It is the last entry of the
+// archive and holds the sha256 checksum of every other entry.
+type BackupManifest struct {
+	FormatVersion int       `json:"formatVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+
+	Files       int64 `json:"files"`
+	Tombstones  int64 `json:"tombstones"`
+	Versions    int64 `json:"versions"`
+	AuditEvents int64 `json:"auditEvents"`
+	Blobs       int64 `json:"blobs"`
+	// MissingBlobs are the keys of content deleted after the records were
+	// read, it belongs to files changed or deleted while the backup ran
+	MissingBlobs []string `json:"missingBlobs,omitempty"`
+
+	Entries map[string]string `json:"entries"`
+}
+
+// IntegrityReport is the result of checking the content of every file and
+// version against its checksum, and the audit log chain.
+type IntegrityReport struct {
+	FilesVerified int64
+	// InvalidFiles are the ids of files whose content or a version is
+	// missing or does not match its checksum
+	InvalidFiles []uint
+
+	AuditEventsVerified int64
+	// FirstInvalidAuditEvent is the id of the first event breaking the
+	// audit chain, 0 if the chain is intact
+	FirstInvalidAuditEvent uint
+}
+
+func (r *IntegrityReport) Valid() bool {
+	return len(r.InvalidFiles) == 0 && r.FirstInvalidAuditEvent == 0
+}
+
+// Backup writes a gzipped tar archive of the files, including tombstones,
+// their metadata, versions and content, and the audit log to w. The records
+// and the keys of their content are read in a single transaction. Changes
+// are not blocked while the content is archived, content deleted since is
+// left out and listed in the manifest.
+func (d *fileStore) Backup(ctx context.Context, w io.Writer) (*BackupManifest, error) {
+	var (
+		files       []modelsv2.StoredFile
+		events      []modelsv2.AuditEvent
+		storedBlobs []modelsv2.StoredBlob
+	)
+
+	err := d.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().
+			Preload("Metadata").
+			Preload("File").
+			Preload("Versions").
+			Order("id").
+			Find(&files).Error
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := tx.Order("id").Find(&events).Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		return errors.WithStack(tx.Order("id").Find(&storedBlobs).Error)
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to read records")
+	}
+
+	manifest := &BackupManifest{
+		FormatVersion: backupFormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		Files:         int64(len(files)),
+		AuditEvents:   int64(len(events)),
+		Entries:       map[string]string{},
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	var (
+		keys  []string
+		sizes = map[string]int64{}
+	)
+	for i := range files {
+		if files[i].DeletedAt.Valid {
+			manifest.Tombstones++
+		}
+		manifest.Versions += int64(len(files[i].Versions))
+
+		keys = append(keys, files[i].File.BlobKey)
+		sizes[files[i].File.BlobKey] = int64(files[i].File.Size)
+		for _, version := range files[i].Versions {
+			keys = append(keys, version.BlobKey)
+			sizes[version.BlobKey] = int64(version.Size)
+		}
+	}
+	keys = uniqueKeys(keys)
+
+	// blobs are archived as stored, compressed and encrypted
+	for _, blob := range storedBlobs {
+		sizes[blob.BlobKey] = blob.StoredSize
+	}
+
+	if err := writeRecords(tw, manifest, backupFilesEntry, files); err != nil {
+		return nil, err
+	}
+
+	if err := writeRecords(tw, manifest, backupAuditEventsEntry, events); err != nil {
+		return nil, err
+	}
+
+	if err := writeRecords(tw, manifest, backupBlobsEntry, storedBlobs); err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		err := d.writeBlob(ctx, tw, manifest, key, sizes[key])
+		if errors.Is(err, blobstore.ErrNotFound) {
+			d.Log.Info("blob deleted during backup", "key", key)
+			manifest.MissingBlobs = append(manifest.MissingBlobs, key)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		manifest.Blobs++
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if err := writeEntry(tw, backupManifestEntry, int64(len(data)), bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return manifest, errors.WithStack(gz.Close())
+}
+
+// writeRecords writes records as json lines
+func writeRecords[T any](tw *tar.Writer, manifest *BackupManifest, name string, records []T) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i := range records {
+		if err := enc.Encode(&records[i]); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	manifest.Entries[name] = fmt.Sprintf("%x", sha256.Sum256(buf.Bytes()))
+	return writeEntry(tw, name, int64(buf.Len()), &buf)
+}
+
+func (d *fileStore) writeBlob(ctx context.Context, tw *tar.Writer, manifest *BackupManifest, key string, size int64) error {
+	rc, err := d.config.BlobStore.Get(ctx, key)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to open blob", "key", key)
+	}
+	defer rc.Close()
+
+	h := sha256.New()
+	name := backupBlobPrefix + key
+	if err := writeEntry(tw, name, size, io.TeeReader(rc, h)); err != nil {
+		return errors.WithDetails(err, "key", key)
+	}
+
+	manifest.Entries[name] = fmt.Sprintf("%x", h.Sum(nil))
+	return nil
+}
+
+func writeEntry(tw *tar.Writer, name string, size int64, r io.Reader) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0600,
+		Size:     size,
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	n, err := io.Copy(tw, r)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to write archive entry", "name", name)
+	}
+
+	if n != size {
+		return errors.WithDetails(errors.New("archive entry is shorter than its recorded size"), "name", name)
+	}
+
+	return nil
+}
+
+// Restore reads an archive written by Backup into the store, which must not
+// have any file or audit event. Every entry is checked against the manifest
+// before any record is written, on failure the restored blobs are removed.
+func (d *fileStore) Restore(ctx context.Context, r io.Reader) (manifest *BackupManifest, err error) {
+	var files, events int64
+	if err := d.WithContext(ctx).Unscoped().Model(&modelsv2.StoredFile{}).Count(&files).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := d.WithContext(ctx).Model(&modelsv2.AuditEvent{}).Count(&events).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if files != 0 || events != 0 {
+		return nil, errors.WithDetails(ErrRestoreNotEmpty, "files", files, "auditEvents", events)
+	}
+
+	var restoredBlobs []string
+	defer func() {
+		if err != nil {
+			d.deleteBlobs(restoredBlobs...)
+		}
+	}()
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidBackup, err.Error())
+	}
+
+	var (
+		tr       = tar.NewReader(gz)
+		records  = map[string][]byte{}
+		checksum = map[string]string{}
+	)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidBackup, err.Error())
+		}
+
+		if _, ok := checksum[header.Name]; ok || (manifest != nil) {
+			return nil, errors.WithDetails(errors.Wrap(ErrInvalidBackup, "unexpected entry"), "name", header.Name)
+		}
+
+		h := sha256.New()
+		switch {
+		case header.Name == backupManifestEntry:
+			manifest = &BackupManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, errors.Wrap(ErrInvalidBackup, "failed to read the manifest")
+			}
+			continue
+		case header.Name == backupFilesEntry || header.Name == backupAuditEventsEntry || header.Name == backupBlobsEntry:
+			data, err := io.ReadAll(io.TeeReader(tr, h))
+			if err != nil {
+				return nil, errors.Wrap(ErrInvalidBackup, err.Error())
+			}
+			records[header.Name] = data
+		case strings.HasPrefix(header.Name, backupBlobPrefix):
+			key := strings.TrimPrefix(header.Name, backupBlobPrefix)
+			restoredBlobs = append(restoredBlobs, key)
+			if _, err := d.config.BlobStore.Put(ctx, key, io.TeeReader(tr, h)); err != nil {
+				return nil, errors.WrapIfWithDetails(err, "failed to restore blob", "key", key)
+			}
+		default:
+			return nil, errors.WithDetails(errors.Wrap(ErrInvalidBackup, "unexpected entry"), "name", header.Name)
+		}
+
+		checksum[header.Name] = fmt.Sprintf("%x", h.Sum(nil))
+	}
+
+	if err := manifest.check(checksum); err != nil {
+		return nil, err
+	}
+
+	var duplicates []string
+	err = d.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := readRecords(records[backupFilesEntry], func(file *modelsv2.StoredFile) error {
+			return errors.WrapIfWithDetails(tx.Create(file).Error, "failed to restore file", "id", file.ID)
+		})
+		if err != nil {
+			return err
+		}
+
+		err = readRecords(records[backupAuditEventsEntry], func(event *modelsv2.AuditEvent) error {
+			return errors.WrapIfWithDetails(tx.Create(event).Error, "failed to restore audit event", "id", event.ID)
+		})
+		if err != nil {
+			return err
+		}
+
+		// archives of the first format have no blob records, their blobs are stored as is
+		err = readRecords(records[backupBlobsEntry], func(blob *modelsv2.StoredBlob) error {
+			return errors.WrapIfWithDetails(tx.Create(blob).Error, "failed to restore blob", "key", blob.BlobKey)
+		})
+		if err != nil {
+			return err
+		}
+
+		// the blobs are shared by checksum, archives of older stores may
+		// hold the same content more than once
+		duplicates, err = rebuildBlobs(tx)
+		if err != nil {
+			return err
+		}
+
+		if err := recountUsage(tx); err != nil {
+			return err
+		}
+
+		return resetSequences(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.deleteBlobs(duplicates...)
+
+	return manifest, nil
+}
+
+// resetSequences moves the postgres id sequences past the restored ids, the
+// other databases pick the next id from the table itself
+func resetSequences(tx *gorm.DB) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	tables := []interface{}{
+		&modelsv2.StoredFile{}, &modelsv2.StoredFileMetadata{}, &modelsv2.StoredFileContent{},
+		&modelsv2.StoredFileVersion{}, &modelsv2.StoredBlob{}, &modelsv2.AuditEvent{},
+	}
+
+	for _, model := range tables {
+		stmt := &gorm.Statement{DB: tx}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+
+		table := stmt.Schema.Table
+		err := tx.Exec(fmt.Sprintf(
+			"SELECT setval(pg_get_serial_sequence('%s', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM %s",
+			table, tx.Statement.Quote(table))).Error
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to reset sequence", "table", table)
+		}
+	}
+
+	return nil
+}
+
+// check compares the checksums of the entries read to the manifest
+func (m *BackupManifest) check(checksums map[string]string) error {
+	if m == nil {
+		return errors.Wrap(ErrInvalidBackup, "the manifest is missing")
+	}
+
+	if m.FormatVersion < 1 || m.FormatVersion > backupFormatVersion {
+		return errors.WithDetails(errors.Wrap(ErrInvalidBackup, "unsupported format version"), "formatVersion", m.FormatVersion)
+	}
+
+	if len(checksums) != len(m.Entries) {
+		return errors.WithDetails(errors.Wrap(ErrInvalidBackup, "entries are missing"), "expected", len(m.Entries), "found", len(checksums))
+	}
+
+	names := make([]string, 0, len(m.Entries))
+	for name := range m.Entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if checksums[name] != m.Entries[name] {
+			return errors.WithDetails(errors.Wrap(ErrInvalidBackup, "checksum mismatch"), "name", name)
+		}
+	}
+
+	return nil
+}
+
+// readRecords decodes json lines and calls fn with each record
+func readRecords[T any](data []byte, fn func(*T) error) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+
+	for scanner.Scan() {
+		record := new(T)
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return errors.Wrap(ErrInvalidBackup, err.Error())
+		}
+
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	return errors.WithStack(scanner.Err())
+}
+
+// VerifyIntegrity checks the content of every file and version, including
+// tombstones, against its checksum, and verifies the audit log chain.
+func (d *fileStore) VerifyIntegrity(ctx context.Context) (*IntegrityReport, error) {
+	report := &IntegrityReport{}
+	checked := map[string]bool{}
+
+	valid := func(key, checksum string) bool {
+		if key == "" {
+			return true
+		}
+
+		if ok, found := checked[key]; found {
+			return ok
+		}
+
+		ok := d.blobMatches(ctx, key, checksum, sha256.New())
+		checked[key] = ok
+		return ok
+	}
+
+	var files []modelsv2.StoredFile
+	err := d.WithContext(ctx).
+		Unscoped().
+		Preload("File").
+		Preload("Versions").
+		FindInBatches(&files, 100, func(tx *gorm.DB, batch int) error {
+			for _, file := range files {
+				ok := valid(file.File.BlobKey, file.File.Checksum)
+				for _, version := range file.Versions {
+					ok = valid(version.BlobKey, version.Checksum) && ok
+				}
+
+				report.FilesVerified++
+				if !ok {
+					report.InvalidFiles = append(report.InvalidFiles, file.ID)
+				}
+			}
+			return ctx.Err()
+		}).Error
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	report.AuditEventsVerified, report.FirstInvalidAuditEvent, err = d.VerifyAuditLog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// blobMatches returns true if the blob under key exists and its original
+// content matches checksum
+func (d *fileStore) blobMatches(ctx context.Context, key, checksum string, h hash.Hash) bool {
+	rc, err := d.openBlob(ctx, key)
+	if err != nil {
+		d.Log.Error(err, "failed to open blob", "key", key)
+		return false
+	}
+	defer rc.Close()
+
+	if _, err := io.Copy(h, rc); err != nil {
+		d.Log.Error(err, "failed to read blob", "key", key)
+		return false
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)) == checksum
+}
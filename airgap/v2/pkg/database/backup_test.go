@@ -0,0 +1,236 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var _ = Describe("backup", func() {
+	var (
+		ctx = context.Background()
+
+		sut, restored            StoredFileStore
+		closers                  []io.Closer
+		restoredDB               *gorm.DB
+		blobDir, restoredBlobDir string
+
+		keptID, deletedID string
+	)
+
+	open := func(name string) (*gorm.DB, string, StoredFileStore) {
+		writer := logger.Writer(log.New(GinkgoWriter, "gorm-", log.LstdFlags))
+		db := openTestDB(name, &gorm.Config{
+			Logger: logger.New(writer, logger.Config{LogLevel: logger.Error}),
+		})
+
+		dir := GinkgoT().TempDir()
+		blobs, err := blobstore.NewLocal(dir)
+		Expect(err).To(Succeed())
+		Expect(Migrate(db, blobs)).To(Succeed())
+
+		store, closer := New(db, FileStoreConfig{BlobStore: blobs})
+		closers = append(closers, closer)
+		return db, dir, store
+	}
+
+	save := func(name, content string) string {
+		id, err := sut.Save(ctx, &modelsv2.StoredFile{
+			Name:       name,
+			Source:     "redhat-marketplace",
+			SourceType: "report",
+			File:       modelsv2.StoredFileContent{Content: []byte(content), MimeType: "text/plain"},
+			Metadata:   []modelsv2.StoredFileMetadata{{Key: "content", Value: content}},
+		})
+		Expect(err).To(Succeed())
+		return id
+	}
+
+	backup := func() []byte {
+		var buf bytes.Buffer
+		manifest, err := sut.Backup(ctx, &buf)
+		Expect(err).To(Succeed())
+		Expect(manifest.Files).To(Equal(int64(2)))
+		Expect(manifest.Tombstones).To(Equal(int64(1)))
+		Expect(manifest.Versions).To(Equal(int64(3)))
+		Expect(manifest.Blobs).To(Equal(int64(3)))
+		Expect(manifest.AuditEvents).To(Equal(int64(1)))
+		return buf.Bytes()
+	}
+
+	BeforeEach(func() {
+		closers = nil
+		_, blobDir, sut = open("backup.gorm.db")
+		restoredDB, restoredBlobDir, restored = open("restored.gorm.db")
+
+		save("kept.txt", "first")
+		keptID = save("kept.txt", "second")
+		deletedID = save("deleted.txt", "deleted")
+		Expect(sut.Delete(ctx, deletedID, false)).To(Succeed())
+		Expect(sut.AppendAuditEvent(ctx, &modelsv2.AuditEvent{Method: "DeleteFile", Name: "deleted.txt"})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		for _, closer := range closers {
+			Expect(closer.Close()).To(Succeed())
+		}
+	})
+
+	It("should restore files, versions, tombstones and the audit log", func() {
+		manifest, err := restored.Restore(ctx, bytes.NewReader(backup()))
+		Expect(err).To(Succeed())
+		Expect(manifest.Entries).To(HaveLen(6))
+
+		file, err := restored.Download(ctx, keptID)
+		Expect(err).To(Succeed())
+		Expect(string(file.File.Content)).To(Equal("second"))
+		Expect(file.Metadata).To(HaveLen(1))
+		Expect(file.Version).To(Equal(uint64(2)))
+
+		_, rc, err := restored.OpenVersion(ctx, keptID, 1)
+		Expect(err).To(Succeed())
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		Expect(err).To(Succeed())
+		Expect(string(content)).To(Equal("first"))
+
+		files, _, err := restored.List(ctx)
+		Expect(err).To(Succeed())
+		Expect(files).To(HaveLen(1))
+
+		files, _, err = restored.List(ctx, ShowDeleted())
+		Expect(err).To(Succeed())
+		Expect(files).To(HaveLen(2))
+
+		report, err := restored.VerifyIntegrity(ctx)
+		Expect(err).To(Succeed())
+		Expect(report.Valid()).To(BeTrue())
+		Expect(report.FilesVerified).To(Equal(int64(2)))
+		Expect(report.AuditEventsVerified).To(Equal(int64(1)))
+
+		Expect(restored.AppendAuditEvent(ctx, &modelsv2.AuditEvent{Method: "UploadFile"})).To(Succeed())
+		_, invalid, err := restored.VerifyAuditLog(ctx)
+		Expect(err).To(Succeed())
+		Expect(invalid).To(BeZero())
+	})
+
+	It("should leave out content deleted while the backup runs", func() {
+		files, _, err := sut.List(ctx, ShowDeleted())
+		Expect(err).To(Succeed())
+
+		var key string
+		for _, file := range files {
+			if strconv.Itoa(int(file.ID)) == deletedID {
+				key = file.File.BlobKey
+			}
+		}
+		Expect(key).ToNot(BeEmpty())
+
+		// the tombstone is cleaned once its records are read
+		blobs, err := blobstore.NewLocal(blobDir)
+		Expect(err).To(Succeed())
+		Expect(blobs.Delete(ctx, key)).To(Succeed())
+
+		var buf bytes.Buffer
+		manifest, err := sut.Backup(ctx, &buf)
+		Expect(err).To(Succeed())
+		Expect(manifest.Files).To(Equal(int64(2)))
+		Expect(manifest.Blobs).To(Equal(int64(2)))
+		Expect(manifest.MissingBlobs).To(ConsistOf(key))
+
+		_, err = restored.Restore(ctx, &buf)
+		Expect(err).To(Succeed())
+
+		report, err := restored.VerifyIntegrity(ctx)
+		Expect(err).To(Succeed())
+		Expect(report.InvalidFiles).To(HaveLen(1))
+		Expect(strconv.Itoa(int(report.InvalidFiles[0]))).To(Equal(deletedID))
+	})
+
+	It("should only restore into an empty store", func() {
+		archive := backup()
+		_, err := sut.Restore(ctx, bytes.NewReader(archive))
+		Expect(err).To(MatchError(ErrRestoreNotEmpty))
+	})
+
+	It("should reject a changed archive without restoring anything", func() {
+		// rewrite the archive with a changed blob, keeping the manifest
+		gz, err := gzip.NewReader(bytes.NewReader(backup()))
+		Expect(err).To(Succeed())
+		tr := tar.NewReader(gz)
+
+		var buf bytes.Buffer
+		gzw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gzw)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).To(Succeed())
+
+			data, err := io.ReadAll(tr)
+			Expect(err).To(Succeed())
+			if filepath.Dir(header.Name) == "blobs" {
+				data = bytes.ToUpper(data)
+			}
+
+			Expect(tw.WriteHeader(header)).To(Succeed())
+			_, err = tw.Write(data)
+			Expect(err).To(Succeed())
+		}
+		Expect(tw.Close()).To(Succeed())
+		Expect(gzw.Close()).To(Succeed())
+
+		_, err = restored.Restore(ctx, &buf)
+		Expect(err).To(MatchError(ErrInvalidBackup))
+
+		var count int64
+		Expect(restoredDB.Unscoped().Model(&modelsv2.StoredFile{}).Count(&count).Error).To(Succeed())
+		Expect(count).To(BeZero())
+		Expect(filepath.Glob(filepath.Join(restoredBlobDir, "*", "*"))).To(BeEmpty())
+
+		_, err = restored.Restore(ctx, bytes.NewReader([]byte("not an archive")))
+		Expect(err).To(MatchError(ErrInvalidBackup))
+	})
+
+	It("should report files with changed content", func() {
+		file, err := sut.Get(ctx, keptID)
+		Expect(err).To(Succeed())
+		key := file.File.BlobKey
+		Expect(os.WriteFile(filepath.Join(blobDir, key[:2], key), []byte("changed"), 0600)).To(Succeed())
+
+		report, err := sut.VerifyIntegrity(ctx)
+		Expect(err).To(Succeed())
+		Expect(report.Valid()).To(BeFalse())
+		id, _ := strconv.ParseUint(keptID, 10, 64)
+		Expect(report.InvalidFiles).To(ConsistOf(uint(id)))
+	})
+})
@@ -0,0 +1,75 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"gorm.io/gorm"
+)
+
+// errRollback rolls back the transaction of a dry run
+const errRollback = errors.Sentinel("rollback")
+
+// batch holds the blobs whose deletion waits for the end of a batch
+type batch struct {
+	// created are the blobs put in the batch, they are deleted when it is
+	// rolled back
+	created []string
+	// orphans are the blobs no longer referenced once the batch commits
+	orphans []string
+}
+
+// Batch calls fn with a store whose changes are made in a single transaction,
+// committed once fn returns nil and rolled back when it returns an error,
+// which Batch returns. With dryRun the changes are always rolled back. The
+// store passed to fn must not be used once fn returns.
+func (d *fileStore) Batch(ctx context.Context, dryRun bool, fn func(store StoredFileStore) error) error {
+	b := &batch{}
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txStore := &fileStore{
+			DB:      tx,
+			Log:     d.Log,
+			config:  d.config,
+			cursors: d.cursors,
+			batch:   b,
+		}
+
+		if err := fn(txStore); err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errRollback
+		}
+		return nil
+	})
+
+	if err != nil {
+		d.deleteBlobs(b.created...)
+
+		if errors.Is(err, errRollback) {
+			return nil
+		}
+		return err
+	}
+
+	d.deleteBlobs(b.orphans...)
+	d.changes.notify()
+
+	return nil
+}
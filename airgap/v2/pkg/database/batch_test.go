@@ -0,0 +1,109 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("batch", func() {
+	var (
+		blobs blobstore.BlobStore
+		ctx   = context.Background()
+		sut   StoredFileStore
+		id    string
+	)
+
+	blobKey := func(store StoredFileStore, id string) string {
+		file, err := store.Get(ctx, id)
+		Expect(err).To(Succeed())
+		return file.File.BlobKey
+	}
+
+	hasBlob := func(key string) bool {
+		rc, err := blobs.Get(ctx, key)
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return false
+		}
+		Expect(err).To(Succeed())
+		rc.Close()
+		return true
+	}
+
+	BeforeEach(func() {
+		var db *gorm.DB
+		db, blobs = openTestStore("batch.gorm.db")
+
+		sut = newTestStore(db, FileStoreConfig{BlobStore: blobs})
+		id = saveTestFile(ctx, sut, testFile("report.tar.gz", "old"))
+	})
+
+	It("should keep the blobs of the files deleted until the batch commits", func() {
+		key := blobKey(sut, id)
+
+		err := sut.Batch(ctx, false, func(store StoredFileStore) error {
+			Expect(store.Delete(ctx, id, true)).To(Succeed())
+			Expect(hasBlob(key)).To(BeTrue())
+			return nil
+		})
+		Expect(err).To(Succeed())
+		Expect(hasBlob(key)).To(BeFalse())
+
+		file, err := sut.Get(ctx, id)
+		Expect(err).To(Succeed())
+		Expect(file.ID).To(BeZero())
+	})
+
+	It("should roll back the changes and remove the blobs created", func() {
+		key := blobKey(sut, id)
+		var created string
+
+		err := sut.Batch(ctx, false, func(store StoredFileStore) error {
+			Expect(store.Delete(ctx, id, true)).To(Succeed())
+
+			newID, err := store.Save(ctx, testFile("events.json", "new"))
+			Expect(err).To(Succeed())
+			created = blobKey(store, newID)
+
+			return errors.New("failed")
+		})
+		Expect(err).To(MatchError("failed"))
+
+		Expect(hasBlob(key)).To(BeTrue())
+		Expect(hasBlob(created)).To(BeFalse())
+		Expect(blobKey(sut, id)).To(Equal(key))
+
+		files, _, err := sut.List(ctx)
+		Expect(err).To(Succeed())
+		Expect(files).To(HaveLen(1))
+	})
+
+	It("should always roll back a dry run", func() {
+		err := sut.Batch(ctx, true, func(store StoredFileStore) error {
+			return store.Delete(ctx, id, false)
+		})
+		Expect(err).To(Succeed())
+
+		file, err := sut.Get(ctx, id)
+		Expect(err).To(Succeed())
+		Expect(file.DeletedAt.Valid).To(BeFalse())
+	})
+})
@@ -0,0 +1,256 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"emperror.dev/errors"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// File content is stored once per checksum. A StoredBlob counts the file
+// contents and versions referencing its blob, the blob is only deleted once
+// the last reference is released. The counts change in the transaction
+// changing the references, the blobs are deleted once it commits.
+
+// claimBlob records the blob holding the new content of a file. When a blob
+// with the same checksum is already stored, content is pointed at it and the
+// key of the new, duplicate blob is returned to be deleted.
+func claimBlob(tx *gorm.DB, content *modelsv2.StoredFileContent, blob *modelsv2.StoredBlob) (duplicate string, err error) {
+	if blob.BlobKey == "" {
+		return "", nil
+	}
+
+	result := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "checksum"}},
+		DoNothing: true,
+	}).Create(blob)
+	if result.Error != nil {
+		return "", errors.WithStack(result.Error)
+	}
+
+	if result.RowsAffected == 1 {
+		return "", nil
+	}
+
+	existing := &modelsv2.StoredBlob{}
+	if err := tx.Where("checksum = ?", content.Checksum).First(existing).Error; err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	duplicate = blob.BlobKey
+	content.BlobKey = existing.BlobKey
+	return duplicate, nil
+}
+
+// retainBlobs adds a reference to the blobs for every time their key is listed
+func retainBlobs(tx *gorm.DB, keys ...string) error {
+	for key, n := range countKeys(keys) {
+		if err := tx.Model(&modelsv2.StoredBlob{}).
+			Where("blob_key = ?", key).
+			UpdateColumn("ref_count", gorm.Expr("ref_count + ?", n)).Error; err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+// releaseBlobs removes a reference to the blobs for every time their key is
+// listed. It returns the keys of the blobs no longer referenced, which are
+// deleted once the transaction commits.
+func releaseBlobs(tx *gorm.DB, keys ...string) ([]string, error) {
+	counts := countKeys(keys)
+	if len(counts) == 0 {
+		return nil, nil
+	}
+
+	released := make([]string, 0, len(counts))
+	for key, n := range counts {
+		if err := tx.Model(&modelsv2.StoredBlob{}).
+			Where("blob_key = ?", key).
+			UpdateColumn("ref_count", gorm.Expr("ref_count - ?", n)).Error; err != nil {
+			return nil, errors.WithStack(err)
+		}
+		released = append(released, key)
+	}
+
+	var blobs []modelsv2.StoredBlob
+	if err := tx.Select("blob_key", "size").
+		Where("blob_key in ? AND ref_count <= 0", released).
+		Find(&blobs).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if len(blobs) == 0 {
+		return nil, nil
+	}
+
+	var (
+		orphans = make([]string, 0, len(blobs))
+		bytes   int64
+	)
+	for _, blob := range blobs {
+		orphans = append(orphans, blob.BlobKey)
+		bytes += int64(blob.Size)
+	}
+
+	if err := tx.Where("blob_key in ?", orphans).
+		Delete(&modelsv2.StoredBlob{}).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// the blobs deleted no longer count in the global usage
+	if err := addUsage(tx, globalUsage(), QuotaLimits{}, Usage{Bytes: -bytes}); err != nil {
+		return nil, err
+	}
+
+	return orphans, nil
+}
+
+// sourceBlobRefs returns the blob keys of the contents and versions of the
+// files by their source
+func sourceBlobRefs(tx *gorm.DB, fileIDs interface{}) (map[string][]string, error) {
+	var refs []struct {
+		Source  string
+		BlobKey string
+	}
+	err := tx.Raw(`SELECT stored_files.source, stored_file_contents.blob_key
+FROM stored_file_contents JOIN stored_files ON stored_files.id = stored_file_contents.file_id
+WHERE stored_files.id IN (?) AND stored_file_contents.blob_key <> ''
+UNION
+SELECT stored_files.source, stored_file_versions.blob_key
+FROM stored_file_versions JOIN stored_files ON stored_files.id = stored_file_versions.file_id
+WHERE stored_files.id IN (?) AND stored_file_versions.blob_key <> ''`, fileIDs, fileIDs).
+		Scan(&refs).Error
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	sources := map[string][]string{}
+	for _, ref := range refs {
+		sources[ref.Source] = append(sources[ref.Source], ref.BlobKey)
+	}
+
+	return sources, nil
+}
+
+// blobRefs returns the blob key of every content and version of the files,
+// once per reference
+func blobRefs(tx *gorm.DB, fileIDs interface{}) ([]string, error) {
+	var keys, versionKeys []string
+	if err := tx.Model(&modelsv2.StoredFileContent{}).
+		Where("file_id in (?) AND blob_key <> ''", fileIDs).
+		Pluck("blob_key", &keys).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if err := tx.Model(&modelsv2.StoredFileVersion{}).
+		Where("file_id in (?) AND blob_key <> ''", fileIDs).
+		Pluck("blob_key", &versionKeys).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return append(keys, versionKeys...), nil
+}
+
+func countKeys(keys []string) map[string]int {
+	counts := map[string]int{}
+	for _, key := range keys {
+		if key != "" {
+			counts[key]++
+		}
+	}
+	return counts
+}
+
+// rebuildBlobs points every content and version at a single blob per
+// checksum and recounts the references of the blobs. It returns the keys of
+// the duplicate blobs no longer referenced, to delete once the transaction
+// commits.
+func rebuildBlobs(tx *gorm.DB) ([]string, error) {
+	type blobRef struct {
+		BlobKey  string
+		Checksum string
+		Size     int
+		Refs     int64
+	}
+
+	var refs []blobRef
+	if err := tx.Raw(`SELECT blob_key, MAX(checksum) AS checksum, MAX(size) AS size, COUNT(*) AS refs FROM (
+SELECT blob_key, checksum, size FROM stored_file_contents WHERE blob_key <> ''
+UNION ALL
+SELECT blob_key, checksum, size FROM stored_file_versions WHERE blob_key <> ''
+) blob_refs GROUP BY blob_key ORDER BY blob_key`).Scan(&refs).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// the blobs already recorded keep how they are stored
+	var recorded []modelsv2.StoredBlob
+	if err := tx.Find(&recorded).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	byKey := map[string]modelsv2.StoredBlob{}
+	for _, blob := range recorded {
+		byKey[blob.BlobKey] = blob
+	}
+
+	var (
+		blobs      []*modelsv2.StoredBlob
+		byChecksum = map[string]*modelsv2.StoredBlob{}
+		duplicates []string
+	)
+
+	for _, ref := range refs {
+		blob, ok := byChecksum[ref.Checksum]
+		if !ok {
+			blob = &modelsv2.StoredBlob{Checksum: ref.Checksum, Size: ref.Size, BlobKey: ref.BlobKey, StoredSize: int64(ref.Size)}
+			if stored, ok := byKey[ref.BlobKey]; ok {
+				blob.Encoding = stored.Encoding
+				blob.KeyID = stored.KeyID
+				blob.DataKey = stored.DataKey
+				blob.StoredSize = stored.StoredSize
+			}
+			byChecksum[ref.Checksum] = blob
+			blobs = append(blobs, blob)
+		} else {
+			for _, model := range []interface{}{&modelsv2.StoredFileContent{}, &modelsv2.StoredFileVersion{}} {
+				if err := tx.Model(model).
+					Where("blob_key = ?", ref.BlobKey).
+					UpdateColumn("blob_key", blob.BlobKey).Error; err != nil {
+					return nil, errors.WithStack(err)
+				}
+			}
+			duplicates = append(duplicates, ref.BlobKey)
+		}
+
+		blob.RefCount += ref.Refs
+	}
+
+	if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).
+		Delete(&modelsv2.StoredBlob{}).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if len(blobs) > 0 {
+		if err := tx.CreateInBatches(blobs, 100).Error; err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	return duplicates, nil
+}
@@ -0,0 +1,412 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/klauspost/compress/zstd"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/envelope"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	ErrUnknownCompression = errors.Sentinel("unknown compression algorithm")
+	ErrNotEncrypted       = errors.Sentinel("content encryption is not enabled")
+)
+
+// Compression algorithms of the stored content
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+const (
+	// maxCompressMinSize bounds the size read before compressing content
+	maxCompressMinSize = 64 * 1024
+	// sniffSize is enough to read the magic numbers of compressed formats
+	sniffSize = 8
+)
+
+// CompressionPolicy sets how content is compressed in the blob store. Content
+// that is already compressed, such as report tarballs, is stored as is.
+type CompressionPolicy struct {
+	// Algorithm is none, gzip or zstd, content is not compressed when empty
+	Algorithm string `json:"algorithm" mapstructure:"algorithm"`
+	// MinSize is the size in bytes under which content is not compressed, up to 64KiB
+	MinSize int `json:"minSize" mapstructure:"minSize"`
+}
+
+// Validate checks the algorithm is known
+func (p CompressionPolicy) Validate() error {
+	switch p.Algorithm {
+	case "", CompressionNone, CompressionGzip, CompressionZstd:
+		return nil
+	default:
+		return errors.WithDetails(ErrUnknownCompression, "algorithm", p.Algorithm)
+	}
+}
+
+func (p CompressionPolicy) enabled() bool {
+	return p.Algorithm != "" && p.Algorithm != CompressionNone
+}
+
+// compressedSignatures are the magic numbers of formats not worth compressing
+var compressedSignatures = [][]byte{
+	{0x1f, 0x8b},             // gzip
+	{0x28, 0xb5, 0x2f, 0xfd}, // zstd
+	{'P', 'K', 0x03, 0x04},   // zip
+	{'B', 'Z', 'h'},          // bzip2
+	{0xfd, '7', 'z', 'X', 'Z', 0x00},
+	{0x89, 'P', 'N', 'G'},
+	{0xff, 0xd8, 0xff}, // jpeg
+}
+
+func alreadyCompressed(head []byte) bool {
+	for _, signature := range compressedSignatures {
+		if bytes.HasPrefix(head, signature) {
+			return true
+		}
+	}
+	return false
+}
+
+// compression returns the algorithm compressing the content starting with
+// head, none when it is stored as is
+func (p CompressionPolicy) compression(head []byte) string {
+	if !p.enabled() || len(head) < p.minSize() || alreadyCompressed(head) {
+		return CompressionNone
+	}
+	return p.Algorithm
+}
+
+func (p CompressionPolicy) minSize() int {
+	if p.MinSize > maxCompressMinSize {
+		return maxCompressMinSize
+	}
+	return p.MinSize
+}
+
+// countingReader counts the bytes read
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// putContent streams r into a new blob, compressed and encrypted as
+// configured, and records its key, size and checksum on content. It returns
+// the blob to claim when the content is saved.
+func (d *fileStore) putContent(ctx context.Context, content *modelsv2.StoredFileContent, r io.Reader) (*modelsv2.StoredBlob, error) {
+	blob := &modelsv2.StoredBlob{BlobKey: blobstore.NewKey()}
+	h := sha256.New()
+	counter := &countingReader{r: io.TeeReader(r, h)}
+
+	encoded, err := d.encodeContent(counter, blob)
+	if err != nil {
+		return nil, err
+	}
+	defer encoded.Close()
+
+	n, err := d.config.BlobStore.Put(ctx, blob.BlobKey, encoded)
+	if err != nil {
+		d.deleteBlobs(blob.BlobKey)
+		return nil, errors.WrapIf(err, "failed to store file content")
+	}
+
+	if d.batch != nil {
+		d.batch.created = append(d.batch.created, blob.BlobKey)
+	}
+
+	blob.StoredSize = n
+	blob.Size = int(counter.n)
+	blob.Checksum = fmt.Sprintf("%x", h.Sum(nil))
+
+	content.BlobKey = blob.BlobKey
+	content.Size = blob.Size
+	content.Checksum = blob.Checksum
+	return blob, nil
+}
+
+// encodeContent returns a reader of the content of r as stored in the blob
+// store, recording the compression and wrapped data key on blob. Closing the
+// reader stops the encoding.
+func (d *fileStore) encodeContent(r io.Reader, blob *modelsv2.StoredBlob) (io.ReadCloser, error) {
+	policy := d.config.Compression
+	br := bufio.NewReaderSize(r, maxCompressMinSize)
+
+	if policy.enabled() {
+		// enough to check the size and the magic numbers
+		size := policy.minSize()
+		if size < sniffSize {
+			size = sniffSize
+		}
+
+		head, err := br.Peek(size)
+		if err != nil && err != io.EOF {
+			return nil, errors.WrapIf(err, "failed to read file content")
+		}
+
+		if algorithm := policy.compression(head); algorithm != CompressionNone {
+			blob.Encoding = algorithm
+		}
+	}
+
+	var dataKey []byte
+	if keyring := d.config.Keyring; keyring != nil {
+		var err error
+		dataKey, blob.DataKey, blob.KeyID, err = keyring.NewDataKey()
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to create data key")
+		}
+	}
+
+	if blob.Encoding == "" && dataKey == nil {
+		return io.NopCloser(br), nil
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(encode(pw, br, blob.Encoding, dataKey))
+	}()
+
+	return pr, nil
+}
+
+// encode compresses then encrypts the content of r to w
+func encode(w io.Writer, r io.Reader, encoding string, dataKey []byte) error {
+	var closers []io.Closer
+
+	if dataKey != nil {
+		ew, err := envelope.NewWriter(w, dataKey)
+		if err != nil {
+			return err
+		}
+		w = ew
+		closers = append(closers, ew)
+	}
+
+	switch encoding {
+	case CompressionGzip:
+		gw := gzip.NewWriter(w)
+		w = gw
+		closers = append(closers, gw)
+	case CompressionZstd:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		w = zw
+		closers = append(closers, zw)
+	}
+
+	if _, err := io.Copy(w, r); err != nil {
+		return err
+	}
+
+	// the compression is flushed before the encryption
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].Close(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+// openBlob returns a reader of the original content of the blob under key
+func (d *fileStore) openBlob(ctx context.Context, key string) (io.ReadCloser, error) {
+	var blobs []modelsv2.StoredBlob
+	if err := d.WithContext(ctx).
+		Where("blob_key = ?", key).
+		Limit(1).
+		Find(&blobs).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	rc, err := d.config.BlobStore.Get(ctx, key)
+	if err != nil || len(blobs) == 0 {
+		return rc, err
+	}
+
+	decoded, err := d.decodeContent(rc, &blobs[0])
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+
+	return decoded, nil
+}
+
+// decodeContent returns a reader decrypting and decompressing the stored
+// content of blob read from rc
+func (d *fileStore) decodeContent(rc io.ReadCloser, blob *modelsv2.StoredBlob) (io.ReadCloser, error) {
+	var r io.Reader = rc
+
+	if blob.KeyID != "" {
+		if d.config.Keyring == nil {
+			return nil, errors.WithDetails(envelope.ErrUnknownKey, "keyID", blob.KeyID, "blobKey", blob.BlobKey)
+		}
+
+		dataKey, err := d.config.Keyring.Unwrap(blob.KeyID, blob.DataKey)
+		if err != nil {
+			return nil, errors.WithDetails(err, "blobKey", blob.BlobKey)
+		}
+
+		r, err = envelope.NewReader(r, dataKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch blob.Encoding {
+	case "", CompressionNone:
+		return &readCloser{Reader: r, closers: []io.Closer{rc}}, nil
+	case CompressionGzip:
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to read compressed content", "blobKey", blob.BlobKey)
+		}
+		return &readCloser{Reader: gr, closers: []io.Closer{gr, rc}}, nil
+	case CompressionZstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to read compressed content", "blobKey", blob.BlobKey)
+		}
+		return &readCloser{Reader: zr, closers: []io.Closer{zstdCloser{zr}, rc}}, nil
+	default:
+		return nil, errors.WithDetails(ErrUnknownCompression, "algorithm", blob.Encoding, "blobKey", blob.BlobKey)
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *readCloser) Close() error {
+	var err error
+	for _, c := range r.closers {
+		err = errors.Append(err, c.Close())
+	}
+	return err
+}
+
+type zstdCloser struct {
+	*zstd.Decoder
+}
+
+func (z zstdCloser) Close() error {
+	z.Decoder.Close()
+	return nil
+}
+
+// KeyRotation is the result of rewrapping the data keys
+type KeyRotation struct {
+	// Rotated is the number of data keys rewrapped with the primary key
+	Rotated int64
+	// Skipped are the blob keys of the data keys the keyring can not
+	// unwrap, such as those of a key encryption key no longer mounted
+	Skipped []string
+}
+
+// RotateKeys wraps the data keys of the blobs with the primary key of the
+// keyring once it changed, the content is not rewritten. A data key that
+// can not be unwrapped is skipped and reported, the others are still
+// rewrapped. Content stored in plaintext, before the keyring was set, has no
+// data key and stays in plaintext. It returns ErrNotEncrypted without a
+// keyring.
+func (d *fileStore) RotateKeys(ctx context.Context) (*KeyRotation, error) {
+	keyring := d.config.Keyring
+	if keyring == nil {
+		return nil, ErrNotEncrypted
+	}
+
+	const batchSize = 100
+
+	result := &KeyRotation{}
+	var (
+		lastID uint
+		done   bool
+	)
+	for !done {
+		err := d.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var blobs []modelsv2.StoredBlob
+			if err := tx.
+				Where("id > ? AND key_id <> '' AND key_id <> ?", lastID, keyring.Primary()).
+				Order("id").
+				Limit(batchSize).
+				Find(&blobs).Error; err != nil {
+				return errors.WithStack(err)
+			}
+
+			if len(blobs) != 0 {
+				lastID = blobs[len(blobs)-1].ID
+			}
+
+			for _, blob := range blobs {
+				wrapped, keyID, err := keyring.Rewrap(blob.KeyID, blob.DataKey)
+				if errors.Is(err, envelope.ErrUnknownKey) || errors.Is(err, envelope.ErrInvalidKey) {
+					d.Log.Error(err, "skipped data key", "blobKey", blob.BlobKey, "keyID", blob.KeyID)
+					result.Skipped = append(result.Skipped, blob.BlobKey)
+					continue
+				}
+				if err != nil {
+					return errors.WithDetails(err, "blobKey", blob.BlobKey)
+				}
+
+				// only the key read is replaced
+				updated := tx.Model(&modelsv2.StoredBlob{}).
+					Where("id = ? AND key_id = ?", blob.ID, blob.KeyID).
+					UpdateColumns(map[string]interface{}{"key_id": keyID, "data_key": wrapped})
+				if updated.Error != nil {
+					return errors.WithStack(updated.Error)
+				}
+				result.Rotated += updated.RowsAffected
+			}
+
+			// the audit event of ctx is recorded with the last batch
+			if done = len(blobs) < batchSize; !done {
+				return nil
+			}
+
+			return auditOperation(ctx, tx, map[string]string{
+				"dataKeysRotated": strconv.FormatInt(result.Rotated, 10),
+				"dataKeysSkipped": strconv.Itoa(len(result.Skipped)),
+			})
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
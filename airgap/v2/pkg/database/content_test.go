@@ -0,0 +1,259 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/envelope"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("content encoding", func() {
+	var (
+		db      *gorm.DB
+		blobs   blobstore.BlobStore
+		ctx     = context.Background()
+		sut     StoredFileStore
+		config  FileStoreConfig
+		payload = strings.Repeat("cluster-1,usage,42\n", 1000)
+	)
+
+	newKey := func(b byte) []byte {
+		return bytes.Repeat([]byte{b}, envelope.KeySize)
+	}
+
+	BeforeEach(func() {
+		db, blobs = openTestStore("content.gorm.db")
+
+		keyring, err := envelope.NewKeyring("", map[string][]byte{"kek-1": newKey(1)})
+		Expect(err).To(Succeed())
+
+		config = FileStoreConfig{
+			BlobStore:   blobs,
+			Compression: CompressionPolicy{Algorithm: CompressionZstd, MinSize: 512},
+			Keyring:     keyring,
+		}
+	})
+
+	JustBeforeEach(func() {
+		sut = newTestStore(db, config)
+	})
+
+	save := func(name string, content []byte) string {
+		return saveTestFile(ctx, sut, testFile(name, string(content)))
+	}
+
+	storedBlob := func(id string) (modelsv2.StoredBlob, []byte) {
+		file, err := sut.Get(ctx, id)
+		Expect(err).To(Succeed())
+
+		blob := modelsv2.StoredBlob{}
+		Expect(db.Where("blob_key = ?", file.File.BlobKey).First(&blob).Error).To(Succeed())
+
+		rc, err := blobs.Get(ctx, blob.BlobKey)
+		Expect(err).To(Succeed())
+		defer rc.Close()
+		raw, err := io.ReadAll(rc)
+		Expect(err).To(Succeed())
+		return blob, raw
+	}
+
+	It("should compress and encrypt content at rest", func() {
+		id := save("usage.csv", []byte(payload))
+
+		blob, raw := storedBlob(id)
+		Expect(blob.Encoding).To(Equal(CompressionZstd))
+		Expect(blob.KeyID).To(Equal("kek-1"))
+		Expect(blob.Size).To(Equal(len(payload)))
+		Expect(blob.StoredSize).To(Equal(int64(len(raw))))
+		Expect(blob.StoredSize).To(BeNumerically("<", len(payload)))
+		Expect(bytes.Contains(raw, []byte("cluster-1"))).To(BeFalse())
+
+		file, err := sut.Download(ctx, id)
+		Expect(err).To(Succeed())
+		Expect(string(file.File.Content)).To(Equal(payload))
+		Expect(file.File.Checksum).To(Equal(fmt.Sprintf("%x", sha256.Sum256([]byte(payload)))))
+
+		report, err := sut.VerifyIntegrity(ctx)
+		Expect(err).To(Succeed())
+		Expect(report.Valid()).To(BeTrue())
+	})
+
+	It("should not compress content that is already compressed", func() {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(payload))
+		Expect(err).To(Succeed())
+		Expect(gz.Close()).To(Succeed())
+		tarball := buf.Bytes()
+
+		id := save("report.tar.gz", tarball)
+
+		blob, _ := storedBlob(id)
+		Expect(blob.Encoding).To(BeEmpty())
+		Expect(blob.KeyID).To(Equal("kek-1"))
+
+		file, err := sut.Download(ctx, id)
+		Expect(err).To(Succeed())
+		Expect(file.File.Content).To(Equal(tarball))
+	})
+
+	Context("without a keyring", func() {
+		BeforeEach(func() {
+			config.Keyring = nil
+			config.Compression = CompressionPolicy{Algorithm: CompressionGzip}
+		})
+
+		It("should only compress content", func() {
+			id := save("usage.csv", []byte(payload))
+
+			blob, raw := storedBlob(id)
+			Expect(blob.Encoding).To(Equal(CompressionGzip))
+			Expect(blob.KeyID).To(BeEmpty())
+
+			zr, err := gzip.NewReader(bytes.NewReader(raw))
+			Expect(err).To(Succeed())
+			content, err := io.ReadAll(zr)
+			Expect(err).To(Succeed())
+			Expect(string(content)).To(Equal(payload))
+
+			_, err = sut.RotateKeys(ctx)
+			Expect(err).To(MatchError(ErrNotEncrypted))
+		})
+	})
+
+	It("should rewrap data keys without rewriting content", func() {
+		first := save("first.csv", []byte(payload))
+		second := save("second.csv", []byte("small"))
+		_, before := storedBlob(first)
+
+		keyring, err := envelope.NewKeyring("kek-2", map[string][]byte{
+			"kek-1": newKey(1),
+			"kek-2": newKey(2),
+		})
+		Expect(err).To(Succeed())
+		config.Keyring = keyring
+		sut = newTestStore(db, config)
+
+		rotation, err := sut.RotateKeys(ctx)
+		Expect(err).To(Succeed())
+		Expect(rotation.Rotated).To(Equal(int64(2)))
+		Expect(rotation.Skipped).To(BeEmpty())
+
+		blob, after := storedBlob(first)
+		Expect(blob.KeyID).To(Equal("kek-2"))
+		Expect(after).To(Equal(before))
+
+		rotation, err = sut.RotateKeys(ctx)
+		Expect(err).To(Succeed())
+		Expect(rotation.Rotated).To(BeZero())
+
+		By("reading the content without the previous key")
+		keyring, err = envelope.NewKeyring("", map[string][]byte{"kek-2": newKey(2)})
+		Expect(err).To(Succeed())
+		config.Keyring = keyring
+		sut = newTestStore(db, config)
+
+		file, err := sut.Download(ctx, first)
+		Expect(err).To(Succeed())
+		Expect(string(file.File.Content)).To(Equal(payload))
+
+		file, err = sut.Download(ctx, second)
+		Expect(err).To(Succeed())
+		Expect(string(file.File.Content)).To(Equal("small"))
+	})
+
+	It("should skip the data keys it can not unwrap", func() {
+		useKeyring := func(primary string, keys ...byte) {
+			kek := map[string][]byte{}
+			for _, k := range keys {
+				kek[fmt.Sprintf("kek-%d", k)] = newKey(k)
+			}
+			keyring, err := envelope.NewKeyring(primary, kek)
+			Expect(err).To(Succeed())
+			config.Keyring = keyring
+			sut = newTestStore(db, config)
+		}
+
+		lost := save("lost.csv", []byte("lost"))
+
+		By("storing content before the keyring was set")
+		config.Keyring = nil
+		sut = newTestStore(db, config)
+		plain := save("plain.csv", []byte("plain"))
+
+		useKeyring("", 3)
+		kept := save("kept.csv", []byte("kept"))
+
+		useKeyring("kek-2", 2, 3)
+		rotation, err := sut.RotateKeys(ctx)
+		Expect(err).To(Succeed())
+		Expect(rotation.Rotated).To(Equal(int64(1)))
+
+		lostBlob, _ := storedBlob(lost)
+		Expect(rotation.Skipped).To(ConsistOf(lostBlob.BlobKey))
+		Expect(lostBlob.KeyID).To(Equal("kek-1"))
+
+		keptBlob, _ := storedBlob(kept)
+		Expect(keptBlob.KeyID).To(Equal("kek-2"))
+
+		plainBlob, raw := storedBlob(plain)
+		Expect(plainBlob.KeyID).To(BeEmpty())
+		Expect(string(raw)).To(Equal("plain"))
+
+		By("rotating once the missing key is mounted again")
+		useKeyring("kek-2", 1, 2)
+		rotation, err = sut.RotateKeys(ctx)
+		Expect(err).To(Succeed())
+		Expect(rotation.Rotated).To(Equal(int64(1)))
+		Expect(rotation.Skipped).To(BeEmpty())
+	})
+
+	It("should restore encrypted content from a backup", func() {
+		id := save("usage.csv", []byte(payload))
+
+		var buf bytes.Buffer
+		_, err := sut.Backup(ctx, &buf)
+		Expect(err).To(Succeed())
+
+		restoredDB, restoredBlobs := openTestStore("restored.gorm.db")
+
+		restoredConfig := config
+		restoredConfig.BlobStore = restoredBlobs
+		restored := newTestStore(restoredDB, restoredConfig)
+
+		_, err = restored.Restore(ctx, &buf)
+		Expect(err).To(Succeed())
+
+		file, err := restored.Download(ctx, id)
+		Expect(err).To(Succeed())
+		Expect(string(file.File.Content)).To(Equal(payload))
+
+		report, err := restored.VerifyIntegrity(ctx)
+		Expect(err).To(Succeed())
+		Expect(report.Valid()).To(BeTrue())
+	})
+})
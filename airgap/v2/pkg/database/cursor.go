@@ -0,0 +1,291 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"emperror.dev/errors"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	ErrInvalidPageToken = errors.Sentinel("invalid page token")
+	ErrInvalidOrderBy   = errors.Sentinel("invalid order by")
+)
+
+// cursor is the position of the last item of a page, the next page starts
+// after it. Query binds the cursor to the order and filter of the list.
+type cursor struct {
+	Query string          `json:"q"`
+	Value json.RawMessage `json:"v,omitempty"`
+	ID    uint            `json:"id"`
+}
+
+// cursorSigner signs cursors so page tokens are opaque to clients and can't
+// be forged.
+type cursorSigner struct {
+	key []byte
+}
+
+// newCursorSigner returns a signer using key, or a random key if empty. A
+// random key makes page tokens valid only for the life of the process.
+func newCursorSigner(key []byte) *cursorSigner {
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic(errors.Wrap(err, "failed to generate page token key"))
+		}
+	}
+	return &cursorSigner{key: key}
+}
+
+func (s *cursorSigner) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func (s *cursorSigner) encode(c *cursor) (string, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(s.sign(payload)), nil
+}
+
+// decode returns the cursor of token, nil for the first page. The cursor
+// must have been made for query.
+func (s *cursorSigner) decode(token, query string) (*cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	enc := base64.RawURLEncoding
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errors.WithStack(ErrInvalidPageToken)
+	}
+
+	payload, err := enc.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, errors.WithStack(ErrInvalidPageToken)
+	}
+
+	signature, err := enc.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, s.sign(payload)) {
+		return nil, errors.WithStack(ErrInvalidPageToken)
+	}
+
+	c := &cursor{}
+	if err := json.Unmarshal(payload, c); err != nil {
+		return nil, errors.WithStack(ErrInvalidPageToken)
+	}
+
+	if c.Query != query {
+		return nil, errors.Wrap(ErrInvalidPageToken, "page token was made for another order or filter")
+	}
+
+	return c, nil
+}
+
+// listOrder is the sort order of a list, ties are broken by id in the same
+// direction so every item has a unique position.
+type listOrder struct {
+	table string
+	field *schema.Field
+	desc  bool
+}
+
+// parseOrder resolves orderBy, a field name optionally followed by asc or
+// desc, against the fields of s. Nullable fields can't be used.
+func parseOrder(s *schema.Schema, orderBy string) (*listOrder, error) {
+	words := strings.Fields(orderBy)
+	if len(words) == 0 || len(words) > 2 {
+		return nil, errors.WithDetails(errors.Wrap(ErrInvalidOrderBy, "expected a field and an optional direction"), "orderBy", orderBy)
+	}
+
+	order := &listOrder{table: s.Table}
+	if len(words) == 2 {
+		switch strings.ToLower(words[1]) {
+		case "asc":
+		case "desc":
+			order.desc = true
+		default:
+			return nil, errors.WithDetails(errors.Wrapf(ErrInvalidOrderBy, "unknown direction %s", words[1]), "orderBy", orderBy)
+		}
+	}
+
+	// a name such as sourceType is the field SourceType or the column source_type
+	name := words[0]
+	for _, f := range []*schema.Field{
+		s.FieldsByName[cases.Title(language.Und, cases.NoLower).String(name)],
+		s.FieldsByDBName[name],
+		s.FieldsByDBName[camelToSnakeCase(name)],
+	} {
+		if f != nil && f.DBName != "" {
+			order.field = f
+			break
+		}
+	}
+
+	if order.field == nil {
+		return nil, errors.WithDetails(errors.Wrapf(ErrInvalidOrderBy, "field %s not found", name), "orderBy", orderBy)
+	}
+
+	switch order.field.FieldType.Kind() {
+	case reflect.String, reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+	default:
+		if order.field.FieldType != timeType {
+			return nil, errors.WithDetails(errors.Wrapf(ErrInvalidOrderBy, "can't order by %s", name), "orderBy", orderBy)
+		}
+	}
+
+	return order, nil
+}
+
+func (o *listOrder) String() string {
+	direction := "asc"
+	if o.desc {
+		direction = "desc"
+	}
+	return fmt.Sprintf("%s %s", o.field.DBName, direction)
+}
+
+func (o *listOrder) isID() bool {
+	return o.field.PrimaryKey
+}
+
+// scope orders db and starts after c when set.
+func (o *listOrder) scope(db *gorm.DB, c *cursor) (*gorm.DB, error) {
+	id := clause.Column{Table: o.table, Name: "id"}
+	column := clause.Column{Table: o.table, Name: o.field.DBName}
+
+	if c != nil {
+		comparison := ">"
+		if o.desc {
+			comparison = "<"
+		}
+
+		idSQL := db.Statement.Quote(id)
+		if o.isID() {
+			db = db.Where(fmt.Sprintf("%s %s ?", idSQL, comparison), c.ID)
+		} else {
+			value := reflect.New(o.field.FieldType)
+			if err := json.Unmarshal(c.Value, value.Interface()); err != nil {
+				return nil, errors.WithStack(ErrInvalidPageToken)
+			}
+
+			columnSQL := db.Statement.Quote(column)
+			db = db.Where(
+				fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", columnSQL, comparison, columnSQL, idSQL, comparison),
+				value.Elem().Interface(), value.Elem().Interface(), c.ID,
+			)
+		}
+	}
+
+	db = db.Order(clause.OrderByColumn{Column: column, Desc: o.desc})
+	if !o.isID() {
+		db = db.Order(clause.OrderByColumn{Column: id, Desc: o.desc})
+	}
+
+	return db, nil
+}
+
+// cursor returns the cursor positioned on item, a pointer to a model of the
+// ordered schema.
+func (o *listOrder) cursor(db *gorm.DB, query string, item interface{}, id uint) (*cursor, error) {
+	c := &cursor{Query: query, ID: id}
+	if o.isID() {
+		return c, nil
+	}
+
+	value, _ := o.field.ValueOf(db.Statement.Context, reflect.ValueOf(item).Elem())
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	c.Value = raw
+	return c, nil
+}
+
+// listQuery identifies the order and filter of a list so a page token can't
+// be used with another.
+func listQuery(order *listOrder, opts *ListOptions) string {
+	filter := ""
+	if opts.Filter != nil {
+		filter = opts.Filter.String()
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\n%s\n%t", order, filter, opts.ShowDeleted)))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// startPage orders db by opts.OrderBy, or defaultOrder, and starts it after
+// the page token. It returns the order and query to make the next page token.
+func (d *fileStore) startPage(db *gorm.DB, s *schema.Schema, defaultOrder string, opts *ListOptions) (*gorm.DB, *listOrder, string, error) {
+	orderBy := opts.OrderBy
+	if orderBy == "" {
+		orderBy = defaultOrder
+	}
+
+	order, err := parseOrder(s, orderBy)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	query := listQuery(order, opts)
+
+	token := ""
+	if opts.Pagination != nil {
+		token = opts.Pagination.Token
+	}
+
+	c, err := d.cursors.decode(token, query)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	db, err = order.scope(db, c)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return db, order, query, nil
+}
+
+// nextPageToken returns the token of the page after last, the last item
+// returned.
+func (d *fileStore) nextPageToken(order *listOrder, query string, last interface{}, id uint) (string, error) {
+	c, err := order.cursor(d.DB, query, last, id)
+	if err != nil {
+		return "", err
+	}
+	return d.cursors.encode(c)
+}
@@ -15,10 +15,23 @@
 package database
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
@@ -29,3 +42,109 @@ func TestDatabase(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Database Suite")
 }
+
+// testDrivers open an empty database for a test. The suite runs against the
+// driver named by DATABASE_TEST_DRIVER, sqlite by default. The test-postgres
+// and test-dqlite make targets run it against the other drivers:
+//
+//	DATABASE_TEST_DRIVER=postgres DATABASE_TEST_DSN="host=localhost user=postgres" go test ./pkg/database/
+//	DATABASE_TEST_DRIVER=dqlite go test -tags dqlite,libsqlite3 ./pkg/database/
+var testDrivers = map[string]func(name string) gorm.Dialector{
+	"sqlite": func(name string) gorm.Dialector {
+		return sqlite.Open(SQLiteDSN(filepath.Join(GinkgoT().TempDir(), name)))
+	},
+	"postgres": openTestPostgres,
+}
+
+func openTestDB(name string, config *gorm.Config) *gorm.DB {
+	driver := os.Getenv("DATABASE_TEST_DRIVER")
+	if driver == "" {
+		driver = "sqlite"
+	}
+
+	open, ok := testDrivers[driver]
+	Expect(ok).To(BeTrue(), "unsupported DATABASE_TEST_DRIVER %s", driver)
+
+	db, err := gorm.Open(open(name), config)
+	Expect(err).To(Succeed())
+
+	DeferCleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	return db
+}
+
+// openTestStore opens the empty database name of openTestDB and migrates it
+// with a local blob store in a temporary directory
+func openTestStore(name string) (*gorm.DB, blobstore.BlobStore) {
+	writer := logger.Writer(log.New(GinkgoWriter, "gorm-", log.LstdFlags))
+	db := openTestDB(name, &gorm.Config{
+		Logger: logger.New(writer, logger.Config{LogLevel: logger.Error}),
+	})
+
+	blobs, err := blobstore.NewLocal(GinkgoT().TempDir())
+	Expect(err).To(Succeed())
+	Expect(Migrate(db, blobs)).To(Succeed())
+
+	return db, blobs
+}
+
+// newTestStore opens a store on db, it is closed once the test ends
+func newTestStore(db *gorm.DB, config FileStoreConfig) StoredFileStore {
+	store, closer := New(db, config)
+	DeferCleanup(func() { Expect(closer.Close()).To(Succeed()) })
+	return store
+}
+
+// testFile is a report of redhat-marketplace named name
+func testFile(name, content string) *modelsv2.StoredFile {
+	return &modelsv2.StoredFile{
+		Name:       name,
+		Source:     "redhat-marketplace",
+		SourceType: "report",
+		File:       modelsv2.StoredFileContent{Content: []byte(content)},
+	}
+}
+
+// saveTestFile saves file to store and returns its id
+func saveTestFile(ctx context.Context, store StoredFileStore, file *modelsv2.StoredFile) string {
+	id, err := store.Save(ctx, file)
+	Expect(err).To(Succeed())
+	return id
+}
+
+// openTestPostgres isolates each test in its own schema of the database of
+// DATABASE_TEST_DSN, dropped once the test ends
+func openTestPostgres(name string) gorm.Dialector {
+	dsn := os.Getenv("DATABASE_TEST_DSN")
+	Expect(dsn).ToNot(BeEmpty(), "DATABASE_TEST_DSN is required by the postgres driver")
+
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	Expect(err).To(Succeed())
+
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	Expect(admin.Exec(fmt.Sprintf("CREATE SCHEMA %s", schema)).Error).To(Succeed())
+
+	DeferCleanup(func() {
+		Expect(admin.Exec(fmt.Sprintf("DROP SCHEMA %s CASCADE", schema)).Error).To(Succeed())
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	switch {
+	case strings.HasPrefix(dsn, "postgres://"), strings.HasPrefix(dsn, "postgresql://"):
+		separator := "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		dsn += separator + "search_path=" + schema
+	default:
+		dsn += " search_path=" + schema
+	}
+
+	return postgres.Open(dsn)
+}
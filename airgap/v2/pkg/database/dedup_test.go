@@ -0,0 +1,178 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("deduplication", func() {
+	var (
+		db    *gorm.DB
+		blobs blobstore.BlobStore
+		ctx   = context.Background()
+		sut   StoredFileStore
+	)
+
+	BeforeEach(func() {
+		db, blobs = openTestStore("dedup.gorm.db")
+		sut = newTestStore(db, FileStoreConfig{BlobStore: blobs})
+	})
+
+	save := func(name, content string) string {
+		return saveTestFile(ctx, sut, testFile(name, content))
+	}
+
+	blobExists := func(key string) bool {
+		rc, err := blobs.Get(ctx, key)
+		if err == nil {
+			rc.Close()
+			return true
+		}
+		Expect(err).To(MatchError(blobstore.ErrNotFound))
+		return false
+	}
+
+	storedBlobs := func() []modelsv2.StoredBlob {
+		stored := []modelsv2.StoredBlob{}
+		Expect(db.Order("id").Find(&stored).Error).To(Succeed())
+		return stored
+	}
+
+	It("should store identical content once", func() {
+		first := save("first.txt", "payload")
+		second := save("second.txt", "payload")
+		save("other.txt", "other payload")
+
+		firstFile, err := sut.Get(ctx, first)
+		Expect(err).To(Succeed())
+		secondFile, err := sut.Get(ctx, second)
+		Expect(err).To(Succeed())
+		Expect(secondFile.File.BlobKey).To(Equal(firstFile.File.BlobKey))
+
+		stored := storedBlobs()
+		Expect(stored).To(HaveLen(2))
+		Expect(stored[0].BlobKey).To(Equal(firstFile.File.BlobKey))
+		Expect(stored[0].Checksum).To(Equal(firstFile.File.Checksum))
+		// the content and first version of each file
+		Expect(stored[0].RefCount).To(Equal(int64(4)))
+
+		file, err := sut.Download(ctx, second)
+		Expect(err).To(Succeed())
+		Expect(string(file.File.Content)).To(Equal("payload"))
+	})
+
+	It("should only delete content once no file references it", func() {
+		first := save("first.txt", "payload")
+		second := save("second.txt", "payload")
+
+		file, err := sut.Get(ctx, first)
+		Expect(err).To(Succeed())
+		key := file.File.BlobKey
+
+		Expect(sut.Delete(ctx, first, true)).To(Succeed())
+		Expect(blobExists(key)).To(BeTrue())
+		Expect(storedBlobs()).To(ConsistOf(HaveField("RefCount", int64(2))))
+
+		By("cleaning up the tombstone of the last file")
+		Expect(sut.Delete(ctx, second, false)).To(Succeed())
+		Expect(blobExists(key)).To(BeTrue())
+
+		count, err := sut.CleanTombstones(ctx)
+		Expect(err).To(Succeed())
+		Expect(count).To(Equal(int64(1)))
+		Expect(blobExists(key)).To(BeFalse())
+		Expect(storedBlobs()).To(BeEmpty())
+	})
+
+	It("should keep content replaced in a file while other files reference it", func() {
+		first := save("first.txt", "payload")
+		save("second.txt", "payload")
+
+		file, err := sut.Get(ctx, first)
+		Expect(err).To(Succeed())
+		key := file.File.BlobKey
+
+		Expect(save("first.txt", "new payload")).To(Equal(first))
+		Expect(blobExists(key)).To(BeTrue())
+
+		// the second file and the first version of the first file
+		Expect(storedBlobs()).To(ContainElement(And(
+			HaveField("BlobKey", key),
+			HaveField("RefCount", int64(3)),
+		)))
+	})
+
+	It("should dedupe the content of existing files when migrating", func() {
+		Expect(db.Migrator().DropTable(&modelsv2.StoredBlob{})).To(Succeed())
+		Expect(db.Exec("DELETE FROM migrations WHERE id = ?", "202312010000").Error).To(Succeed())
+
+		// files stored before deduplication each have their own blob
+		var keys []string
+		for i := 0; i < 3; i++ {
+			key := blobstore.NewKey()
+			_, err := blobs.Put(ctx, key, strings.NewReader("payload"))
+			Expect(err).To(Succeed())
+			keys = append(keys, key)
+
+			file := &modelsv2.StoredFile{
+				Name:       "file" + strconv.Itoa(i),
+				Source:     "redhat-marketplace",
+				SourceType: "report",
+				Version:    1,
+				File: modelsv2.StoredFileContent{
+					Checksum: "239f59ed55e737c77147cf55ad0c1b030b6d7ee748a7426952f9b852d5a935e5",
+					Size:     len("payload"),
+					BlobKey:  key,
+				},
+			}
+			Expect(db.Create(file).Error).To(Succeed())
+
+			version, err := modelsv2.NewStoredFileVersion(file, 1)
+			Expect(err).To(Succeed())
+			Expect(db.Create(version).Error).To(Succeed())
+		}
+
+		Expect(Migrate(db, blobs)).To(Succeed())
+
+		files := []modelsv2.StoredFile{}
+		Expect(db.Preload("File").Preload("Versions").Find(&files).Error).To(Succeed())
+		Expect(files).To(HaveLen(3))
+		for _, file := range files {
+			Expect(file.File.BlobKey).To(Equal(files[0].File.BlobKey))
+			Expect(file.Versions).To(ConsistOf(HaveField("BlobKey", files[0].File.BlobKey)))
+		}
+
+		stored := storedBlobs()
+		Expect(stored).To(HaveLen(1))
+		Expect(stored[0].RefCount).To(Equal(int64(6)))
+
+		existing := 0
+		for _, key := range keys {
+			if blobExists(key) {
+				existing++
+			}
+		}
+		Expect(existing).To(Equal(1))
+	})
+})
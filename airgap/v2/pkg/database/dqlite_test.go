@@ -0,0 +1,56 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build dqlite
+// +build dqlite
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/canonical/go-dqlite/app"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	dqlite "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/dqlite/driver"
+	"gorm.io/gorm"
+)
+
+func init() {
+	testDrivers["dqlite"] = openTestDqlite
+}
+
+// openTestDqlite starts a single dqlite node for the test
+func openTestDqlite(name string) gorm.Dialector {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(Succeed())
+	address := listener.Addr().String()
+	Expect(listener.Close()).To(Succeed())
+
+	node, err := app.New(GinkgoT().TempDir(), app.WithAddress(address))
+	Expect(err).To(Succeed())
+	Expect(node.Ready(context.Background())).To(Succeed())
+
+	conn, err := node.Open(context.Background(), fmt.Sprintf("test-%s", name))
+	Expect(err).To(Succeed())
+
+	DeferCleanup(func() {
+		conn.Close()
+		node.Close()
+	})
+
+	return dqlite.Open(conn)
+}
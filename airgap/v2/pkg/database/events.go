@@ -0,0 +1,249 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	ErrInvalidRevision = errors.Sentinel("invalid revision")
+	// ErrRevisionCompacted is returned when the events after a revision are no
+	// longer kept, or the revision was not issued by this store. Watchers list
+	// the files again and watch from a new revision.
+	ErrRevisionCompacted = errors.Sentinel("revision is no longer available")
+)
+
+const defaultFileEventsLimit = 100
+
+// FileChange is a file event and the current state of the file it changed,
+// File is nil once the file has been removed.
+type FileChange struct {
+	Event modelsv2.FileEvent
+	File  *modelsv2.StoredFile
+	// Revision resumes watching after this change
+	Revision string
+}
+
+func formatRevision(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+func parseRevision(revision string) (uint, error) {
+	id, err := strconv.ParseUint(revision, 10, 64)
+	if err != nil {
+		return 0, errors.WithDetails(ErrInvalidRevision, "revision", revision)
+	}
+	return uint(id), nil
+}
+
+// recordFileEvent appends a change of file to the file events, in the
+// transaction making the change. On postgres the table stays locked until
+// the transaction commits, so events become visible in the order of their ids
+// and a watcher never skips an event committed late. Other databases already
+// serialize writes.
+func recordFileEvent(tx *gorm.DB, eventType modelsv2.FileEventType, file *modelsv2.StoredFile) error {
+	if tx.Dialector.Name() == "postgres" {
+		if err := tx.Exec("LOCK TABLE file_events IN EXCLUSIVE MODE").Error; err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	event := &modelsv2.FileEvent{
+		Type:       eventType,
+		FileID:     file.ID,
+		Version:    file.Version,
+		Name:       file.Name,
+		Source:     file.Source,
+		SourceType: file.SourceType,
+	}
+
+	return errors.WithStack(tx.Create(event).Error)
+}
+
+// FileEvents returns up to limit changes to files made after revision, oldest
+// first, with the revision to read the next changes from. Only the changes to
+// files matching the filter of opts are returned, the filter is matched
+// against the current state of the files. The deletion of a file removed
+// since can not be matched and is always returned. An empty revision returns
+// no changes and the latest revision.
+func (d *fileStore) FileEvents(ctx context.Context, revision string, limit int, opts ...ListOption) ([]FileChange, string, error) {
+	listOpts := (&ListOptions{}).ApplyOptions(opts)
+	db := d.WithContext(ctx)
+
+	if err := checkFilter(db, listOpts); err != nil {
+		return nil, "", err
+	}
+
+	if limit <= 0 {
+		limit = defaultFileEventsLimit
+	}
+
+	var bounds struct {
+		Oldest uint
+		Latest uint
+	}
+	if err := db.Model(&modelsv2.FileEvent{}).
+		Select("COALESCE(MIN(id), 0) AS oldest, COALESCE(MAX(id), 0) AS latest").
+		Scan(&bounds).Error; err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+
+	if revision == "" {
+		return nil, formatRevision(bounds.Latest), nil
+	}
+
+	after, err := parseRevision(revision)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// the latest event is never cleaned, so a revision past it was not
+	// issued by this store
+	if after > bounds.Latest || after+1 < bounds.Oldest {
+		return nil, "", errors.WithDetails(ErrRevisionCompacted,
+			"revision", revision, "oldest", bounds.Oldest, "latest", bounds.Latest)
+	}
+
+	events := []modelsv2.FileEvent{}
+	if err := db.Where("id > ?", after).
+		Order("id").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+
+	if len(events) == 0 {
+		return nil, revision, nil
+	}
+
+	next := formatRevision(events[len(events)-1].ID)
+
+	ids := make([]uint, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.FileID)
+	}
+
+	query := db.Unscoped().Where("stored_files.id in ?", ids)
+	if listOpts.Filter != nil {
+		query = query.Where("stored_files.id in (?)", db.Unscoped().
+			Model(&modelsv2.StoredFile{}).
+			Scopes(filter{}.ToScope(listOpts)).
+			Distinct("stored_files.id"))
+	}
+
+	files := []modelsv2.StoredFile{}
+	if err := query.
+		Preload("Metadata").
+		Preload("File").
+		Find(&files).Error; err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+
+	matched := make(map[uint]*modelsv2.StoredFile, len(files))
+	for i := range files {
+		matched[files[i].ID] = &files[i]
+	}
+
+	// files not matching the filter are told apart from files removed since
+	existing := map[uint]bool{}
+	if listOpts.Filter != nil {
+		var found []uint
+		if err := db.Unscoped().
+			Model(&modelsv2.StoredFile{}).
+			Where("id in ?", ids).
+			Pluck("id", &found).Error; err != nil {
+			return nil, "", errors.WithStack(err)
+		}
+
+		for _, id := range found {
+			existing[id] = true
+		}
+	}
+
+	changes := []FileChange{}
+	for _, event := range events {
+		file, ok := matched[event.FileID]
+		switch {
+		case ok:
+			changes = append(changes, FileChange{Event: event, File: file, Revision: formatRevision(event.ID)})
+		case event.Type == modelsv2.FileEventDeleted && !existing[event.FileID]:
+			changes = append(changes, FileChange{Event: event, Revision: formatRevision(event.ID)})
+		}
+	}
+
+	return changes, next, nil
+}
+
+// Changed returns a channel closed once the next change to files made by
+// this store is committed. Changes made through other stores of the same
+// database, such as other dqlite nodes, are not signaled.
+func (d *fileStore) Changed() <-chan struct{} {
+	return d.changes.wait()
+}
+
+// CleanFileEvents deletes the file events older than the event retention.
+// The latest event is kept to tell the revisions issued apart from unknown ones.
+func (d *fileStore) CleanFileEvents(ctx context.Context) (int64, error) {
+	if d.config.EventRetention <= 0 {
+		return 0, nil
+	}
+
+	before := time.Now().Add(-d.config.EventRetention)
+	latest := d.WithContext(ctx).Model(&modelsv2.FileEvent{}).Select("MAX(id)")
+
+	result := d.WithContext(ctx).
+		Where("created_at < ? AND id < (?)", before, latest).
+		Delete(&modelsv2.FileEvent{})
+	if result.Error != nil {
+		return 0, errors.WithStack(result.Error)
+	}
+
+	d.Log.Info("cleaned up file events", "count", result.RowsAffected, "before", before.String())
+	return result.RowsAffected, nil
+}
+
+// changeNotifier wakes up the watchers waiting for changes
+type changeNotifier struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+func (n *changeNotifier) wait() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.ch == nil {
+		n.ch = make(chan struct{})
+	}
+	return n.ch
+}
+
+func (n *changeNotifier) notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.ch != nil {
+		close(n.ch)
+		n.ch = nil
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("file events", func() {
+	var (
+		db     *gorm.DB
+		ctx    = context.Background()
+		sut    StoredFileStore
+		config FileStoreConfig
+	)
+
+	BeforeEach(func() {
+		var blobs blobstore.BlobStore
+		db, blobs = openTestStore("events.gorm.db")
+		config = FileStoreConfig{BlobStore: blobs}
+	})
+
+	JustBeforeEach(func() {
+		sut = newTestStore(db, config)
+	})
+
+	save := func(name, sourceType, content string) string {
+		file := testFile(name, content)
+		file.SourceType = sourceType
+		return saveTestFile(ctx, sut, file)
+	}
+
+	latest := func() string {
+		changes, revision, err := sut.FileEvents(ctx, "", 0)
+		Expect(err).To(Succeed())
+		Expect(changes).To(BeEmpty())
+		return revision
+	}
+
+	It("should return the changes after a revision", func() {
+		start := latest()
+		Expect(start).To(Equal("0"))
+
+		id := save("report.tar.gz", "report", "first")
+		save("report.tar.gz", "report", "second")
+		Expect(sut.Delete(ctx, id, false)).To(Succeed())
+
+		changes, revision, err := sut.FileEvents(ctx, start, 0)
+		Expect(err).To(Succeed())
+		Expect(changes).To(HaveLen(3))
+		Expect(revision).To(Equal(latest()))
+		Expect(revision).To(Equal(changes[2].Revision))
+
+		Expect(changes[0].Event.Type).To(Equal(modelsv2.FileEventCreated))
+		Expect(changes[0].Event.Version).To(Equal(uint64(1)))
+		Expect(changes[1].Event.Type).To(Equal(modelsv2.FileEventUpdated))
+		Expect(changes[1].Event.Version).To(Equal(uint64(2)))
+		Expect(changes[1].Event.Name).To(Equal("report.tar.gz"))
+		Expect(changes[2].Event.Type).To(Equal(modelsv2.FileEventDeleted))
+		Expect(changes[2].File).ToNot(BeNil())
+		Expect(changes[2].File.DeletedAt.Valid).To(BeTrue())
+
+		By("reading them in batches")
+		changes, next, err := sut.FileEvents(ctx, start, 2)
+		Expect(err).To(Succeed())
+		Expect(changes).To(HaveLen(2))
+		Expect(next).To(Equal(changes[1].Revision))
+
+		changes, next, err = sut.FileEvents(ctx, next, 2)
+		Expect(err).To(Succeed())
+		Expect(changes).To(HaveLen(1))
+		Expect(next).To(Equal(revision))
+
+		changes, next, err = sut.FileEvents(ctx, next, 2)
+		Expect(err).To(Succeed())
+		Expect(changes).To(BeEmpty())
+		Expect(next).To(Equal(revision))
+	})
+
+	It("should only return the changes to files matching the filter", func() {
+		start := latest()
+
+		save("report.tar.gz", "report", "report")
+		events := save("events.json", "eventjson", "events")
+		removed := save("removed.json", "eventjson", "removed")
+		Expect(sut.Delete(ctx, events, false)).To(Succeed())
+		Expect(sut.Delete(ctx, removed, true)).To(Succeed())
+
+		expr, err := fileserver.ParseFilter(`sourceType == "report"`)
+		Expect(err).To(Succeed())
+
+		changes, revision, err := sut.FileEvents(ctx, start, 0, ApplyFilter(expr))
+		Expect(err).To(Succeed())
+		Expect(revision).To(Equal(latest()))
+
+		// the deletion of a removed file can not be matched
+		Expect(changes).To(HaveLen(2))
+		Expect(changes[0].File.Name).To(Equal("report.tar.gz"))
+		Expect(changes[1].Event.Type).To(Equal(modelsv2.FileEventDeleted))
+		Expect(changes[1].Event.Name).To(Equal("removed.json"))
+		Expect(changes[1].File).To(BeNil())
+
+		expr, err = fileserver.ParseFilter(`unknown == "report"`)
+		Expect(err).To(Succeed())
+		_, _, err = sut.FileEvents(ctx, start, 0, ApplyFilter(expr))
+		Expect(err).To(MatchError(ErrInvalidFilter))
+	})
+
+	It("should reject revisions it did not issue", func() {
+		_, _, err := sut.FileEvents(ctx, "not a revision", 0)
+		Expect(err).To(MatchError(ErrInvalidRevision))
+
+		save("report.tar.gz", "report", "report")
+
+		_, _, err = sut.FileEvents(ctx, "100", 0)
+		Expect(err).To(MatchError(ErrRevisionCompacted))
+	})
+
+	It("should signal committed changes", func() {
+		changed := sut.Changed()
+		Consistently(changed).ShouldNot(BeClosed())
+
+		id := save("report.tar.gz", "report", "report")
+		Expect(changed).To(BeClosed())
+
+		changed = sut.Changed()
+		Expect(sut.Delete(ctx, id, false)).To(Succeed())
+		Expect(changed).To(BeClosed())
+	})
+
+	Context("with an event retention", func() {
+		BeforeEach(func() {
+			config.EventRetention = time.Hour
+		})
+
+		It("should clean old events but the latest", func() {
+			start := latest()
+			save("first.json", "eventjson", "first")
+			save("second.json", "eventjson", "second")
+			save("third.json", "eventjson", "third")
+
+			Expect(db.Model(&modelsv2.FileEvent{}).
+				Where("1 = 1").
+				Update("created_at", time.Now().Add(-2*time.Hour)).Error).To(Succeed())
+
+			count, err := sut.CleanFileEvents(ctx)
+			Expect(err).To(Succeed())
+			Expect(count).To(Equal(int64(2)))
+
+			_, _, err = sut.FileEvents(ctx, start, 0)
+			Expect(err).To(MatchError(ErrRevisionCompacted))
+
+			revision := latest()
+			changes, next, err := sut.FileEvents(ctx, revision, 0)
+			Expect(err).To(Succeed())
+			Expect(changes).To(BeEmpty())
+			Expect(next).To(Equal(revision))
+		})
+	})
+})
@@ -15,6 +15,7 @@
 package database
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -23,7 +24,8 @@ import (
 
 	"emperror.dev/errors"
 	"github.com/go-logr/logr"
-	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/envelope"
 	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -35,22 +37,94 @@ type StoredFileStore interface {
 	Get(ctx context.Context, id string) (*modelsv2.StoredFile, error)
 	GetByFileKey(ctx context.Context, fileKey *modelsv2.StoredFileKey) (*modelsv2.StoredFile, error)
 	Save(ctx context.Context, file *modelsv2.StoredFile) (id string, err error)
+	Upload(ctx context.Context, r io.Reader, prepare PrepareUpload) (id string, err error)
 	Delete(ctx context.Context, id string, permanent bool) error
 	Download(ctx context.Context, id string) (*modelsv2.StoredFile, error)
+	Open(ctx context.Context, id string) (*modelsv2.StoredFile, io.ReadCloser, error)
+	GetVersion(ctx context.Context, id string, version uint64) (*modelsv2.StoredFile, error)
+	OpenVersion(ctx context.Context, id string, version uint64) (*modelsv2.StoredFile, io.ReadCloser, error)
+	ListVersions(ctx context.Context, id string) ([]modelsv2.StoredFile, error)
 	CleanTombstones(ctx context.Context) (int64, error)
+	// ApplyRetention deletes the files past their retention rule, or only lists them with dryRun
+	ApplyRetention(ctx context.Context, dryRun bool) ([]ExpiredFile, error)
+
+	// Usage returns the storage used by all the files and by each source
+	Usage(ctx context.Context) (*StorageUsage, error)
+	// CheckQuota returns a *QuotaExceededError if storing bytes more for source would exceed a quota
+	CheckQuota(ctx context.Context, source string, bytes int64) error
+	// RotateKeys wraps the data keys of the stored content with the primary key encryption key
+	RotateKeys(ctx context.Context) (*KeyRotation, error)
+
+	// FileEvents returns the changes to files after a revision and the revision to read the next ones from
+	FileEvents(ctx context.Context, revision string, limit int, opts ...ListOption) (changes []FileChange, nextRevision string, err error)
+	// Changed returns a channel closed once the next change to files is committed by this store
+	Changed() <-chan struct{}
+	// CleanFileEvents deletes the file events older than the event retention
+	CleanFileEvents(ctx context.Context) (int64, error)
+
+	StartUpload(ctx context.Context, session *modelsv2.UploadSession) (id string, err error)
+	GetUpload(ctx context.Context, id string) (*modelsv2.UploadSession, error)
+	PutUploadChunk(ctx context.Context, id string, offset int64, r io.Reader) (committed int64, err error)
+	OpenUpload(ctx context.Context, id string) (*modelsv2.UploadSession, io.ReadCloser, error)
+	FinishUpload(ctx context.Context, id string, r io.Reader, prepare PrepareUpload) (fileID string, err error)
+	AbortUpload(ctx context.Context, id string) error
+	CleanUploads(ctx context.Context) (int64, error)
+
+	AppendAuditEvent(ctx context.Context, events ...*modelsv2.AuditEvent) error
+	ListAuditEvents(ctx context.Context, opts ...ListOption) (events []modelsv2.AuditEvent, nextPageToken string, err error)
+	VerifyAuditLog(ctx context.Context) (verified int64, firstInvalidID uint, err error)
+
+	// Batch calls fn with a store whose changes are committed together once fn returns nil
+	Batch(ctx context.Context, dryRun bool, fn func(store StoredFileStore) error) error
+
+	// Backup writes a consistent, checksummed archive of the store to w
+	Backup(ctx context.Context, w io.Writer) (*BackupManifest, error)
+	// Restore reads an archive written by Backup into an empty store
+	Restore(ctx context.Context, r io.Reader) (*BackupManifest, error)
+	// VerifyIntegrity checks the content of every file and the audit log
+	VerifyIntegrity(ctx context.Context) (*IntegrityReport, error)
 }
 
+// PrepareUpload is called by Upload once the content has been streamed to the
+// blob store. It returns the file to save with the content, or an error to
+// discard the content.
+type PrepareUpload func(content *modelsv2.StoredFileContent) (*modelsv2.StoredFile, error)
+
 func New(db *gorm.DB, config FileStoreConfig) (StoredFileStore, io.Closer) {
 	store := &fileStore{
-		DB:     db,
-		Log:    logf.Log.WithName("file_store"),
-		config: config,
+		DB:      db,
+		Log:     logf.Log.WithName("file_store"),
+		config:  config,
+		cursors: newCursorSigner(config.PageTokenKey),
 	}
 	return store, store
 }
 
 type FileStoreConfig struct {
 	CleanupAfter time.Duration
+	// BlobStore holds the file content, the database only keeps a reference
+	BlobStore blobstore.BlobStore
+	// UploadExpiry is how long an idle upload session is kept, 0 keeps them forever
+	UploadExpiry time.Duration
+	// MaxVersions is the number of versions kept for each file, 0 keeps them all
+	MaxVersions int
+	// PageTokenKey signs page tokens, a random key is used when empty. The key
+	// must be shared by the nodes of a cluster and kept across restarts.
+	PageTokenKey []byte
+	// RetentionRules set how long live files are kept, files are kept forever without rules
+	RetentionRules []RetentionRule
+	// Quotas limit the storage used by files, it is unlimited by default
+	Quotas Quotas
+	// Compression sets how content is compressed in the blob store, it is
+	// stored as is by default
+	Compression CompressionPolicy
+	// Keyring encrypts content in the blob store with a data key per blob,
+	// content is stored in plaintext when nil. Content stored before the
+	// keyring was set stays in plaintext, it is not re-encrypted.
+	Keyring *envelope.Keyring
+	// EventRetention is how long file events are kept for watchers to resume
+	// from, 0 keeps them forever
+	EventRetention time.Duration
 }
 
 type fileStore struct {
@@ -58,6 +132,15 @@ type fileStore struct {
 	Log logr.Logger
 
 	config FileStoreConfig
+
+	// cursors signs the page tokens
+	cursors *cursorSigner
+
+	// changes wakes up the watchers once files are changed
+	changes changeNotifier
+
+	// batch is set on the store of a batch, blobs are only deleted once it ends
+	batch *batch
 }
 
 type SortOrder struct {
@@ -76,6 +159,13 @@ type metaDataQuery struct {
 	MetaValue  []interface{}
 }
 
+// SQLiteDSN returns the dsn of the sqlite database at path. SQLite has a
+// single writer, transactions take the write lock when they begin and wait
+// for it rather than failing once they write.
+func SQLiteDSN(path string) string {
+	return path + "?_txlock=immediate&_busy_timeout=5000"
+}
+
 const (
 	ErrInvalidInput = errors.Sentinel("invalid input")
 	ErrNotFound     = errors.Sentinel("not found")
@@ -103,9 +193,7 @@ func (d *fileStore) Get(ctx context.Context, id string) (*modelsv2.StoredFile, e
 	if err := db.Unscoped().
 		Model(&file).
 		Preload("Metadata").
-		Preload("File", func(db *gorm.DB) *gorm.DB {
-			return db.Omit("content")
-		}).
+		Preload("File").
 		Find(&file, idInt).Error; err != nil {
 		return nil, err
 	}
@@ -123,9 +211,7 @@ func (d *fileStore) GetByFileKey(ctx context.Context, fileKey *modelsv2.StoredFi
 	if err := db.Unscoped().
 		Model(&file).
 		Preload("Metadata").
-		Preload("File", func(db *gorm.DB) *gorm.DB {
-			return db.Omit("content")
-		}).
+		Preload("File").
 		Where(modelsv2.StoredFile{
 			Name:       fileKey.Name,
 			Source:     fileKey.Source,
@@ -148,37 +234,238 @@ func (d *fileStore) Save(ctx context.Context, file *modelsv2.StoredFile) (string
 		return "", err
 	}
 
-	db := d.DB.WithContext(ctx)
-	foundFile := &modelsv2.StoredFile{}
+	var (
+		content = &file.File
+		blob    *modelsv2.StoredBlob
+	)
 
-	// attempt to find file
-	if file.ID != 0 {
+	if len(content.Content) != 0 {
 		var err error
-		foundFile, err = d.Get(ctx, strconv.Itoa(int(file.ID)))
-		if err != nil && !errors.Is(err, ErrNotFound) {
+		blob, err = d.putContent(ctx, content, bytes.NewReader(content.Content))
+		if err != nil {
 			return "", err
 		}
+	}
+
+	return d.save(ctx, file, blob, nil)
+}
+
+func (d *fileStore) Upload(ctx context.Context, r io.Reader, prepare PrepareUpload) (string, error) {
+	return d.upload(ctx, r, prepare, nil)
+}
+
+// afterSave is called in the transaction saving a file, an error rolls the save back.
+type afterSave func(tx *gorm.DB, id string) error
+
+func (d *fileStore) upload(ctx context.Context, r io.Reader, prepare PrepareUpload, after afterSave) (string, error) {
+	content := &modelsv2.StoredFileContent{}
+	blob, err := d.putContent(ctx, content, r)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := prepare(content)
+	if err != nil {
+		d.deleteBlobs(blob.BlobKey)
+		return "", err
+	}
+
+	// the streamed content replaces any content set on the file
+	file.File.Content = nil
+	file.File.BlobKey = content.BlobKey
+	file.File.Checksum = content.Checksum
+	file.File.Size = content.Size
+
+	return d.save(ctx, file, blob, after)
+}
+
+// save writes the file and its metadata in a single transaction, adding a
+// version of the file. When blob is set the new content it holds replaces the
+// current one, or the stored blob with the same checksum when there is one.
+// Blobs no longer referenced are only deleted once the transaction commits.
+func (d *fileStore) save(ctx context.Context, file *modelsv2.StoredFile, blob *modelsv2.StoredBlob, after afterSave) (string, error) {
+	var (
+		id         string
+		orphans    []string
+		newKey     string
+		newContent = blob != nil
+	)
+
+	if newContent {
+		newKey = blob.BlobKey
+	}
+
+	err := d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txStore := &fileStore{DB: tx, Log: d.Log, config: d.config}
+
+		foundFile, err := txStore.find(ctx, file)
+		if err != nil {
+			return err
+		}
+
+		var (
+			released []string
+			newBytes int64
+			files    int64
+			source   = file.Source
+			moved    bool
+		)
+
+		if newContent {
+			duplicate, err := claimBlob(tx, &file.File, blob)
+			if err != nil {
+				return err
+			}
+			orphans = append(orphans, duplicate)
+
+			// content stored already only charges the sources it is new to
+			if duplicate == "" {
+				newBytes = int64(blob.Size)
+			}
+
+			if err := retainBlobs(tx, file.File.BlobKey); err != nil {
+				return err
+			}
+		}
+
+		if foundFile == nil || foundFile.ID == 0 || foundFile.DeletedAt.Valid {
+			files = 1
+		}
+		var checksumBefore string
+		if foundFile != nil && foundFile.ID != 0 {
+			// the update sets the new content on foundFile
+			checksumBefore = foundFile.File.Checksum
+
+			if source == "" {
+				source = foundFile.Source
+			}
+			moved = source != foundFile.Source
+		}
+
+		var key string
+		if newContent {
+			key = file.File.BlobKey
+		}
+		if err := txStore.reserveUsage(tx, source, files, newBytes, key); err != nil {
+			return err
+		}
+
+		eventType := modelsv2.FileEventUpdated
+
+		//notFound create it
+		if foundFile == nil || foundFile.ID == 0 {
+			if err := tx.Create(file).Error; err != nil {
+				return errors.WithStack(err)
+			}
+
+			id = fmt.Sprintf("%d", file.ID)
+			eventType = modelsv2.FileEventCreated
+		} else {
+			if newContent {
+				released = append(released, foundFile.File.BlobKey)
+			}
+
+			id, err = txStore.update(ctx, file, foundFile, newContent)
+			if err != nil {
+				return err
+			}
+		}
+
+		version, pruned, err := txStore.addVersion(ctx, id)
+		if err != nil {
+			return err
+		}
+		file.Version = version
+
+		changed := eventFile(file, foundFile)
+		if err := recordFileEvent(tx, eventType, changed); err != nil {
+			return err
+		}
+
+		if err := auditChange(ctx, tx, changed.ID, checksumBefore); err != nil {
+			return err
+		}
+
+		released = append(released, pruned...)
+		if moved {
+			// the usage of the file moves to the new source with it
+			err = recountUsage(tx)
+		} else {
+			err = releaseUsage(tx, source, 0, released...)
+		}
+		if err != nil {
+			return err
+		}
+
+		released, err = releaseBlobs(tx, released...)
+		if err != nil {
+			return err
+		}
+		orphans = append(orphans, released...)
+
+		if after != nil {
+			return after(tx, id)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		d.deleteBlobs(newKey)
+		return "", err
+	}
+
+	d.deleteBlobs(orphans...)
+	d.changes.notify()
+
+	return id, nil
+}
+
+// eventFile returns the file saved with the key of the stored file it
+// updates, a file may be updated by id only.
+func eventFile(file, foundFile *modelsv2.StoredFile) *modelsv2.StoredFile {
+	if foundFile == nil || foundFile.ID == 0 {
+		return file
+	}
+
+	return &modelsv2.StoredFile{
+		Model:      gorm.Model{ID: foundFile.ID},
+		Name:       foundFile.Name,
+		Source:     foundFile.Source,
+		SourceType: foundFile.SourceType,
+		Version:    file.Version,
+	}
+}
+
+// find looks up the stored version of file by id, or by its key when it has no id.
+func (d *fileStore) find(ctx context.Context, file *modelsv2.StoredFile) (*modelsv2.StoredFile, error) {
+	var (
+		foundFile *modelsv2.StoredFile
+		err       error
+	)
+
+	if file.ID != 0 {
+		foundFile, err = d.Get(ctx, strconv.Itoa(int(file.ID)))
 	} else if file.Name != "" &&
 		file.Source != "" &&
 		file.SourceType != "" {
-		var err error
 		foundFile, err = d.GetByFileKey(ctx, &modelsv2.StoredFileKey{
 			Name:       file.Name,
 			Source:     file.Source,
 			SourceType: file.SourceType,
 		})
-		if err != nil && !errors.Is(err, ErrNotFound) {
-			return "", err
-		}
 	}
 
-	//notFound create it
-	if foundFile == nil || foundFile.ID == 0 {
-		err := d.DB.WithContext(ctx).
-			Create(file).Error
-		return fmt.Sprintf("%d", file.ID), err
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
 	}
 
+	return foundFile, nil
+}
+
+func (d *fileStore) update(ctx context.Context, file, foundFile *modelsv2.StoredFile, newContent bool) (string, error) {
+	db := d.DB.WithContext(ctx)
+
 	// Explicitly set DeletedAt.Valid false to prevent UPDATE of deleted_at
 	// A DeletedAt.Time = 0 is set in the struct produced by a Get(), even if deleted_at is NULL
 	// A default DeletedAt struct will also trigger and UPDATE of deleted_at from NULL to 0
@@ -190,7 +477,7 @@ func (d *fileStore) Save(ctx context.Context, file *modelsv2.StoredFile) (string
 
 		foundMetadata := &modelsv2.StoredFileMetadata{}
 		err := db.Model(&modelsv2.StoredFileMetadata{}).
-			Where("file_id == ? AND key == ?", foundFile.ID, metadata.Key).
+			Where("file_id = ? AND key = ?", foundFile.ID, metadata.Key).
 			First(foundMetadata).Error
 
 		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -214,8 +501,8 @@ func (d *fileStore) Save(ctx context.Context, file *modelsv2.StoredFile) (string
 	}
 
 	content := &file.File
-	if len(content.Content) != 0 {
-		if content.ID != 0 {
+	if newContent {
+		if foundFile.File.ID != 0 {
 			foundContent := &foundFile.File
 			err := db.Model(foundContent).
 				Updates(content).Error
@@ -224,6 +511,9 @@ func (d *fileStore) Save(ctx context.Context, file *modelsv2.StoredFile) (string
 				err = errors.WithStack(err)
 				return "", err
 			}
+
+			content.ID = foundContent.ID
+			content.FileID = foundFile.ID
 		} else {
 			content.FileID = foundFile.ID
 			err := db.Create(content).Error
@@ -235,7 +525,7 @@ func (d *fileStore) Save(ctx context.Context, file *modelsv2.StoredFile) (string
 		}
 	}
 
-	err := d.DB.WithContext(ctx).
+	err := db.
 		Omit("Content").
 		Model(foundFile).
 		Where("id = ?", foundFile.ID).
@@ -245,20 +535,71 @@ func (d *fileStore) Save(ctx context.Context, file *modelsv2.StoredFile) (string
 }
 
 func (d *fileStore) Delete(ctx context.Context, id string, permanent bool) (err error) {
-	db := d.DB.WithContext(ctx)
-
-	if permanent {
-		db = db.Unscoped().Select(clause.Associations)
-	}
-
 	idInt, err := modelsv2.ConvertStrToUint(id)
 
 	if err != nil {
 		return err
 	}
 
-	err = db.Model(&modelsv2.StoredFile{}).
-		Delete(&modelsv2.StoredFile{}, idInt).Error
+	var (
+		orphans []string
+		deleted bool
+	)
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		db := tx
+
+		file := &modelsv2.StoredFile{}
+		if err := tx.Unscoped().Preload("File").Find(file, idInt).Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		// a tombstone removed for good was already reported deleted
+		if file.ID != 0 && !file.DeletedAt.Valid {
+			if err := recordFileEvent(tx, modelsv2.FileEventDeleted, file); err != nil {
+				return err
+			}
+			deleted = true
+		}
+
+		if err := auditChange(ctx, tx, idInt, file.File.Checksum); err != nil {
+			return err
+		}
+
+		var keys []string
+		if permanent {
+			db = tx.Unscoped().Select(clause.Associations)
+
+			keys, err = blobRefs(tx, []uint{idInt})
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := db.Model(&modelsv2.StoredFile{}).
+			Delete(&modelsv2.StoredFile{Model: gorm.Model{ID: idInt}}).Error; err != nil {
+			return err
+		}
+
+		if file.ID != 0 {
+			var files int64
+			if deleted {
+				files = 1
+			}
+			if err := releaseUsage(tx, file.Source, files, keys...); err != nil {
+				return err
+			}
+		}
+
+		orphans, err = releaseBlobs(tx, keys...)
+		return err
+	})
+
+	if err == nil {
+		d.deleteBlobs(orphans...)
+		if deleted {
+			d.changes.notify()
+		}
+	}
 
 	return err
 }
@@ -271,6 +612,10 @@ func (d *fileStore) List(ctx context.Context, opts ...ListOption) (files []model
 
 	db := d.DB.WithContext(ctx)
 
+	if err := checkFilter(db, &listOpts); err != nil {
+		return nil, "", err
+	}
+
 	idQuery := db.Model(&modelsv2.StoredFile{}).
 		Unscoped().
 		Scopes(listOpts2.scopes()...).
@@ -278,40 +623,97 @@ func (d *fileStore) List(ctx context.Context, opts ...ListOption) (files []model
 		Joins("left join stored_file_contents on stored_file_contents.file_id = stored_files.id").
 		Distinct("stored_files.id")
 
+	query, order, cursorQuery, err := d.startPage(db.Model(&modelsv2.StoredFile{}), file, "created_at desc", &listOpts)
+	if err != nil {
+		return nil, "", err
+	}
+
 	// reset filters for this
-	listOpts.Filters = []*fileserver.Filter{}
+	listOpts.Filter = nil
+	countOpts := listOpts
+	countOpts.Pagination = nil
+
+	if listOpts.TotalSize != nil {
+		err = db.Model(&modelsv2.StoredFile{}).
+			Scopes(countOpts.scopes()...).
+			Where("stored_files.id in (?)", idQuery).
+			Count(listOpts.TotalSize).Error
+		if err != nil {
+			return nil, "", err
+		}
+	}
 
-	query := db.Model(&modelsv2.StoredFile{}).
+	err = query.
 		Scopes(listOpts.scopes()...).
 		Where("stored_files.id in (?)", idQuery).
 		Preload("Metadata").
 		Preload("File").
-		Order("stored_files.created_at desc").
-		Find(&files)
-
-	err = query.Error
+		Find(&files).Error
+	if err != nil {
+		return nil, "", err
+	}
 
 	if len(files) == listOpts.Pagination.PageSize+1 {
-		nextPageToken = fmt.Sprintf("%d", listOpts.Pagination.Page+1)
-
 		// We purposely limit to pagesize + 1 to check if
 		// there is a page past the last number.
 		// We trim to the page size to prevent having too many
 		// results on each call
 		files = files[:len(files)-1]
+
+		last := &files[len(files)-1]
+		nextPageToken, err = d.nextPageToken(order, cursorQuery, last, last.ID)
+		if err != nil {
+			return nil, "", err
+		}
 	}
 
 	return
 }
 
-func (d *fileStore) Download(ctx context.Context, id string) (file *modelsv2.StoredFile, err error) {
-	file = &modelsv2.StoredFile{}
-	err = d.WithContext(ctx).
+func (d *fileStore) Download(ctx context.Context, id string) (*modelsv2.StoredFile, error) {
+	file, rc, err := d.Open(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	file.File.Content, err = io.ReadAll(rc)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return file, nil
+}
+
+// Open returns the file and a reader over its content, callers must close the reader.
+func (d *fileStore) Open(ctx context.Context, id string) (*modelsv2.StoredFile, io.ReadCloser, error) {
+	file := &modelsv2.StoredFile{}
+	err := d.WithContext(ctx).
 		Unscoped().
 		Preload("Metadata").
 		Preload("File").
 		First(file, id).Error
-	return
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+
+	rc, err := d.openContent(ctx, &file.File)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, rc, nil
+}
+
+func (d *fileStore) openContent(ctx context.Context, content *modelsv2.StoredFileContent) (io.ReadCloser, error) {
+	if content.BlobKey == "" {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	return d.openBlob(ctx, content.BlobKey)
 }
 
 func (d *fileStore) CleanTombstones(ctx context.Context) (int64, error) {
@@ -326,8 +728,21 @@ func (d *fileStore) CleanTombstones(ctx context.Context) (int64, error) {
 		Where("deleted_at < ?", now).
 		Select("id")
 
-	var rowsAffected int64
+	var (
+		rowsAffected int64
+		orphans      []string
+	)
 	err := d.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		keys, err := blobRefs(tx, q)
+		if err != nil {
+			return err
+		}
+
+		sources, err := sourceBlobRefs(tx, q)
+		if err != nil {
+			return err
+		}
+
 		if err := tx.Model(&modelsv2.StoredFileContent{}).
 			Where("file_id in (?)", q).
 			Delete(&[]modelsv2.StoredFileContent{}).Error; err != nil {
@@ -340,6 +755,12 @@ func (d *fileStore) CleanTombstones(ctx context.Context) (int64, error) {
 			return err
 		}
 
+		if err := tx.Model(&modelsv2.StoredFileVersion{}).
+			Where("file_id in (?)", q).
+			Delete(&[]modelsv2.StoredFileVersion{}).Error; err != nil {
+			return err
+		}
+
 		tx1 := tx.Unscoped().
 			Model(&modelsv2.StoredFile{}).
 			Where("id in (?)", q).
@@ -349,12 +770,61 @@ func (d *fileStore) CleanTombstones(ctx context.Context) (int64, error) {
 		}
 
 		rowsAffected = tx1.RowsAffected
-		return nil
+
+		if err := auditOperation(ctx, tx, map[string]string{
+			"tombstonesCleaned": strconv.FormatInt(rowsAffected, 10),
+		}); err != nil {
+			return err
+		}
+
+		for source, sourceKeys := range sources {
+			if err := releaseUsage(tx, source, 0, sourceKeys...); err != nil {
+				return err
+			}
+		}
+
+		orphans, err = releaseBlobs(tx, keys...)
+		return err
 	})
 
 	if err != nil {
 		return 0, err
 	}
 
+	d.deleteBlobs(orphans...)
+
 	return rowsAffected, nil
 }
+
+func uniqueKeys(keys []string) []string {
+	seen := map[string]bool{}
+	unique := keys[:0]
+	for _, key := range keys {
+		if key != "" && !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+	return unique
+}
+
+// deleteBlobs removes blobs that are no longer referenced. Failures are only
+// logged, a leftover blob is unreachable but does not affect the store. It does
+// not use the request context so cleanup still happens for cancelled requests.
+func (d *fileStore) deleteBlobs(keys ...string) {
+	if d.batch != nil {
+		d.batch.orphans = append(d.batch.orphans, keys...)
+		return
+	}
+
+	ctx := context.Background()
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		if err := d.config.BlobStore.Delete(ctx, key); err != nil {
+			d.Log.Error(err, "failed to delete blob", "key", key)
+		}
+	}
+}
@@ -16,10 +16,11 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
-	"os"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -27,8 +28,8 @@ import (
 	. "github.com/onsi/gomega/gstruct"
 	"github.com/onsi/gomega/types"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
 	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
@@ -39,27 +40,30 @@ var _ = Describe("filestore", func() {
 		db     *gorm.DB
 		sut    StoredFileStore
 		closer io.Closer
+		blobs  blobstore.BlobStore
 		ctx    = context.Background()
 
 		files []*modelsv2.StoredFile
 	)
 
 	BeforeEach(func() {
-		os.Remove("filestore.gorm.db")
 		writer := logger.Writer(log.New(GinkgoWriter, "gorm-", log.LstdFlags))
 		log := logger.New(writer, logger.Config{
 			LogLevel: logger.Error,
 		})
-		db, _ = gorm.Open(sqlite.Open("filestore.gorm.db"), &gorm.Config{
+		db = openTestDB("filestore.gorm.db", &gorm.Config{
 			Logger: log,
 		})
-		Expect(Migrate(db)).To(Succeed())
-		sut, closer = New(db, FileStoreConfig{CleanupAfter: time.Duration(0)})
+
+		var err error
+		blobs, err = blobstore.NewLocal(GinkgoT().TempDir())
+		Expect(err).To(Succeed())
+		Expect(Migrate(db, blobs)).To(Succeed())
+		sut, closer = New(db, FileStoreConfig{CleanupAfter: time.Duration(0), BlobStore: blobs})
 	})
 
 	AfterEach(func() {
 		Expect(closer.Close()).To(Succeed())
-		os.Remove("filestore.gorm.db")
 	})
 
 	Context("Paginate", func() {
@@ -169,43 +173,118 @@ var _ = Describe("filestore", func() {
 		It("should paginate by default", func() {
 			results, token, err := sut.List(context.Background())
 			Expect(err).To(Succeed())
-			Expect(token).To(Equal("2"))
+			Expect(token).ToNot(BeEmpty())
 			Expect(results).To(HaveLen(10))
 		})
 
+		ids := func(files []modelsv2.StoredFile) []interface{} {
+			ids := []interface{}{}
+			for _, file := range files {
+				ids = append(ids, file.ID)
+			}
+			return ids
+		}
+
 		It("should paginate", func() {
-			results, token, err := sut.List(context.Background(), Paginate("", 10))
+			seen := []interface{}{}
+			token := ""
+			for page := 0; page < 6; page++ {
+				results, next, err := sut.List(context.Background(), Paginate(token, 10))
+				Expect(err).To(Succeed())
+				Expect(results).To(HaveLen(10))
+				Expect(seen).ToNot(ContainElements(ids(results)...))
+				seen = append(seen, ids(results)...)
+
+				token = next
+				if page < 5 {
+					Expect(token).ToNot(BeEmpty())
+				}
+			}
+			Expect(token).To(BeEmpty())
+
+			var total int64
+			lastResults, token, err := sut.List(context.Background(), Paginate("", 100), CountTotal(&total))
 			Expect(err).To(Succeed())
-			Expect(token).To(Equal("2"))
-			Expect(results).To(HaveLen(10))
+			Expect(token).To(Equal(""))
+			Expect(lastResults).To(HaveLen(60))
+			Expect(total).To(Equal(int64(60)))
+			Expect(ids(lastResults)).To(Equal(seen))
+		})
 
-			results2, token, err := sut.List(context.Background(), Paginate(token, 10))
+		It("should not repeat or skip files added while paging", func() {
+			results, token, err := sut.List(context.Background(), Paginate("", 25))
 			Expect(err).To(Succeed())
-			Expect(token).To(Equal("3"))
-			Expect(results2).To(HaveLen(10))
 
-			newResults := []interface{}{}
+			Expect(db.Save(&modelsv2.StoredFile{Name: "new.txt", Source: "test", SourceType: "report"}).Error).To(Succeed())
+
+			var total int64
+			results2, token, err := sut.List(context.Background(), Paginate(token, 25), CountTotal(&total))
+			Expect(err).To(Succeed())
+			results3, token, err := sut.List(context.Background(), Paginate(token, 25))
+			Expect(err).To(Succeed())
+			Expect(token).To(BeEmpty())
+			Expect(total).To(Equal(int64(61)))
 
-			for _, r := range results2 {
-				newResults = append(newResults, r)
+			saved := []interface{}{}
+			for _, file := range files {
+				if file != nil {
+					saved = append(saved, file.ID)
+				}
 			}
 
-			Expect(results).ToNot(ContainElements(newResults...))
+			all := append(append(ids(results), ids(results2)...), ids(results3)...)
+			Expect(all).To(ConsistOf(saved...))
+		})
 
-			results3, token, err := sut.List(context.Background(), Paginate(token, 10))
+		It("should order by a field", func() {
+			filter, err := fileserver.ParseFilter(`source == "test"`)
 			Expect(err).To(Succeed())
-			Expect(token).To(Equal("4"))
-			Expect(results3).To(HaveLen(10))
 
-			results4, token, err := sut.List(context.Background(), Paginate(token, 10))
-			Expect(err).To(Succeed())
-			Expect(token).To(Equal("5"))
-			Expect(results4).To(HaveLen(10))
+			var names []string
+			token := ""
+			for {
+				results, next, err := sut.List(context.Background(), ApplyFilter(filter), OrderBy("name asc"), Paginate(token, 3))
+				Expect(err).To(Succeed())
+				for _, file := range results {
+					names = append(names, file.Name)
+				}
 
-			lastResults, token, err := sut.List(context.Background(), Paginate("", 100))
+				if next == "" {
+					break
+				}
+				token = next
+			}
+
+			Expect(names).To(Equal([]string{
+				"foo-0.txt", "foo-1.txt", "foo-2.txt", "foo-3.txt", "foo-4.txt",
+				"foo-5.txt", "foo-6.txt", "foo-7.txt", "foo-8.txt", "foo-9.txt",
+			}))
+
+			_, _, err = sut.List(context.Background(), OrderBy("owner"))
+			Expect(err).To(MatchError(ErrInvalidOrderBy))
+			_, _, err = sut.List(context.Background(), OrderBy("name sideways"))
+			Expect(err).To(MatchError(ErrInvalidOrderBy))
+			_, _, err = sut.List(context.Background(), OrderBy("deletedAt"))
+			Expect(err).To(MatchError(ErrInvalidOrderBy))
+		})
+
+		It("should reject forged or reused page tokens", func() {
+			_, token, err := sut.List(context.Background(), Paginate("", 10))
 			Expect(err).To(Succeed())
-			Expect(token).To(Equal(""))
-			Expect(lastResults).To(HaveLen(60))
+
+			_, _, err = sut.List(context.Background(), Paginate("2", 10))
+			Expect(err).To(MatchError(ErrInvalidPageToken))
+
+			payload, signature, _ := strings.Cut(token, ".")
+			_, _, err = sut.List(context.Background(), Paginate(payload+"x."+signature, 10))
+			Expect(err).To(MatchError(ErrInvalidPageToken))
+
+			_, _, err = sut.List(context.Background(), Paginate(token, 10), OrderBy("name"))
+			Expect(err).To(MatchError(ErrInvalidPageToken))
+
+			other := newTestStore(db, FileStoreConfig{BlobStore: blobs, PageTokenKey: []byte("other")})
+			_, _, err = other.List(context.Background(), Paginate(token, 10))
+			Expect(err).To(MatchError(ErrInvalidPageToken))
 		})
 
 		It("should apply simple filter", func() {
@@ -273,6 +352,38 @@ var _ = Describe("filestore", func() {
 			Expect(lastResults).To(HaveLen(3))
 			Expect(lastResults).To(MatchMetadata(Or(HaveLen(3), HaveLen(2))))
 		})
+
+		DescribeTable("should apply filter expressions",
+			func(filter string, count int) {
+				expr, err := fileserver.ParseFilter(filter)
+				Expect(err).To(Succeed())
+
+				results, _, err := sut.List(context.Background(), ApplyFilter(expr), Paginate("", 100))
+				Expect(err).To(Succeed())
+				Expect(results).To(HaveLen(count))
+			},
+			Entry("metadata value", `metadata.foo == "test-1"`, 3),
+			Entry("metadata exists", `metadata.foo2 exists`, 10),
+			Entry("metadata not exists", `!metadata.foo2 exists`, 50),
+			Entry("two metadata keys", `metadata.foo == "test-1" && metadata.foo2 == "test-1"`, 1),
+			Entry("in", `source in ("test", "test-2") && metadata.foo in ("test-1", "test-2")`, 4),
+			Entry("like", `name like "empty-1*"`, 11),
+			Entry("like single character", `name like "empty-?.txt"`, 10),
+			Entry("grouping and negation", `(source == "test" || source == "test-2") and not metadata.foo == "test-1"`, 18),
+		)
+
+		DescribeTable("should reject invalid filter expressions",
+			func(filter string) {
+				expr, err := fileserver.ParseFilter(filter)
+				Expect(err).To(Succeed())
+
+				_, _, err = sut.List(context.Background(), ApplyFilter(expr))
+				Expect(err).To(MatchError(ErrInvalidFilter))
+			},
+			Entry("unknown field", `owner == "jane"`),
+			Entry("like on a number", `size like "1*"`),
+			Entry("bad date", `createdAt > "yesterday"`),
+		)
 	})
 
 	Context("saveOverwrite", func() {
@@ -394,6 +505,105 @@ var _ = Describe("filestore", func() {
 			db.Unscoped().Association(clause.Associations).Delete(&file)
 		})
 
+		It("should upload and open a stream", func() {
+			id, err := sut.Upload(ctx, strings.NewReader("streamed"), func(content *modelsv2.StoredFileContent) (*modelsv2.StoredFile, error) {
+				Expect(content.Size).To(Equal(8))
+				Expect(content.BlobKey).ToNot(BeEmpty())
+				return &file, nil
+			})
+			Expect(err).To(Succeed())
+
+			found, rc, err := sut.Open(ctx, id)
+			Expect(err).To(Succeed())
+			data, err := io.ReadAll(rc)
+			Expect(err).To(Succeed())
+			Expect(rc.Close()).To(Succeed())
+
+			Expect(string(data)).To(Equal("streamed"))
+			Expect(found.File.Size).To(Equal(8))
+			Expect(found.File.Checksum).To(Equal("97a78c00831554f7cc9745e8f6732edcfb571cf548a8d12b48a6e3fc31e5e3e6"))
+
+			_, _, err = sut.Open(ctx, "999")
+			Expect(err).To(MatchError(ErrNotFound))
+		})
+
+		It("should download through a second store sharing the database", func() {
+			id, err := sut.Upload(ctx, strings.NewReader("shared"), func(content *modelsv2.StoredFileContent) (*modelsv2.StoredFile, error) {
+				return &file, nil
+			})
+			Expect(err).To(Succeed())
+
+			other, otherCloser := New(db, FileStoreConfig{BlobStore: blobs})
+			defer otherCloser.Close()
+
+			_, rc, err := other.Open(ctx, id)
+			Expect(err).To(Succeed())
+			data, err := io.ReadAll(rc)
+			Expect(err).To(Succeed())
+			Expect(rc.Close()).To(Succeed())
+			Expect(string(data)).To(Equal("shared"))
+
+			// a blob store local to the other node does not have the content
+			localBlobs, err := blobstore.NewLocal(GinkgoT().TempDir())
+			Expect(err).To(Succeed())
+			local, localCloser := New(db, FileStoreConfig{BlobStore: localBlobs})
+			defer localCloser.Close()
+
+			_, _, err = local.Open(ctx, id)
+			Expect(err).To(MatchError(blobstore.ErrNotFound))
+		})
+
+		It("should discard the upload when prepare fails", func() {
+			var blobKey string
+			_, err := sut.Upload(ctx, strings.NewReader("streamed"), func(content *modelsv2.StoredFileContent) (*modelsv2.StoredFile, error) {
+				blobKey = content.BlobKey
+				return nil, ErrInvalidInput
+			})
+			Expect(err).To(MatchError(ErrInvalidInput))
+
+			_, err = blobs.Get(ctx, blobKey)
+			Expect(err).To(MatchError(blobstore.ErrNotFound))
+		})
+
+		It("should roll back a failed update", func() {
+			id, err := sut.Save(ctx, &file)
+			Expect(err).To(Succeed())
+
+			saved, err := sut.Get(ctx, id)
+			Expect(err).To(Succeed())
+			oldBlobKey := saved.File.BlobKey
+
+			Expect(db.Callback().Update().Before("gorm:update").Register("test:fail_file_update", func(tx *gorm.DB) {
+				if tx.Statement.Table == "stored_files" {
+					tx.AddError(errors.New("update failed"))
+				}
+			})).To(Succeed())
+			defer db.Callback().Update().Remove("test:fail_file_update")
+
+			var newBlobKey string
+			_, err = sut.Upload(ctx, strings.NewReader("new content"), func(content *modelsv2.StoredFileContent) (*modelsv2.StoredFile, error) {
+				newBlobKey = content.BlobKey
+				update := file
+				update.Model = gorm.Model{}
+				update.Metadata = []modelsv2.StoredFileMetadata{{Key: "version", Value: "2"}}
+				return &update, nil
+			})
+			Expect(err).To(HaveOccurred())
+
+			found, rc, err := sut.Open(ctx, id)
+			Expect(err).To(Succeed())
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+
+			Expect(string(data)).To(Equal("test"))
+			Expect(found.File.BlobKey).To(Equal(oldBlobKey))
+			Expect(found.Metadata).To(HaveLen(1))
+			Expect(found.Metadata[0].Key).To(Equal("intervalStart"))
+
+			_, err = blobs.Get(ctx, newBlobKey)
+			Expect(err).To(MatchError(blobstore.ErrNotFound))
+		})
+
 		It("should save, update, soft delete and cleanup", func() {
 			id, err := sut.Save(ctx, &file)
 			Expect(err).To(Succeed())
@@ -454,6 +664,11 @@ var _ = Describe("filestore", func() {
 			Expect(db.Unscoped().Find(&content).Error).To(Succeed())
 			Expect(content).To(HaveLen(1))
 
+			blobKey := content[0].BlobKey
+			Expect(blobKey).ToNot(BeEmpty())
+			_, err = blobs.Get(ctx, blobKey)
+			Expect(err).To(Succeed())
+
 			results, _, err = sut.List(ctx)
 			Expect(results).To(HaveLen(0))
 			results, _, err = sut.List(ctx, ShowDeleted())
@@ -471,6 +686,9 @@ var _ = Describe("filestore", func() {
 			Expect(db.Unscoped().Find(&content).Error).To(Succeed())
 			Expect(content).To(HaveLen(0))
 
+			_, err = blobs.Get(ctx, blobKey)
+			Expect(err).To(MatchError(blobstore.ErrNotFound))
+
 			metadata := []modelsv2.StoredFileMetadata{}
 			Expect(db.Unscoped().Find(&metadata).Error).To(Succeed())
 			Expect(metadata).To(HaveLen(0))
@@ -490,9 +708,14 @@ var _ = Describe("filestore", func() {
 			Expect(deletedFile.File.Content).ToNot(BeEmpty())
 			Expect(deletedFile.ID).To(Not(BeNumerically("==", 0)))
 
+			Expect(deletedFile.File.BlobKey).ToNot(Equal(blobKey))
+
 			Expect(sut.Delete(ctx, id, true)).To(Succeed())
 			results, _, err = sut.List(ctx, ShowDeleted())
 			Expect(results).To(HaveLen(0))
+
+			_, err = blobs.Get(ctx, deletedFile.File.BlobKey)
+			Expect(err).To(MatchError(blobstore.ErrNotFound))
 		})
 
 	})
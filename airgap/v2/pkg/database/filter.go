@@ -26,149 +26,332 @@ import (
 	"emperror.dev/errors"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
 	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
 )
 
-type filter struct {
-	Filters fileserver.Filters
-}
+// ErrInvalidFilter is returned when a filter can not be applied, such as a
+// comparison on an unknown field or a value of the wrong type.
+const ErrInvalidFilter = errors.Sentinel("invalid filter")
+
+type filter struct{}
 
-func (file filter) ToScope(opts *ListOptions) func(db *gorm.DB) *gorm.DB {
-	if len(opts.Filters) == 0 {
+func (f filter) ToScope(opts *ListOptions) func(db *gorm.DB) *gorm.DB {
+	if opts.Filter == nil {
 		return func(db *gorm.DB) *gorm.DB {
 			return db
 		}
 	}
 
-	builder := &strings.Builder{}
-	variables := []interface{}{}
-	var filterError error
+	return func(db *gorm.DB) *gorm.DB {
+		builder := &filterBuilder{stmt: db.Statement, schemas: opts.filterSchemas()}
 
-	for _, f := range opts.Filters {
-		op := operatorToSql(f.Operator)
-		left, err := strconv.Unquote(f.Left)
+		sql, err := builder.build(opts.Filter)
 		if err != nil {
-			left = f.Left
+			db.AddError(err)
+			return db
+		}
+
+		for _, join := range builder.joins {
+			db = db.Joins(join.sql, join.vars...)
 		}
-		left = strings.Title(left)
 
-		// fieldType used in left side
-		fieldName, fieldType, err := getFieldMapping(left)
+		return db.Where(sql, builder.vars...)
+	}
+}
+
+// checkFilter builds the filter of opts without running a query, scopes of a
+// subquery lose their errors.
+func checkFilter(db *gorm.DB, opts *ListOptions) error {
+	if opts.Filter == nil {
+		return nil
+	}
+
+	builder := &filterBuilder{stmt: db.Statement, schemas: opts.filterSchemas()}
+	_, err := builder.build(opts.Filter)
+	return err
+}
 
+type filterJoin struct {
+	sql  string
+	vars []interface{}
+}
+
+// filterBuilder translates a filter expression to a SQL condition. Metadata
+// keys are joined once each from stored_file_metadata.
+type filterBuilder struct {
+	stmt    *gorm.Statement
+	schemas []*schema.Schema
+
+	vars     []interface{}
+	joins    []filterJoin
+	metadata map[string]string
+}
+
+func (b *filterBuilder) build(expr fileserver.FilterExpr) (string, error) {
+	switch expr := expr.(type) {
+	case *fileserver.FilterLogical:
+		left, err := b.build(expr.Left)
 		if err != nil {
-			filterError = err
-			break
+			return "", err
 		}
 
-		builder.WriteString(fmt.Sprintf("%s %s ?", fieldName, op))
+		right, err := b.build(expr.Right)
+		if err != nil {
+			return "", err
+		}
 
-		// write boolean operator if it is prsent
-		if f.NextFilterOperator != fileserver.FilterBooleanOperator("") {
-			builder.WriteString(" ")
-			builder.WriteString(boolOperatorToSQL(f.NextFilterOperator))
-			builder.WriteString(" ")
+		operator := "AND"
+		if expr.Operator == fileserver.FilterOr {
+			operator = "OR"
 		}
 
-		// set variable and append to array, unquote it in case it's quoted
-		right, err := strconv.Unquote(f.Right)
+		return fmt.Sprintf("(%s %s %s)", left, operator, right), nil
+	case *fileserver.FilterNot:
+		inner, err := b.build(expr.Expr)
 		if err != nil {
-			right = f.Right
+			return "", err
 		}
 
-		if fieldType == reflect.TypeOf(time.Time{}) {
-			rightAsDate, err1 := time.Parse(time.RFC3339, right)
+		return fmt.Sprintf("NOT %s", inner), nil
+	case *fileserver.FilterCondition:
+		if expr.IsMetadata() {
+			return b.metadataCondition(expr)
+		}
+		return b.fieldCondition(expr)
+	}
 
-			if err1 == nil {
-				variables = append(variables, rightAsDate)
-				continue
-			}
+	return "", errors.Wrapf(ErrInvalidFilter, "unsupported expression %T", expr)
+}
 
-			rightAsInt, err2 := strconv.ParseInt(right, 0, 64)
+func (b *filterBuilder) fieldCondition(cond *fileserver.FilterCondition) (string, error) {
+	table, field, err := b.field(cond.Field)
+	if err != nil {
+		return "", err
+	}
 
-			if err2 == nil {
-				variables = append(variables, time.Unix(rightAsInt, 0))
-				continue
-			}
+	column := b.stmt.Quote(clause.Column{Table: table, Name: field.DBName})
+	isText := field.FieldType.Kind() == reflect.String
+	nullable := field.FieldType.Kind() == reflect.Ptr || field.FieldType == reflect.TypeOf(gorm.DeletedAt{})
 
-			filterError = errors.Combine(err1, err2)
-			break
+	if cond.Operator == fileserver.FilterExists {
+		if isText {
+			return fmt.Sprintf("(%s IS NOT NULL AND %s <> '')", column, column), nil
 		}
+		return fmt.Sprintf("%s IS NOT NULL", column), nil
+	}
 
-		variables = append(variables, right)
+	if cond.Operator == fileserver.FilterLike && !isText {
+		return "", errors.WithDetails(errors.Wrapf(ErrInvalidFilter, "like is only supported on text fields"), "field", cond.Field)
 	}
 
-	return func(db *gorm.DB) *gorm.DB {
-		if filterError != nil {
-			db.AddError(filterError)
+	values := make([]interface{}, 0, len(cond.Values))
+	for _, value := range cond.Values {
+		converted, err := convertFilterValue(field, value)
+		if err != nil {
+			return "", errors.WithDetails(errors.Wrapf(ErrInvalidFilter, "invalid value %q for %s", value, cond.Field), "err", err)
 		}
+		values = append(values, converted)
+	}
 
-		return db.Where(builder.String(), variables...)
+	sql, err := b.compare(column, cond, values)
+	if err != nil || !nullable {
+		return sql, err
 	}
+
+	// a negated comparison still excludes unset values
+	return fmt.Sprintf("(%s IS NOT NULL AND %s)", column, sql), nil
 }
 
-func isDateField(in string) bool {
-	return strings.Contains(in, "updated_at") ||
-		strings.Contains(in, "deleted_at") ||
-		strings.Contains(in, "created_at")
+func (b *filterBuilder) metadataCondition(cond *fileserver.FilterCondition) (string, error) {
+	if !b.hasMetadata() {
+		return "", errors.Wrapf(ErrInvalidFilter, "metadata fields are not supported")
+	}
+
+	alias := b.joinMetadata(cond.MetadataKey())
+	id := b.stmt.Quote(clause.Column{Table: alias, Name: "id"})
+
+	if cond.Operator == fileserver.FilterExists {
+		return fmt.Sprintf("%s IS NOT NULL", id), nil
+	}
+
+	values := make([]interface{}, 0, len(cond.Values))
+	for _, value := range cond.Values {
+		values = append(values, value)
+	}
+
+	sql, err := b.compare(b.stmt.Quote(clause.Column{Table: alias, Name: "value"}), cond, values)
+	if err != nil {
+		return "", err
+	}
+
+	// files without the key never match, even when the condition is negated
+	return fmt.Sprintf("(%s IS NOT NULL AND %s)", id, sql), nil
 }
 
-func camelToSnakeCase(in string) string {
-	out := &strings.Builder{}
-	for _, c := range in {
-		if unicode.IsUpper(c) {
-			out.WriteRune('_')
-			out.WriteRune(unicode.ToLower(c))
-			continue
+func (b *filterBuilder) compare(column string, cond *fileserver.FilterCondition, values []interface{}) (string, error) {
+	switch cond.Operator {
+	case fileserver.FilterIn:
+		b.vars = append(b.vars, values)
+		return fmt.Sprintf("%s IN ?", column), nil
+	case fileserver.FilterLike:
+		b.vars = append(b.vars, globToLike(cond.Values[0]))
+
+		// like ignores the case of ascii letters in sqlite, postgres needs ilike to match
+		like := "LIKE"
+		if b.stmt.Dialector.Name() == "postgres" {
+			like = "ILIKE"
 		}
-		out.WriteRune(c)
+		return fmt.Sprintf(`%s %s ? ESCAPE '\'`, column, like), nil
+	case fileserver.FilterEqual:
+		b.vars = append(b.vars, values[0])
+		return fmt.Sprintf("%s = ?", column), nil
+	case fileserver.FilterNotEqual:
+		b.vars = append(b.vars, values[0])
+		return fmt.Sprintf("%s <> ?", column), nil
+	case fileserver.FilterGreaterThan, fileserver.FilterGreaterThanEqual,
+		fileserver.FilterLessThan, fileserver.FilterLessThanEqual:
+		b.vars = append(b.vars, values[0])
+		return fmt.Sprintf("%s %s ?", column, cond.Operator), nil
 	}
-	return out.String()
+
+	return "", errors.Wrapf(ErrInvalidFilter, "unsupported operator %s", cond.Operator)
 }
 
-func boolOperatorToSQL(operator fileserver.FilterBooleanOperator) string {
-	switch operator {
-	case fileserver.FilterAnd:
-		return "AND"
-	case fileserver.FilterOr:
-		return "OR"
+func (b *filterBuilder) hasMetadata() bool {
+	for _, s := range b.schemas {
+		if s == metadata {
+			return true
+		}
+	}
+	return false
+}
+
+// joinMetadata joins the metadata row of key and returns its alias
+func (b *filterBuilder) joinMetadata(key string) string {
+	if alias, ok := b.metadata[key]; ok {
+		return alias
+	}
+
+	if b.metadata == nil {
+		b.metadata = map[string]string{}
 	}
 
-	return ""
+	alias := fmt.Sprintf("md_%d", len(b.metadata))
+	b.metadata[key] = alias
+
+	b.joins = append(b.joins, filterJoin{
+		sql: fmt.Sprintf("LEFT JOIN %s %s ON %s = %s AND %s = ?",
+			b.stmt.Quote(metadata.Table),
+			b.stmt.Quote(alias),
+			b.stmt.Quote(clause.Column{Table: alias, Name: "file_id"}),
+			b.stmt.Quote(clause.Column{Table: file.Table, Name: "id"}),
+			b.stmt.Quote(clause.Column{Table: alias, Name: "key"}),
+		),
+		vars: []interface{}{key},
+	})
+
+	return alias
 }
 
-func operatorToSql(operator fileserver.FilterOperator) string {
-	switch operator {
-	case fileserver.FilterEqual:
-		return "="
-	case fileserver.FilterNotEqual:
-		return "<>"
-	default:
-		return operator.String()
+// field finds a field by its name, such as sourceType, or by its column, such as source_type
+func (b *filterBuilder) field(name string) (string, *schema.Field, error) {
+	for _, s := range b.schemas {
+		for _, f := range []*schema.Field{
+			s.FieldsByName[cases.Title(language.Und, cases.NoLower).String(name)],
+			s.FieldsByDBName[name],
+			s.FieldsByDBName[camelToSnakeCase(name)],
+		} {
+			if f != nil && f.DBName != "" {
+				return s.Table, f, nil
+			}
+		}
 	}
+
+	return "", nil, errors.WithDetails(errors.Wrapf(ErrInvalidFilter, "field %s not found", name), "field", name)
 }
 
 var (
-	namingStrat = schema.NamingStrategy{}
-	file, _     = schema.Parse(&modelsv2.StoredFile{}, &sync.Map{}, namingStrat)
-	metadata, _ = schema.Parse(&modelsv2.StoredFileMetadata{}, &sync.Map{}, namingStrat)
-	content, _  = schema.Parse(&modelsv2.StoredFileContent{}, &sync.Map{}, namingStrat)
+	timeType      = reflect.TypeOf(time.Time{})
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
 )
 
-func getFieldMapping(name string) (string, reflect.Type, error) {
-	for _, schema := range []*schema.Schema{file, content, metadata} {
-		f, ok := schema.FieldsByName[name]
-		if ok {
-			name := getFieldName(*schema, *f)
-			return name, f.FieldType, nil
+// convertFilterValue converts a filter value to the type of field. Times are
+// RFC3339 or seconds since the epoch.
+func convertFilterValue(field *schema.Field, value string) (interface{}, error) {
+	fieldType := field.FieldType
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	if fieldType == timeType || fieldType == deletedAtType {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t, nil
 		}
+
+		seconds, err := strconv.ParseInt(value, 0, 64)
+		if err != nil {
+			return nil, errors.Errorf("%s is not a RFC3339 time or a unix timestamp", value)
+		}
+		return time.Unix(seconds, 0), nil
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(value, 0, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(value, 0, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	case reflect.Bool:
+		return strconv.ParseBool(value)
 	}
 
-	return "", nil, errors.Errorf("field %s not found", name)
+	return value, nil
 }
 
-func getFieldName(schema schema.Schema, field schema.Field) string {
-	tableName := namingStrat.TableName(schema.Name)
-	fieldName := namingStrat.ColumnName(tableName, field.Name)
-	return fmt.Sprintf("`%s`.`%s`", tableName, fieldName)
+// globToLike converts a glob using * and ? to a LIKE pattern escaped with \
+func globToLike(glob string) string {
+	like := &strings.Builder{}
+	for _, c := range glob {
+		switch c {
+		case '*':
+			like.WriteRune('%')
+		case '?':
+			like.WriteRune('_')
+		case '%', '_', '\\':
+			like.WriteRune('\\')
+			like.WriteRune(c)
+		default:
+			like.WriteRune(c)
+		}
+	}
+	return like.String()
+}
+
+func camelToSnakeCase(in string) string {
+	out := &strings.Builder{}
+	for _, c := range in {
+		if unicode.IsUpper(c) {
+			out.WriteRune('_')
+			out.WriteRune(unicode.ToLower(c))
+			continue
+		}
+		out.WriteRune(c)
+	}
+	return out.String()
 }
+
+var (
+	namingStrat   = schema.NamingStrategy{}
+	file, _       = schema.Parse(&modelsv2.StoredFile{}, &sync.Map{}, namingStrat)
+	metadata, _   = schema.Parse(&modelsv2.StoredFileMetadata{}, &sync.Map{}, namingStrat)
+	content, _    = schema.Parse(&modelsv2.StoredFileContent{}, &sync.Map{}, namingStrat)
+	auditEvent, _ = schema.Parse(&modelsv2.AuditEvent{}, &sync.Map{}, namingStrat)
+
+	fileSchemas       = []*schema.Schema{file, content, metadata}
+	auditEventSchemas = []*schema.Schema{auditEvent}
+)
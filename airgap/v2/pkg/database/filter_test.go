@@ -0,0 +1,147 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var _ = Describe("filter", func() {
+	var (
+		db     *gorm.DB
+		sut    StoredFileStore
+		closer io.Closer
+		ctx    = context.Background()
+	)
+
+	BeforeEach(func() {
+		db = openTestDB("filter.gorm.db", &gorm.Config{Logger: logger.Discard})
+		blobs, err := blobstore.NewLocal(GinkgoT().TempDir())
+		Expect(err).To(Succeed())
+		Expect(Migrate(db, blobs)).To(Succeed())
+
+		sut, closer = New(db, FileStoreConfig{BlobStore: blobs})
+	})
+
+	AfterEach(func() {
+		Expect(closer.Close()).To(Succeed())
+	})
+
+	// build returns the query listing the files matching expr and its parameters
+	build := func(expr fileserver.FilterExpr) (string, []interface{}, error) {
+		tx := db.Session(&gorm.Session{DryRun: true}).
+			Model(&modelsv2.StoredFile{}).
+			Scopes(filter{}.ToScope(&ListOptions{Filter: expr})).
+			Find(&[]modelsv2.StoredFile{})
+		return tx.Statement.SQL.String(), tx.Statement.Vars, tx.Error
+	}
+
+	metadataCondition := func(key string, operator fileserver.FilterOperator, values ...string) fileserver.FilterExpr {
+		return &fileserver.FilterCondition{Field: fileserver.MetadataFieldPrefix + key, Operator: operator, Values: values}
+	}
+
+	injections := []string{
+		`x' OR '1'='1`,
+		`x" OR "1"="1`,
+		`x') OR 1=1 --`,
+		`x"; DROP TABLE stored_files; --`,
+		`md_0"."value`,
+		`x\' OR 1=1 /*`,
+		"x`) OR 1=1 --",
+	}
+
+	It("should pass metadata keys and values as parameters", func() {
+		for _, injection := range injections {
+			sql, vars, err := build(&fileserver.FilterLogical{
+				Operator: fileserver.FilterOr,
+				Left:     metadataCondition(injection, fileserver.FilterEqual, injection),
+				Right: &fileserver.FilterNot{
+					Expr: metadataCondition(injection+"2", fileserver.FilterIn, injection, "b"),
+				},
+			})
+			Expect(err).To(Succeed())
+			Expect(sql).NotTo(ContainSubstring(injection), "key %s", injection)
+			Expect(vars).To(ContainElements(injection, injection+"2"))
+		}
+	})
+
+	It("should pass like patterns as parameters", func() {
+		sql, vars, err := build(&fileserver.FilterCondition{
+			Field:    "name",
+			Operator: fileserver.FilterLike,
+			Values:   []string{`' OR 1=1 -- 100%_\*`},
+		})
+		Expect(err).To(Succeed())
+		Expect(sql).NotTo(ContainSubstring("OR 1=1"))
+		Expect(vars).To(ContainElement(`' OR 1=1 -- 100\%\_\\%`))
+	})
+
+	It("should only use the fields of the model as columns", func() {
+		for _, injection := range injections {
+			_, _, err := build(&fileserver.FilterCondition{
+				Field:    injection,
+				Operator: fileserver.FilterEqual,
+				Values:   []string{"a"},
+			})
+			Expect(err).To(MatchError(ErrInvalidFilter), "field %s", injection)
+		}
+	})
+
+	It("should match metadata keys and values literally", func() {
+		for i, injection := range injections {
+			_, err := sut.Save(ctx, &modelsv2.StoredFile{
+				Name:       injection,
+				Source:     "redhat-marketplace",
+				SourceType: "report",
+				File:       modelsv2.StoredFileContent{Content: []byte(injection)},
+				Metadata:   []modelsv2.StoredFileMetadata{{Key: injection, Value: injections[len(injections)-1-i]}},
+			})
+			Expect(err).To(Succeed())
+		}
+		_, err := sut.Save(ctx, &modelsv2.StoredFile{
+			Name:       "plain.csv",
+			Source:     "redhat-marketplace",
+			SourceType: "report",
+			File:       modelsv2.StoredFileContent{Content: []byte("plain")},
+			Metadata:   []modelsv2.StoredFileMetadata{{Key: "x", Value: "1"}},
+		})
+		Expect(err).To(Succeed())
+
+		for i, injection := range injections {
+			files, _, err := sut.List(ctx, ApplyFilter(
+				metadataCondition(injection, fileserver.FilterEqual, injections[len(injections)-1-i])))
+			Expect(err).To(Succeed())
+			Expect(files).To(HaveLen(1), "key %s", injection)
+			Expect(files[0].Name).To(Equal(injection))
+
+			files, _, err = sut.List(ctx, ApplyFilter(metadataCondition(injection, fileserver.FilterExists)))
+			Expect(err).To(Succeed())
+			Expect(files).To(HaveLen(1), "key %s", injection)
+		}
+
+		files, _, err := sut.List(ctx)
+		Expect(err).To(Succeed())
+		Expect(files).To(HaveLen(len(injections) + 1))
+	})
+})
@@ -15,10 +15,9 @@
 package database
 
 import (
-	"strconv"
-
 	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 type ListOption interface {
@@ -28,7 +27,14 @@ type ListOption interface {
 type ListOptions struct {
 	Pagination  *ListPagination
 	ShowDeleted bool
-	Filters     fileserver.Filters
+	Filter      fileserver.FilterExpr
+	OrderBy     string
+
+	// TotalSize is set to the number of items matching when not nil
+	TotalSize *int64
+
+	// schemas holds the fields filters can use, the file fields by default
+	schemas []*schema.Schema
 }
 
 func (o *ListOptions) ApplyOptions(opts []ListOption) *ListOptions {
@@ -37,7 +43,7 @@ func (o *ListOptions) ApplyOptions(opts []ListOption) *ListOptions {
 	}
 
 	//defaults
-	o.Pagination = &ListPagination{Page: 1, PageSize: 10}
+	o.Pagination = &ListPagination{PageSize: 10}
 
 	for _, opt := range opts {
 		opt.ApplyToList(o)
@@ -55,22 +61,16 @@ func (o *ListOptions) scopes() []func(db *gorm.DB) *gorm.DB {
 	return scopesToApply
 }
 
-func Paginate(pageToken string, pageSize int) ListOption {
-	var (
-		page int
-		err  error
-	)
-
-	if pageToken == "" {
-		page = 1
-	} else {
-		page, err = strconv.Atoi(pageToken)
-
-		if err != nil {
-			page = 1
-		}
+func (o *ListOptions) filterSchemas() []*schema.Schema {
+	if o.schemas == nil {
+		return fileSchemas
 	}
+	return o.schemas
+}
 
+// Paginate lists a page of pageSize items starting after pageToken, the
+// next page token returned by the previous List call.
+func Paginate(pageToken string, pageSize int) ListOption {
 	switch {
 	case pageSize > 100:
 		pageSize = 100
@@ -79,19 +79,45 @@ func Paginate(pageToken string, pageSize int) ListOption {
 	}
 
 	return ListPagination{
-		Page:     page,
+		Token:    pageToken,
 		PageSize: pageSize,
 	}
 }
 
 type ListPagination struct {
-	Page, PageSize int
+	Token    string
+	PageSize int
 }
 
 func (l ListPagination) ApplyToList(opts *ListOptions) {
 	opts.Pagination = &l
 }
 
+// OrderBy sorts the list by a field, optionally followed by asc or desc
+func OrderBy(orderBy string) ListOption {
+	return orderByOpt(orderBy)
+}
+
+type orderByOpt string
+
+func (o orderByOpt) ApplyToList(opts *ListOptions) {
+	opts.OrderBy = string(o)
+}
+
+// CountTotal sets total to the number of items matching the list, ignoring
+// pagination.
+func CountTotal(total *int64) ListOption {
+	return countTotal{total: total}
+}
+
+type countTotal struct {
+	total *int64
+}
+
+func (c countTotal) ApplyToList(opts *ListOptions) {
+	opts.TotalSize = c.total
+}
+
 func ShowDeleted() ListOption {
 	return showDeleted{}
 }
@@ -102,12 +128,20 @@ func (f showDeleted) ApplyToList(opts *ListOptions) {
 	opts.ShowDeleted = true
 }
 
-type filtersOpt fileserver.Filters
+type filterOpt struct {
+	expr fileserver.FilterExpr
+}
+
+// ApplyFilter only lists the items matching expr
+func ApplyFilter(expr fileserver.FilterExpr) ListOption {
+	return filterOpt{expr: expr}
+}
 
+// ApplyFilters only lists the items matching filters chained left to right
 func ApplyFilters(filters fileserver.Filters) ListOption {
-	return filtersOpt(filters)
+	return filterOpt{expr: filters.Expr()}
 }
 
-func (f filtersOpt) ApplyToList(opts *ListOptions) {
-	opts.Filters = fileserver.Filters(f)
+func (f filterOpt) ApplyToList(opts *ListOptions) {
+	opts.Filter = f.expr
 }
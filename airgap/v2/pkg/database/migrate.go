@@ -15,15 +15,24 @@
 package database
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
 	gormigrate "github.com/go-gormigrate/gormigrate/v2"
 	"github.com/google/uuid"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
 	modelsv1 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v1"
 	models "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
 	"gorm.io/gorm"
 )
 
-var (
-	migrations = []*gormigrate.Migration{
+func migrations(blobs blobstore.BlobStore) []*gormigrate.Migration {
+	return []*gormigrate.Migration{
 		// create v1 tables
 		{
 			ID: "202109010000",
@@ -46,7 +55,7 @@ var (
 		{
 			ID: "202110160004",
 			Migrate: func(tx *gorm.DB) (err error) {
-				if err = tx.AutoMigrate(models.StoredFileContent{}, models.StoredFileMetadata{}, models.StoredFile{}); err != nil {
+				if err = tx.AutoMigrate(storedFileContent20211120{}, models.StoredFileMetadata{}, storedFile20211120{}); err != nil {
 					return
 				}
 
@@ -58,14 +67,14 @@ var (
 						Preload("FileMetadata").
 						FindInBatches(&oldFiles, batchLimit, func(_ *gorm.DB, batch int) error {
 							uniqueID := uuid.NewString()
-							newFiles := []*models.StoredFile{}
+							newFiles := []*storedFile20211120{}
 
 							for _, file := range oldFiles {
-								newFile := &models.StoredFile{
+								newFile := &storedFile20211120{
 									Name:       file.ProvidedName,
 									Source:     "redhat-marketplace",
 									SourceType: "migrate-202110160004-" + uniqueID,
-									File: models.StoredFileContent{
+									File: storedFileContent20211120{
 										Content:  file.File.Content,
 										MimeType: "application/gzip",
 									},
@@ -138,9 +147,9 @@ var (
 				}
 
 				if err = tx.AutoMigrate(
-					models.StoredFileContent{},
+					storedFileContent20211120{},
 					models.StoredFileMetadata{},
-					models.StoredFile{}); err != nil {
+					storedFile20211120{}); err != nil {
 					return
 				}
 
@@ -151,32 +160,345 @@ var (
 			ID: "20211120000",
 			Migrate: func(tx *gorm.DB) (err error) {
 				if err = tx.AutoMigrate(
-					models.StoredFileContent{},
+					storedFileContent20211120{},
 					models.StoredFileMetadata{},
-					models.StoredFile{}); err != nil {
+					storedFile20211120{}); err != nil {
 					return
 				}
 
 				return
 			},
 		},
+		// move file content out of the database into the blob store
+		{
+			ID: "202311010000",
+			Migrate: func(tx *gorm.DB) (err error) {
+				if err = tx.AutoMigrate(models.StoredFileContent{}); err != nil {
+					return
+				}
+
+				if !tx.Migrator().HasColumn(&storedFileContent20211120{}, "content") {
+					return
+				}
+
+				// the content is read through the other nodes from the same blob
+				// store, a dqlite cluster refuses a store local to a node
+				batchLimit := 10
+				oldContents := []storedFileContent20211120{}
+				result := tx.Unscoped().
+					Where("content IS NOT NULL AND (blob_key IS NULL OR blob_key = '')").
+					FindInBatches(&oldContents, batchLimit, func(batchTx *gorm.DB, batch int) error {
+						for i := range oldContents {
+							content := &oldContents[i]
+							if len(content.Content) == 0 {
+								continue
+							}
+
+							key := blobstore.NewKey()
+							if _, err := blobs.Put(context.Background(), key, bytes.NewReader(content.Content)); err != nil {
+								return err
+							}
+
+							if err := tx.Model(&models.StoredFileContent{}).
+								Where("id = ?", content.ID).
+								Update("blob_key", key).Error; err != nil {
+								return err
+							}
+						}
+
+						// returns error will stop future batches
+						return nil
+					})
+
+				if err = result.Error; err != nil {
+					return
+				}
+
+				return tx.Migrator().DropColumn(&storedFileContent20211120{}, "content")
+			},
+		},
+		// resumable upload sessions
+		{
+			ID: "202311150000",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(models.UploadSession{}, models.UploadSessionPart{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(models.UploadSessionPart{}, models.UploadSession{})
+			},
+		},
+		// audit log
+		{
+			ID: "202311200000",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(models.AuditEvent{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(models.AuditEvent{})
+			},
+		},
+		// file versions, the current content of every file becomes its first version
+		{
+			ID: "202311250000",
+			Migrate: func(tx *gorm.DB) (err error) {
+				if err = tx.AutoMigrate(storedFileVersion20231125{}); err != nil {
+					return
+				}
+
+				if !tx.Migrator().HasColumn(&storedFile20231125{}, "version") {
+					if err = tx.Migrator().AddColumn(&storedFile20231125{}, "Version"); err != nil {
+						return
+					}
+				}
+
+				batchLimit := 10
+				files := []storedFile20231125{}
+				result := tx.Unscoped().
+					Preload("File").
+					Preload("Metadata").
+					Where("version = 0 OR version IS NULL").
+					FindInBatches(&files, batchLimit, func(_ *gorm.DB, batch int) error {
+						for i := range files {
+							version, err := files[i].firstVersion()
+							if err != nil {
+								return err
+							}
+
+							if err := tx.Create(version).Error; err != nil {
+								return err
+							}
+
+							if err := tx.Model(&storedFile20231125{}).
+								Where("id = ?", files[i].ID).
+								UpdateColumn("version", 1).Error; err != nil {
+								return err
+							}
+						}
+
+						// returns error will stop future batches
+						return nil
+					})
+
+				return result.Error
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(storedFileVersion20231125{}); err != nil {
+					return err
+				}
+
+				return tx.Migrator().DropColumn(&storedFile20231125{}, "version")
+			},
+		},
+		// store content once per checksum, files with the same content share its blob
+		{
+			ID: "202312010000",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(models.StoredBlob{}); err != nil {
+					return err
+				}
+
+				var duplicates []string
+				err := tx.Transaction(func(tx *gorm.DB) (err error) {
+					duplicates, err = rebuildBlobs(tx)
+					return
+				})
+				if err != nil {
+					return err
+				}
+
+				for _, key := range duplicates {
+					if err := blobs.Delete(context.Background(), key); err != nil && !errors.Is(err, blobstore.ErrNotFound) {
+						return err
+					}
+				}
+
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(models.StoredBlob{})
+			},
+		},
+		// compressed and encrypted content, the blobs stored before are plain
+		{
+			ID: "202312050000",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(models.StoredBlob{}); err != nil {
+					return err
+				}
+
+				return tx.Model(&models.StoredBlob{}).
+					Where("stored_size = 0 OR stored_size IS NULL").
+					UpdateColumn("stored_size", gorm.Expr("size")).Error
+			},
+		},
+		{
+			ID: "202312100000",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(models.FileEvent{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(models.FileEvent{})
+			},
+		},
+		// storage usage counters, set from the files stored already
+		{
+			ID: "202312150000",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(models.StorageUsage{}); err != nil {
+					return err
+				}
+
+				return recountUsage(tx)
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(models.StorageUsage{})
+			},
+		},
 	}
-)
+}
 
-func migrator(db *gorm.DB) *gormigrate.Gormigrate {
-	return gormigrate.New(db, gormigrate.DefaultOptions, migrations)
+func migrator(db *gorm.DB, blobs blobstore.BlobStore) *gormigrate.Gormigrate {
+	return gormigrate.New(db, gormigrate.DefaultOptions, migrations(blobs))
 }
 
-func Migrate(db *gorm.DB) error {
-	m := migrator(db)
+// Migrate brings the database schema up to date. File content still stored
+// in the database is moved to the blob store.
+func Migrate(db *gorm.DB, blobs blobstore.BlobStore) error {
+	m := migrator(db, blobs)
 
 	if err := m.Migrate(); err != nil {
 		return err
 	}
 
-	if err := db.AutoMigrate(models.StoredFile{}, models.StoredFileContent{}, models.StoredFileMetadata{}); err != nil {
+	if err := db.AutoMigrate(models.StoredFile{}, models.StoredFileContent{}, models.StoredFileMetadata{},
+		models.StoredFileVersion{}, models.StoredBlob{}, models.UploadSession{}, models.UploadSessionPart{}, models.AuditEvent{}, models.FileEvent{}); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// storedFileContent20211120 and storedFile20211120 are snapshots of the v2 models
+// from before the file content moved to the blob store. Migrations up to 20211120000
+// use them so they keep working as the models change.
+type storedFileContent20211120 struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	FileID uint `gorm:"uniqueIndex"`
+
+	Checksum string
+	Size     int
+	MimeType string
+
+	Content []byte
+}
+
+func (storedFileContent20211120) TableName() string {
+	return "stored_file_contents"
+}
+
+func (f *storedFileContent20211120) BeforeSave(tx *gorm.DB) (err error) {
+	if len(f.Content) == 0 {
+		return nil
+	}
+
+	f.Size = len(f.Content)
+	f.Checksum = fmt.Sprintf("%x", sha256.Sum256(f.Content))
+	return
+}
+
+type storedFile20211120 struct {
+	gorm.Model
+
+	Name       string `gorm:"uniqueIndex:idx_stored_file-name"`
+	Source     string `gorm:"uniqueIndex:idx_stored_file-name"`
+	SourceType string `gorm:"uniqueIndex:idx_stored_file-name"`
+
+	File     storedFileContent20211120   `gorm:"foreignKey:FileID"`
+	Metadata []models.StoredFileMetadata `gorm:"foreignKey:FileID"`
+}
+
+func (storedFile20211120) TableName() string {
+	return "stored_files"
+}
+
+// storedFileContent20231125, storedFile20231125 and storedFileVersion20231125
+// are snapshots of the v2 models when file versions were added, with the
+// content in the blob store. Migration 202311250000 uses them so it keeps
+// working as the models change.
+type storedFileContent20231125 struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	FileID uint `gorm:"uniqueIndex"`
+
+	Checksum string
+	Size     int
+	MimeType string
+	BlobKey  string
+}
+
+func (storedFileContent20231125) TableName() string {
+	return "stored_file_contents"
+}
+
+type storedFile20231125 struct {
+	gorm.Model
+
+	Name       string `gorm:"uniqueIndex:idx_stored_file-name"`
+	Source     string `gorm:"uniqueIndex:idx_stored_file-name"`
+	SourceType string `gorm:"uniqueIndex:idx_stored_file-name"`
+
+	Version uint64
+
+	File     storedFileContent20231125   `gorm:"foreignKey:FileID"`
+	Metadata []models.StoredFileMetadata `gorm:"foreignKey:FileID"`
+}
+
+func (storedFile20231125) TableName() string {
+	return "stored_files"
+}
+
+// firstVersion snapshots the content and metadata of the file as its version 1
+func (f *storedFile20231125) firstVersion() (*storedFileVersion20231125, error) {
+	metadata := map[string]string{}
+	for _, md := range f.Metadata {
+		metadata[md.Key] = md.Value
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storedFileVersion20231125{
+		FileID:   f.ID,
+		Version:  1,
+		Checksum: f.File.Checksum,
+		Size:     f.File.Size,
+		MimeType: f.File.MimeType,
+		BlobKey:  f.File.BlobKey,
+		Metadata: string(data),
+	}, nil
+}
+
+type storedFileVersion20231125 struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	FileID  uint   `gorm:"uniqueIndex:idx_stored_file_version"`
+	Version uint64 `gorm:"uniqueIndex:idx_stored_file_version"`
+
+	Checksum string
+	Size     int
+	MimeType string
+	BlobKey  string
+	Metadata string
+}
+
+func (storedFileVersion20231125) TableName() string {
+	return "stored_file_versions"
+}
@@ -15,34 +15,39 @@
 package database
 
 import (
+	"context"
+	"crypto/sha256"
 	"fmt"
-	"os"
+	"io"
 	"strconv"
 
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
 	modelsv1 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v1"
 	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
 var _ = Describe("filestore", func() {
 	Context("migrate", func() {
-		var db *gorm.DB
+		var (
+			db    *gorm.DB
+			blobs blobstore.BlobStore
+		)
 		BeforeEach(func() {
-			os.Remove("migrate.gorm.db")
-			db, _ = gorm.Open(sqlite.Open("migrate.gorm.db"), &gorm.Config{})
-		})
-		AfterEach(func() {
-			//os.Remove("migrate.gorm.db")
+			db = openTestDB("migrate.gorm.db", &gorm.Config{})
+
+			var err error
+			blobs, err = blobstore.NewLocal(GinkgoT().TempDir())
+			Expect(err).To(Succeed())
 		})
 		It("should migrate", func() {
-			Expect(Migrate(db)).To(Succeed())
+			Expect(Migrate(db, blobs)).To(Succeed())
 		})
 		It("should migrate data", func() {
-			m := migrator(db)
+			m := migrator(db, blobs)
 
 			Expect(m.MigrateTo("202109010000")).To(Succeed())
 
@@ -101,15 +106,32 @@ var _ = Describe("filestore", func() {
 			Expect(db.Preload("File").Preload("Metadata").First(&file).Error).To(Succeed())
 			Expect(file.File.Checksum).ToNot(BeEmpty())
 			Expect(file.File.Size).ToNot(BeZero())
-			Expect(file.File.Content).To(HavePrefix("foo"))
+			Expect(file.File.BlobKey).ToNot(BeEmpty())
+
+			rc, err := blobs.Get(context.Background(), file.File.BlobKey)
+			Expect(err).To(Succeed())
+			content, err := io.ReadAll(rc)
+			Expect(err).To(Succeed())
+			Expect(rc.Close()).To(Succeed())
+			Expect(string(content)).To(HavePrefix("foo"))
+			Expect(fmt.Sprintf("%x", sha256.Sum256(content))).To(Equal(file.File.Checksum))
 			Expect(file.Metadata).To(HaveLen(2))
 			Expect(file.Metadata[0].Key).To(Equal("baz"))
 			Expect(file.Metadata[0].Value).To(Equal("bar"))
 
+			version := modelsv2.StoredFileVersion{}
+			Expect(file.Version).To(Equal(uint64(1)))
+			Expect(db.Where("file_id = ?", file.ID).First(&version).Error).To(Succeed())
+			Expect(version.Version).To(Equal(uint64(1)))
+			Expect(version.BlobKey).To(Equal(file.File.BlobKey))
+			Expect(version.Checksum).To(Equal(file.File.Checksum))
+			Expect(version.Metadata).To(MatchJSON(`{"baz":"bar","baz2":"bar2"}`))
+
 			Expect(db.Migrator().HasTable("files")).To(BeFalse())
 			Expect(db.Migrator().HasTable("metadata")).To(BeFalse())
 			Expect(db.Migrator().HasTable("file_metadata")).To(BeFalse())
 			Expect(db.Migrator().HasTable("file_contents")).To(BeFalse())
+			Expect(db.Migrator().HasColumn(&modelsv2.StoredFileContent{}, "content")).To(BeFalse())
 		})
 	})
 })
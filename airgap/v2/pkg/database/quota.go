@@ -0,0 +1,406 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrQuotaExceeded is matched by a *QuotaExceededError when a file would
+// exceed a storage quota.
+const ErrQuotaExceeded = errors.Sentinel("quota exceeded")
+
+// QuotaLimits limits the bytes stored and the number of files, 0 is unlimited
+type QuotaLimits struct {
+	MaxBytes int64
+	MaxFiles int64
+}
+
+// Quotas limit the storage used by all the files, Global, and the files of
+// each source. Sources lists the limits of a source, DefaultSource applies to
+// the sources not listed.
+type Quotas struct {
+	Global        QuotaLimits
+	DefaultSource QuotaLimits
+	Sources       map[string]QuotaLimits
+}
+
+// SourceLimits returns the limits of the files of source
+func (q *Quotas) SourceLimits(source string) QuotaLimits {
+	if limits, ok := q.Sources[source]; ok {
+		return limits
+	}
+	return q.DefaultSource
+}
+
+func (q *Quotas) enabled() bool {
+	if q.Global != (QuotaLimits{}) || q.DefaultSource != (QuotaLimits{}) {
+		return true
+	}
+
+	for _, limits := range q.Sources {
+		if limits != (QuotaLimits{}) {
+			return true
+		}
+	}
+	return false
+}
+
+// Usage is the storage used by files. Bytes counts the content of every
+// version kept, including deleted files until their tombstones are cleaned.
+// Files counts the files that are not deleted.
+type Usage struct {
+	Bytes int64
+	Files int64
+}
+
+// StorageUsage is the storage used by all the files and by each source, with
+// the quotas that apply.
+type StorageUsage struct {
+	Usage
+	Limits QuotaLimits
+
+	Sources map[string]*SourceUsage
+}
+
+type SourceUsage struct {
+	Usage
+	Limits QuotaLimits
+}
+
+// QuotaExceededError describes the quota a file would exceed
+type QuotaExceededError struct {
+	// Source is empty when the global quota is exceeded
+	Source string
+	// Resource is bytes or files
+	Resource  string
+	Limit     int64
+	Used      int64
+	Requested int64
+}
+
+func (e *QuotaExceededError) Error() string {
+	scope := "global"
+	if e.Source != "" {
+		scope = fmt.Sprintf("source %s", e.Source)
+	}
+
+	return fmt.Sprintf("%s: %s quota of %d %s, %d used and %d requested",
+		ErrQuotaExceeded, scope, e.Limit, e.Resource, e.Used, e.Requested)
+}
+
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// Usage returns the storage used by all the files and by each source
+func (d *fileStore) Usage(ctx context.Context) (*StorageUsage, error) {
+	return d.usage(d.WithContext(ctx), "")
+}
+
+// CheckQuota returns a *QuotaExceededError if storing bytes more for source
+// would exceed a quota, it lets clients stop before sending the content.
+func (d *fileStore) CheckQuota(ctx context.Context, source string, bytes int64) error {
+	if !d.config.Quotas.enabled() {
+		return nil
+	}
+
+	usage, err := d.usage(d.WithContext(ctx), source)
+	if err != nil {
+		return err
+	}
+
+	return usage.check(source, Usage{Bytes: bytes})
+}
+
+// The usage counters are changed in the transactions changing the files. A
+// file is counted until it is deleted. The size of a blob is counted once
+// in the global counter while it is stored, and once in the counter of
+// each source with files referencing it, deleted files included.
+
+// reserveUsage counts a file saved to source, files is the number of files
+// it adds, newBytes the size of the blob stored for it when no blob held its
+// content yet, and key the blob of its new content. The blob is only
+// charged to source when none of its files references it yet, the content
+// and versions of the file must not reference it yet either. It returns a
+// *QuotaExceededError when a quota would be exceeded.
+func (d *fileStore) reserveUsage(tx *gorm.DB, source string, files, newBytes int64, key string) error {
+	sourceBytes, err := unreferencedBytes(tx, source, key)
+	if err != nil {
+		return err
+	}
+
+	quotas := &d.config.Quotas
+
+	// the global counter first, saves lock the counters in the same order
+	if err := addUsage(tx, globalUsage(), quotas.Global, Usage{Bytes: newBytes, Files: files}); err != nil {
+		return err
+	}
+
+	return addUsage(tx, sourceUsage(source), quotas.SourceLimits(source), Usage{Bytes: sourceBytes, Files: files})
+}
+
+// releaseUsage takes the files removed from source off the counters, and the
+// size of the blobs of keys its files no longer reference. It is called once
+// the references are removed and before the blobs are released, releaseBlobs
+// takes the blobs deleted off the global counter.
+func releaseUsage(tx *gorm.DB, source string, files int64, keys ...string) error {
+	sourceBytes, err := unreferencedBytes(tx, source, keys...)
+	if err != nil {
+		return err
+	}
+
+	if err := addUsage(tx, globalUsage(), QuotaLimits{}, Usage{Files: -files}); err != nil {
+		return err
+	}
+
+	return addUsage(tx, sourceUsage(source), QuotaLimits{}, Usage{Bytes: -sourceBytes, Files: -files})
+}
+
+func globalUsage() *modelsv2.StorageUsage {
+	return &modelsv2.StorageUsage{IsGlobal: true}
+}
+
+func sourceUsage(source string) *modelsv2.StorageUsage {
+	return &modelsv2.StorageUsage{Source: source}
+}
+
+// addUsage adds delta to the counter if it stays within limits. The
+// conditional update locks the counter until the transaction ends, saves
+// racing on this node or another wait for the lock and see the usage of
+// each other.
+func addUsage(tx *gorm.DB, counter *modelsv2.StorageUsage, limits QuotaLimits, delta Usage) error {
+	if delta == (Usage{}) {
+		return nil
+	}
+
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(counter).Error
+	if err != nil {
+		return errors.WrapIf(err, "failed to create usage counter")
+	}
+
+	q := tx.Model(&modelsv2.StorageUsage{}).
+		Where("is_global = ? AND source = ?", counter.IsGlobal, counter.Source)
+	if limits.MaxBytes > 0 && delta.Bytes > 0 {
+		q = q.Where("bytes + ? <= ?", delta.Bytes, limits.MaxBytes)
+	}
+	if limits.MaxFiles > 0 && delta.Files > 0 {
+		q = q.Where("files + ? <= ?", delta.Files, limits.MaxFiles)
+	}
+
+	result := q.UpdateColumns(map[string]interface{}{
+		"bytes": gorm.Expr("bytes + ?", delta.Bytes),
+		"files": gorm.Expr("files + ?", delta.Files),
+	})
+	if result.Error != nil {
+		return errors.WrapIf(result.Error, "failed to update usage counter")
+	}
+
+	if result.RowsAffected == 1 {
+		return nil
+	}
+
+	err = tx.Where("is_global = ? AND source = ?", counter.IsGlobal, counter.Source).First(counter).Error
+	if err != nil {
+		return errors.WrapIf(err, "failed to read usage counter")
+	}
+
+	return checkLimits(counter.Source, limits, Usage{Bytes: counter.Bytes, Files: counter.Files}, delta)
+}
+
+// unreferencedBytes sums the size of the blobs of keys that no content or
+// version of the files of source references
+func unreferencedBytes(tx *gorm.DB, source string, keys ...string) (int64, error) {
+	keys = uniqueKeys(append([]string(nil), keys...))
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	var bytes int64
+	err := tx.Raw(`SELECT COALESCE(SUM(size), 0) FROM stored_blobs
+WHERE blob_key IN ?
+AND NOT EXISTS (SELECT 1 FROM stored_file_contents JOIN stored_files ON stored_files.id = stored_file_contents.file_id
+	WHERE stored_files.source = ? AND stored_file_contents.blob_key = stored_blobs.blob_key)
+AND NOT EXISTS (SELECT 1 FROM stored_file_versions JOIN stored_files ON stored_files.id = stored_file_versions.file_id
+	WHERE stored_files.source = ? AND stored_file_versions.blob_key = stored_blobs.blob_key)`,
+		keys, source, source).Scan(&bytes).Error
+	return bytes, errors.WrapIf(err, "failed to sum unreferenced blob sizes")
+}
+
+func (u *StorageUsage) check(source string, requested Usage) error {
+	sourceUsage := u.Sources[source]
+	if sourceUsage == nil {
+		sourceUsage = &SourceUsage{}
+	}
+
+	if err := checkLimits("", u.Limits, u.Usage, requested); err != nil {
+		return err
+	}
+
+	return checkLimits(source, sourceUsage.Limits, sourceUsage.Usage, requested)
+}
+
+func checkLimits(source string, limits QuotaLimits, used, requested Usage) error {
+	if limits.MaxBytes > 0 && requested.Bytes > 0 && used.Bytes+requested.Bytes > limits.MaxBytes {
+		return &QuotaExceededError{Source: source, Resource: "bytes",
+			Limit: limits.MaxBytes, Used: used.Bytes, Requested: requested.Bytes}
+	}
+
+	if limits.MaxFiles > 0 && requested.Files > 0 && used.Files+requested.Files > limits.MaxFiles {
+		return &QuotaExceededError{Source: source, Resource: "files",
+			Limit: limits.MaxFiles, Used: used.Files, Requested: requested.Files}
+	}
+
+	return nil
+}
+
+// usage reads the usage counters. With source set the usage of the other
+// sources is not reported.
+func (d *fileStore) usage(db *gorm.DB, source string) (*StorageUsage, error) {
+	quotas := &d.config.Quotas
+	usage := &StorageUsage{
+		Limits:  quotas.Global,
+		Sources: map[string]*SourceUsage{},
+	}
+
+	sourceUsage := func(name string) *SourceUsage {
+		u, ok := usage.Sources[name]
+		if !ok {
+			u = &SourceUsage{Limits: quotas.SourceLimits(name)}
+			usage.Sources[name] = u
+		}
+		return u
+	}
+
+	for name := range quotas.Sources {
+		if source == "" || name == source {
+			sourceUsage(name)
+		}
+	}
+
+	q := db.Model(&modelsv2.StorageUsage{})
+	if source != "" {
+		q = q.Where("is_global = ? OR source = ?", true, source)
+	}
+
+	var counters []modelsv2.StorageUsage
+	if err := q.Find(&counters).Error; err != nil {
+		return nil, errors.WrapIf(err, "failed to read usage counters")
+	}
+
+	for _, counter := range counters {
+		used := Usage{Bytes: counter.Bytes, Files: counter.Files}
+		switch {
+		case counter.IsGlobal:
+			usage.Usage = used
+		case used != (Usage{}):
+			sourceUsage(counter.Source).Usage = used
+		}
+	}
+
+	if source != "" {
+		sourceUsage(source)
+	}
+
+	return usage, nil
+}
+
+// recountUsage sets the usage counters from the stored files, after the
+// files were changed without updating them. A blob shared by the content of
+// a file and its versions is only counted once.
+func recountUsage(tx *gorm.DB) error {
+	var files []struct {
+		Source string
+		Files  int64
+	}
+	err := tx.Model(&modelsv2.StoredFile{}).
+		Select("source, COUNT(*) AS files").
+		Group("source").
+		Scan(&files).Error
+	if err != nil {
+		return errors.WrapIf(err, "failed to count files")
+	}
+
+	blobs := tx.Raw(`SELECT stored_files.source, stored_file_contents.blob_key, stored_file_contents.size
+FROM stored_file_contents JOIN stored_files ON stored_files.id = stored_file_contents.file_id
+UNION
+SELECT stored_files.source, stored_file_versions.blob_key, stored_file_versions.size
+FROM stored_file_versions JOIN stored_files ON stored_files.id = stored_file_versions.file_id`)
+
+	var sizes []struct {
+		Source string
+		Bytes  int64
+	}
+	err = tx.Table("(?) AS blobs", blobs).
+		Select("source, COALESCE(SUM(size), 0) AS bytes").
+		Group("source").
+		Scan(&sizes).Error
+	if err != nil {
+		return errors.WrapIf(err, "failed to sum file sizes")
+	}
+
+	global := globalUsage()
+
+	// sources storing the same content share its blob, which is only
+	// counted once in the total
+	err = tx.Model(&modelsv2.StoredBlob{}).
+		Select("COALESCE(SUM(size), 0)").
+		Scan(&global.Bytes).Error
+	if err != nil {
+		return errors.WrapIf(err, "failed to sum blob sizes")
+	}
+
+	counters := map[string]*modelsv2.StorageUsage{}
+	counter := func(source string) *modelsv2.StorageUsage {
+		c, ok := counters[source]
+		if !ok {
+			c = sourceUsage(source)
+			counters[source] = c
+		}
+		return c
+	}
+
+	for _, f := range files {
+		global.Files += f.Files
+		counter(f.Source).Files = f.Files
+	}
+
+	for _, s := range sizes {
+		counter(s.Source).Bytes = s.Bytes
+	}
+
+	if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).
+		Delete(&modelsv2.StorageUsage{}).Error; err != nil {
+		return errors.WrapIf(err, "failed to reset usage counters")
+	}
+
+	if err := tx.Create(global).Error; err != nil {
+		return errors.WrapIf(err, "failed to set usage counter")
+	}
+
+	for _, c := range counters {
+		if err := tx.Create(c).Error; err != nil {
+			return errors.WrapIf(err, "failed to set usage counter")
+		}
+	}
+
+	return nil
+}
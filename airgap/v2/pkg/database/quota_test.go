@@ -0,0 +1,251 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"emperror.dev/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("quotas", func() {
+	var (
+		db     *gorm.DB
+		ctx    = context.Background()
+		config FileStoreConfig
+		sut    StoredFileStore
+	)
+
+	BeforeEach(func() {
+		var blobs blobstore.BlobStore
+		db, blobs = openTestStore("quota.gorm.db")
+		config = FileStoreConfig{
+			BlobStore: blobs,
+			Quotas: Quotas{
+				Global:        QuotaLimits{MaxBytes: 100},
+				DefaultSource: QuotaLimits{MaxFiles: 2},
+				Sources: map[string]QuotaLimits{
+					"small": {MaxBytes: 10},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		sut = newTestStore(db, config)
+	})
+
+	saveContent := func(name, source, content string) (string, error) {
+		file := testFile(name, content)
+		file.Source = source
+		return sut.Save(ctx, file)
+	}
+
+	// files with different names have different content
+	save := func(name, source string, size int) (string, error) {
+		return saveContent(name, source, strings.Repeat(name[:1], size))
+	}
+
+	upload := func(name, source string, size int) (string, error) {
+		return sut.Upload(ctx, strings.NewReader(strings.Repeat(name[:1], size)), func(content *modelsv2.StoredFileContent) (*modelsv2.StoredFile, error) {
+			return &modelsv2.StoredFile{Name: name, Source: source, SourceType: "report"}, nil
+		})
+	}
+
+	quotaError := func(err error) *QuotaExceededError {
+		quotaErr := &QuotaExceededError{}
+		Expect(errors.As(err, &quotaErr)).To(BeTrue(), "expected a quota error but got %v", err)
+		return quotaErr
+	}
+
+	// expectUsage checks the usage of source and that the counters match the
+	// usage counted from the files
+	expectUsage := func(global, source Usage, name string) {
+		usage, err := sut.Usage(ctx)
+		Expect(err).To(Succeed())
+		Expect(usage.Usage).To(Equal(global))
+		if source == (Usage{}) {
+			Expect(usage.Sources).NotTo(HaveKey(name))
+		} else {
+			Expect(usage.Sources).To(HaveKeyWithValue(name, &SourceUsage{Usage: source, Limits: QuotaLimits{MaxFiles: 2}}))
+		}
+
+		Expect(recountUsage(db)).To(Succeed())
+		recounted, err := sut.Usage(ctx)
+		Expect(err).To(Succeed())
+		Expect(recounted).To(Equal(usage))
+	}
+
+	It("should report the storage used by each source", func() {
+		_, err := save("a", "one", 10)
+		Expect(err).To(Succeed())
+		_, err = save("a", "one", 20)
+		Expect(err).To(Succeed())
+		_, err = save("b", "two", 5)
+		Expect(err).To(Succeed())
+
+		usage, err := sut.Usage(ctx)
+		Expect(err).To(Succeed())
+		Expect(usage.Usage).To(Equal(Usage{Bytes: 35, Files: 2}))
+		Expect(usage.Limits).To(Equal(QuotaLimits{MaxBytes: 100}))
+		Expect(usage.Sources).To(HaveKeyWithValue("one", &SourceUsage{Usage: Usage{Bytes: 30, Files: 1}, Limits: QuotaLimits{MaxFiles: 2}}))
+		Expect(usage.Sources).To(HaveKeyWithValue("two", &SourceUsage{Usage: Usage{Bytes: 5, Files: 1}, Limits: QuotaLimits{MaxFiles: 2}}))
+		Expect(usage.Sources).To(HaveKeyWithValue("small", &SourceUsage{Limits: QuotaLimits{MaxBytes: 10}}))
+	})
+
+	It("should reject files over the quota of their source", func() {
+		_, err := save("a", "small", 6)
+		Expect(err).To(Succeed())
+
+		_, err = upload("b", "small", 6)
+		Expect(err).To(MatchError(ErrQuotaExceeded))
+		Expect(quotaError(err)).To(Equal(&QuotaExceededError{
+			Source: "small", Resource: "bytes", Limit: 10, Used: 6, Requested: 6,
+		}))
+
+		Expect(sut.CheckQuota(ctx, "small", 4)).To(Succeed())
+		Expect(sut.CheckQuota(ctx, "small", 5)).To(MatchError(ErrQuotaExceeded))
+
+		_, err = save("a", "other", 1)
+		Expect(err).To(Succeed())
+		_, err = save("b", "other", 1)
+		Expect(err).To(Succeed())
+		_, err = save("c", "other", 1)
+		Expect(quotaError(err)).To(Equal(&QuotaExceededError{
+			Source: "other", Resource: "files", Limit: 2, Used: 2, Requested: 1,
+		}))
+
+		By("replacing a file of a source at its file quota")
+		_, err = save("b", "other", 2)
+		Expect(err).To(Succeed())
+	})
+
+	It("should reject files over the global quota", func() {
+		_, err := save("a", "one", 60)
+		Expect(err).To(Succeed())
+
+		_, err = save("b", "two", 50)
+		Expect(quotaError(err)).To(Equal(&QuotaExceededError{
+			Resource: "bytes", Limit: 100, Used: 60, Requested: 50,
+		}))
+
+		files, _, err := sut.List(ctx)
+		Expect(err).To(Succeed())
+		Expect(files).To(HaveLen(1))
+	})
+
+	It("should only charge the blobs new to a source", func() {
+		_, err := saveContent("a", "one", "0123456789")
+		Expect(err).To(Succeed())
+		expectUsage(Usage{Bytes: 10, Files: 1}, Usage{Bytes: 10, Files: 1}, "one")
+
+		_, err = saveContent("b", "one", "0123456789")
+		Expect(err).To(Succeed())
+		expectUsage(Usage{Bytes: 10, Files: 2}, Usage{Bytes: 10, Files: 2}, "one")
+
+		By("storing the content for another source")
+		_, err = saveContent("a", "two", "0123456789")
+		Expect(err).To(Succeed())
+		expectUsage(Usage{Bytes: 10, Files: 3}, Usage{Bytes: 10, Files: 1}, "two")
+
+		By("saving the same content again")
+		_, err = saveContent("a", "two", "0123456789")
+		Expect(err).To(Succeed())
+		expectUsage(Usage{Bytes: 10, Files: 3}, Usage{Bytes: 10, Files: 1}, "two")
+
+		By("accepting content stored already within the quota of the source")
+		_, err = saveContent("a", "small", "0123456789")
+		Expect(err).To(Succeed())
+		_, err = saveContent("b", "small", "0123456789")
+		Expect(err).To(Succeed())
+	})
+
+	It("should release the storage of deleted files", func() {
+		id1, err := save("a", "one", 10)
+		Expect(err).To(Succeed())
+		id2, err := save("b", "one", 5)
+		Expect(err).To(Succeed())
+		expectUsage(Usage{Bytes: 15, Files: 2}, Usage{Bytes: 15, Files: 2}, "one")
+
+		By("keeping the content of a deleted file until it is removed")
+		Expect(sut.Delete(ctx, id1, false)).To(Succeed())
+		expectUsage(Usage{Bytes: 15, Files: 1}, Usage{Bytes: 15, Files: 1}, "one")
+
+		Expect(sut.Delete(ctx, id2, true)).To(Succeed())
+		expectUsage(Usage{Bytes: 10}, Usage{Bytes: 10}, "one")
+
+		_, err = sut.CleanTombstones(ctx)
+		Expect(err).To(Succeed())
+		expectUsage(Usage{}, Usage{}, "one")
+
+		By("restoring the file quota of the source")
+		_, err = save("c", "one", 1)
+		Expect(err).To(Succeed())
+		_, err = save("d", "one", 1)
+		Expect(err).To(Succeed())
+	})
+
+	It("should keep quotas with saves from stores sharing the database", func() {
+		other := newTestStore(db, config)
+
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			saved    int
+			exceeded int
+		)
+		for i := 0; i < 8; i++ {
+			store := sut
+			if i%2 == 0 {
+				store = other
+			}
+
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				file := testFile(fmt.Sprintf("file-%d", i), fmt.Sprintf("content-%d", i))
+				file.Source = "shared"
+				_, err := store.Save(ctx, file)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if errors.Is(err, ErrQuotaExceeded) {
+					exceeded++
+					return
+				}
+				Expect(err).To(Succeed())
+				saved++
+			}(i)
+		}
+		wg.Wait()
+
+		Expect(saved).To(Equal(2))
+		Expect(exceeded).To(Equal(6))
+
+		counter := modelsv2.StorageUsage{}
+		Expect(db.Where("source = ?", "shared").First(&counter).Error).To(Succeed())
+		Expect(counter.Files).To(Equal(int64(2)))
+	})
+})
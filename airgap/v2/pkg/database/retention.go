@@ -0,0 +1,232 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidRetentionRule is returned when a retention rule has an invalid
+// filter or age.
+const ErrInvalidRetentionRule = errors.Sentinel("invalid retention rule")
+
+// RetentionRule selects files by source, source type and filter, all optional,
+// and sets how long they are kept after they were last saved. Rules are
+// evaluated in order and the first rule a file matches applies, a rule
+// without MaxAge keeps the files it matches forever. Files matching no rule
+// are kept.
+type RetentionRule struct {
+	Name       string `json:"name" mapstructure:"name"`
+	Source     string `json:"source" mapstructure:"source"`
+	SourceType string `json:"sourceType" mapstructure:"sourceType"`
+	// Filter uses the syntax of the ListFiles filter, such as metadata.acknowledged == "false"
+	Filter string        `json:"filter" mapstructure:"filter"`
+	MaxAge time.Duration `json:"maxAge" mapstructure:"maxAge"`
+}
+
+// ExpiredFile is a file removed by a retention rule
+type ExpiredFile struct {
+	File modelsv2.StoredFile
+	Rule string
+}
+
+// ValidateRetentionRules checks the filter and age of each rule
+func ValidateRetentionRules(rules []RetentionRule) error {
+	for i := range rules {
+		if _, err := rules[i].expr(); err != nil {
+			return errors.WithDetails(err, "rule", rules[i].name(i))
+		}
+	}
+	return nil
+}
+
+func (r *RetentionRule) name(i int) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("rule-%d", i+1)
+}
+
+// expr returns the expression matching the files of the rule, nil if the
+// rule matches every file
+func (r *RetentionRule) expr() (fileserver.FilterExpr, error) {
+	if r.MaxAge < 0 {
+		return nil, errors.Wrapf(ErrInvalidRetentionRule, "max age %s is negative", r.MaxAge)
+	}
+
+	var expr fileserver.FilterExpr
+	if r.Source != "" {
+		expr = &fileserver.FilterCondition{Field: "source", Operator: fileserver.FilterEqual, Values: []string{r.Source}}
+	}
+
+	if r.SourceType != "" {
+		expr = and(expr, &fileserver.FilterCondition{Field: "sourceType", Operator: fileserver.FilterEqual, Values: []string{r.SourceType}})
+	}
+
+	if r.Filter != "" {
+		filter, err := fileserver.ParseFilter(r.Filter)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidRetentionRule, "invalid filter: %s", err)
+		}
+		expr = and(expr, filter)
+	}
+
+	return expr, nil
+}
+
+func and(left, right fileserver.FilterExpr) fileserver.FilterExpr {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	}
+	return &fileserver.FilterLogical{Operator: fileserver.FilterAnd, Left: left, Right: right}
+}
+
+// ApplyRetention deletes the files that have outlived their retention rule,
+// they are kept as tombstones until CleanTombstones purges them. With dryRun
+// the files are only listed.
+func (d *fileStore) ApplyRetention(ctx context.Context, dryRun bool) ([]ExpiredFile, error) {
+	now := time.Now()
+	db := d.WithContext(ctx)
+
+	var (
+		expired []ExpiredFile
+		// previous excludes the files matched by earlier rules
+		previous fileserver.FilterExpr
+	)
+
+	for i := range d.config.RetentionRules {
+		rule := &d.config.RetentionRules[i]
+		name := rule.name(i)
+
+		match, err := rule.expr()
+		if err != nil {
+			return nil, errors.WithDetails(err, "rule", name)
+		}
+
+		expr := match
+		if previous != nil {
+			expr = and(expr, &fileserver.FilterNot{Expr: previous})
+		}
+
+		switch {
+		case match == nil:
+			// every remaining file matches, later rules never apply
+			previous = &fileserver.FilterCondition{Field: "id", Operator: fileserver.FilterExists}
+		case previous == nil:
+			previous = match
+		default:
+			previous = &fileserver.FilterLogical{Operator: fileserver.FilterOr, Left: previous, Right: match}
+		}
+
+		if rule.MaxAge == 0 {
+			continue
+		}
+
+		expr = and(expr, &fileserver.FilterCondition{
+			Field:    "updatedAt",
+			Operator: fileserver.FilterLessThan,
+			Values:   []string{now.Add(-rule.MaxAge).UTC().Format(time.RFC3339Nano)},
+		})
+
+		opts := &ListOptions{Filter: expr}
+		if err := checkFilter(db, opts); err != nil {
+			return nil, errors.WithDetails(errors.Wrapf(ErrInvalidRetentionRule, "%s", err), "rule", name)
+		}
+
+		idQuery := db.Model(&modelsv2.StoredFile{}).
+			Scopes(filter{}.ToScope(opts)).
+			Distinct("stored_files.id")
+
+		files := []modelsv2.StoredFile{}
+		err = db.Where("stored_files.id in (?)", idQuery).
+			Preload("Metadata").
+			Preload("File").
+			Order("stored_files.id").
+			Find(&files).Error
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to find expired files", "rule", name)
+		}
+
+		for _, file := range files {
+			expired = append(expired, ExpiredFile{File: file, Rule: name})
+		}
+	}
+
+	if dryRun {
+		return expired, nil
+	}
+
+	// the audit event of ctx is recorded with the last file deleted
+	filesDeleted := map[string]string{"filesDeleted": strconv.Itoa(len(expired))}
+
+	if len(expired) == 0 {
+		return expired, db.Transaction(func(tx *gorm.DB) error {
+			return auditOperation(ctx, tx, filesDeleted)
+		})
+	}
+
+	for i := range expired {
+		file := &expired[i].File
+		details, _ := json.Marshal(map[string]string{"rule": expired[i].Rule})
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			result := tx.Delete(&modelsv2.StoredFile{}, file.ID)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if err := releaseUsage(tx, file.Source, result.RowsAffected); err != nil {
+				return err
+			}
+
+			if err := recordFileEvent(tx, modelsv2.FileEventDeleted, file); err != nil {
+				return err
+			}
+
+			err := appendAuditEvent(tx, &modelsv2.AuditEvent{
+				Method:         "ApplyRetention",
+				FileID:         file.ID,
+				Name:           file.Name,
+				Source:         file.Source,
+				SourceType:     file.SourceType,
+				ChecksumBefore: file.File.Checksum,
+				Details:        string(details),
+			})
+			if err != nil || i < len(expired)-1 {
+				return err
+			}
+
+			return auditOperation(ctx, tx, filesDeleted)
+		})
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to delete expired file", "id", file.ID)
+		}
+		d.changes.notify()
+	}
+
+	return expired, nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("retention", func() {
+	var (
+		db     *gorm.DB
+		ctx    = context.Background()
+		config FileStoreConfig
+		sut    StoredFileStore
+	)
+
+	const day = 24 * time.Hour
+
+	BeforeEach(func() {
+		var blobs blobstore.BlobStore
+		db, blobs = openTestStore("retention.gorm.db")
+		config = FileStoreConfig{
+			BlobStore: blobs,
+			RetentionRules: []RetentionRule{
+				{Name: "unacknowledged", Filter: `metadata.acknowledged == "false"`},
+				{Name: "reports", SourceType: "report", MaxAge: 400 * day},
+				{Name: "data-reporter", Source: "dataReporter", MaxAge: 90 * day},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		sut = newTestStore(db, config)
+	})
+
+	// save stores a file last saved age ago
+	save := func(name, source, sourceType string, age time.Duration, metadata ...modelsv2.StoredFileMetadata) uint {
+		file := testFile(name, name)
+		file.Source = source
+		file.SourceType = sourceType
+		file.Metadata = metadata
+		id := saveTestFile(ctx, sut, file)
+
+		fileID, err := modelsv2.ConvertStrToUint(id)
+		Expect(err).To(Succeed())
+		Expect(db.Model(&modelsv2.StoredFile{}).
+			Where("id = ?", fileID).
+			UpdateColumn("updated_at", time.Now().Add(-age)).Error).To(Succeed())
+		return fileID
+	}
+
+	expiredIDs := func(expired []ExpiredFile) map[uint]string {
+		ids := map[uint]string{}
+		for _, file := range expired {
+			ids[file.File.ID] = file.Rule
+		}
+		return ids
+	}
+
+	It("should delete the files past the age of the first rule they match", func() {
+		oldReport := save("old-report", "dataReporter", "report", 401*day)
+		report := save("report", "dataReporter", "report", 100*day)
+		upload := save("upload", "dataReporter", "upload", 91*day)
+		recent := save("recent", "dataReporter", "upload", 10*day)
+		unacknowledged := save("unacknowledged", "dataReporter", "upload", 500*day,
+			modelsv2.StoredFileMetadata{Key: "acknowledged", Value: "false"})
+		acknowledged := save("acknowledged", "dataReporter", "upload", 500*day,
+			modelsv2.StoredFileMetadata{Key: "acknowledged", Value: "true"})
+		other := save("other", "other", "upload", 500*day)
+
+		expired, err := sut.ApplyRetention(ctx, true)
+		Expect(err).To(Succeed())
+		Expect(expiredIDs(expired)).To(Equal(map[uint]string{
+			oldReport:    "reports",
+			upload:       "data-reporter",
+			acknowledged: "data-reporter",
+		}))
+
+		By("keeping the files on a dry run")
+		for _, id := range []uint{oldReport, upload, acknowledged} {
+			_, err := sut.Get(ctx, fmt.Sprint(id))
+			Expect(err).To(Succeed())
+		}
+
+		expired, err = sut.ApplyRetention(ctx, false)
+		Expect(err).To(Succeed())
+		Expect(expired).To(HaveLen(3))
+
+		for _, id := range []uint{oldReport, upload, acknowledged} {
+			file := &modelsv2.StoredFile{}
+			Expect(db.Unscoped().First(file, id).Error).To(Succeed())
+			Expect(file.DeletedAt.Valid).To(BeTrue(), "expired files are kept as tombstones")
+		}
+
+		for _, id := range []uint{report, recent, unacknowledged, other} {
+			_, err := sut.Get(ctx, fmt.Sprint(id))
+			Expect(err).To(Succeed())
+		}
+
+		events, _, err := sut.ListAuditEvents(ctx)
+		Expect(err).To(Succeed())
+		Expect(events).To(HaveLen(3))
+		Expect(events[0].Method).To(Equal("ApplyRetention"))
+
+		expired, err = sut.ApplyRetention(ctx, false)
+		Expect(err).To(Succeed())
+		Expect(expired).To(BeEmpty())
+	})
+
+	Context("with a rule without selectors", func() {
+		BeforeEach(func() {
+			config.RetentionRules = append(config.RetentionRules, RetentionRule{MaxAge: 30 * day})
+		})
+
+		It("should apply it to every remaining file", func() {
+			report := save("report", "dataReporter", "report", 100*day)
+			other := save("other", "other", "upload", 31*day)
+
+			expired, err := sut.ApplyRetention(ctx, true)
+			Expect(err).To(Succeed())
+			Expect(expiredIDs(expired)).To(Equal(map[uint]string{other: "rule-4"}))
+			Expect(expiredIDs(expired)).ToNot(HaveKey(report))
+		})
+	})
+
+	DescribeTable("should reject invalid rules",
+		func(rule RetentionRule) {
+			err := ValidateRetentionRules([]RetentionRule{rule})
+			Expect(err).To(MatchError(ErrInvalidRetentionRule))
+		},
+		Entry("bad filter", RetentionRule{Filter: "name =="}),
+		Entry("negative age", RetentionRule{SourceType: "report", MaxAge: -day}),
+	)
+})
@@ -37,11 +37,7 @@ func (p paginator) ToScope(opts *ListOptions) func(db *gorm.DB) *gorm.DB {
 	}
 
 	return func(db *gorm.DB) *gorm.DB {
-		page, pageSize := opts.Pagination.Page, opts.Pagination.PageSize
-
-		if page == 0 {
-			page = 1
-		}
+		pageSize := opts.Pagination.PageSize
 
 		switch {
 		case pageSize > 100:
@@ -50,8 +46,7 @@ func (p paginator) ToScope(opts *ListOptions) func(db *gorm.DB) *gorm.DB {
 			pageSize = 10
 		}
 
-		offset := (page - 1) * pageSize
-		return db.Offset(offset).Limit(pageSize + 1)
+		return db.Limit(pageSize + 1)
 	}
 }
 
@@ -0,0 +1,102 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const tracerName = "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+
+// spanKey keeps the span of a query in the statement
+const spanKey = "airgap:span"
+
+// Tracing is a gorm plugin recording a span for each query, as a child of the
+// span in the context of the query. Spans go to the global tracer provider.
+type Tracing struct{}
+
+func (Tracing) Name() string {
+	return "airgap:tracing"
+}
+
+func (Tracing) Initialize(db *gorm.DB) error {
+	tracer := otel.Tracer(tracerName)
+	system := db.Dialector.Name()
+
+	before := func(operation string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			ctx := tx.Statement.Context
+			if ctx == nil || !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+				// queries outside of a traced call start no trace
+				return
+			}
+
+			ctx, span := tracer.Start(ctx, "gorm."+operation,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(semconv.DBSystemKey.String(system), attribute.String("db.operation", operation)))
+			tx.Statement.Context = ctx
+			tx.InstanceSet(spanKey, span)
+		}
+	}
+
+	after := func(tx *gorm.DB) {
+		value, ok := tx.InstanceGet(spanKey)
+		if !ok {
+			return
+		}
+		span := value.(trace.Span)
+		defer span.End()
+
+		span.SetAttributes(
+			semconv.DBStatement(tx.Statement.SQL.String()),
+			attribute.Int64("db.rows_affected", tx.RowsAffected),
+		)
+		if table := tx.Statement.Table; table != "" {
+			span.SetAttributes(semconv.DBSQLTable(table))
+		}
+
+		if err := tx.Error; err != nil && err != gorm.ErrRecordNotFound {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("gorm:create").Register("airgap:trace_before_create", before("create")),
+		callbacks.Create().After("gorm:create").Register("airgap:trace_after_create", after),
+		callbacks.Query().Before("gorm:query").Register("airgap:trace_before_query", before("query")),
+		callbacks.Query().After("gorm:query").Register("airgap:trace_after_query", after),
+		callbacks.Update().Before("gorm:update").Register("airgap:trace_before_update", before("update")),
+		callbacks.Update().After("gorm:update").Register("airgap:trace_after_update", after),
+		callbacks.Delete().Before("gorm:delete").Register("airgap:trace_before_delete", before("delete")),
+		callbacks.Delete().After("gorm:delete").Register("airgap:trace_after_delete", after),
+		callbacks.Row().Before("gorm:row").Register("airgap:trace_before_row", before("row")),
+		callbacks.Row().After("gorm:row").Register("airgap:trace_after_row", after),
+		callbacks.Raw().Before("gorm:raw").Register("airgap:trace_before_raw", before("raw")),
+		callbacks.Raw().After("gorm:raw").Register("airgap:trace_after_raw", after),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
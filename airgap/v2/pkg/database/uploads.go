@@ -0,0 +1,339 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	ErrUploadOffset     = errors.Sentinel("chunk offset is past the committed offset")
+	ErrUploadConflict   = errors.Sentinel("upload session was changed by another request")
+	ErrUploadFinished   = errors.Sentinel("upload session is finished")
+	ErrUploadIncomplete = errors.Sentinel("upload session is missing content")
+)
+
+// StartUpload creates a resumable upload session for the file described by session.
+func (d *fileStore) StartUpload(ctx context.Context, session *modelsv2.UploadSession) (string, error) {
+	if session == nil {
+		return "", errors.Wrap(ErrInvalidInput, "session is nil")
+	}
+
+	session.ID = uuid.NewString()
+	session.CommittedOffset = 0
+	session.FileID = 0
+	session.Parts = nil
+
+	if err := d.WithContext(ctx).Create(session).Error; err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return session.ID, nil
+}
+
+// GetUpload returns the session with its parts in offset order.
+func (d *fileStore) GetUpload(ctx context.Context, id string) (*modelsv2.UploadSession, error) {
+	session := &modelsv2.UploadSession{}
+	err := d.WithContext(ctx).
+		Preload("Parts", func(db *gorm.DB) *gorm.DB {
+			return db.Order("start")
+		}).
+		First(session, "id = ?", id).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithDetails(ErrNotFound, "session", id)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	return session, nil
+}
+
+// PutUploadChunk stores the content of r at offset and returns the new
+// committed offset. The offset may be before the committed offset, when a
+// chunk is sent again after its response was lost the content already
+// committed is skipped.
+func (d *fileStore) PutUploadChunk(ctx context.Context, id string, offset int64, r io.Reader) (int64, error) {
+	session := &modelsv2.UploadSession{}
+	err := d.WithContext(ctx).First(session, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errors.WithDetails(ErrNotFound, "session", id)
+		}
+		return 0, errors.WithStack(err)
+	}
+
+	if session.FileID != 0 {
+		return 0, errors.WithDetails(ErrUploadFinished, "session", id)
+	}
+
+	committed := session.CommittedOffset
+	if offset < 0 || offset > committed {
+		return 0, errors.WithDetails(ErrUploadOffset, "session", id, "offset", offset, "committed", committed)
+	}
+
+	if skip := committed - offset; skip > 0 {
+		_, err := io.CopyN(io.Discard, r, skip)
+		if err == io.EOF {
+			return committed, nil
+		}
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+	}
+
+	// read one more byte than allowed to detect a chunk past the declared size
+	if session.TotalSize > 0 {
+		r = io.LimitReader(r, session.TotalSize-committed+1)
+	}
+
+	part := &modelsv2.UploadSessionPart{
+		SessionID: id,
+		Start:     committed,
+		BlobKey:   blobstore.NewKey(),
+	}
+
+	part.Size, err = d.config.BlobStore.Put(ctx, part.BlobKey, r)
+	if err != nil {
+		d.deleteBlobs(part.BlobKey)
+		return 0, errors.WrapIf(err, "failed to store upload chunk")
+	}
+
+	if part.Size == 0 {
+		d.deleteBlobs(part.BlobKey)
+		return committed, nil
+	}
+
+	if session.TotalSize > 0 && committed+part.Size > session.TotalSize {
+		d.deleteBlobs(part.BlobKey)
+		return 0, errors.WrapWithDetails(ErrInvalidInput, "chunk exceeds the upload size",
+			"session", id, "size", session.TotalSize)
+	}
+
+	err = d.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// only one chunk can be committed at an offset
+		result := tx.Model(&modelsv2.UploadSession{}).
+			Where("id = ? AND committed_offset = ? AND file_id = 0", id, committed).
+			Update("committed_offset", committed+part.Size)
+		if result.Error != nil {
+			return errors.WithStack(result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.WithDetails(ErrUploadConflict, "session", id, "offset", committed)
+		}
+
+		return errors.WithStack(tx.Create(part).Error)
+	})
+
+	if err != nil {
+		d.deleteBlobs(part.BlobKey)
+		return 0, err
+	}
+
+	return committed + part.Size, nil
+}
+
+// OpenUpload returns the session and a reader over the content committed so
+// far, callers must close the reader.
+func (d *fileStore) OpenUpload(ctx context.Context, id string) (*modelsv2.UploadSession, io.ReadCloser, error) {
+	session, err := d.GetUpload(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var next int64
+	for _, part := range session.Parts {
+		if part.Start != next {
+			return nil, nil, errors.WithDetails(ErrUploadIncomplete, "session", id, "offset", next)
+		}
+		next += part.Size
+	}
+
+	return session, &partsReader{ctx: ctx, blobs: d.config.BlobStore, parts: session.Parts}, nil
+}
+
+// FinishUpload saves the file with the content read from r, the reader
+// returned by OpenUpload. The session is marked finished in the transaction
+// saving the file and its parts are deleted. Finishing a finished session
+// returns the id of the saved file.
+func (d *fileStore) FinishUpload(ctx context.Context, id string, r io.Reader, prepare PrepareUpload) (string, error) {
+	session, err := d.GetUpload(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	if session.FileID != 0 {
+		return fmt.Sprintf("%d", session.FileID), nil
+	}
+
+	if session.TotalSize > 0 && session.CommittedOffset != session.TotalSize {
+		return "", errors.WithDetails(ErrUploadIncomplete, "session", id,
+			"committed", session.CommittedOffset, "size", session.TotalSize)
+	}
+
+	checkSize := func(content *modelsv2.StoredFileContent) (*modelsv2.StoredFile, error) {
+		if int64(content.Size) != session.CommittedOffset {
+			return nil, errors.WithDetails(ErrUploadIncomplete, "session", id,
+				"committed", session.CommittedOffset, "read", content.Size)
+		}
+		return prepare(content)
+	}
+
+	fileID, err := d.upload(ctx, r, checkSize, func(tx *gorm.DB, fileID string) error {
+		idInt, err := modelsv2.ConvertStrToUint(fileID)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&modelsv2.UploadSession{}).
+			Where("id = ? AND committed_offset = ? AND file_id = 0", id, session.CommittedOffset).
+			Update("file_id", idInt)
+		if result.Error != nil {
+			return errors.WithStack(result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.WithDetails(ErrUploadConflict, "session", id)
+		}
+
+		return errors.WithStack(tx.Where("session_id = ?", id).
+			Delete(&modelsv2.UploadSessionPart{}).Error)
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	d.deleteBlobs(partKeys(session.Parts)...)
+	return fileID, nil
+}
+
+// AbortUpload deletes the session and the content received.
+func (d *fileStore) AbortUpload(ctx context.Context, id string) error {
+	session, err := d.GetUpload(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = d.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("session_id = ?", id).
+			Delete(&modelsv2.UploadSessionPart{}).Error; err != nil {
+			return errors.WithStack(err)
+		}
+
+		return errors.WithStack(tx.Delete(session).Error)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	d.deleteBlobs(partKeys(session.Parts)...)
+	return nil
+}
+
+// CleanUploads aborts the upload sessions idle for longer than the upload expiry.
+func (d *fileStore) CleanUploads(ctx context.Context) (int64, error) {
+	if d.config.UploadExpiry <= 0 {
+		return 0, nil
+	}
+
+	before := time.Now().Add(-d.config.UploadExpiry)
+
+	var ids []string
+	if err := d.WithContext(ctx).
+		Model(&modelsv2.UploadSession{}).
+		Where("updated_at < ?", before).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	var count int64
+	for _, id := range ids {
+		if err := d.AbortUpload(ctx, id); err != nil && !errors.Is(err, ErrNotFound) {
+			return count, err
+		}
+		count++
+	}
+
+	d.Log.Info("cleaned up upload sessions", "count", count, "before", before.String())
+	return count, nil
+}
+
+func partKeys(parts []modelsv2.UploadSessionPart) []string {
+	keys := make([]string, 0, len(parts))
+	for _, part := range parts {
+		keys = append(keys, part.BlobKey)
+	}
+	return keys
+}
+
+// partsReader reads the parts of an upload one after the other, opening each
+// part only when it is reached.
+type partsReader struct {
+	ctx     context.Context
+	blobs   blobstore.BlobStore
+	parts   []modelsv2.UploadSessionPart
+	current io.ReadCloser
+}
+
+func (p *partsReader) Read(b []byte) (int, error) {
+	for {
+		if p.current == nil {
+			if len(p.parts) == 0 {
+				return 0, io.EOF
+			}
+
+			rc, err := p.blobs.Get(p.ctx, p.parts[0].BlobKey)
+			if err != nil {
+				return 0, err
+			}
+
+			p.current = rc
+			p.parts = p.parts[1:]
+		}
+
+		n, err := p.current.Read(b)
+		if err == io.EOF {
+			p.current.Close()
+			p.current = nil
+
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+
+		return n, err
+	}
+}
+
+func (p *partsReader) Close() error {
+	if p.current == nil {
+		return nil
+	}
+	return p.current.Close()
+}
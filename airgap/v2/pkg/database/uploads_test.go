@@ -0,0 +1,182 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("uploads", func() {
+	var (
+		db    *gorm.DB
+		sut   StoredFileStore
+		blobs blobstore.BlobStore
+		ctx   = context.Background()
+
+		session *modelsv2.UploadSession
+	)
+
+	BeforeEach(func() {
+		db, blobs = openTestStore("uploads.gorm.db")
+		sut = newTestStore(db, FileStoreConfig{BlobStore: blobs, UploadExpiry: time.Hour})
+
+		session = &modelsv2.UploadSession{
+			Name:       "upload.txt",
+			Source:     "redhat-marketplace",
+			SourceType: "report",
+			MimeType:   "text/plain",
+			Metadata:   `{"version":"1"}`,
+			TotalSize:  11,
+		}
+	})
+
+	prepare := func(content *modelsv2.StoredFileContent) (*modelsv2.StoredFile, error) {
+		return &modelsv2.StoredFile{
+			Name:       session.Name,
+			Source:     session.Source,
+			SourceType: session.SourceType,
+			File:       modelsv2.StoredFileContent{MimeType: session.MimeType},
+		}, nil
+	}
+
+	finish := func(id string) (string, error) {
+		_, rc, err := sut.OpenUpload(ctx, id)
+		Expect(err).To(Succeed())
+		defer rc.Close()
+		return sut.FinishUpload(ctx, id, rc, prepare)
+	}
+
+	It("should put chunks at the committed offset and finish", func() {
+		id, err := sut.StartUpload(ctx, session)
+		Expect(err).To(Succeed())
+
+		committed, err := sut.PutUploadChunk(ctx, id, 0, strings.NewReader("hello"))
+		Expect(err).To(Succeed())
+		Expect(committed).To(Equal(int64(5)))
+
+		By("resending a chunk already committed")
+		committed, err = sut.PutUploadChunk(ctx, id, 0, strings.NewReader("hello"))
+		Expect(err).To(Succeed())
+		Expect(committed).To(Equal(int64(5)))
+
+		By("resending a chunk partially committed")
+		committed, err = sut.PutUploadChunk(ctx, id, 3, strings.NewReader("lo wo"))
+		Expect(err).To(Succeed())
+		Expect(committed).To(Equal(int64(8)))
+
+		_, err = sut.PutUploadChunk(ctx, id, 10, strings.NewReader("d"))
+		Expect(err).To(MatchError(ErrUploadOffset))
+
+		_, err = finish(id)
+		Expect(err).To(MatchError(ErrUploadIncomplete))
+
+		_, err = sut.PutUploadChunk(ctx, id, 8, strings.NewReader("rld!"))
+		Expect(err).To(MatchError(ErrInvalidInput))
+
+		committed, err = sut.PutUploadChunk(ctx, id, 8, strings.NewReader("rld"))
+		Expect(err).To(Succeed())
+		Expect(committed).To(Equal(int64(11)))
+
+		found, err := sut.GetUpload(ctx, id)
+		Expect(err).To(Succeed())
+		Expect(found.Parts).To(HaveLen(3))
+		partKeys := partKeys(found.Parts)
+
+		fileID, err := finish(id)
+		Expect(err).To(Succeed())
+
+		_, rc, err := sut.Open(ctx, fileID)
+		Expect(err).To(Succeed())
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		Expect(string(data)).To(Equal("hello world"))
+
+		for _, key := range partKeys {
+			_, err = blobs.Get(ctx, key)
+			Expect(err).To(MatchError(blobstore.ErrNotFound))
+		}
+
+		By("finishing again")
+		again, err := finish(id)
+		Expect(err).To(Succeed())
+		Expect(again).To(Equal(fileID))
+
+		_, err = sut.PutUploadChunk(ctx, id, 11, strings.NewReader("!"))
+		Expect(err).To(MatchError(ErrUploadFinished))
+	})
+
+	It("should survive reopening the database", func() {
+		id, err := sut.StartUpload(ctx, session)
+		Expect(err).To(Succeed())
+		_, err = sut.PutUploadChunk(ctx, id, 0, strings.NewReader("hello "))
+		Expect(err).To(Succeed())
+
+		sut = &fileStore{DB: db, config: FileStoreConfig{BlobStore: blobs}}
+
+		found, err := sut.GetUpload(ctx, id)
+		Expect(err).To(Succeed())
+		Expect(found.CommittedOffset).To(Equal(int64(6)))
+		Expect(found.Metadata).To(Equal(`{"version":"1"}`))
+
+		_, err = sut.PutUploadChunk(ctx, id, 6, strings.NewReader("world"))
+		Expect(err).To(Succeed())
+		_, err = finish(id)
+		Expect(err).To(Succeed())
+	})
+
+	It("should abort and clean up sessions", func() {
+		id, err := sut.StartUpload(ctx, session)
+		Expect(err).To(Succeed())
+		_, err = sut.PutUploadChunk(ctx, id, 0, strings.NewReader("hello"))
+		Expect(err).To(Succeed())
+
+		found, err := sut.GetUpload(ctx, id)
+		Expect(err).To(Succeed())
+		key := found.Parts[0].BlobKey
+
+		Expect(sut.AbortUpload(ctx, id)).To(Succeed())
+		_, err = sut.GetUpload(ctx, id)
+		Expect(err).To(MatchError(ErrNotFound))
+		_, err = blobs.Get(ctx, key)
+		Expect(err).To(MatchError(blobstore.ErrNotFound))
+
+		idle, err := sut.StartUpload(ctx, session)
+		Expect(err).To(Succeed())
+		active, err := sut.StartUpload(ctx, session)
+		Expect(err).To(Succeed())
+
+		Expect(db.Model(&modelsv2.UploadSession{}).
+			Where("id = ?", idle).
+			UpdateColumn("updated_at", time.Now().Add(-2*time.Hour)).Error).To(Succeed())
+
+		count, err := sut.CleanUploads(ctx)
+		Expect(err).To(Succeed())
+		Expect(count).To(Equal(int64(1)))
+
+		_, err = sut.GetUpload(ctx, idle)
+		Expect(err).To(MatchError(ErrNotFound))
+		_, err = sut.GetUpload(ctx, active)
+		Expect(err).To(Succeed())
+	})
+})
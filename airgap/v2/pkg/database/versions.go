@@ -0,0 +1,164 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"io"
+
+	"emperror.dev/errors"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+)
+
+// addVersion snapshots the saved file as its next version and prunes the
+// versions past the number kept. It returns the version added and the blobs
+// of the pruned versions.
+func (d *fileStore) addVersion(ctx context.Context, id string) (uint64, []string, error) {
+	file, err := d.Get(ctx, id)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	version, err := modelsv2.NewStoredFileVersion(file, file.Version+1)
+	if err != nil {
+		return 0, nil, errors.WithStack(err)
+	}
+
+	db := d.DB.WithContext(ctx)
+
+	if err := db.Create(version).Error; err != nil {
+		return 0, nil, errors.WithStack(err)
+	}
+
+	if err := retainBlobs(db, version.BlobKey); err != nil {
+		return 0, nil, err
+	}
+
+	if err := db.Model(&modelsv2.StoredFile{}).
+		Where("id = ?", file.ID).
+		UpdateColumn("version", version.Version).Error; err != nil {
+		return 0, nil, errors.WithStack(err)
+	}
+
+	pruned, err := d.pruneVersions(ctx, file.ID, version.Version)
+	return version.Version, pruned, err
+}
+
+func (d *fileStore) pruneVersions(ctx context.Context, fileID uint, latest uint64) ([]string, error) {
+	keep := uint64(d.config.MaxVersions)
+	if keep == 0 || latest <= keep {
+		return nil, nil
+	}
+
+	db := d.DB.WithContext(ctx)
+
+	var keys []string
+	if err := db.Model(&modelsv2.StoredFileVersion{}).
+		Where("file_id = ? AND version <= ?", fileID, latest-keep).
+		Pluck("blob_key", &keys).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if err := db.Where("file_id = ? AND version <= ?", fileID, latest-keep).
+		Delete(&modelsv2.StoredFileVersion{}).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return keys, nil
+}
+
+// GetVersion returns the file with the content and metadata of a version,
+// the latest version when version is 0.
+func (d *fileStore) GetVersion(ctx context.Context, id string, version uint64) (*modelsv2.StoredFile, error) {
+	file, err := d.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.ID == 0 {
+		return nil, ErrNotFound
+	}
+
+	if version == 0 || version == file.Version {
+		return file, nil
+	}
+
+	fileVersion := &modelsv2.StoredFileVersion{}
+	err = d.DB.WithContext(ctx).
+		Where("file_id = ? AND version = ?", file.ID, version).
+		First(fileVersion).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.WithDetails(ErrNotFound, "id", id, "version", version)
+	}
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return file.AtVersion(fileVersion)
+}
+
+// OpenVersion returns a version of the file and a reader over its content,
+// callers must close the reader.
+func (d *fileStore) OpenVersion(ctx context.Context, id string, version uint64) (*modelsv2.StoredFile, io.ReadCloser, error) {
+	if version == 0 {
+		return d.Open(ctx, id)
+	}
+
+	file, err := d.GetVersion(ctx, id, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rc, err := d.openContent(ctx, &file.File)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, rc, nil
+}
+
+// ListVersions returns the file at each version kept, newest first.
+func (d *fileStore) ListVersions(ctx context.Context, id string) ([]modelsv2.StoredFile, error) {
+	file, err := d.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.ID == 0 {
+		return nil, ErrNotFound
+	}
+
+	versions := []modelsv2.StoredFileVersion{}
+	if err := d.DB.WithContext(ctx).
+		Where("file_id = ?", file.ID).
+		Order("version desc").
+		Find(&versions).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	files := make([]modelsv2.StoredFile, 0, len(versions))
+	for i := range versions {
+		at, err := file.AtVersion(&versions[i])
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		files = append(files, *at)
+	}
+
+	return files, nil
+}
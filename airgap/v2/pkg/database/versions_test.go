@@ -0,0 +1,173 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	modelsv2 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/models/v2"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("versions", func() {
+	var (
+		db     *gorm.DB
+		blobs  blobstore.BlobStore
+		ctx    = context.Background()
+		config FileStoreConfig
+		sut    StoredFileStore
+	)
+
+	BeforeEach(func() {
+		db, blobs = openTestStore("versions.gorm.db")
+		config = FileStoreConfig{BlobStore: blobs}
+	})
+
+	JustBeforeEach(func() {
+		sut = newTestStore(db, config)
+	})
+
+	save := func(content, version string) string {
+		file := testFile("report.txt", content)
+		file.Metadata = []modelsv2.StoredFileMetadata{{Key: "version", Value: version}}
+		return saveTestFile(ctx, sut, file)
+	}
+
+	read := func(id string, version uint64) string {
+		_, rc, err := sut.OpenVersion(ctx, id, version)
+		Expect(err).To(Succeed())
+		defer rc.Close()
+
+		content, err := io.ReadAll(rc)
+		Expect(err).To(Succeed())
+		return string(content)
+	}
+
+	blobExists := func(key string) bool {
+		rc, err := blobs.Get(ctx, key)
+		if err == nil {
+			rc.Close()
+			return true
+		}
+		Expect(err).To(MatchError(blobstore.ErrNotFound))
+		return false
+	}
+
+	It("should keep every version of a file", func() {
+		id := save("first", "1")
+		Expect(save("second", "2")).To(Equal(id))
+		Expect(save("third", "3")).To(Equal(id))
+
+		file, err := sut.Get(ctx, id)
+		Expect(err).To(Succeed())
+		Expect(file.Version).To(Equal(uint64(3)))
+
+		versions, err := sut.ListVersions(ctx, id)
+		Expect(err).To(Succeed())
+		Expect(versions).To(HaveLen(3))
+		Expect(versions[0].Version).To(Equal(uint64(3)))
+		Expect(versions[2].Version).To(Equal(uint64(1)))
+
+		first, err := sut.GetVersion(ctx, id, 1)
+		Expect(err).To(Succeed())
+		Expect(first.Metadata).To(ConsistOf(HaveField("Value", "1")))
+		Expect(first.File.Size).To(Equal(len("first")))
+
+		Expect(read(id, 1)).To(Equal("first"))
+		Expect(read(id, 2)).To(Equal("second"))
+		Expect(read(id, 0)).To(Equal("third"))
+
+		_, err = sut.GetVersion(ctx, id, 4)
+		Expect(err).To(MatchError(ErrNotFound))
+
+		_, err = sut.ListVersions(ctx, "999")
+		Expect(err).To(MatchError(ErrNotFound))
+	})
+
+	It("should version a metadata update without copying the content", func() {
+		id := save("content", "1")
+
+		file, err := sut.Get(ctx, id)
+		Expect(err).To(Succeed())
+		file.Metadata = []modelsv2.StoredFileMetadata{{Key: "version", Value: "2"}}
+		_, err = sut.Save(ctx, file)
+		Expect(err).To(Succeed())
+		Expect(file.Version).To(Equal(uint64(2)))
+
+		versions, err := sut.ListVersions(ctx, id)
+		Expect(err).To(Succeed())
+		Expect(versions).To(HaveLen(2))
+		Expect(versions[0].File.BlobKey).To(Equal(versions[1].File.BlobKey))
+		Expect(versions[0].Metadata).To(ConsistOf(HaveField("Value", "2")))
+		Expect(versions[1].Metadata).To(ConsistOf(HaveField("Value", "1")))
+	})
+
+	It("should delete the versions and their content with the file", func() {
+		id := save("first", "1")
+		save("second", "2")
+
+		versions, err := sut.ListVersions(ctx, id)
+		Expect(err).To(Succeed())
+
+		Expect(sut.Delete(ctx, id, true)).To(Succeed())
+
+		var count int64
+		Expect(db.Model(&modelsv2.StoredFileVersion{}).Count(&count).Error).To(Succeed())
+		Expect(count).To(BeZero())
+		for _, version := range versions {
+			Expect(blobExists(version.File.BlobKey)).To(BeFalse())
+		}
+	})
+
+	Context("with a version limit", func() {
+		BeforeEach(func() {
+			config.MaxVersions = 2
+		})
+
+		It("should prune the oldest versions", func() {
+			id := save("first", "1")
+
+			first, err := sut.GetVersion(ctx, id, 1)
+			Expect(err).To(Succeed())
+
+			file, err := sut.Get(ctx, id)
+			Expect(err).To(Succeed())
+			file.Metadata = []modelsv2.StoredFileMetadata{{Key: "version", Value: "2"}}
+			_, err = sut.Save(ctx, file)
+			Expect(err).To(Succeed())
+
+			// version 1 is pruned but its content is still used by version 2
+			save("third", "3")
+			Expect(blobExists(first.File.BlobKey)).To(BeTrue())
+
+			save("fourth", "4")
+			Expect(blobExists(first.File.BlobKey)).To(BeFalse())
+
+			versions, err := sut.ListVersions(ctx, id)
+			Expect(err).To(Succeed())
+			Expect(versions).To(HaveLen(2))
+			Expect(versions[0].Version).To(Equal(uint64(4)))
+			Expect(versions[1].Version).To(Equal(uint64(3)))
+
+			_, err = sut.GetVersion(ctx, id, 2)
+			Expect(err).To(MatchError(ErrNotFound))
+			Expect(read(id, 3)).To(Equal("third"))
+		})
+	})
+})
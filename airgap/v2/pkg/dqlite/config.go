@@ -0,0 +1,222 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dqlite
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/blobstore"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/envelope"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Database drivers the data service can store its records in
+const (
+	DriverDqlite   = "dqlite"
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+var (
+	ErrUnknownDriver  = errors.New("unknown database driver")
+	ErrLocalBlobStore = errors.New("the local blob store is not shared by the dqlite nodes, content stored through one node can not be read through the others")
+	ErrNoPageTokenKey = errors.New("a page token key shared by the dqlite nodes is required, page tokens signed by one node can not be read by the others")
+)
+
+type DatabaseConfig struct {
+	// Driver is dqlite, sqlite or postgres, DefaultDriver when empty
+	Driver string
+	// DSN is the connection string of the postgres database
+	DSN string
+
+	Name         string
+	Dir          string
+	Url          string
+	Join         *[]string
+	Verbose      bool
+	Log          logr.Logger
+	CACert       string
+	TLSCert      string
+	TLSKey       string
+	CipherSuites []uint16
+	MinVersion   uint16
+
+	BlobStore blobstore.BlobStore
+	// LocalBlobStore is set when the content in BlobStore can only be read
+	// through this node, a dqlite node with peers then refuses to start
+	LocalBlobStore bool
+	UploadExpiry   time.Duration
+	MaxVersions    int
+	// PageTokenKey signs list page tokens, a dqlite node with peers refuses
+	// to start without one
+	PageTokenKey []byte
+	// RetentionRules set how long live files are kept
+	RetentionRules []database.RetentionRule
+	// Quotas limit the storage used by files
+	Quotas database.Quotas
+	// Compression sets how content is compressed in the blob store
+	Compression database.CompressionPolicy
+	// Keyring encrypts content in the blob store when set
+	Keyring *envelope.Keyring
+	// EventRetention is how long file events are kept for watchers to resume from
+	EventRetention time.Duration
+
+	gormDB *gorm.DB
+	closer io.Closer
+	node   *dqliteNode
+}
+
+func (dc *DatabaseConfig) driver() string {
+	if dc.Driver == "" {
+		return DefaultDriver
+	}
+	return dc.Driver
+}
+
+// InitDB initializes the GORM connection and returns a connected struct
+func (dc *DatabaseConfig) InitDB(
+	cleanUpAfter time.Duration,
+) (database.StoredFileStore, error) {
+	var (
+		dialector gorm.Dialector
+		err       error
+	)
+
+	switch dc.driver() {
+	case DriverDqlite:
+		dialector, err = dc.openDqlite()
+		if err == nil {
+			err = dc.checkCluster()
+		}
+	case DriverSQLite:
+		err = os.MkdirAll(dc.Dir, 0755)
+		dialector = sqlite.Open(database.SQLiteDSN(filepath.Join(dc.Dir, dc.Name)))
+	case DriverPostgres:
+		if dc.DSN == "" {
+			err = errors.New("a dsn is required by the postgres driver")
+		}
+		dialector = postgres.Open(dc.DSN)
+	default:
+		err = errors.Wrap(ErrUnknownDriver, dc.Driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	writer := logger.Writer(log.New(os.Stdout, "gorm-", log.LstdFlags))
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.New(writer, logger.Config{
+			LogLevel: logger.Info,
+		}),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// queries are traced when tracing is enabled
+	if err := db.Use(database.Tracing{}); err != nil {
+		return nil, err
+	}
+
+	dc.Log.Info("database initialized", "driver", dc.driver())
+
+	store, closer := database.New(db, database.FileStoreConfig{
+		CleanupAfter:   cleanUpAfter,
+		BlobStore:      dc.BlobStore,
+		UploadExpiry:   dc.UploadExpiry,
+		EventRetention: dc.EventRetention,
+		MaxVersions:    dc.MaxVersions,
+		PageTokenKey:   dc.PageTokenKey,
+		RetentionRules: dc.RetentionRules,
+		Quotas:         dc.Quotas,
+		Compression:    dc.Compression,
+		Keyring:        dc.Keyring,
+	})
+	dc.gormDB = db
+	dc.closer = closer
+	return store, nil
+}
+
+// checkCluster refuses a local blob store or a random page token key once
+// the dqlite node has peers, each node would only be able to read the content
+// stored through it and the page tokens it signed
+func (dc *DatabaseConfig) checkCluster() error {
+	if dc.node == nil || (!dc.LocalBlobStore && len(dc.PageTokenKey) != 0) {
+		return nil
+	}
+
+	members, err := dc.node.members()
+	if err != nil {
+		return err
+	}
+
+	nodes := 0
+	for _, count := range members {
+		nodes += count
+	}
+
+	if nodes <= 1 {
+		return nil
+	}
+
+	if dc.LocalBlobStore {
+		return errors.Wrapf(ErrLocalBlobStore, "cluster of %d nodes", nodes)
+	}
+
+	return errors.Wrapf(ErrNoPageTokenKey, "cluster of %d nodes", nodes)
+}
+
+// TryMigrate  performs database migration
+func (dc *DatabaseConfig) TryMigrate() error {
+	return database.Migrate(dc.gormDB, dc.BlobStore)
+}
+
+// IsLeader returns true if running node is leader. Only dqlite nodes elect a
+// leader, every other node is its own leader.
+func (dc *DatabaseConfig) IsLeader() (bool, error) {
+	if dc.node == nil {
+		return true, nil
+	}
+	return dc.node.isLeader()
+}
+
+// Close ensures all responsibilities for the node are handled gracefully on exit
+func (dc *DatabaseConfig) Close() {
+	if dc == nil {
+		return
+	}
+
+	if dc.closer != nil {
+		dc.closer.Close()
+	}
+
+	if dc.node != nil {
+		dc.node.close()
+	} else if dc.gormDB != nil {
+		if db, err := dc.gormDB.DB(); err == nil {
+			db.Close()
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dqlite
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	leaderDesc = prometheus.NewDesc(
+		"airgap_dqlite_leader",
+		"Whether this node is the leader of the database cluster, always 1 when the database is not replicated.",
+		nil, nil)
+	membersDesc = prometheus.NewDesc(
+		"airgap_dqlite_members",
+		"Number of nodes of the database cluster by role, only reported by dqlite nodes.",
+		[]string{"role"}, nil)
+)
+
+// Collector returns the collector of the leader and membership state of the
+// database node, read when the metrics are scraped
+func (dc *DatabaseConfig) Collector() prometheus.Collector {
+	return &clusterCollector{dc: dc}
+}
+
+type clusterCollector struct {
+	dc *DatabaseConfig
+}
+
+func (c *clusterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- leaderDesc
+	ch <- membersDesc
+}
+
+func (c *clusterCollector) Collect(ch chan<- prometheus.Metric) {
+	isLeader, err := c.dc.IsLeader()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(leaderDesc, err)
+	} else {
+		var leader float64
+		if isLeader {
+			leader = 1
+		}
+		ch <- prometheus.MustNewConstMetric(leaderDesc, prometheus.GaugeValue, leader)
+	}
+
+	if c.dc.node == nil {
+		return
+	}
+
+	roles, err := c.dc.node.members()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(membersDesc, err)
+		return
+	}
+
+	for role, count := range roles {
+		ch <- prometheus.MustNewConstMetric(membersDesc, prometheus.GaugeValue, float64(count), role)
+	}
+}
@@ -24,7 +24,6 @@ import (
 	"database/sql"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -32,62 +31,28 @@ import (
 
 	"github.com/canonical/go-dqlite/app"
 	"github.com/canonical/go-dqlite/client"
-	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
-	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
 	dqlite "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/dqlite/driver"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
-type DatabaseConfig struct {
-	Name         string
-	Dir          string
-	Url          string
-	Join         *[]string
-	Verbose      bool
-	Log          logr.Logger
-	dqliteDB     *sql.DB
-	app          *app.App
-	gormDB       *gorm.DB
-	CACert       string
-	TLSCert      string
-	TLSKey       string
-	CipherSuites []uint16
-	MinVersion   uint16
+// DefaultDriver is used when no database driver is configured
+const DefaultDriver = DriverDqlite
+
+type dqliteNode struct {
+	db  *sql.DB
+	app *app.App
 }
 
-// InitDB initializes the GORM connection and returns a connected struct
-func (dc *DatabaseConfig) InitDB(
-	cleanUpAfter time.Duration,
-) (database.StoredFileStore, error) {
-	err := dc.initDqlite()
-	if err != nil {
-		return nil, err
+// openDqlite starts the dqlite node, joining the cluster, and returns its dialector
+func (dc *DatabaseConfig) openDqlite() (gorm.Dialector, error) {
+	if dc.Url == "" {
+		return nil, errors.New("the dqlite driver requires a database address")
 	}
-	writer := logger.Writer(log.New(os.Stdout, "gorm-", log.LstdFlags))
-	log := logger.New(writer, logger.Config{
-		LogLevel: logger.Info,
-	})
-	dqliteDialector := dqlite.Open(dc.dqliteDB)
-
-	db, err := gorm.Open(dqliteDialector, &gorm.Config{
-		Logger: log,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	database, _ := database.New(db, database.FileStoreConfig{CleanupAfter: cleanUpAfter})
-	dc.gormDB = db
-	return database, err
-}
 
-// initDqlite initializes the underlying dqlite database
-func (dc *DatabaseConfig) initDqlite() error {
 	dc.Dir = filepath.Join(dc.Dir, dc.Url)
 	if err := os.MkdirAll(dc.Dir, 0755); err != nil {
-		return errors.Wrapf(err, "can't create %s", dc.Dir)
+		return nil, errors.Wrapf(err, "can't create %s", dc.Dir)
 	}
 	logFunc := func(l client.LogLevel, format string, a ...interface{}) {
 		if !dc.Verbose {
@@ -97,16 +62,21 @@ func (dc *DatabaseConfig) initDqlite() error {
 		dc.Log.Info(strings.TrimRight(s, "\n"))
 	}
 
-	options := []app.Option{app.WithAddress(dc.Url), app.WithCluster(*dc.Join), app.WithLogFunc(logFunc)}
+	var join []string
+	if dc.Join != nil {
+		join = *dc.Join
+	}
+
+	options := []app.Option{app.WithAddress(dc.Url), app.WithCluster(join), app.WithLogFunc(logFunc)}
 
 	if len(dc.TLSCert) != 0 && len(dc.TLSKey) != 0 && len(dc.CACert) != 0 {
 		tlsCert, err := tls.LoadX509KeyPair(dc.TLSCert, dc.TLSKey)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		caCert, err := ioutil.ReadFile(dc.CACert)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		pool := x509.NewCertPool()
 		pool.AppendCertsFromPEM(caCert)
@@ -124,34 +94,31 @@ func (dc *DatabaseConfig) initDqlite() error {
 
 	app, err := app.New(dc.Dir, options...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := app.Ready(context.Background()); err != nil {
-		return err
+		return nil, err
 	}
 
 	conn, err := app.Open(context.Background(), dc.Name)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	dc.app = app
-	dc.dqliteDB = conn
-	return conn.Ping()
-}
+	dc.node = &dqliteNode{db: conn, app: app}
+	if err := conn.Ping(); err != nil {
+		return nil, err
+	}
 
-// TryMigrate  performs database migration
-func (dc *DatabaseConfig) TryMigrate() error {
-	return database.Migrate(dc.gormDB)
+	return dqlite.Open(conn), nil
 }
 
-// IsLeader returns true if running node is leader
-func (dc *DatabaseConfig) IsLeader() (bool, error) {
+func (n *dqliteNode) isLeader() (bool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
 
-	cli, err := dc.app.Leader(ctx)
+	cli, err := n.app.Leader(ctx)
 	if err != nil {
 		return false, err
 	}
@@ -162,17 +129,37 @@ func (dc *DatabaseConfig) IsLeader() (bool, error) {
 			return false, err
 		}
 	}
-	if leader.Address == dc.app.Address() {
+	if leader.Address == n.app.Address() {
 		return true, nil
 	}
 	return false, nil
 }
 
-// Close ensures all responsibilites for the node are handled gracefully on exit
-func (dc *DatabaseConfig) Close() {
-	if dc != nil {
-		dc.dqliteDB.Close()
-		dc.app.Handover(context.Background())
-		dc.app.Close()
+// members counts the nodes of the cluster by role
+func (n *dqliteNode) members() (map[string]int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cli, err := n.app.Leader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	nodes, err := cli.Cluster(ctx)
+	if err != nil {
+		return nil, err
 	}
+
+	roles := make(map[string]int)
+	for _, node := range nodes {
+		roles[node.Role.String()]++
+	}
+	return roles, nil
+}
+
+func (n *dqliteNode) close() {
+	n.db.Close()
+	n.app.Handover(context.Background())
+	n.app.Close()
 }
@@ -0,0 +1,142 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("envelope", func() {
+	newKey := func() []byte {
+		key := make([]byte, KeySize)
+		_, err := rand.Read(key)
+		Expect(err).To(Succeed())
+		return key
+	}
+
+	encrypt := func(dataKey, content []byte) []byte {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, dataKey)
+		Expect(err).To(Succeed())
+		_, err = w.Write(content)
+		Expect(err).To(Succeed())
+		Expect(w.Close()).To(Succeed())
+		return buf.Bytes()
+	}
+
+	decrypt := func(dataKey, sealed []byte) ([]byte, error) {
+		r, err := NewReader(bytes.NewReader(sealed), dataKey)
+		Expect(err).To(Succeed())
+		return io.ReadAll(r)
+	}
+
+	DescribeTable("should decrypt the content encrypted",
+		func(size int) {
+			dataKey := newKey()
+			content := make([]byte, size)
+			_, err := rand.Read(content)
+			Expect(err).To(Succeed())
+
+			sealed := encrypt(dataKey, content)
+			Expect(len(sealed)).To(BeNumerically(">", size))
+			if size > 0 {
+				Expect(bytes.Contains(sealed, content)).To(BeFalse())
+			}
+
+			plain, err := decrypt(dataKey, sealed)
+			Expect(err).To(Succeed())
+			Expect(plain).To(Equal(content))
+		},
+		Entry("empty", 0),
+		Entry("small", 100),
+		Entry("one segment", segmentSize),
+		Entry("several segments", 3*segmentSize+7),
+	)
+
+	It("should detect changed and truncated content", func() {
+		dataKey := newKey()
+		sealed := encrypt(dataKey, bytes.Repeat([]byte("x"), 2*segmentSize+10))
+
+		changed := append([]byte(nil), sealed...)
+		changed[10] ^= 1
+		_, err := decrypt(dataKey, changed)
+		Expect(err).To(MatchError(ErrCorrupted))
+
+		// dropping the last segment leaves a full segment not sealed as the last one
+		_, err = decrypt(dataKey, sealed[:2*(segmentSize+16)])
+		Expect(err).To(MatchError(ErrCorrupted))
+
+		_, err = decrypt(dataKey, nil)
+		Expect(err).To(MatchError(ErrCorrupted))
+
+		_, err = decrypt(newKey(), sealed)
+		Expect(err).To(MatchError(ErrCorrupted))
+	})
+
+	It("should wrap data keys with the primary key and rewrap them after a rotation", func() {
+		oldKey, newKEK := newKey(), newKey()
+
+		keyring, err := NewKeyring("", map[string][]byte{"2023": oldKey})
+		Expect(err).To(Succeed())
+
+		dataKey, wrapped, keyID, err := keyring.NewDataKey()
+		Expect(err).To(Succeed())
+		Expect(keyID).To(Equal("2023"))
+
+		rotated, err := NewKeyring("2024", map[string][]byte{"2023": oldKey, "2024": newKEK})
+		Expect(err).To(Succeed())
+
+		rewrapped, keyID, err := rotated.Rewrap("2023", wrapped)
+		Expect(err).To(Succeed())
+		Expect(keyID).To(Equal("2024"))
+
+		unwrapped, err := rotated.Unwrap("2024", rewrapped)
+		Expect(err).To(Succeed())
+		Expect(unwrapped).To(Equal(dataKey))
+
+		_, err = rotated.Unwrap("2023", rewrapped)
+		Expect(err).To(MatchError(ErrInvalidKey))
+
+		_, err = keyring.Unwrap("2024", rewrapped)
+		Expect(err).To(MatchError(ErrUnknownKey))
+	})
+
+	It("should load the keys of a mounted secret", func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "2023"), newKey(), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "2024"), []byte(base64.StdEncoding.EncodeToString(newKey())+"\n"), 0600)).To(Succeed())
+		Expect(os.Mkdir(filepath.Join(dir, "..data"), 0700)).To(Succeed())
+
+		keyring, err := LoadKeyring(dir, "2024")
+		Expect(err).To(Succeed())
+		Expect(keyring.Primary()).To(Equal("2024"))
+		Expect(keyring.KeyIDs()).To(Equal([]string{"2023", "2024"}))
+
+		_, err = LoadKeyring(dir, "")
+		Expect(err).To(HaveOccurred())
+
+		Expect(os.WriteFile(filepath.Join(dir, "short"), []byte("c2hvcnQ="), 0600)).To(Succeed())
+		_, err = LoadKeyring(dir, "2024")
+		Expect(err).To(MatchError(ErrInvalidKey))
+	})
+})
@@ -0,0 +1,217 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package envelope encrypts content with a data key per blob, the data keys
+// are wrapped by key encryption keys read from a mounted Kubernetes Secret.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+const (
+	ErrUnknownKey = errors.Sentinel("unknown key encryption key")
+	ErrInvalidKey = errors.Sentinel("invalid encryption key")
+	ErrNoKeys     = errors.Sentinel("no key encryption keys")
+)
+
+// KeySize is the size in bytes of the key encryption and data keys, AES-256
+const KeySize = 32
+
+// Keyring holds the key encryption keys by id. New data keys are wrapped by
+// the primary key, the other keys only unwrap data keys wrapped before a
+// rotation.
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring returns a keyring of the keys, which must be KeySize bytes.
+// primary may be empty when there is a single key.
+func NewKeyring(primary string, keys map[string][]byte) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+
+	if primary == "" {
+		if len(keys) != 1 {
+			return nil, errors.WithDetails(errors.New("the primary key must be set when there are several keys"), "keys", len(keys))
+		}
+
+		for id := range keys {
+			primary = id
+		}
+	}
+
+	if _, ok := keys[primary]; !ok {
+		return nil, errors.WithDetails(ErrUnknownKey, "keyID", primary)
+	}
+
+	k := &Keyring{primary: primary, keys: map[string]cipher.AEAD{}}
+	for id, key := range keys {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, errors.WithDetails(err, "keyID", id)
+		}
+		k.keys[id] = aead
+	}
+
+	return k, nil
+}
+
+// LoadKeyring reads the keys from the files of dir, as mounted from a
+// Kubernetes Secret. The name of a file is the id of its key, which is
+// KeySize bytes either raw or base64 encoded. Hidden files, such as the
+// ..data link of a mounted Secret, are skipped.
+func LoadKeyring(dir, primary string) (*Keyring, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to read the key directory")
+	}
+
+	keys := map[string][]byte{}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		key, err := decodeKey(data)
+		if err != nil {
+			return nil, errors.WithDetails(err, "keyID", entry.Name())
+		}
+		keys[entry.Name()] = key
+	}
+
+	return NewKeyring(primary, keys)
+}
+
+func decodeKey(data []byte) ([]byte, error) {
+	if len(data) == KeySize {
+		return data, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != KeySize {
+		return nil, errors.WithDetails(ErrInvalidKey, "expectedSize", KeySize)
+	}
+
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, errors.WithDetails(ErrInvalidKey, "expectedSize", KeySize, "size", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// Primary returns the id of the key wrapping new data keys
+func (k *Keyring) Primary() string {
+	return k.primary
+}
+
+// KeyIDs returns the ids of the keys, sorted
+func (k *Keyring) KeyIDs() []string {
+	ids := make([]string, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// NewDataKey returns a random data key and the data key wrapped by the
+// primary key
+func (k *Keyring) NewDataKey() (dataKey, wrapped []byte, keyID string, err error) {
+	dataKey = make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, nil, "", errors.WithStack(err)
+	}
+
+	wrapped, keyID, err = k.Wrap(dataKey)
+	return dataKey, wrapped, keyID, err
+}
+
+// Wrap encrypts the data key with the primary key
+func (k *Keyring) Wrap(dataKey []byte) (wrapped []byte, keyID string, err error) {
+	aead := k.keys[k.primary]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+
+	// the key id is authenticated so a wrapped key can not be moved to another key
+	return aead.Seal(nonce, nonce, dataKey, []byte(k.primary)), k.primary, nil
+}
+
+// Unwrap decrypts a data key wrapped by the key keyID
+func (k *Keyring) Unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, errors.WithDetails(ErrUnknownKey, "keyID", keyID)
+	}
+
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.WithDetails(ErrInvalidKey, "keyID", keyID)
+	}
+
+	nonce, sealed := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, sealed, []byte(keyID))
+	if err != nil {
+		return nil, errors.WithDetails(errors.Wrap(ErrInvalidKey, err.Error()), "keyID", keyID)
+	}
+
+	return dataKey, nil
+}
+
+// Rewrap wraps a data key wrapped by keyID with the primary key
+func (k *Keyring) Rewrap(keyID string, wrapped []byte) ([]byte, string, error) {
+	dataKey, err := k.Unwrap(keyID, wrapped)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return k.Wrap(dataKey)
+}
@@ -0,0 +1,189 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"encoding/binary"
+	"io"
+
+	"emperror.dev/errors"
+)
+
+const ErrCorrupted = errors.Sentinel("encrypted content is corrupted")
+
+// Content is encrypted in segments of segmentSize bytes, each sealed with
+// AES-GCM under the data key. The nonce of a segment is its number and a
+// flag set on the last segment, so segments can not be reordered and a
+// truncated stream fails to decrypt. A data key encrypts a single stream.
+const (
+	segmentSize = 64 * 1024
+	finalFlag   = 1
+)
+
+func segmentNonce(nonce []byte, counter uint64, final bool) {
+	binary.BigEndian.PutUint64(nonce, counter)
+	nonce[len(nonce)-1] = 0
+	if final {
+		nonce[len(nonce)-1] = finalFlag
+	}
+}
+
+type writer struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	nonce   []byte
+	buf     []byte
+	counter uint64
+	closed  bool
+}
+
+// NewWriter returns a writer encrypting to w with the data key, Close writes
+// the last segment and must be called.
+func NewWriter(w io.Writer, dataKey []byte) (io.WriteCloser, error) {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &writer{
+		w:     w,
+		aead:  aead,
+		nonce: make([]byte, aead.NonceSize()),
+		buf:   make([]byte, 0, segmentSize+aead.Overhead()),
+	}, nil
+}
+
+func (e *writer) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to a closed writer")
+	}
+
+	written := 0
+	for len(p) > 0 {
+		// a full segment is only sealed once more content follows, the
+		// last segment is sealed by Close
+		if len(e.buf) == segmentSize {
+			if err := e.seal(false); err != nil {
+				return written, err
+			}
+		}
+
+		n := copy(e.buf[len(e.buf):segmentSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+func (e *writer) seal(final bool) error {
+	segmentNonce(e.nonce, e.counter, final)
+	e.counter++
+
+	sealed := e.aead.Seal(e.buf[:0], e.nonce, e.buf, nil)
+	e.buf = e.buf[:0]
+
+	_, err := e.w.Write(sealed)
+	return errors.WithStack(err)
+}
+
+func (e *writer) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.seal(true)
+}
+
+type reader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	nonce   []byte
+	segment []byte
+	plain   []byte
+	counter uint64
+	done    bool
+}
+
+// NewReader returns a reader decrypting the content written by a writer with
+// the same data key. Reads fail with ErrCorrupted when the content was
+// changed or truncated.
+func NewReader(r io.Reader, dataKey []byte) (io.Reader, error) {
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reader{
+		r:       bufio.NewReaderSize(r, segmentSize+aead.Overhead()+1),
+		aead:    aead,
+		nonce:   make([]byte, aead.NonceSize()),
+		segment: make([]byte, segmentSize+aead.Overhead()),
+	}, nil
+}
+
+func (d *reader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *reader) open() error {
+	n, err := io.ReadFull(d.r, d.segment)
+
+	final := false
+	switch {
+	case err == io.ErrUnexpectedEOF || err == io.EOF:
+		final = true
+	case err != nil:
+		return errors.WithStack(err)
+	default:
+		// a full segment is the last one when nothing follows
+		if _, err := d.r.Peek(1); err == io.EOF {
+			final = true
+		} else if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	if n < d.aead.Overhead() {
+		return errors.WithDetails(ErrCorrupted, "segment", d.counter)
+	}
+
+	segmentNonce(d.nonce, d.counter, final)
+	d.counter++
+
+	plain, err := d.aead.Open(d.segment[:0], d.nonce, d.segment[:n], nil)
+	if err != nil {
+		return errors.WithDetails(ErrCorrupted, "segment", d.counter-1)
+	}
+
+	d.plain = plain
+	d.done = final
+	return nil
+}
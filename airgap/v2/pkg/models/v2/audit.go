@@ -0,0 +1,85 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modelsv2
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"gorm.io/gorm"
+)
+
+const ErrAuditAppendOnly = errors.Sentinel("audit events can not be changed or deleted")
+
+// AuditEvent records a call to the data service. Events are only ever
+// appended, each one holds the hash of the previous event so a changed or
+// removed event breaks the chain.
+type AuditEvent struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	Username string
+	// Groups is the json encoded list of groups of the caller
+	Groups string
+
+	Method string
+
+	FileID     uint
+	Name       string
+	Source     string
+	SourceType string
+
+	ChecksumBefore string
+	ChecksumAfter  string
+
+	// Details is the json encoded map of details of the call
+	Details string
+
+	// PreviousHash is unique so concurrent appends can not fork the chain
+	PreviousHash string `gorm:"uniqueIndex"`
+	Hash         string
+}
+
+// ComputeHash returns the hash of the event chained to the previous event.
+func (e *AuditEvent) ComputeHash() string {
+	// fixed field order, CreatedAt in microseconds which every database keeps
+	fields, _ := json.Marshal([]interface{}{
+		e.PreviousHash,
+		e.CreatedAt.UnixMicro(),
+		e.Username,
+		e.Groups,
+		e.Method,
+		e.FileID,
+		e.Name,
+		e.Source,
+		e.SourceType,
+		e.ChecksumBefore,
+		e.ChecksumAfter,
+		e.Details,
+	})
+
+	return fmt.Sprintf("%x", sha256.Sum256(fields))
+}
+
+func (e *AuditEvent) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditAppendOnly
+}
+
+func (e *AuditEvent) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditAppendOnly
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modelsv2
+
+import "time"
+
+// StoredBlob is content kept once in the blob store under its sha256
+// checksum. Every file content and version with that checksum references the
+// same blob, which is deleted once nothing references it. Checksum and Size
+// are those of the original content, which may be stored compressed and
+// encrypted.
+type StoredBlob struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	Checksum string `gorm:"uniqueIndex"`
+	Size     int
+	BlobKey  string `gorm:"uniqueIndex"`
+
+	// Encoding is the compression of the stored content, empty when it is
+	// stored as is
+	Encoding string
+	// KeyID is the key encryption key wrapping DataKey, empty when the
+	// content is not encrypted
+	KeyID string `gorm:"index"`
+	// DataKey is the wrapped key encrypting the stored content
+	DataKey []byte
+	// StoredSize is the size of the content in the blob store
+	StoredSize int64
+
+	// RefCount is the number of file contents and versions referencing the blob
+	RefCount int64
+}
@@ -0,0 +1,43 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modelsv2
+
+import "time"
+
+// FileEventType is the change recorded by a FileEvent
+type FileEventType string
+
+const (
+	FileEventCreated FileEventType = "created"
+	FileEventUpdated FileEventType = "updated"
+	FileEventDeleted FileEventType = "deleted"
+)
+
+// FileEvent records a change to a file in the transaction making it. Events
+// are only appended, their increasing ids are the revisions watchers resume
+// from. The key of the file is kept so the deletion of a file that was
+// removed since can still be reported.
+type FileEvent struct {
+	ID        uint      `gorm:"primarykey"`
+	CreatedAt time.Time `gorm:"index"`
+
+	Type    FileEventType
+	FileID  uint `gorm:"index"`
+	Version uint64
+
+	Name       string
+	Source     string
+	SourceType string
+}
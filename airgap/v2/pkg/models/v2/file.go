@@ -43,7 +43,11 @@ type StoredFileContent struct {
 	Size     int
 	MimeType string
 
-	Content []byte
+	// BlobKey references the content in the blob store
+	BlobKey string
+
+	// Content is kept in the blob store, it is never written to the database
+	Content []byte `gorm:"-"`
 }
 
 type StoredFile struct {
@@ -53,8 +57,12 @@ type StoredFile struct {
 	Source     string `gorm:"uniqueIndex:idx_stored_file-name"`
 	SourceType string `gorm:"uniqueIndex:idx_stored_file-name"`
 
+	// Version is the number of the latest version saved
+	Version uint64
+
 	File     StoredFileContent    `gorm:"foreignKey:FileID"`
 	Metadata []StoredFileMetadata `gorm:"foreignKey:FileID"`
+	Versions []StoredFileVersion  `gorm:"foreignKey:FileID"`
 }
 
 type StoredFileKey struct {
@@ -15,6 +15,7 @@
 package modelsv2
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 
@@ -68,6 +69,7 @@ func StoredFileToProto(file *StoredFile) (fileInfo *dataservicev1.FileInfo, err
 		Checksum:   file.File.Checksum,
 		Size:       uint32(file.File.Size),
 		MimeType:   file.File.MimeType,
+		Version:    file.Version,
 		Metadata:   map[string]string{},
 	}
 
@@ -82,3 +84,82 @@ func StoredFileToProto(file *StoredFile) (fileInfo *dataservicev1.FileInfo, err
 
 	return
 }
+
+func UploadSessionFromProto(finfo *dataservicev1.FileInfo, totalSize int64) (*UploadSession, error) {
+	metadata, err := json.Marshal(finfo.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UploadSession{
+		Name:              finfo.Name,
+		Source:            finfo.Source,
+		SourceType:        finfo.SourceType,
+		MimeType:          finfo.MimeType,
+		Checksum:          finfo.Checksum,
+		ChecksumAlgorithm: finfo.ChecksumAlgorithm,
+		Metadata:          string(metadata),
+		TotalSize:         totalSize,
+	}, nil
+}
+
+// UploadSessionToProto returns the file info the session was started with
+func UploadSessionToProto(session *UploadSession) (*dataservicev1.FileInfo, error) {
+	fileInfo := &dataservicev1.FileInfo{
+		Name:              session.Name,
+		Source:            session.Source,
+		SourceType:        session.SourceType,
+		MimeType:          session.MimeType,
+		Checksum:          session.Checksum,
+		ChecksumAlgorithm: session.ChecksumAlgorithm,
+		Metadata:          map[string]string{},
+	}
+
+	if session.Metadata != "" {
+		if err := json.Unmarshal([]byte(session.Metadata), &fileInfo.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	return fileInfo, nil
+}
+
+func AuditEventToProto(event *AuditEvent) (*dataservicev1.AuditEvent, error) {
+	auditEvent := &dataservicev1.AuditEvent{
+		Id:             fmt.Sprintf("%d", event.ID),
+		CreatedAt:      timestamppb.New(event.CreatedAt),
+		Username:       event.Username,
+		Method:         event.Method,
+		ChecksumBefore: event.ChecksumBefore,
+		ChecksumAfter:  event.ChecksumAfter,
+		Details:        map[string]string{},
+		PreviousHash:   event.PreviousHash,
+		Hash:           event.Hash,
+	}
+
+	if event.FileID != 0 {
+		auditEvent.FileId = fmt.Sprintf("%d", event.FileID)
+	}
+
+	if event.Name != "" || event.Source != "" || event.SourceType != "" {
+		auditEvent.FileKey = &dataservicev1.FileKey{
+			Name:       event.Name,
+			Source:     event.Source,
+			SourceType: event.SourceType,
+		}
+	}
+
+	if event.Groups != "" {
+		if err := json.Unmarshal([]byte(event.Groups), &auditEvent.Groups); err != nil {
+			return nil, err
+		}
+	}
+
+	if event.Details != "" {
+		if err := json.Unmarshal([]byte(event.Details), &auditEvent.Details); err != nil {
+			return nil, err
+		}
+	}
+
+	return auditEvent, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modelsv2
+
+import (
+	"time"
+)
+
+// UploadSession tracks a resumable upload. The file info is kept until the
+// upload is finished, the content received so far is stored as parts in the
+// blob store.
+type UploadSession struct {
+	ID        string `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Name              string
+	Source            string
+	SourceType        string
+	MimeType          string
+	Checksum          string
+	ChecksumAlgorithm string
+
+	// Metadata is the json encoded file metadata
+	Metadata string
+
+	// TotalSize is the size declared by the client, 0 if unknown
+	TotalSize int64
+
+	// CommittedOffset is the number of bytes stored so far
+	CommittedOffset int64
+
+	// FileID is set once the upload is finished
+	FileID uint
+
+	Parts []UploadSessionPart `gorm:"foreignKey:SessionID"`
+}
+
+// UploadSessionPart is a chunk of an upload session stored in the blob store.
+type UploadSessionPart struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	SessionID string `gorm:"uniqueIndex:idx_upload_session_part"`
+	// Start is the offset of the part in the file
+	Start   int64 `gorm:"uniqueIndex:idx_upload_session_part"`
+	Size    int64
+	BlobKey string
+}
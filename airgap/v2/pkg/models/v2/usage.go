@@ -0,0 +1,26 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modelsv2
+
+// StorageUsage counts the storage used by the files of Source, or by every
+// file for the global row. The counters are updated in the transaction
+// saving a file so quotas hold across the nodes sharing the database.
+type StorageUsage struct {
+	IsGlobal bool   `gorm:"primaryKey;autoIncrement:false"`
+	Source   string `gorm:"primaryKey"`
+
+	Bytes int64
+	Files int64
+}
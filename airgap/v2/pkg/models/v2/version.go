@@ -0,0 +1,92 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modelsv2
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// StoredFileVersion is an immutable copy of a file as it was saved. Every
+// save adds a version, the latest one matches the current file.
+type StoredFileVersion struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	FileID  uint   `gorm:"uniqueIndex:idx_stored_file_version"`
+	Version uint64 `gorm:"uniqueIndex:idx_stored_file_version"`
+
+	Checksum string
+	Size     int
+	MimeType string
+	BlobKey  string
+
+	// Metadata is the json encoded file metadata of the version
+	Metadata string
+}
+
+// NewStoredFileVersion snapshots the content and metadata of file
+func NewStoredFileVersion(file *StoredFile, version uint64) (*StoredFileVersion, error) {
+	metadata := map[string]string{}
+	for _, md := range file.Metadata {
+		metadata[md.Key] = md.Value
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StoredFileVersion{
+		FileID:   file.ID,
+		Version:  version,
+		Checksum: file.File.Checksum,
+		Size:     file.File.Size,
+		MimeType: file.File.MimeType,
+		BlobKey:  file.File.BlobKey,
+		Metadata: string(data),
+	}, nil
+}
+
+// AtVersion returns a copy of file with the content and metadata of version
+func (file *StoredFile) AtVersion(version *StoredFileVersion) (*StoredFile, error) {
+	metadata := map[string]string{}
+	if version.Metadata != "" {
+		if err := json.Unmarshal([]byte(version.Metadata), &metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	at := *file
+	at.Version = version.Version
+	at.UpdatedAt = version.CreatedAt
+	at.File = StoredFileContent{
+		ID:        file.File.ID,
+		CreatedAt: version.CreatedAt,
+		UpdatedAt: version.CreatedAt,
+		FileID:    file.ID,
+		Checksum:  version.Checksum,
+		Size:      version.Size,
+		MimeType:  version.MimeType,
+		BlobKey:   version.BlobKey,
+	}
+
+	at.Metadata = make([]StoredFileMetadata, 0, len(metadata))
+	for key, value := range metadata {
+		at.Metadata = append(at.Metadata, StoredFileMetadata{FileID: file.ID, Key: key, Value: value})
+	}
+
+	return &at, nil
+}
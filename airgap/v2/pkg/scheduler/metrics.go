@@ -0,0 +1,100 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metrics of the cleanup runs of the scheduler
+type metrics struct {
+	runs            *prometheus.CounterVec
+	steps           *prometheus.CounterVec
+	lastRun         prometheus.Gauge
+	filesExpired    prometheus.Counter
+	tombstones      prometheus.Counter
+	uploadSessions  prometheus.Counter
+	fileEvents      prometheus.Counter
+	dataKeysRotated prometheus.Counter
+	dataKeysSkipped prometheus.Counter
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "airgap",
+			Subsystem: "cleanup",
+			Name:      "runs_total",
+			Help:      "Cleanup runs by result, skipped when the node is not the leader.",
+		}, []string{"result"}),
+		steps: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "airgap",
+			Subsystem: "cleanup",
+			Name:      "step_runs_total",
+			Help:      "Cleanup steps run by step and result, skipped when the step does not apply.",
+		}, []string{"step", "result"}),
+		lastRun: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "airgap",
+			Subsystem: "cleanup",
+			Name:      "last_success_timestamp_seconds",
+			Help:      "Time of the last successful cleanup run.",
+		}),
+		filesExpired: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "airgap",
+			Subsystem: "cleanup",
+			Name:      "files_expired_total",
+			Help:      "Files deleted by the retention rules.",
+		}),
+		tombstones: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "airgap",
+			Subsystem: "cleanup",
+			Name:      "tombstones_cleaned_total",
+			Help:      "Deleted files purged once older than the cleanup delay.",
+		}),
+		uploadSessions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "airgap",
+			Subsystem: "cleanup",
+			Name:      "upload_sessions_cleaned_total",
+			Help:      "Expired resumable upload sessions discarded.",
+		}),
+		fileEvents: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "airgap",
+			Subsystem: "cleanup",
+			Name:      "file_events_cleaned_total",
+			Help:      "File events deleted once older than the event retention.",
+		}),
+		dataKeysRotated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "airgap",
+			Subsystem: "cleanup",
+			Name:      "data_keys_rotated_total",
+			Help:      "Data keys rewrapped with the primary key encryption key.",
+		}),
+		dataKeysSkipped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "airgap",
+			Subsystem: "cleanup",
+			Name:      "data_keys_skipped_total",
+			Help:      "Data keys left as is because the keyring can not unwrap them.",
+		}),
+	}
+}
+
+func (m *metrics) register(registerer prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{m.runs, m.steps, m.lastRun, m.filesExpired, m.tombstones, m.uploadSessions, m.fileEvents, m.dataKeysRotated, m.dataKeysSkipped} {
+		if err := registerer.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -19,8 +19,10 @@ import (
 	"sync"
 	"time"
 
+	"emperror.dev/errors"
 	"github.com/go-co-op/gocron"
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/database"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/dqlite"
 )
@@ -31,6 +33,10 @@ type SchedulerConfig struct {
 	DB             *dqlite.DatabaseConfig
 	CleanAfter     string
 	CronExpression string
+	// Registerer registers the metrics of the cleanup runs when not nil
+	Registerer prometheus.Registerer
+
+	metrics *metrics
 }
 
 var (
@@ -45,6 +51,7 @@ func (sfg *SchedulerConfig) handler() (int64, error) {
 	}
 
 	if !isLeader {
+		sfg.metrics.runs.WithLabelValues("skipped").Inc()
 		return 0, nil
 	}
 
@@ -55,18 +62,107 @@ func (sfg *SchedulerConfig) handler() (int64, error) {
 		}
 	})
 
-	count, err := sfg.Fs.CleanTombstones(context.Background())
-	if err != nil {
-		sfg.Log.Error(err, "error while executing handler")
-		return 0, err
+	// every step runs even when a previous one failed, they clean up different tables
+	ctx := context.Background()
+	var count int64
+	var errs error
+	step := func(name string, fn func() error) {
+		err := fn()
+		switch {
+		case errors.Is(err, database.ErrNotEncrypted):
+			sfg.metrics.steps.WithLabelValues(name, "skipped").Inc()
+		case err != nil:
+			sfg.Log.Error(err, "error while running cleanup step", "step", name)
+			sfg.metrics.steps.WithLabelValues(name, "error").Inc()
+			errs = errors.Append(errs, errors.WrapIf(err, name))
+		default:
+			sfg.metrics.steps.WithLabelValues(name, "success").Inc()
+		}
 	}
 
-	sfg.Log.Info("result", "count", count)
+	step("ApplyRetention", func() error {
+		expired, err := sfg.Fs.ApplyRetention(ctx, false)
+		if err != nil {
+			return err
+		}
+
+		sfg.Log.Info("expired files deleted", "count", len(expired))
+		sfg.metrics.filesExpired.Add(float64(len(expired)))
+		return nil
+	})
+
+	step("CleanTombstones", func() error {
+		var err error
+		count, err = sfg.Fs.CleanTombstones(ctx)
+		if err != nil {
+			return err
+		}
+
+		sfg.Log.Info("result", "count", count)
+		sfg.metrics.tombstones.Add(float64(count))
+		return nil
+	})
+
+	step("CleanUploads", func() error {
+		uploads, err := sfg.Fs.CleanUploads(ctx)
+		if err != nil {
+			return err
+		}
+
+		sfg.Log.Info("upload sessions cleaned", "count", uploads)
+		sfg.metrics.uploadSessions.Add(float64(uploads))
+		return nil
+	})
+
+	step("CleanFileEvents", func() error {
+		events, err := sfg.Fs.CleanFileEvents(ctx)
+		if err != nil {
+			return err
+		}
+
+		sfg.Log.Info("file events cleaned", "count", events)
+		sfg.metrics.fileEvents.Add(float64(events))
+		return nil
+	})
+
+	// data keys are rewrapped once the primary key encryption key changes
+	step("RotateKeys", func() error {
+		rotation, err := sfg.Fs.RotateKeys(ctx)
+		if err != nil {
+			return err
+		}
+
+		if rotation.Rotated > 0 {
+			sfg.Log.Info("data keys rotated", "count", rotation.Rotated)
+		}
+		if len(rotation.Skipped) > 0 {
+			sfg.Log.Info("data keys skipped", "count", len(rotation.Skipped), "blobKeys", rotation.Skipped)
+		}
+		sfg.metrics.dataKeysRotated.Add(float64(rotation.Rotated))
+		sfg.metrics.dataKeysSkipped.Add(float64(len(rotation.Skipped)))
+		return nil
+	})
+
+	if errs != nil {
+		sfg.metrics.runs.WithLabelValues("error").Inc()
+		return count, errs
+	}
+
+	sfg.metrics.runs.WithLabelValues("success").Inc()
+	sfg.metrics.lastRun.SetToCurrentTime()
 	return count, nil
 }
 
 // StartScheduler starts all job(s) for created scheduler
 func (sfg *SchedulerConfig) Start(ctx context.Context) error {
+	sfg.metrics = newMetrics()
+	if sfg.Registerer != nil {
+		if err := sfg.metrics.register(sfg.Registerer); err != nil {
+			sfg.Log.Error(err, "error while registering metrics")
+			return err
+		}
+	}
+
 	s := gocron.NewScheduler(time.UTC)
 	defer s.Stop()
 
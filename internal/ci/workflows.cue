@@ -492,6 +492,37 @@ branch_build: _#bashWorkflow & {
 				},
 			]
 		}
+		"database-test": _#job & {
+			name:      "Database Test"
+			"runs-on": _#linuxMachine
+			strategy: matrix: {
+				driver: ["postgres", "dqlite"]
+			}
+			services: postgres: {
+				image: "postgres:15"
+				env: POSTGRES_PASSWORD: "postgres"
+				ports: ["5432:5432"]
+				options: "--health-cmd pg_isready --health-interval 10s --health-timeout 5s --health-retries 5"
+			}
+			steps: [
+				_#checkoutCode,
+				_#installGo,
+				_#cacheGoModules,
+				_#step & {
+					name: "Install dqlite"
+					if:   "matrix.driver == 'dqlite'"
+					run: """
+						sudo add-apt-repository -y ppa:dqlite/dev
+						sudo apt-get update
+						sudo apt-get install -y libdqlite-dev libsqlite3-dev
+						"""
+				},
+				_#step & {
+					name: "Test"
+					run:  "make airgap/test-${{ matrix.driver }}"
+				},
+			]
+		}
 		"images": _#job & {
 			name:      "Build Images"
 			"runs-on": _#linuxMachine
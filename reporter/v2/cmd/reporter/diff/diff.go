@@ -0,0 +1,82 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"os"
+
+	"emperror.dev/errors"
+	reportdiff "github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/pkg/reporter/diff"
+	"github.com/spf13/cobra"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("reporter_diff_cmd")
+
+var output string
+var tolerance float64
+var exitCode bool
+
+var DiffCmd = &cobra.Command{
+	Use:   "diff <old report> <new report>",
+	Short: "Compare the usage of two reports",
+	Long: `Compares the usage of two reports, each an upload-<uuid>.tar.gz bundle or a directory of its slices,
+in the v1alpha1 or v2alpha1 schema. The usage is matched by metric id, meter definition, namespace and interval,
+summing the usage of the resources of a namespace, and the added, removed and changed usage is printed with its
+absolute and percent delta.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if output != "table" && output != "json" {
+			log.Error(errors.New("output must be table or json"), "invalid output", "output", output)
+			os.Exit(1)
+		}
+
+		oldReport, err := reportdiff.ReadReport(args[0])
+		if err != nil {
+			log.Error(err, "could not read the old report")
+			os.Exit(1)
+		}
+
+		newReport, err := reportdiff.ReadReport(args[1])
+		if err != nil {
+			log.Error(err, "could not read the new report")
+			os.Exit(1)
+		}
+
+		result := reportdiff.Compare(oldReport, newReport, tolerance)
+
+		if output == "json" {
+			err = result.WriteJSON(os.Stdout)
+		} else {
+			err = result.WriteTable(os.Stdout)
+		}
+		if err != nil {
+			log.Error(err, "could not write the differences")
+			os.Exit(1)
+		}
+
+		if exitCode && !result.Equal() {
+			os.Exit(2)
+		}
+
+		os.Exit(0)
+	},
+}
+
+func init() {
+	DiffCmd.Flags().StringVarP(&output, "output", "o", "table", "output format, table or json")
+	DiffCmd.Flags().Float64Var(&tolerance, "tolerance", 0, "absolute difference under which usage is unchanged")
+	DiffCmd.Flags().BoolVar(&exitCode, "exit-code", false, "exit with 2 when the reports differ")
+}
@@ -0,0 +1,157 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/pkg/bundle"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/pkg/reporter"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/pkg/uploaders"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/v2/pkg/marketplace"
+	"github.com/spf13/cobra"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("reporter_import_cmd")
+
+var bundleFile, publicKey, output, tokenFile, marketplaceURL string
+
+var ImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import a bundle exported by the data service",
+	Long: `Verifies the signature and checksums of a bundle exported by a disconnected data service and uploads
+its files to Red Hat Marketplace. The acknowledgement written next to the bundle is carried back and imported
+with the acknowledge command of the data service. Importing the bundle again only uploads the files that failed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if bundleFile == "" || publicKey == "" || tokenFile == "" {
+			log.Error(errors.New("bundle, publickey or tokenfile not provided"), "bundle, publickey or tokenfile not provided")
+			os.Exit(1)
+		}
+
+		if output == "" {
+			output = bundleFile + ".ack.json"
+		}
+
+		key, err := bundle.LoadPublicKey(publicKey)
+		if err != nil {
+			log.Error(err, "could not read the public key")
+			os.Exit(1)
+		}
+
+		uploader, err := newMarketplaceUploader()
+		if err != nil {
+			log.Error(err, "could not create the marketplace uploader")
+			os.Exit(1)
+		}
+
+		var previous *bundle.Acknowledgement
+		if data, err := os.ReadFile(output); err == nil {
+			previous, err = bundle.ParseAcknowledgement(data)
+			if err != nil {
+				log.Error(err, "could not read the acknowledgement of the previous import", "output", output)
+				os.Exit(1)
+			}
+		}
+
+		f, err := os.Open(bundleFile)
+		if err != nil {
+			log.Error(err, "could not open the bundle")
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		r, err := bundle.NewReader(f, key)
+		if err != nil {
+			log.Error(err, "bundle failed verification")
+			os.Exit(1)
+		}
+
+		log.Info("importing bundle", "bundle", r.Manifest.ID, "createdAt", r.Manifest.CreatedAt, "files", len(r.Manifest.Files))
+
+		ack, importErr := bundle.Import(context.Background(), r, uploader, previous)
+
+		// the files delivered before a failure are acknowledged
+		data, err := json.MarshalIndent(ack, "", "  ")
+		if err == nil {
+			err = os.WriteFile(output, data, 0600)
+		}
+		if err != nil {
+			log.Error(err, "could not write the acknowledgement", "output", output)
+			os.Exit(1)
+		}
+
+		if importErr != nil {
+			log.Error(importErr, "import failed", "output", output)
+			os.Exit(1)
+		}
+
+		failed := 0
+		for _, d := range ack.Files {
+			if !d.Delivered() {
+				failed++
+				log.Info("file failed to upload", "id", d.ID, "name", d.Name, "error", d.Error)
+			}
+		}
+
+		log.Info("import complete", "output", output, "delivered", len(ack.Files)-failed, "failed", failed)
+
+		if failed != 0 {
+			os.Exit(1)
+		}
+
+		os.Exit(0)
+	},
+}
+
+// newMarketplaceUploader returns the uploader to the marketplace of the pull
+// secret token, or to marketplaceURL when set
+func newMarketplaceUploader() (uploaders.Uploader, error) {
+	data, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the token file")
+	}
+	token := strings.TrimSpace(string(data))
+
+	url := marketplaceURL
+	if url == "" {
+		claims, err := marketplace.GetJWTTokenClaim(token)
+		if err != nil {
+			return nil, err
+		}
+
+		url = reporter.MktplProductionURL
+		if strings.EqualFold(claims.Env, marketplace.EnvStage) {
+			url = reporter.MktplStageURL
+		}
+	}
+
+	return uploaders.NewMarketplaceUploader(&uploaders.MarketplaceUploaderConfig{
+		URL:   url,
+		Token: token,
+	})
+}
+
+func init() {
+	ImportCmd.Flags().StringVar(&bundleFile, "bundle", "", "bundle written by the export command of the data service")
+	ImportCmd.Flags().StringVar(&publicKey, "publickey", "", "PEM file of the ed25519 public key of the bundle signing key of the data service")
+	ImportCmd.Flags().StringVar(&output, "output", "", "acknowledgement file, defaults to the bundle file with a .ack.json suffix")
+	ImportCmd.Flags().StringVar(&tokenFile, "tokenfile", "", "file holding the Red Hat Marketplace pull secret token")
+	ImportCmd.Flags().StringVar(&marketplaceURL, "marketplaceURL", "", "url of Red Hat Marketplace, defaults to the environment of the pull secret token")
+}
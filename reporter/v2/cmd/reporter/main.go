@@ -19,6 +19,8 @@ import (
 	"os"
 
 	homedir "github.com/mitchellh/go-homedir"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/cmd/reporter/diff"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/cmd/reporter/importer"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/cmd/reporter/reconciler"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/cmd/reporter/report"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/cmd/reporter/sign"
@@ -55,6 +57,8 @@ func init() {
 	rootCmd.AddCommand(sign.SignCmd)
 	rootCmd.AddCommand(verify.VerifyCmd)
 	rootCmd.AddCommand(reconciler.ReconcileCmd)
+	rootCmd.AddCommand(importer.ImportCmd)
+	rootCmd.AddCommand(diff.DiffCmd)
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cobra.yaml)")
 }
 
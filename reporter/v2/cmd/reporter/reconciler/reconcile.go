@@ -59,7 +59,10 @@ var ReconcileCmd = &cobra.Command{
 
 		targets := uploaders.UploaderTargets{}
 		for _, uploadTarget := range uploadTargets {
-			uploadTarget := uploaders.MustParseUploaderTarget(uploadTarget)
+			uploadTarget, err := uploaders.ParseUploaderTarget(uploadTarget)
+			if err != nil {
+				return err
+			}
 			log.Info("upload target", "target set to", uploadTarget.Name())
 
 			switch v := uploadTarget.(type) {
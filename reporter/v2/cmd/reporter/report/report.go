@@ -33,6 +33,7 @@ var name, namespace, cafile, tokenFile string
 var localFilePath, deployedNamespace string
 var dataServiceTokenFile, dataServiceCertFile string
 var reporterSchema string
+var snapshotDir, meterReportFile, marketplaceConfigFile, meterDefinitionsFile string
 var uploadTargets []string
 var local, upload bool
 var retry int
@@ -40,10 +41,17 @@ var retry int
 var ReportCmd = &cobra.Command{
 	Use:   "report",
 	Short: "Run the report",
-	Long:  `Runs the report. Takes it name and namespace as args`,
+	Long: `Runs the report. Takes it name and namespace as args.
+With --from-snapshot the report is generated from a Prometheus TSDB snapshot and the yaml of the MeterReport,
+MarketplaceConfig and MeterDefinitions instead of the cluster, it is written to the localFilePath and not uploaded.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Info("running the report command")
 
+		if snapshotDir != "" {
+			runFromSnapshot()
+			return
+		}
+
 		if name == "" || namespace == "" {
 			log.Error(errors.New("name or namespace not provided"), "namespace or name not provided")
 			os.Exit(1)
@@ -56,7 +64,11 @@ var ReportCmd = &cobra.Command{
 
 		targets := uploaders.UploaderTargets{}
 		for _, uploadTarget := range uploadTargets {
-			uploadTarget := uploaders.MustParseUploaderTarget(uploadTarget)
+			uploadTarget, err := uploaders.ParseUploaderTarget(uploadTarget)
+			if err != nil {
+				log.Error(err, "invalid upload target")
+				os.Exit(1)
+			}
 			log.Info("upload target", "target set to", uploadTarget.Name())
 
 			switch v := uploadTarget.(type) {
@@ -106,6 +118,43 @@ var ReportCmd = &cobra.Command{
 	},
 }
 
+// runFromSnapshot regenerates the report of the meter report file from the
+// snapshot, without cluster access
+func runFromSnapshot() {
+	if meterReportFile == "" || marketplaceConfigFile == "" {
+		log.Error(errors.New("meterReport or marketplaceConfig not provided"), "meterReport and marketplaceConfig are required with a snapshot")
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	cfg := &reporter.Config{
+		OutputDirectory:   localFilePath,
+		ReporterSchema:    reporterSchema,
+		SnapshotDirectory: snapshotDir,
+	}
+	err := cfg.SetDefaults()
+	if err != nil {
+		log.Error(err, "error default config")
+		os.Exit(1)
+	}
+
+	task, err := reporter.NewSnapshotTask(cfg, meterReportFile, marketplaceConfigFile, meterDefinitionsFile)
+	if err != nil {
+		log.Error(err, "couldn't initialize task")
+		os.Exit(1)
+	}
+
+	err = task.Run(ctx)
+	if err != nil {
+		log.Error(err, "error running task")
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
 func init() {
 	ReportCmd.Flags().StringVar(&name, "name", "", "name of the report")
 	ReportCmd.Flags().StringVar(&namespace, "namespace", "", "namespace of the report")
@@ -119,5 +168,9 @@ func init() {
 	ReportCmd.Flags().BoolVar(&upload, "upload", true, "to upload the payload")
 	ReportCmd.Flags().IntVar(&retry, "retry", 24, "number of retries")
 	ReportCmd.Flags().StringVar(&reporterSchema, "reporterSchema", "v1alpha1", "reporter version schema to write")
+	ReportCmd.Flags().StringVar(&snapshotDir, "from-snapshot", "", "prometheus tsdb snapshot, data or block directory to generate the report from instead of the cluster")
+	ReportCmd.Flags().StringVar(&meterReportFile, "meterReport", "", "yaml of the meter report to generate from the snapshot")
+	ReportCmd.Flags().StringVar(&marketplaceConfigFile, "marketplaceConfig", "", "yaml of the marketplace config of the cluster of the snapshot")
+	ReportCmd.Flags().StringVar(&meterDefinitionsFile, "meterDefinitions", "", "yaml of the meter definitions referenced by the meter report without a spec")
 	ReportCmd.Flags().StringVar(&deployedNamespace, "deployedNamespace", "openshift-redhat-marketplace", "namespace where the rhm operator is deployed")
 }
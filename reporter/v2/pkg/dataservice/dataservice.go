@@ -32,8 +32,12 @@ import (
 	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
 	"golang.org/x/oauth2"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/oauth"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -56,6 +60,8 @@ type DataService struct {
 
 	opts  []grpc.CallOption
 	mutex sync.RWMutex
+
+	uploadBackoff wait.Backoff
 }
 
 var _ FileStorage = &DataService{}
@@ -219,8 +225,26 @@ func (d *DataService) DownloadFile(ctx context.Context, info *dataservicev1.File
 	return
 }
 
-const chunkSize = 1024
+const (
+	chunkSize = 1024
+
+	// uploadChunkSize is the size of the chunks of a resumable upload, each
+	// one is kept in memory until the data service has committed it
+	uploadChunkSize = 1024 * 1024
+)
+
+// defaultUploadBackoff retries a chunk for about 30 seconds
+var defaultUploadBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+}
 
+// Upload sends the file through a resumable upload session. A chunk that
+// fails to send is retried from the offset committed by the data service, so
+// a flaky connection does not restart the upload from the beginning. Data
+// services without resumable uploads are sent the file in a single stream.
 func (d *DataService) Upload(ctx context.Context, info *dataservicev1.FileInfo, reader io.Reader) (id string, err error) {
 	if info == nil {
 		err = errors.New("info provided is empty")
@@ -245,6 +269,152 @@ func (d *DataService) Upload(ctx context.Context, info *dataservicev1.FileInfo,
 		info.Id = resp.Info.Id
 	}
 
+	start, err := d.fileServer.StartUpload(ctx, &fileserver.StartUploadRequest{
+		Info: info,
+	}, d.opts...)
+
+	if status.Code(err) == codes.Unimplemented {
+		logger.Info("resumable upload is not supported, streaming the file")
+		return d.uploadStream(ctx, info, reader)
+	}
+
+	if err != nil {
+		logger.Error(err, "Failed to StartUpload request")
+		return
+	}
+
+	session := start.SessionId
+	offset := start.CommittedOffset
+	buffer := make([]byte, uploadChunkSize)
+
+	for {
+		n, readErr := io.ReadFull(reader, buffer)
+
+		if n > 0 {
+			offset, err = d.putChunk(ctx, session, offset, buffer[:n])
+			if err != nil {
+				logger.Error(err, "Failed to PutChunk request", "session", session, "offset", offset)
+				d.abortUpload(session)
+				return
+			}
+		}
+
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+
+		if readErr != nil {
+			err = readErr
+			logger.Error(err, "failed to read file")
+			d.abortUpload(session)
+			return
+		}
+	}
+
+	// finalizing again returns the same file if the response is lost
+	var res *fileserver.FinalizeUploadResponse
+	err = retry.OnError(d.backoff(), d.retryable(ctx), func() (err error) {
+		res, err = d.fileServer.FinalizeUpload(ctx, &fileserver.FinalizeUploadRequest{
+			SessionId: session,
+		}, d.opts...)
+		return
+	})
+
+	if err != nil {
+		logger.Error(err, "Failed to FinalizeUpload request", "session", session)
+		return
+	}
+
+	id = res.Id
+
+	logger.Info("airgap upload response", "response", res)
+	return
+}
+
+// putChunk sends a chunk starting at offset until the data service has
+// committed all of it and returns the new committed offset.
+func (d *DataService) putChunk(ctx context.Context, session string, offset uint64, chunk []byte) (uint64, error) {
+	end := offset + uint64(len(chunk))
+	committed := offset
+
+	err := retry.OnError(d.backoff(), d.retryable(ctx), func() error {
+		res, err := d.fileServer.PutChunk(ctx, &fileserver.PutChunkRequest{
+			SessionId: session,
+			Offset:    committed,
+			ChunkData: chunk[committed-offset:],
+		}, d.opts...)
+
+		if err == nil {
+			committed = res.CommittedOffset
+			return nil
+		}
+
+		// part of the chunk may be committed even though the request failed,
+		// resume from the offset committed by the data service
+		if st, statusErr := d.fileServer.GetUploadStatus(ctx, &fileserver.GetUploadStatusRequest{
+			SessionId: session,
+		}, d.opts...); statusErr == nil &&
+			st.CommittedOffset >= offset && st.CommittedOffset <= end {
+			committed = st.CommittedOffset
+		}
+
+		logger.Info("retrying chunk", "session", session, "offset", committed, "err", err)
+		return err
+	})
+
+	if err != nil {
+		return committed, err
+	}
+
+	if committed != end {
+		return committed, errors.NewWithDetails("chunk was not fully committed", "session", session, "committed", committed, "end", end)
+	}
+
+	return committed, nil
+}
+
+// abortUpload discards a failed upload, the data service would otherwise
+// keep its content until the session expires.
+func (d *DataService) abortUpload(session string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := d.fileServer.AbortUpload(ctx, &fileserver.AbortUploadRequest{SessionId: session}, d.opts...)
+	if err != nil {
+		logger.Error(err, "failed to abort upload", "session", session)
+	}
+}
+
+func (d *DataService) backoff() wait.Backoff {
+	if d.uploadBackoff.Steps == 0 {
+		return defaultUploadBackoff
+	}
+	return d.uploadBackoff
+}
+
+// retryable returns true for errors of a connection or a concurrent request,
+// as long as ctx is not done
+func (d *DataService) retryable(ctx context.Context) func(error) bool {
+	return func(err error) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		switch status.Code(err) {
+		case codes.Unavailable,
+			codes.DeadlineExceeded,
+			codes.Aborted,
+			codes.ResourceExhausted,
+			codes.OutOfRange:
+			return true
+		}
+
+		return false
+	}
+}
+
+// uploadStream sends the file in a single UploadFile stream.
+func (d *DataService) uploadStream(ctx context.Context, info *dataservicev1.FileInfo, reader io.Reader) (id string, err error) {
 	var upload fileserver.FileServer_UploadFileClient
 	upload, err = d.fileServer.UploadFile(ctx, d.opts...)
 
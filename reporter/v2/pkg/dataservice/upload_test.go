@@ -0,0 +1,216 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataservice
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	dataservicev1 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1/fileserver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// flakyFileServer commits the chunks it receives but loses every other
+// response, as a data service behind a flaky link would.
+type flakyFileServer struct {
+	fileserver.UnimplementedFileServerServer
+
+	mu        sync.Mutex
+	resumable bool
+	content   bytes.Buffer
+	calls     int
+	finalized bool
+	aborted   bool
+	streamed  bool
+}
+
+func (f *flakyFileServer) lose() error {
+	f.calls++
+	if f.calls%2 == 0 {
+		return status.Error(codes.Unavailable, "connection lost")
+	}
+	return nil
+}
+
+func (f *flakyFileServer) GetFile(context.Context, *fileserver.GetFileRequest) (*fileserver.GetFileResponse, error) {
+	return nil, status.Error(codes.NotFound, "not found")
+}
+
+func (f *flakyFileServer) StartUpload(context.Context, *fileserver.StartUploadRequest) (*fileserver.StartUploadResponse, error) {
+	if !f.resumable {
+		return nil, status.Error(codes.Unimplemented, "not implemented")
+	}
+	return &fileserver.StartUploadResponse{SessionId: "session"}, nil
+}
+
+func (f *flakyFileServer) PutChunk(_ context.Context, req *fileserver.PutChunkRequest) (*fileserver.PutChunkResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	committed := uint64(f.content.Len())
+	if req.Offset > committed {
+		return nil, status.Error(codes.OutOfRange, "offset past committed offset")
+	}
+
+	if skip := committed - req.Offset; skip < uint64(len(req.ChunkData)) {
+		f.content.Write(req.ChunkData[skip:])
+	}
+
+	if err := f.lose(); err != nil {
+		return nil, err
+	}
+	return &fileserver.PutChunkResponse{CommittedOffset: uint64(f.content.Len())}, nil
+}
+
+func (f *flakyFileServer) GetUploadStatus(context.Context, *fileserver.GetUploadStatusRequest) (*fileserver.GetUploadStatusResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &fileserver.GetUploadStatusResponse{CommittedOffset: uint64(f.content.Len())}, nil
+}
+
+func (f *flakyFileServer) FinalizeUpload(context.Context, *fileserver.FinalizeUploadRequest) (*fileserver.FinalizeUploadResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.finalized = true
+	if err := f.lose(); err != nil {
+		return nil, err
+	}
+	return &fileserver.FinalizeUploadResponse{Id: "1", BytesReceived: uint64(f.content.Len())}, nil
+}
+
+func (f *flakyFileServer) AbortUpload(context.Context, *fileserver.AbortUploadRequest) (*fileserver.AbortUploadResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.aborted = true
+	return &fileserver.AbortUploadResponse{}, nil
+}
+
+func (f *flakyFileServer) UploadFile(stream fileserver.FileServer_UploadFileServer) error {
+	f.streamed = true
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&fileserver.UploadFileResponse{Id: "2", BytesReceived: uint64(f.content.Len())})
+		}
+		if err != nil {
+			return err
+		}
+		f.content.Write(req.GetChunkData())
+	}
+}
+
+var _ = Describe("Upload", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+		server *grpc.Server
+		conn   *grpc.ClientConn
+		fake   *flakyFileServer
+		sut    *DataService
+
+		content []byte
+		info    *dataservicev1.FileInfo
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+
+		fake = &flakyFileServer{resumable: true}
+		listener := bufconn.Listen(1024 * 1024)
+		server = grpc.NewServer()
+		fileserver.RegisterFileServerServer(server, fake)
+		go server.Serve(listener)
+
+		var err error
+		conn, err = grpc.DialContext(ctx, "bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		Expect(err).To(Succeed())
+
+		sut = &DataService{
+			fileServer:    fileserver.NewFileServerClient(conn),
+			uploadBackoff: wait.Backoff{Steps: 5, Duration: time.Millisecond},
+		}
+
+		content = make([]byte, 3*uploadChunkSize+512)
+		_, err = rand.Read(content)
+		Expect(err).To(Succeed())
+
+		info = &dataservicev1.FileInfo{
+			Name:       "report.tar.gz",
+			Source:     "redhat-marketplace",
+			SourceType: "report",
+		}
+	})
+
+	AfterEach(func() {
+		conn.Close()
+		server.Stop()
+		cancel()
+	})
+
+	It("should resume chunks after lost responses", func() {
+		id, err := sut.Upload(ctx, info, bytes.NewReader(content))
+		Expect(err).To(Succeed())
+		Expect(id).To(Equal("1"))
+
+		Expect(fake.finalized).To(BeTrue())
+		Expect(fake.aborted).To(BeFalse())
+		Expect(bytes.Equal(fake.content.Bytes(), content)).To(BeTrue())
+	})
+
+	It("should abort the upload when the file can't be read", func() {
+		reader := io.MultiReader(bytes.NewReader(content[:uploadChunkSize+10]), &failingReader{})
+
+		_, err := sut.Upload(ctx, info, reader)
+		Expect(err).To(HaveOccurred())
+		Expect(fake.aborted).To(BeTrue())
+		Expect(fake.finalized).To(BeFalse())
+	})
+
+	It("should stream the file to a data service without resumable uploads", func() {
+		fake.resumable = false
+
+		id, err := sut.Upload(ctx, info, bytes.NewReader(content))
+		Expect(err).To(Succeed())
+		Expect(id).To(Equal("2"))
+
+		Expect(fake.streamed).To(BeTrue())
+		Expect(bytes.Equal(fake.content.Bytes(), content)).To(BeTrue())
+	})
+})
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
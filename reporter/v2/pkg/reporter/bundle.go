@@ -22,11 +22,22 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"emperror.dev/errors"
 )
 
 func TargzFolder(srcFolder, destFileName string) error {
+	return targzFolder(srcFolder, destFileName, nil)
+}
+
+// TargzFolderAt is TargzFolder with every entry stamped with modTime and no
+// owner, the same files always give the same tarball.
+func TargzFolderAt(srcFolder, destFileName string, modTime time.Time) error {
+	return targzFolder(srcFolder, destFileName, &modTime)
+}
+
+func targzFolder(srcFolder, destFileName string, modTime *time.Time) error {
 	f, err := os.Create(destFileName)
 
 	if err != nil {
@@ -35,13 +46,19 @@ func TargzFolder(srcFolder, destFileName string) error {
 
 	defer f.Close()
 
-	return Tar(srcFolder, f)
+	return tarFolder(srcFolder, modTime, f)
 }
 
 // Tar takes a source and variable writers and walks 'source' writing each file
 // found to the tar writer; the purpose for accepting multiple writers is to allow
 // for multiple outputs (for example a file, or md5 hash)
 func Tar(src string, writers ...io.Writer) error {
+	return tarFolder(src, nil, writers...)
+}
+
+// tarFolder is Tar, with modTime set the headers do not depend on the files
+// written: the entries have modTime, fixed permissions and no owner.
+func tarFolder(src string, modTime *time.Time, writers ...io.Writer) error {
 	// ensure the src actually exists before trying to tar it
 	if _, err := os.Stat(src); err != nil {
 		return fmt.Errorf("unable to tar files - %v", err.Error())
@@ -76,6 +93,16 @@ func Tar(src string, writers ...io.Writer) error {
 		// update the name to correctly reflect the desired destination when untaring
 		header.Name = strings.TrimPrefix(strings.ReplaceAll(file, src, ""), string(filepath.Separator))
 
+		if modTime != nil {
+			header.ModTime = modTime.UTC().Truncate(time.Second)
+			header.AccessTime = time.Time{}
+			header.ChangeTime = time.Time{}
+			header.Mode = 0644
+			header.Uid, header.Gid = 0, 0
+			header.Uname, header.Gname = "", ""
+			header.Format = tar.FormatUSTAR
+		}
+
 		// write the header
 		if err = tw.WriteHeader(header); err != nil {
 			return errors.Wrap(err, "failed to write header")
@@ -51,6 +51,10 @@ type Config struct {
 	CipherSuites   []uint16
 	MinVersion     uint16
 
+	// SnapshotDirectory is the Prometheus TSDB snapshot the report is
+	// generated from, without cluster access, when set
+	SnapshotDirectory string
+
 	K8sRestConfig *rest.Config
 }
 
@@ -76,7 +80,7 @@ func (c *Config) SetDefaults() error {
 		c.UploaderTargets = uploaders.UploaderTargets{&dataservice.DataService{}}
 	}
 
-	if c.K8sRestConfig == nil {
+	if c.K8sRestConfig == nil && c.SnapshotDirectory == "" {
 		var err error
 		c.K8sRestConfig, err = kconfig.GetConfig()
 		if err != nil {
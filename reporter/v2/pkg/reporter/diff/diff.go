@@ -0,0 +1,178 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Change is how the usage of a key changed between reports
+type Change string
+
+const (
+	Added   Change = "added"
+	Removed Change = "removed"
+	Changed Change = "changed"
+)
+
+// Difference is the usage of a key that differs between reports
+type Difference struct {
+	Key
+	Change   Change  `json:"change"`
+	OldValue float64 `json:"oldValue"`
+	NewValue float64 `json:"newValue"`
+	// Delta is the new value minus the old value
+	Delta float64 `json:"delta"`
+	// Percent is the delta relative to the old value, it is not set when the
+	// old value is 0
+	Percent *float64 `json:"percent,omitempty"`
+}
+
+// Result is the comparison of two reports
+type Result struct {
+	Added       int          `json:"added"`
+	Removed     int          `json:"removed"`
+	Changed     int          `json:"changed"`
+	Unchanged   int          `json:"unchanged"`
+	Differences []Difference `json:"differences"`
+}
+
+// Equal reports whether the usage of the reports is the same
+func (r *Result) Equal() bool {
+	return len(r.Differences) == 0
+}
+
+// Compare returns the usage added, removed and changed from old to new. The
+// usage of a key present in both reports is changed when the values differ
+// by more than tolerance.
+func Compare(old, new *ReportUsage, tolerance float64) *Result {
+	result := &Result{Differences: []Difference{}}
+
+	for key, oldValue := range old.Usage {
+		newValue, ok := new.Usage[key]
+		if !ok {
+			result.Removed++
+			result.Differences = append(result.Differences, newDifference(key, Removed, oldValue, 0))
+			continue
+		}
+
+		if math.Abs(newValue-oldValue) <= tolerance {
+			result.Unchanged++
+			continue
+		}
+
+		result.Changed++
+		result.Differences = append(result.Differences, newDifference(key, Changed, oldValue, newValue))
+	}
+
+	for key, newValue := range new.Usage {
+		if _, ok := old.Usage[key]; !ok {
+			result.Added++
+			result.Differences = append(result.Differences, newDifference(key, Added, 0, newValue))
+		}
+	}
+
+	sort.Slice(result.Differences, func(i, j int) bool {
+		return result.Differences[i].Key.less(result.Differences[j].Key)
+	})
+
+	return result
+}
+
+func newDifference(key Key, change Change, oldValue, newValue float64) Difference {
+	d := Difference{
+		Key:      key,
+		Change:   change,
+		OldValue: oldValue,
+		NewValue: newValue,
+		Delta:    newValue - oldValue,
+	}
+
+	if oldValue != 0 {
+		percent := d.Delta / math.Abs(oldValue) * 100
+		d.Percent = &percent
+	}
+
+	return d
+}
+
+func (k Key) less(o Key) bool {
+	switch {
+	case !k.IntervalStart.Equal(o.IntervalStart):
+		return k.IntervalStart.Before(o.IntervalStart)
+	case !k.IntervalEnd.Equal(o.IntervalEnd):
+		return k.IntervalEnd.Before(o.IntervalEnd)
+	case k.MeterDefinition != o.MeterDefinition:
+		return k.MeterDefinition < o.MeterDefinition
+	case k.Namespace != o.Namespace:
+		return k.Namespace < o.Namespace
+	default:
+		return k.MetricID < o.MetricID
+	}
+}
+
+// WriteJSON writes the result as indented json
+func (r *Result) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return errors.WithStack(encoder.Encode(r))
+}
+
+// WriteTable writes the differences as a table followed by a summary
+func (r *Result) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "CHANGE\tMETER DEFINITION\tNAMESPACE\tMETRIC\tSTART\tEND\tOLD\tNEW\tDELTA\tPERCENT")
+	for _, d := range r.Differences {
+		percent := "-"
+		if d.Percent != nil {
+			percent = strconv.FormatFloat(*d.Percent, 'f', 2, 64) + "%"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			d.Change,
+			d.MeterDefinition,
+			d.Namespace,
+			d.MetricID,
+			d.IntervalStart.Format(time.RFC3339),
+			d.IntervalEnd.Format(time.RFC3339),
+			formatValue(d.OldValue),
+			formatValue(d.NewValue),
+			formatValue(d.Delta),
+			percent,
+		)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d added, %d removed, %d changed, %d unchanged\n",
+		r.Added, r.Removed, r.Changed, r.Unchanged)
+	return errors.WithStack(err)
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
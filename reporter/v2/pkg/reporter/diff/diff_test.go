@@ -0,0 +1,249 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/pkg/reporter/schema/common"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/pkg/reporter/schema/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/pkg/reporter/schema/v2alpha1"
+)
+
+type usage struct {
+	namespace, pod, metric string
+	value                  float64
+}
+
+var _ = Describe("Diff", func() {
+	var (
+		dir   string
+		start = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		end   = start.Add(time.Hour)
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "diff")
+		Expect(err).To(Succeed())
+
+		DeferCleanup(os.RemoveAll, dir)
+	})
+
+	v2Slice := func(usages ...usage) []byte {
+		slice := &v2alpha1.MarketplaceReportSlice{
+			Metadata: &v2alpha1.SourceMetadata{RhmClusterID: "cluster"},
+		}
+		for _, u := range usages {
+			slice.Metrics = append(slice.Metrics, &v2alpha1.MarketplaceReportData{
+				EventID:       u.namespace + u.pod,
+				IntervalStart: start.UnixMilli(),
+				IntervalEnd:   end.UnixMilli(),
+				AdditionalAttributes: map[string]interface{}{
+					"meter_def_name":      "foo",
+					"meter_def_namespace": "bar",
+					"namespace":           u.namespace,
+					"pod":                 u.pod,
+				},
+				MeasuredUsage: []v2alpha1.MeasuredUsage{{MetricID: u.metric, Value: u.value}},
+			})
+		}
+
+		data, err := json.Marshal(slice)
+		Expect(err).To(Succeed())
+		return data
+	}
+
+	v1Slice := func(usages ...usage) []byte {
+		slice := &v1alpha1.MarketplaceReportSlice{}
+		for _, u := range usages {
+			slice.Metrics = append(slice.Metrics, &v1alpha1.MarketplaceReportData{
+				MetricID:          u.namespace + u.pod,
+				IntervalStart:     common.Time(start),
+				IntervalEnd:       common.Time(end),
+				ResourceNamespace: u.namespace,
+				AdditionalLabels: map[string]interface{}{
+					"meter_def_name":      "foo",
+					"meter_def_namespace": "bar",
+					"pod":                 u.pod,
+				},
+				MetricsExtended: []v1alpha1.MarketplaceMetric{
+					{Label: u.metric, Value: formatValue(u.value)},
+				},
+			})
+		}
+
+		data, err := json.Marshal(slice)
+		Expect(err).To(Succeed())
+		return data
+	}
+
+	writeDir := func(name string, slices ...[]byte) string {
+		reportDir := filepath.Join(dir, name)
+		Expect(os.Mkdir(reportDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(reportDir, "manifest.json"), []byte(`{"version":"1","type":"accountMetrics"}`), 0600)).To(Succeed())
+
+		for i, slice := range slices {
+			Expect(os.WriteFile(filepath.Join(reportDir, string(rune('a'+i))+".json"), slice, 0600)).To(Succeed())
+		}
+		return reportDir
+	}
+
+	writeTarGz := func(name string, slices ...[]byte) string {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+
+		files := map[string][]byte{"metadata.json": []byte(`{}`)}
+		for i, slice := range slices {
+			files[string(rune('a'+i))+".json"] = slice
+		}
+
+		for name, data := range files {
+			Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), Typeflag: tar.TypeReg})).To(Succeed())
+			_, err := tw.Write(data)
+			Expect(err).To(Succeed())
+		}
+		Expect(tw.Close()).To(Succeed())
+		Expect(gz.Close()).To(Succeed())
+
+		file := filepath.Join(dir, name)
+		Expect(os.WriteFile(file, buf.Bytes(), 0600)).To(Succeed())
+		return file
+	}
+
+	key := func(namespace, metric string) Key {
+		return Key{
+			MetricID:        metric,
+			MeterDefinition: "bar/foo",
+			Namespace:       namespace,
+			IntervalStart:   start,
+			IntervalEnd:     end,
+		}
+	}
+
+	It("should compare the usage of two reports", func() {
+		oldReport, err := ReadReport(writeDir("old",
+			v2Slice(usage{"ns1", "a", "cpu", 1}, usage{"ns1", "b", "cpu", 2}),
+			v2Slice(usage{"ns2", "a", "cpu", 5}, usage{"ns4", "a", "cpu", 7}),
+		))
+		Expect(err).To(Succeed())
+		Expect(oldReport.Slices).To(Equal(2))
+		Expect(oldReport.Events).To(Equal(4))
+		Expect(oldReport.Usage).To(HaveKeyWithValue(key("ns1", "cpu"), 3.0))
+
+		newReport, err := ReadReport(writeDir("new",
+			v2Slice(usage{"ns1", "a", "cpu", 4}, usage{"ns3", "a", "cpu", 1}, usage{"ns4", "a", "cpu", 7}),
+		))
+		Expect(err).To(Succeed())
+
+		result := Compare(oldReport, newReport, 0)
+		Expect(result.Equal()).To(BeFalse())
+		Expect(result.Added).To(Equal(1))
+		Expect(result.Removed).To(Equal(1))
+		Expect(result.Changed).To(Equal(1))
+		Expect(result.Unchanged).To(Equal(1))
+
+		Expect(result.Differences).To(HaveLen(3))
+
+		changed := result.Differences[0]
+		Expect(changed.Key).To(Equal(key("ns1", "cpu")))
+		Expect(changed.Change).To(Equal(Changed))
+		Expect(changed.OldValue).To(Equal(3.0))
+		Expect(changed.NewValue).To(Equal(4.0))
+		Expect(changed.Delta).To(Equal(1.0))
+		Expect(*changed.Percent).To(BeNumerically("~", 33.33, 0.01))
+
+		removed := result.Differences[1]
+		Expect(removed.Key).To(Equal(key("ns2", "cpu")))
+		Expect(removed.Change).To(Equal(Removed))
+		Expect(removed.Delta).To(Equal(-5.0))
+		Expect(*removed.Percent).To(Equal(-100.0))
+
+		added := result.Differences[2]
+		Expect(added.Key).To(Equal(key("ns3", "cpu")))
+		Expect(added.Change).To(Equal(Added))
+		Expect(added.Delta).To(Equal(1.0))
+		Expect(added.Percent).To(BeNil())
+
+		By("ignoring changes within the tolerance")
+		result = Compare(oldReport, newReport, 1)
+		Expect(result.Changed).To(Equal(0))
+		Expect(result.Unchanged).To(Equal(2))
+
+		By("writing a table")
+		var buf bytes.Buffer
+		Expect(Compare(oldReport, newReport, 0).WriteTable(&buf)).To(Succeed())
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(lines).To(HaveLen(6))
+		Expect(strings.Fields(lines[0])).To(HaveLen(11))
+		Expect(strings.Fields(lines[1])).To(Equal([]string{
+			"changed", "bar/foo", "ns1", "cpu", "2023-01-01T00:00:00Z", "2023-01-01T01:00:00Z", "3", "4", "1", "33.33%",
+		}))
+		Expect(strings.Fields(lines[3])).To(ContainElement("-"))
+		Expect(lines[5]).To(Equal("1 added, 1 removed, 1 changed, 1 unchanged"))
+
+		By("writing json")
+		buf.Reset()
+		Expect(Compare(oldReport, newReport, 0).WriteJSON(&buf)).To(Succeed())
+
+		decoded := map[string]interface{}{}
+		Expect(json.Unmarshal(buf.Bytes(), &decoded)).To(Succeed())
+		Expect(decoded).To(HaveKeyWithValue("added", 1.0))
+		Expect(decoded["differences"]).To(HaveLen(3))
+		Expect(decoded["differences"].([]interface{})[0]).To(And(
+			HaveKeyWithValue("change", "changed"),
+			HaveKeyWithValue("meterDefinition", "bar/foo"),
+			HaveKeyWithValue("intervalStart", "2023-01-01T00:00:00Z"),
+			HaveKeyWithValue("delta", 1.0),
+		))
+	})
+
+	It("should compare reports of different schemas", func() {
+		usages := []usage{{"ns1", "a", "cpu", 1.5}, {"ns1", "b", "cpu", 2}, {"ns2", "a", "memory", 5}}
+
+		v1Report, err := ReadReport(writeTarGz("upload-v1.tar.gz", v1Slice(usages...)))
+		Expect(err).To(Succeed())
+		Expect(v1Report.Usage).To(HaveKeyWithValue(key("ns1", "cpu"), 3.5))
+
+		v2Report, err := ReadReport(writeDir("v2", v2Slice(usages...)))
+		Expect(err).To(Succeed())
+
+		result := Compare(v1Report, v2Report, 0)
+		Expect(result.Equal()).To(BeTrue())
+		Expect(result.Unchanged).To(Equal(2))
+	})
+
+	It("should reject reports without slices", func() {
+		_, err := ReadReport(writeDir("empty"))
+		Expect(err).To(MatchError(ErrNoSlices))
+
+		_, err = ReadReport(writeDir("unknown", []byte(`{"foo":"bar"}`)))
+		Expect(err).To(MatchError(ErrUnknownSchema))
+
+		_, err = ReadReport(filepath.Join(dir, "missing"))
+		Expect(err).To(MatchError(os.ErrNotExist))
+	})
+})
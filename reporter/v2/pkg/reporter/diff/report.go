@@ -0,0 +1,262 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diff compares the usage of two reports written in the v1alpha1 or
+// v2alpha1 schema.
+package diff
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/pkg/reporter/schema/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/pkg/reporter/schema/v2alpha1"
+)
+
+const (
+	ErrUnknownSchema = errors.Sentinel("report slice schema is not v1alpha1 or v2alpha1")
+	ErrNoSlices      = errors.Sentinel("no report slices found")
+)
+
+// Key identifies the usage of a metric compared between reports
+type Key struct {
+	// MetricID is the metricId of the measured usage, the label in v1alpha1
+	MetricID string `json:"metricId"`
+	// MeterDefinition is the namespace/name of the meter definition
+	MeterDefinition string `json:"meterDefinition"`
+	// Namespace is the namespace of the measured resource
+	Namespace     string    `json:"namespace"`
+	IntervalStart time.Time `json:"intervalStart"`
+	IntervalEnd   time.Time `json:"intervalEnd"`
+}
+
+// ReportUsage is the usage of a report by key. The usage of the events sharing a
+// key, such as the pods of a namespace, is summed.
+type ReportUsage struct {
+	Usage map[Key]float64
+	// Slices is the number of slices read
+	Slices int
+	// Events is the number of measured usages read
+	Events int
+}
+
+func newReport() *ReportUsage {
+	return &ReportUsage{Usage: map[Key]float64{}}
+}
+
+func (r *ReportUsage) add(key Key, value float64) {
+	r.Usage[key] += value
+	r.Events++
+}
+
+// ReadReport reads the report slices of an upload-<uuid>.tar.gz bundle or of
+// a directory holding them. The manifest and metadata files are skipped.
+func ReadReport(path string) (*ReportUsage, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var report *ReportUsage
+	if fi.IsDir() {
+		report, err = readDir(path)
+	} else {
+		report, err = readTarGz(path)
+	}
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to read report", "path", path)
+	}
+
+	if report.Slices == 0 {
+		return nil, errors.WithDetails(ErrNoSlices, "path", path)
+	}
+
+	return report, nil
+}
+
+func readDir(dir string) (*ReportUsage, error) {
+	report := newReport()
+
+	err := filepath.Walk(dir, func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() || !isSlice(file) {
+			return nil
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		return errors.WithDetails(report.readSlice(data), "file", file)
+	})
+
+	return report, err
+}
+
+func readTarGz(file string) (*ReportUsage, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer gz.Close()
+
+	report := newReport()
+	tr := tar.NewReader(gz)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return report, nil
+		}
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		if header.Typeflag != tar.TypeReg || !isSlice(header.Name) {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		if err := report.readSlice(data); err != nil {
+			return nil, errors.WithDetails(err, "file", header.Name)
+		}
+	}
+}
+
+// isSlice reports whether the file of a report is a slice of its metrics
+func isSlice(file string) bool {
+	name := filepath.Base(file)
+	return strings.HasSuffix(name, ".json") && name != "manifest.json" && name != "metadata.json"
+}
+
+// readSlice adds the usage of a slice in either schema
+func (r *ReportUsage) readSlice(data []byte) error {
+	var slice struct {
+		Data    json.RawMessage `json:"data"`
+		Metrics json.RawMessage `json:"metrics"`
+	}
+	if err := json.Unmarshal(data, &slice); err != nil {
+		return errors.WithStack(err)
+	}
+	r.Slices++
+
+	switch {
+	case slice.Data != nil:
+		v2 := v2alpha1.MarketplaceReportSlice{}
+		if err := json.Unmarshal(data, &v2); err != nil {
+			return errors.WithStack(err)
+		}
+		r.addV2(&v2)
+	case slice.Metrics != nil:
+		v1 := v1alpha1.MarketplaceReportSlice{}
+		if err := json.Unmarshal(data, &v1); err != nil {
+			return errors.WithStack(err)
+		}
+		return r.addV1(&v1)
+	default:
+		return ErrUnknownSchema
+	}
+
+	return nil
+}
+
+func (r *ReportUsage) addV2(slice *v2alpha1.MarketplaceReportSlice) {
+	for _, event := range slice.Metrics {
+		for _, usage := range event.MeasuredUsage {
+			// the attributes of the usage have priority over the event's
+			attrs := attributes{usage.AdditionalAttributes, event.AdditionalAttributes}
+
+			r.add(Key{
+				MetricID:        usage.MetricID,
+				MeterDefinition: attrs.meterDefinition(),
+				Namespace:       attrs.get("namespace"),
+				IntervalStart:   time.UnixMilli(event.IntervalStart).UTC(),
+				IntervalEnd:     time.UnixMilli(event.IntervalEnd).UTC(),
+			}, usage.Value)
+		}
+	}
+}
+
+func (r *ReportUsage) addV1(slice *v1alpha1.MarketplaceReportSlice) error {
+	for _, event := range slice.Metrics {
+		for _, metric := range event.MetricsExtended {
+			value, err := strconv.ParseFloat(metric.Value, 64)
+			if err != nil {
+				return errors.WrapIfWithDetails(err, "metric value is not a number", "metric_id", event.MetricID, "label", metric.Label)
+			}
+
+			attrs := attributes{metric.Labels, event.AdditionalLabels}
+
+			namespace := event.ResourceNamespace
+			if namespace == "" {
+				namespace = attrs.get("namespace")
+			}
+
+			r.add(Key{
+				MetricID:        metric.Label,
+				MeterDefinition: attrs.meterDefinition(),
+				Namespace:       namespace,
+				IntervalStart:   time.Time(event.IntervalStart).UTC(),
+				IntervalEnd:     time.Time(event.IntervalEnd).UTC(),
+			}, value)
+		}
+	}
+
+	return nil
+}
+
+// attributes are looked up in order
+type attributes []map[string]interface{}
+
+func (a attributes) get(name string) string {
+	for _, attrs := range a {
+		if v, ok := attrs[name]; ok && v != nil {
+			if s, ok := v.(string); ok {
+				return s
+			}
+			data, _ := json.Marshal(v)
+			return string(data)
+		}
+	}
+	return ""
+}
+
+func (a attributes) meterDefinition() string {
+	name := a.get("meter_def_name")
+	if name == "" {
+		return ""
+	}
+	return a.get("meter_def_namespace") + "/" + name
+}
@@ -16,6 +16,7 @@ package reporter
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -296,7 +297,20 @@ func (r *MarketplaceReporter) ProduceMeterDefinitions(
 		}
 	}
 
-	for key, val := range definitionSet {
+	// sent in a stable order so the report of a snapshot is reproducible
+	keys := make([]types.NamespacedName, 0, len(definitionSet))
+	for key := range definitionSet {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		return keys[i].Name < keys[j].Name
+	})
+
+	for _, key := range keys {
+		val := definitionSet[key]
 		logger.V(4).Info("sending", "key", key)
 		for _, query := range val {
 			// if RHM/Software Central account does not exist,
@@ -62,8 +62,7 @@ func (t *Time) UnmarshalText(data []byte) error {
 		return err
 	}
 
-	t2 := Time(t1)
-	t = &t2
+	*t = Time(t1)
 	return nil
 }
 
@@ -0,0 +1,270 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/google/uuid"
+	"github.com/gotidy/ptr"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/pkg/snapshot"
+	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/v2/apis/marketplace/v1alpha1"
+	marketplacev1beta1 "github.com/redhat-marketplace/redhat-marketplace-operator/v2/apis/marketplace/v1beta1"
+	. "github.com/redhat-marketplace/redhat-marketplace-operator/v2/pkg/prometheus"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const ErrMeterDefinitionNotFound = errors.Sentinel("meter definition not found")
+
+// SnapshotTask generates the report of a MeterReport from a Prometheus TSDB
+// snapshot instead of the cluster Prometheus. The MeterReport, the
+// MarketplaceConfig and the MeterDefinitions are read from their yaml, so the
+// report of a cluster can be regenerated without access to it. The queries
+// run one at a time and the report is written deterministically, generating
+// it twice from the same snapshot writes the same files.
+type SnapshotTask struct {
+	Config            *Config
+	MeterReport       *marketplacev1alpha1.MeterReport
+	MarketplaceConfig *marketplacev1alpha1.MarketplaceConfig
+	MeterDefinitions  []marketplacev1beta1.MeterDefinition
+}
+
+var _ TaskRun = &SnapshotTask{}
+
+// NewSnapshotTask reads the MeterReport, MarketplaceConfig and
+// MeterDefinitions files of the report of config.SnapshotDirectory. The
+// MeterDefinitions are only needed for the references of the MeterReport
+// without a spec, meterDefinitionsFile may be empty.
+func NewSnapshotTask(
+	config *Config,
+	meterReportFile, marketplaceConfigFile, meterDefinitionsFile string,
+) (*SnapshotTask, error) {
+	if config.SnapshotDirectory == "" {
+		return nil, errors.New("snapshot directory not provided")
+	}
+
+	task := &SnapshotTask{
+		Config:            config,
+		MeterReport:       &marketplacev1alpha1.MeterReport{},
+		MarketplaceConfig: &marketplacev1alpha1.MarketplaceConfig{},
+	}
+
+	if err := decodeFile(meterReportFile, task.MeterReport); err != nil {
+		return nil, errors.WrapIf(err, "failed to read the meter report")
+	}
+
+	if _, err := uuid.Parse(task.MeterReport.Spec.ReportUUID); err != nil {
+		return nil, errors.WrapIfWithDetails(err, "meter report has no valid reportUUID", "name", task.MeterReport.Name)
+	}
+
+	if err := decodeFile(marketplaceConfigFile, task.MarketplaceConfig); err != nil {
+		return nil, errors.WrapIf(err, "failed to read the marketplace config")
+	}
+
+	if meterDefinitionsFile != "" {
+		var err error
+		task.MeterDefinitions, err = decodeMeterDefinitions(meterDefinitionsFile)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to read the meter definitions")
+		}
+	}
+
+	// the snapshot returns the same result on every try, and the metrics are
+	// processed in the order of the queries
+	config.Retry = ptr.Int(0)
+	config.MaxRoutines = ptr.Int(1)
+
+	return task, nil
+}
+
+func (t *SnapshotTask) Run(ctx context.Context) error {
+	logger.Info("generating report from snapshot", "snapshot", t.Config.SnapshotDirectory, "report", t.MeterReport.Name)
+
+	api, err := snapshot.Open(t.Config.SnapshotDirectory)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	meterDefinitions, err := t.meterDefinitionReferences()
+	if err != nil {
+		return err
+	}
+
+	dataBuilder, err := ProvideDataBuilder(t.Config, logger)
+	if err != nil {
+		return err
+	}
+
+	reportWriter, err := ProvideWriter(t.Config, t.MarketplaceConfig, logger)
+	if err != nil {
+		return err
+	}
+
+	reporter, err := NewMarketplaceReporter(
+		t.Config,
+		t.MeterReport,
+		t.MarketplaceConfig,
+		&PrometheusAPI{API: api},
+		meterDefinitions,
+		dataBuilder,
+		reportWriter,
+	)
+	if err != nil {
+		return err
+	}
+
+	metrics, errorList, warningList, err := reporter.CollectMetrics(ctx)
+
+	for _, err := range warningList {
+		details := append(
+			[]interface{}{"cause", errors.Cause(err)},
+			errors.GetDetails(err)...)
+		logger.Info(fmt.Sprintf("warning: %v", err.Error()), details...)
+	}
+
+	for _, err := range errorList {
+		details := append(
+			[]interface{}{"cause", errors.Cause(err)},
+			errors.GetDetails(err)...)
+		logger.Info(fmt.Sprintf("error: %v", err), details...)
+	}
+
+	if err != nil && len(metrics) == 0 {
+		return errors.Wrap(err, "failure to query metrics")
+	}
+
+	reportID := uuid.MustParse(t.MeterReport.Spec.ReportUUID)
+
+	files, err := reporter.WriteReport(reportID, metrics)
+	if err != nil {
+		return errors.Wrap(err, "error writing report")
+	}
+
+	dirpath := filepath.Dir(files[0])
+	fileName := filepath.Join(t.Config.OutputDirectory, fmt.Sprintf("upload-%s.tar.gz", reportID.String()))
+	// stamped with the end of the report, not when the files were written
+	if err := TargzFolderAt(dirpath, fileName, t.MeterReport.Spec.EndTime.Time); err != nil {
+		return errors.Wrap(err, "error creating tar.gz")
+	}
+
+	logger.Info("wrote report", "directory", dirpath, "file", fileName, "metricsLength", len(metrics))
+
+	if len(errorList) != 0 {
+		return errors.Combine(errorList...)
+	}
+
+	return nil
+}
+
+// meterDefinitionReferences returns the references of the MeterReport, with
+// the spec of the MeterDefinitions read for the references without one
+func (t *SnapshotTask) meterDefinitionReferences() (MeterDefinitionReferences, error) {
+	var defs MeterDefinitionReferences
+
+	for _, ref := range t.MeterReport.Spec.MeterDefinitionReferences {
+		if ref.Spec == nil {
+			mdef := t.findMeterDefinition(ref.Name, ref.Namespace)
+			if mdef == nil {
+				return nil, errors.WithDetails(ErrMeterDefinitionNotFound, "name", ref.Name, "namespace", ref.Namespace)
+			}
+
+			ref.UID = mdef.UID
+			ref.Spec = &mdef.Spec
+		}
+
+		defs = append(defs, ref)
+	}
+
+	return defs, nil
+}
+
+func (t *SnapshotTask) findMeterDefinition(name, namespace string) *marketplacev1beta1.MeterDefinition {
+	for i := range t.MeterDefinitions {
+		if mdef := &t.MeterDefinitions[i]; mdef.Name == name && mdef.Namespace == namespace {
+			return mdef
+		}
+	}
+	return nil
+}
+
+// decodeFile decodes the yaml or json object in file into obj
+func decodeFile(file string, obj interface{}) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+
+	return errors.WrapIfWithDetails(
+		yaml.NewYAMLOrJSONDecoder(f, 4096).Decode(obj),
+		"failed to decode file", "file", file)
+}
+
+// decodeMeterDefinitions reads the MeterDefinitions, or lists of them such as
+// the output of kubectl get -o yaml, of the yaml documents or json objects
+// in file
+func decodeMeterDefinitions(file string) ([]marketplacev1beta1.MeterDefinition, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	var mdefs []marketplacev1beta1.MeterDefinition
+
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			return mdefs, nil
+		} else if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to decode file", "file", file)
+		}
+
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+
+		var kind struct {
+			Kind string `json:"kind"`
+		}
+		if err := json.Unmarshal(raw, &kind); err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to decode file", "file", file)
+		}
+
+		if strings.HasSuffix(kind.Kind, "List") {
+			list := marketplacev1beta1.MeterDefinitionList{}
+			if err := json.Unmarshal(raw, &list); err != nil {
+				return nil, errors.WrapIfWithDetails(err, "failed to decode file", "file", file)
+			}
+			mdefs = append(mdefs, list.Items...)
+			continue
+		}
+
+		mdef := marketplacev1beta1.MeterDefinition{}
+		if err := json.Unmarshal(raw, &mdef); err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to decode file", "file", file)
+		}
+		mdefs = append(mdefs, mdef)
+	}
+}
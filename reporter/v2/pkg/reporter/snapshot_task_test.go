@@ -0,0 +1,219 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"context"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/v2/apis/marketplace/common"
+	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/v2/apis/marketplace/v1alpha1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/v2/apis/marketplace/v1beta1"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/v2/pkg/utils/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+type tsdbSample struct {
+	t int64
+	f float64
+}
+
+func (s tsdbSample) T() int64                      { return s.t }
+func (s tsdbSample) F() float64                    { return s.f }
+func (s tsdbSample) H() *histogram.Histogram       { return nil }
+func (s tsdbSample) FH() *histogram.FloatHistogram { return nil }
+func (s tsdbSample) Type() chunkenc.ValueType      { return chunkenc.ValFloat }
+
+var _ = Describe("SnapshotTask", func() {
+	var (
+		dir                                       string
+		meterReportFile, mktConfigFile, mdefsFile string
+
+		reportID = uuid.New()
+		start    = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		end      = start.Add(3 * time.Hour)
+	)
+
+	writeYAML := func(name string, obj interface{}) string {
+		data, err := yaml.Marshal(obj)
+		Expect(err).To(Succeed())
+
+		file := filepath.Join(dir, name)
+		Expect(os.WriteFile(file, data, 0600)).To(Succeed())
+		return file
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "snapshot")
+		Expect(err).To(Succeed())
+
+		DeferCleanup(os.RemoveAll, dir)
+
+		By("creating the snapshot")
+		var series []storage.Series
+		for i, pod := range []string{"pod-a", "pod-b", "pod-c"} {
+			var info, usage []tsdbutil.Sample
+			for t := start; t.Before(end); t = t.Add(time.Minute) {
+				info = append(info, tsdbSample{t: t.UnixMilli(), f: 1})
+				usage = append(usage, tsdbSample{t: t.UnixMilli(), f: float64(i + 1)})
+			}
+
+			series = append(series,
+				storage.NewListSeries(labels.FromStrings(
+					"__name__", "meterdef_pod_info",
+					"meter_def_name", "foo",
+					"meter_def_namespace", "bar",
+					"namespace", "ns",
+					"pod", pod,
+				), info),
+				storage.NewListSeries(labels.FromStrings(
+					"__name__", "my_usage",
+					"namespace", "ns",
+					"pod", pod,
+				), usage),
+			)
+		}
+
+		Expect(os.Mkdir(filepath.Join(dir, "snapshot"), 0755)).To(Succeed())
+		_, err = tsdb.CreateBlock(series, filepath.Join(dir, "snapshot"), int64(24*time.Hour/time.Millisecond), log.NewNopLogger())
+		Expect(err).To(Succeed())
+
+		By("writing the cluster objects")
+		meterReportFile = writeYAML("meterreport.yaml", &marketplacev1alpha1.MeterReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "openshift-redhat-marketplace"},
+			Spec: marketplacev1alpha1.MeterReportSpec{
+				StartTime:  metav1.Time{Time: start},
+				EndTime:    metav1.Time{Time: end},
+				ReportUUID: reportID.String(),
+				MeterDefinitionReferences: []v1beta1.MeterDefinitionReference{
+					{Name: "foo", Namespace: "bar"},
+				},
+			},
+		})
+
+		mktConfig := &marketplacev1alpha1.MarketplaceConfig{
+			Spec: marketplacev1alpha1.MarketplaceConfigSpec{
+				RhmAccountID: "foo",
+				ClusterUUID:  "foo-id",
+			},
+		}
+		mktConfig.Status.Conditions.SetCondition(status.Condition{
+			Type:    marketplacev1alpha1.ConditionRHMAccountExists,
+			Status:  corev1.ConditionTrue,
+			Reason:  marketplacev1alpha1.ReasonRHMAccountExists,
+			Message: "RHM/Software Central account exists",
+		})
+		mktConfigFile = writeYAML("marketplaceconfig.yaml", mktConfig)
+
+		mdefsFile = writeYAML("meterdefinitions.yaml", &v1beta1.MeterDefinitionList{
+			TypeMeta: metav1.TypeMeta{Kind: "List", APIVersion: "v1"},
+			Items: []v1beta1.MeterDefinition{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", UID: "a"},
+					Spec: v1beta1.MeterDefinitionSpec{
+						Group: "app.partner.metering.com",
+						Kind:  "App",
+						ResourceFilters: []v1beta1.ResourceFilter{
+							{
+								Namespace:    &v1beta1.NamespaceFilter{UseOperatorGroup: true},
+								WorkloadType: common.WorkloadTypePod,
+							},
+						},
+						Meters: []v1beta1.MeterWorkload{
+							{
+								Aggregation:  "sum",
+								Query:        "my_usage",
+								Metric:       "usage",
+								WorkloadType: common.WorkloadTypePod,
+							},
+						},
+					},
+				},
+			},
+		})
+	})
+
+	generate := func(schema string) map[string][]byte {
+		output := filepath.Join(dir, uuid.NewString())
+		Expect(os.Mkdir(output, 0755)).To(Succeed())
+
+		cfg := &Config{
+			OutputDirectory:   output,
+			ReporterSchema:    schema,
+			SnapshotDirectory: filepath.Join(dir, "snapshot"),
+		}
+		Expect(cfg.SetDefaults()).To(Succeed())
+
+		task, err := NewSnapshotTask(cfg, meterReportFile, mktConfigFile, mdefsFile)
+		Expect(err).To(Succeed())
+		Expect(task.Run(context.TODO())).To(Succeed())
+
+		tarball, err := os.ReadFile(filepath.Join(output, "upload-"+reportID.String()+".tar.gz"))
+		Expect(err).To(Succeed())
+
+		files := map[string][]byte{"upload.tar.gz": tarball}
+		entries, err := os.ReadDir(filepath.Join(output, reportID.String()))
+		Expect(err).To(Succeed())
+
+		for _, entry := range entries {
+			data, err := os.ReadFile(filepath.Join(output, reportID.String(), entry.Name()))
+			Expect(err).To(Succeed())
+			files[entry.Name()] = data
+		}
+
+		return files
+	}
+
+	It("should generate the same report from a snapshot", func() {
+		for _, schema := range []string{"v1alpha1", "v2alpha1"} {
+			By("generating the report in " + schema)
+			first := generate(schema)
+			Expect(len(first)).To(BeNumerically(">", 2))
+			Expect(first).To(ContainElement(ContainSubstring(`"usage"`)))
+
+			By("generating the report again")
+			Expect(generate(schema)).To(Equal(first))
+		}
+	})
+
+	It("should fail when a meter definition is missing", func() {
+		cfg := &Config{
+			OutputDirectory:   dir,
+			ReporterSchema:    "v2alpha1",
+			SnapshotDirectory: filepath.Join(dir, "snapshot"),
+		}
+		Expect(cfg.SetDefaults()).To(Succeed())
+
+		task, err := NewSnapshotTask(cfg, meterReportFile, mktConfigFile, "")
+		Expect(err).To(Succeed())
+		Expect(task.Run(context.TODO())).To(MatchError(ErrMeterDefinitionNotFound))
+	})
+})
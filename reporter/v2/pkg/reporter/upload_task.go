@@ -15,11 +15,13 @@
 package reporter
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
+	"sync"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/go-logr/logr"
@@ -31,6 +33,7 @@ import (
 	"github.com/redhat-marketplace/redhat-marketplace-operator/v2/pkg/utils/status"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -48,6 +51,8 @@ type UploadTask struct {
 	k8SScheme   *runtime.Scheme
 	fileStorage dataservice.FileStorage
 	uploaders   uploaders.Uploaders
+
+	uploadBackoff wait.Backoff `wire:"-"`
 }
 
 // RunReport uses status fields on the Report to get identifiers
@@ -68,7 +73,7 @@ func (r *UploadTask) RunReport(ctx context.Context, report *marketplacev1alpha1.
 		return err
 	}
 
-	statuses := r.uploadFile(ctx, file)
+	statuses := r.uploadFile(ctx, file, report.Status.UploadStatus)
 	success, condition := findStatus(statuses)
 
 	if err = updateMeterReportStatus(ctx, r.k8SClient, report.Name, report.Namespace,
@@ -102,7 +107,11 @@ func (r *UploadTask) RunReport(ctx context.Context, report *marketplacev1alpha1.
 	return nil
 }
 
-const uploadAttempts = "uploadAttempts"
+const (
+	uploadAttempts = "uploadAttempts"
+	// uploadStatus is the json of the UploadDetailConditions of a file
+	uploadStatus = "uploadStatus"
+)
 
 // Run checks for just files in DataService and sends them if it can.
 func (r *UploadTask) RunGeneric(ctx context.Context) error {
@@ -146,13 +155,23 @@ func (r *UploadTask) RunGeneric(ctx context.Context) error {
 			continue
 		}
 
-		statuses := r.uploadFile(ctx, file)
+		previous := marketplacev1alpha1.UploadDetailConditions{}
+		if statusStr, ok := file.Metadata[uploadStatus]; ok {
+			if err := json.Unmarshal([]byte(statusStr), &previous); err != nil {
+				logger.Error(err, "failed to parse upload status", "id", file.Id)
+			}
+		}
+
+		statuses := r.uploadFile(ctx, file, previous)
 		success, _ := findStatus(statuses)
 
 		if !success && uploadAttemptsInt < maxUploadAttempts {
 			logger.Info("failed to complete upload without an issue, will not delete the file", "attempts", uploadAttemptsInt)
 			uploadAttemptsInt = uploadAttemptsInt + 1
 			file.Metadata[uploadAttempts] = fmt.Sprintf("%d", uploadAttemptsInt)
+			if statusBytes, err := json.Marshal(statuses); err == nil {
+				file.Metadata[uploadStatus] = string(statusBytes)
+			}
 			err := r.fileStorage.UpdateMetadata(ctx, file)
 			if err != nil {
 				logger.Error(err, "failed to update metadata")
@@ -168,57 +187,147 @@ func (r *UploadTask) RunGeneric(ctx context.Context) error {
 	return nil
 }
 
+// defaultUploadBackoff retries an upload to a target for about 30 seconds
+var defaultUploadBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.5,
+}
+
+// uploadFile sends the file to the uploaders concurrently, each one retries
+// with its own backoff. A target with a success in previous is not sent the
+// file again, and a target that is out of attempts keeps its last error.
 func (r *UploadTask) uploadFile(
 	ctx context.Context,
 	file *dataservicev1.FileInfo,
-) (statuses []*marketplacev1alpha1.UploadDetails) {
+	previous marketplacev1alpha1.UploadDetailConditions,
+) marketplacev1alpha1.UploadDetailConditions {
 	logger := r.logger
 	logger.Info("DownloadFile", "Downloading file from data-service", file)
 	localFileName, downloadErr := r.fileStorage.DownloadFile(ctx, file)
 
-	// Upload the file to uploaders
-	for _, uploader := range r.uploaders {
+	if downloadErr != nil {
+		logger.Error(downloadErr, "failed to download file", "id", file.Id)
+	}
+
+	statuses := make(marketplacev1alpha1.UploadDetailConditions, len(r.uploaders))
+
+	var wg sync.WaitGroup
+
+	for i, uploader := range r.uploaders {
 		details := &marketplacev1alpha1.UploadDetails{}
+		if prev := previous.Get(uploader.Name()); prev != nil {
+			*details = *prev
+		}
 		details.Target = uploader.Name()
+		statuses[i] = details
 
-		if downloadErr != nil {
-			logger.Error(downloadErr, "failed to download file", "id", file.Id)
-			details.Error = fmt.Sprintf("error: %s details: %+v", downloadErr.Error(), errors.GetDetails(downloadErr))
-			details.Status = marketplacev1alpha1.UploadStatusFailure
-			statuses = append(statuses, details)
-			return
+		if details.Success() {
+			logger.Info("file already uploaded", "file", file.Name, "target", details.Target)
+			continue
 		}
 
-		logger.Info("Uploaded file", "file", file, "target", uploader.Name())
-		data, err := os.ReadFile(localFileName)
-
-		onError := func(err error, msg string) {
-			logger.Error(err, msg)
-			details.Error = fmt.Sprintf("error: %s details: %+v", err.Error(), errors.GetDetails(err))
-			details.Status = marketplacev1alpha1.UploadStatusFailure
-			statuses = append(statuses, details)
+		if details.Attempts >= maxUploadAttempts {
+			logger.Info("out of upload attempts", "file", file.Name, "target", details.Target, "attempts", details.Attempts)
+			continue
 		}
 
-		if err != nil {
-			onError(err, "failed to open local file")
+		details.Attempts = details.Attempts + 1
+
+		if downloadErr != nil {
+			setUploadError(details, downloadErr)
 			continue
 		}
 
-		var id string
-		id, err = uploader.UploadFile(ctx, file.Name, bytes.NewReader(data))
+		wg.Add(1)
+		go func(uploader uploaders.Uploader, details *marketplacev1alpha1.UploadDetails) {
+			defer wg.Done()
 
-		if err != nil {
-			logger.Error(err, "failed to upload file", errors.GetDetails(err)...)
-			onError(err, "failed to upload file")
-			continue
+			id, err := r.uploadToTarget(ctx, uploader, file.Name, localFileName)
+			if err != nil {
+				logger.Error(err, "failed to upload file", append(errors.GetDetails(err), "target", details.Target)...)
+				setUploadError(details, err)
+				return
+			}
+
+			logger.Info("Uploaded file", "file", file, "target", details.Target)
+			details.ID = id
+			details.Error = ""
+			details.Status = marketplacev1alpha1.UploadStatusSuccess
+		}(uploader, details)
+	}
+
+	wg.Wait()
+
+	return statuses
+}
+
+// uploadToTarget streams the local file to the uploader, transient failures
+// are retried with the uploader's backoff and the file is reopened for every
+// retry
+func (r *UploadTask) uploadToTarget(
+	ctx context.Context,
+	uploader uploaders.Uploader,
+	fileName, localFileName string,
+) (id string, err error) {
+	backoff := r.backoff(uploader)
+
+	// the cap limits each wait, wait.Backoff would stop retrying once it is reached
+	maxWait := backoff.Cap
+	backoff.Cap = 0
+
+	for {
+		id, err = r.uploadAttempt(ctx, uploader, fileName, localFileName)
+		if err == nil || !uploaders.IsTransient(err) || backoff.Steps <= 1 {
+			return
 		}
 
-		details.ID = id
-		details.Status = marketplacev1alpha1.UploadStatusSuccess
-		statuses = append(statuses, details)
+		delay := backoff.Step()
+		if maxWait > 0 && delay > maxWait {
+			delay = maxWait
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
 	}
+}
 
-	return
+func (r *UploadTask) uploadAttempt(
+	ctx context.Context,
+	uploader uploaders.Uploader,
+	fileName, localFileName string,
+) (string, error) {
+	f, err := os.Open(localFileName)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open local file")
+	}
+	defer f.Close()
+
+	id, err := uploader.UploadFile(ctx, fileName, f)
+	if err != nil {
+		r.logger.Info("upload attempt failed", "target", uploader.Name(), "err", err.Error())
+	}
+	return id, err
+}
+
+// backoff is the uploader's own retry backoff, or the task's
+func (r *UploadTask) backoff(uploader uploaders.Uploader) wait.Backoff {
+	if retryUploader, ok := uploader.(uploaders.RetryUploader); ok {
+		return retryUploader.Backoff()
+	}
+	if r.uploadBackoff.Steps == 0 {
+		return defaultUploadBackoff
+	}
+	return r.uploadBackoff
+}
+
+func setUploadError(details *marketplacev1alpha1.UploadDetails, err error) {
+	details.Error = fmt.Sprintf("error: %s details: %+v", err.Error(), errors.GetDetails(err))
+	details.Status = marketplacev1alpha1.UploadStatusFailure
 }
 
 func (r *UploadTask) deleteFile(ctx context.Context, file *dataservicev1.FileInfo) error {
@@ -15,10 +15,22 @@
 package reporter
 
 import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	dataservicev1 "github.com/redhat-marketplace/redhat-marketplace-operator/airgap/v2/apis/dataservice/v1"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/pkg/uploaders"
 	marketplacev1alpha1 "github.com/redhat-marketplace/redhat-marketplace-operator/v2/apis/marketplace/v1alpha1"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 var _ = Describe("upload_task", func() {
@@ -52,4 +64,166 @@ var _ = Describe("upload_task", func() {
 			Expect(condition.IsTrue()).To(BeFalse())
 		})
 	})
+
+	Context("uploadFile", func() {
+		var (
+			sut         *UploadTask
+			fileStorage *mockFileStorage
+			file        *dataservicev1.FileInfo
+			fast, slow  *testUploader
+		)
+
+		BeforeEach(func() {
+			localFile := filepath.Join(GinkgoT().TempDir(), "upload.tar.gz")
+			Expect(os.WriteFile(localFile, []byte("report"), 0600)).To(Succeed())
+
+			file = &dataservicev1.FileInfo{Id: "1", Name: "upload.tar.gz"}
+			fileStorage = &mockFileStorage{}
+			fileStorage.On("DownloadFile", mock.Anything, file).Return(localFile, nil)
+
+			fast = &testUploader{name: "fast", done: make(chan struct{})}
+			slow = &testUploader{name: "slow", failures: 2, wait: fast.done}
+
+			sut = &UploadTask{
+				logger:        logr.Discard(),
+				fileStorage:   fileStorage,
+				uploaders:     uploaders.Uploaders{slow, fast},
+				uploadBackoff: wait.Backoff{Steps: 3, Duration: time.Millisecond, Factor: 2, Jitter: 0.5},
+			}
+		})
+
+		It("should upload to every target concurrently with retries", func() {
+			statuses := sut.uploadFile(context.Background(), file, nil)
+
+			Expect(statuses).To(HaveLen(2))
+			Expect(statuses.AllSuccesses()).To(BeTrue())
+			Expect(*statuses.Get("slow")).To(Equal(marketplacev1alpha1.UploadDetails{
+				Target: "slow", ID: "slow-id", Status: marketplacev1alpha1.UploadStatusSuccess, Attempts: 1,
+			}))
+			Expect(slow.calls).To(Equal(3))
+			Expect(fast.calls).To(Equal(1))
+			Expect(slow.bodies).To(HaveEach("report"))
+		})
+
+		It("should not send the file again to a target that succeeded", func() {
+			slow.failures = 10
+			previous := marketplacev1alpha1.UploadDetailConditions{
+				{Target: "fast", ID: "previous-id", Status: marketplacev1alpha1.UploadStatusSuccess, Attempts: 1},
+				{Target: "slow", Status: marketplacev1alpha1.UploadStatusFailure, Error: "oh no", Attempts: 2},
+			}
+			close(fast.done)
+
+			statuses := sut.uploadFile(context.Background(), file, previous)
+
+			Expect(fast.calls).To(Equal(0))
+			Expect(statuses.Get("fast").ID).To(Equal("previous-id"))
+			Expect(slow.calls).To(Equal(3))
+			Expect(statuses.Get("slow").Status).To(Equal(marketplacev1alpha1.UploadStatusFailure))
+			Expect(statuses.Get("slow").Error).To(ContainSubstring("slow failed"))
+			Expect(statuses.Get("slow").Attempts).To(Equal(3))
+		})
+
+		It("should not retry an upload that is not transient", func() {
+			slow.permanent = true
+
+			statuses := sut.uploadFile(context.Background(), file, nil)
+
+			Expect(slow.calls).To(Equal(1))
+			Expect(statuses.Get("slow").Status).To(Equal(marketplacev1alpha1.UploadStatusFailure))
+			Expect(statuses.Get("fast").Success()).To(BeTrue())
+		})
+
+		It("should retry with the backoff of the target", func() {
+			slow.failures = 4
+			retrying := &retryUploader{
+				testUploader: slow,
+				backoff:      wait.Backoff{Steps: 5, Duration: time.Millisecond, Factor: 2, Cap: time.Millisecond},
+			}
+			sut.uploaders = uploaders.Uploaders{retrying, fast}
+
+			statuses := sut.uploadFile(context.Background(), file, nil)
+
+			Expect(slow.calls).To(Equal(5))
+			Expect(statuses.AllSuccesses()).To(BeTrue())
+		})
+
+		It("should skip a target that is out of attempts", func() {
+			previous := marketplacev1alpha1.UploadDetailConditions{
+				{Target: "slow", Status: marketplacev1alpha1.UploadStatusFailure, Error: "oh no", Attempts: maxUploadAttempts},
+			}
+
+			statuses := sut.uploadFile(context.Background(), file, previous)
+
+			Expect(slow.calls).To(Equal(0))
+			Expect(*statuses.Get("slow")).To(Equal(*previous[0]))
+			Expect(statuses.Get("fast").Success()).To(BeTrue())
+		})
+	})
 })
+
+// testUploader fails the first uploads with a transient error, or a
+// permanent one, then waits for wait to be closed before it succeeds
+type testUploader struct {
+	name      string
+	failures  int
+	permanent bool
+	wait      chan struct{}
+	done      chan struct{}
+
+	mu     sync.Mutex
+	calls  int
+	bodies []string
+}
+
+var _ uploaders.Uploader = &testUploader{}
+
+// retryUploader is a testUploader with its own backoff
+type retryUploader struct {
+	*testUploader
+	backoff wait.Backoff
+}
+
+var _ uploaders.RetryUploader = &retryUploader{}
+
+func (u *retryUploader) Backoff() wait.Backoff {
+	return u.backoff
+}
+
+func (u *testUploader) Name() string {
+	return u.name
+}
+
+func (u *testUploader) UploadFile(ctx context.Context, fileName string, reader io.Reader) (string, error) {
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	u.mu.Lock()
+	u.calls = u.calls + 1
+	u.bodies = append(u.bodies, string(body))
+	calls := u.calls
+	u.mu.Unlock()
+
+	if calls <= u.failures {
+		err := errors.Errorf("%s failed", u.name)
+		if u.permanent {
+			return "", err
+		}
+		return "", uploaders.Transient(err)
+	}
+
+	if u.wait != nil {
+		select {
+		case <-u.wait:
+		case <-time.After(5 * time.Second):
+			return "", errors.New("uploads are not concurrent")
+		}
+	}
+
+	if u.done != nil {
+		close(u.done)
+	}
+
+	return u.name + "-id", nil
+}
@@ -107,6 +107,15 @@ func ProvideUploaders(
 				uploaders = append(uploaders, uploader)
 			}
 		default:
+			if r, ok := u.Lookup(target.Name()); ok && r.Factory != nil {
+				uploader, err := provideRegisteredUploader(ctx, client, reporterConfig.DeployedNamespace, target.Name())
+				if err != nil {
+					return nil, err
+				}
+				uploaders = append(uploaders, uploader)
+				continue
+			}
+
 			return nil, errors.Errorf("uploader target not available %s", target.Name())
 		}
 	}
@@ -144,6 +153,32 @@ func provideCOSS3Config(
 	return cosS3UploaderConfig, nil
 }
 
+// provideRegisteredUploader builds a registered uploader from the config.yaml
+// of its rhm-<name>-uploader-secret.
+func provideRegisteredUploader(
+	ctx context.Context,
+	client client.Client,
+	deployedNamespace string,
+	name string,
+) (u.Uploader, error) {
+	secret := &corev1.Secret{}
+	secretName := u.ConfigSecretName(name)
+
+	if err := client.Get(ctx, types.NamespacedName{
+		Name:      secretName,
+		Namespace: deployedNamespace,
+	}, secret); err != nil {
+		return nil, err
+	}
+
+	configYamlBytes, ok := secret.Data["config.yaml"]
+	if !ok {
+		return nil, errors.Errorf("%s does not contain a config.yaml", secretName)
+	}
+
+	return u.NewUploader(name, configYamlBytes)
+}
+
 const (
 	MktplProductionURL = "https://marketplace.redhat.com"
 	MktplStageURL      = "https://sandbox.marketplace.redhat.com"
@@ -34,9 +34,9 @@ func ProvideWriter(
 ) (common.ReportWriter, error) {
 	switch config.ReporterSchema {
 	case "v1alpha1":
-		return &writerv1.ReportWriter{MktConfig: MktConfig, Logger: logger}, nil
+		return &writerv1.ReportWriter{MktConfig: MktConfig, Logger: logger, Deterministic: config.SnapshotDirectory != ""}, nil
 	case "v2alpha1":
-		return &writerv2.ReportWriter{MktConfig: MktConfig, Logger: logger}, nil
+		return &writerv2.ReportWriter{MktConfig: MktConfig, Logger: logger, Deterministic: config.SnapshotDirectory != ""}, nil
 	default:
 		return nil, errors.New(fmt.Sprintf("Unsupported reporterSchema: %s", config.ReporterSchema))
 	}
@@ -20,6 +20,8 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 
 	"emperror.dev/errors"
 	"github.com/go-logr/logr"
@@ -35,6 +37,9 @@ import (
 type ReportWriter struct {
 	MktConfig *marketplacev1alpha1.MarketplaceConfig
 	Logger    logr.Logger
+	// Deterministic sorts the metrics and derives the slice ids from the
+	// report id, so the same metrics always write the same report
+	Deterministic bool
 }
 
 func (r *ReportWriter) WriteReport(
@@ -68,16 +73,30 @@ func (r *ReportWriter) WriteReport(
 		return []string{}, errors.Wrap(err, "error creating directory")
 	}
 
-	for _, v := range metrics {
-		metricsArr = append(metricsArr, v)
+	if r.Deterministic {
+		keys := make([]string, 0, len(metrics))
+		for k := range metrics {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			metricsArr = append(metricsArr, metrics[k])
+		}
+	} else {
+		for _, v := range metrics {
+			metricsArr = append(metricsArr, v)
+		}
 	}
 
 	filenames := []string{}
 	reportErrors := []error{}
 
+	slice := 0
 	for idxRange := range gopart.Partition(len(metricsArr), partitionSize) {
+		slice++
 		metricReport := &schemav2alpha1.MarketplaceReportSlice{}
-		reportSliceID := common.ReportSliceKey(uuid.New())
+		reportSliceID := r.newSliceID(source, slice)
 		metricReport.Metadata = &metadata
 
 		for _, builder := range metricsArr[idxRange.Low:idxRange.High] {
@@ -138,3 +157,12 @@ func (r *ReportWriter) WriteReport(
 
 	return filenames, errors.Combine(reportErrors...)
 }
+
+// newSliceID returns a random id, or the id of the nth slice of the report
+// when deterministic
+func (r *ReportWriter) newSliceID(source uuid.UUID, n int) common.ReportSliceKey {
+	if r.Deterministic {
+		return common.ReportSliceKey(uuid.NewSHA1(source, []byte(strconv.Itoa(n))))
+	}
+	return common.ReportSliceKey(uuid.New())
+}
@@ -0,0 +1,308 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package snapshot runs PromQL queries against a Prometheus TSDB snapshot with
+// an embedded engine, so reports can be generated without a Prometheus server.
+package snapshot
+
+import (
+	"context"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+)
+
+const (
+	ErrNoData          = errors.Sentinel("no tsdb data found")
+	ErrUnsupportedType = errors.Sentinel("unsupported result type")
+)
+
+const (
+	defaultLookbackDelta = 5 * time.Minute
+	defaultMaxSamples    = 50000000
+	defaultTimeout       = 2 * time.Minute
+)
+
+// API serves the query methods of the Prometheus HTTP API from a TSDB
+// snapshot. The admin and status methods are not implemented, calling them
+// panics.
+type API struct {
+	v1.API
+
+	engine  *promql.Engine
+	querier storage.Querier
+	closers []io.Closer
+
+	// the readers of the tsdb are not safe for concurrent use
+	mu sync.Mutex
+}
+
+var _ v1.API = &API{}
+
+// Open opens the TSDB under dir read only. Dir is either a data directory or
+// snapshot, holding blocks and optionally a WAL, or a single block directory.
+func Open(dir string) (*API, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	api := &API{
+		engine: promql.NewEngine(promql.EngineOpts{
+			MaxSamples:           defaultMaxSamples,
+			Timeout:              defaultTimeout,
+			LookbackDelta:        defaultLookbackDelta,
+			EnableAtModifier:     true,
+			EnableNegativeOffset: true,
+		}),
+	}
+
+	var err error
+	if _, statErr := os.Stat(filepath.Join(dir, "meta.json")); statErr == nil {
+		err = api.openBlock(dir)
+	} else {
+		err = api.openDB(dir)
+	}
+
+	if err != nil {
+		api.Close()
+		return nil, errors.WrapIfWithDetails(err, "failed to open tsdb", "dir", dir)
+	}
+
+	return api, nil
+}
+
+func (a *API) openBlock(dir string) error {
+	block, err := tsdb.OpenBlock(nil, dir, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	a.closers = append(a.closers, block)
+
+	a.querier, err = tsdb.NewBlockQuerier(block, math.MinInt64, math.MaxInt64)
+	return errors.WithStack(err)
+}
+
+func (a *API) openDB(dir string) error {
+	db, err := tsdb.OpenDBReadOnly(dir, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	a.closers = append(a.closers, db)
+
+	blocks, err := db.Blocks()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "wal")); err != nil {
+		// a snapshot only holds blocks
+		if len(blocks) == 0 {
+			return ErrNoData
+		}
+
+		queriers := make([]storage.Querier, 0, len(blocks))
+		for _, block := range blocks {
+			q, err := tsdb.NewBlockQuerier(block, math.MinInt64, math.MaxInt64)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			queriers = append(queriers, q)
+		}
+
+		a.querier = storage.NewMergeQuerier(queriers, nil, storage.ChainedSeriesMerge)
+		return nil
+	}
+
+	// the read only db reopens its blocks for every querier, a single querier
+	// over all the data is kept instead
+	a.querier, err = db.Querier(context.Background(), math.MinInt64, math.MaxInt64)
+	return errors.WithStack(err)
+}
+
+// Close releases the files of the snapshot
+func (a *API) Close() error {
+	var err error
+	if a.querier != nil {
+		err = errors.Append(err, a.querier.Close())
+	}
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		err = errors.Append(err, a.closers[i].Close())
+	}
+	return err
+}
+
+// queryable returns the snapshot querier for every range, the engine closing
+// it is a no-op
+func (a *API) queryable() storage.Queryable {
+	return storage.QueryableFunc(func(ctx context.Context, mint, maxt int64) (storage.Querier, error) {
+		return nopCloseQuerier{a.querier}, nil
+	})
+}
+
+type nopCloseQuerier struct {
+	storage.Querier
+}
+
+func (nopCloseQuerier) Close() error {
+	return nil
+}
+
+// Query evaluates an instant query at ts
+func (a *API) Query(ctx context.Context, query string, ts time.Time, opts ...v1.Option) (model.Value, v1.Warnings, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	q, err := a.engine.NewInstantQuery(ctx, a.queryable(), nil, query, ts)
+	if err != nil {
+		return nil, nil, badData(err)
+	}
+	defer q.Close()
+
+	return toModel(q.Exec(ctx))
+}
+
+// QueryRange evaluates a range query
+func (a *API) QueryRange(ctx context.Context, query string, r v1.Range, opts ...v1.Option) (model.Value, v1.Warnings, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	q, err := a.engine.NewRangeQuery(ctx, a.queryable(), nil, query, r.Start, r.End, r.Step)
+	if err != nil {
+		return nil, nil, badData(err)
+	}
+	defer q.Close()
+
+	return toModel(q.Exec(ctx))
+}
+
+// LabelValues returns the values of label on the series matching any of the
+// matches. The series are not filtered on the time range, a snapshot only
+// holds the data retained when it was taken.
+func (a *API) LabelValues(ctx context.Context, label string, matches []string, startTime, endTime time.Time) (model.LabelValues, v1.Warnings, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	matcherSets := [][]*labels.Matcher{nil}
+	if len(matches) != 0 {
+		matcherSets = matcherSets[:0]
+		for _, match := range matches {
+			matchers, err := parser.ParseMetricSelector(match)
+			if err != nil {
+				return nil, nil, badData(err)
+			}
+			matcherSets = append(matcherSets, matchers)
+		}
+	}
+
+	set := map[string]struct{}{}
+	var warnings v1.Warnings
+
+	for _, matchers := range matcherSets {
+		values, ws, err := a.querier.LabelValues(label, matchers...)
+		for _, w := range ws {
+			warnings = append(warnings, w.Error())
+		}
+		if err != nil {
+			return nil, warnings, &v1.Error{Type: v1.ErrExec, Msg: err.Error()}
+		}
+		for _, value := range values {
+			set[value] = struct{}{}
+		}
+	}
+
+	result := make(model.LabelValues, 0, len(set))
+	for value := range set {
+		result = append(result, model.LabelValue(value))
+	}
+	sort.Sort(result)
+
+	return result, warnings, nil
+}
+
+// Buildinfo reports the snapshot API, it is used to check the API is up
+func (a *API) Buildinfo(ctx context.Context) (v1.BuildinfoResult, error) {
+	return v1.BuildinfoResult{Version: "snapshot"}, nil
+}
+
+func badData(err error) error {
+	return &v1.Error{Type: v1.ErrBadData, Msg: err.Error()}
+}
+
+// toModel converts the result of the engine to the model of the HTTP API
+func toModel(result *promql.Result) (model.Value, v1.Warnings, error) {
+	var warnings v1.Warnings
+	for _, w := range result.Warnings {
+		warnings = append(warnings, w.Error())
+	}
+
+	if result.Err != nil {
+		return nil, warnings, &v1.Error{Type: v1.ErrExec, Msg: result.Err.Error()}
+	}
+
+	switch v := result.Value.(type) {
+	case promql.Matrix:
+		matrix := make(model.Matrix, 0, len(v))
+		for _, series := range v {
+			stream := &model.SampleStream{
+				Metric: toMetric(series.Metric),
+				Values: make([]model.SamplePair, 0, len(series.Floats)),
+			}
+			for _, point := range series.Floats {
+				stream.Values = append(stream.Values, model.SamplePair{
+					Timestamp: model.Time(point.T),
+					Value:     model.SampleValue(point.F),
+				})
+			}
+			matrix = append(matrix, stream)
+		}
+		return matrix, warnings, nil
+	case promql.Vector:
+		vector := make(model.Vector, 0, len(v))
+		for _, sample := range v {
+			vector = append(vector, &model.Sample{
+				Metric:    toMetric(sample.Metric),
+				Timestamp: model.Time(sample.T),
+				Value:     model.SampleValue(sample.F),
+			})
+		}
+		return vector, warnings, nil
+	case promql.Scalar:
+		return &model.Scalar{Timestamp: model.Time(v.T), Value: model.SampleValue(v.V)}, warnings, nil
+	case promql.String:
+		return &model.String{Timestamp: model.Time(v.T), Value: v.V}, warnings, nil
+	default:
+		return nil, warnings, errors.WithDetails(ErrUnsupportedType, "type", string(result.Value.Type()))
+	}
+}
+
+func toMetric(ls labels.Labels) model.Metric {
+	metric := make(model.Metric, ls.Len())
+	ls.Range(func(l labels.Label) {
+		metric[model.LabelName(l.Name)] = model.LabelValue(l.Value)
+	})
+	return metric
+}
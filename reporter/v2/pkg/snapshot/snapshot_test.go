@@ -0,0 +1,186 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-kit/log"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+)
+
+type sample struct {
+	t int64
+	f float64
+}
+
+func (s sample) T() int64                      { return s.t }
+func (s sample) F() float64                    { return s.f }
+func (s sample) H() *histogram.Histogram       { return nil }
+func (s sample) FH() *histogram.FloatHistogram { return nil }
+func (s sample) Type() chunkenc.ValueType      { return chunkenc.ValFloat }
+
+var _ = Describe("Snapshot", func() {
+	var (
+		dir   string
+		start = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		end   = start.Add(3 * time.Hour)
+
+		series = map[string]float64{
+			"a": 1,
+			"b": 2,
+		}
+	)
+
+	// samples returns a sample of value every minute from start to end
+	samples := func(value float64) []tsdbutil.Sample {
+		var s []tsdbutil.Sample
+		for t := start; !t.After(end); t = t.Add(time.Minute) {
+			s = append(s, sample{t: t.UnixMilli(), f: value})
+		}
+		return s
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "snapshot")
+		Expect(err).To(Succeed())
+
+		DeferCleanup(os.RemoveAll, dir)
+	})
+
+	checkQueries := func(api *API) {
+		By("querying a range")
+		result, _, err := api.QueryRange(context.TODO(), `sum by (pod) (my_metric)`, v1.Range{
+			Start: start,
+			End:   end,
+			Step:  time.Hour,
+		})
+		Expect(err).To(Succeed())
+
+		matrix, ok := result.(model.Matrix)
+		Expect(ok).To(BeTrue())
+		Expect(matrix).To(HaveLen(2))
+		Expect(matrix[0].Metric).To(Equal(model.Metric{"pod": "a"}))
+		Expect(matrix[0].Values).To(HaveLen(4))
+		Expect(matrix[0].Values[0]).To(Equal(model.SamplePair{Timestamp: model.TimeFromUnixNano(start.UnixNano()), Value: 1}))
+		Expect(matrix[1].Metric).To(Equal(model.Metric{"pod": "b"}))
+		Expect(matrix[1].Values[3].Value).To(Equal(model.SampleValue(2)))
+
+		By("querying an instant")
+		result, _, err = api.Query(context.TODO(), `sum(my_metric)`, start.Add(time.Hour))
+		Expect(err).To(Succeed())
+
+		vector, ok := result.(model.Vector)
+		Expect(ok).To(BeTrue())
+		Expect(vector).To(HaveLen(1))
+		Expect(vector[0].Value).To(Equal(model.SampleValue(3)))
+
+		By("listing label values")
+		values, _, err := api.LabelValues(context.TODO(), "pod", []string{`my_metric{pod="b"}`}, start, end)
+		Expect(err).To(Succeed())
+		Expect(values).To(Equal(model.LabelValues{"b"}))
+
+		values, _, err = api.LabelValues(context.TODO(), "pod", nil, start, end)
+		Expect(err).To(Succeed())
+		Expect(values).To(Equal(model.LabelValues{"a", "b"}))
+
+		By("rejecting an invalid query")
+		_, _, err = api.Query(context.TODO(), `sum(`, start)
+		Expect(err).To(HaveOccurred())
+
+		apiErr, ok := err.(*v1.Error)
+		Expect(ok).To(BeTrue())
+		Expect(apiErr.Type).To(Equal(v1.ErrBadData))
+	}
+
+	Context("with blocks", func() {
+		var blockDir string
+
+		BeforeEach(func() {
+			var s []storage.Series
+			for pod, value := range series {
+				s = append(s, storage.NewListSeries(
+					labels.FromStrings("__name__", "my_metric", "pod", pod, "job", "test"),
+					samples(value),
+				))
+			}
+
+			var err error
+			blockDir, err = tsdb.CreateBlock(s, dir, int64(24*time.Hour/time.Millisecond), log.NewNopLogger())
+			Expect(err).To(Succeed())
+		})
+
+		It("should query a snapshot", func() {
+			api, err := Open(dir)
+			Expect(err).To(Succeed())
+			defer api.Close()
+
+			checkQueries(api)
+		})
+
+		It("should query a block", func() {
+			Expect(filepath.Dir(blockDir)).To(Equal(dir))
+
+			api, err := Open(blockDir)
+			Expect(err).To(Succeed())
+			defer api.Close()
+
+			checkQueries(api)
+		})
+	})
+
+	It("should query the wal of a data directory", func() {
+		db, err := tsdb.Open(dir, nil, nil, tsdb.DefaultOptions(), nil)
+		Expect(err).To(Succeed())
+
+		app := db.Appender(context.TODO())
+		for pod, value := range series {
+			lset := labels.FromStrings("__name__", "my_metric", "pod", pod, "job", "test")
+			for _, s := range samples(value) {
+				_, err := app.Append(0, lset, s.T(), s.F())
+				Expect(err).To(Succeed())
+			}
+		}
+		Expect(app.Commit()).To(Succeed())
+		Expect(db.Close()).To(Succeed())
+
+		api, err := Open(dir)
+		Expect(err).To(Succeed())
+		defer api.Close()
+
+		checkQueries(api)
+	})
+
+	It("should fail without data", func() {
+		_, err := Open(dir)
+		Expect(err).To(MatchError(ErrNoData))
+
+		_, err = Open(filepath.Join(dir, "missing"))
+		Expect(err).To(MatchError(os.ErrNotExist))
+	})
+})
@@ -0,0 +1,392 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package uploaders
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/IBM/sarama"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/pkg/reporter/schema/v2alpha1"
+	"github.com/xdg-go/scram"
+)
+
+const ErrUnsupportedReportVersion = errors.Sentinel("kafka uploader only supports v2alpha1 reports")
+
+type KafkaRecordMode string
+
+const (
+	// KafkaRecordModeEvent publishes every MarketplaceReportData as a record keyed by its eventId.
+	KafkaRecordModeEvent KafkaRecordMode = "event"
+	// KafkaRecordModeSlice publishes every report slice as a record keyed by its slice id.
+	KafkaRecordModeSlice KafkaRecordMode = "slice"
+)
+
+const (
+	KafkaSASLMechanismPlain       = sarama.SASLTypePlaintext
+	KafkaSASLMechanismSCRAMSHA256 = sarama.SASLTypeSCRAMSHA256
+	KafkaSASLMechanismSCRAMSHA512 = sarama.SASLTypeSCRAMSHA512
+
+	DefaultKafkaClientID = "rhm-reporter"
+	// DefaultKafkaMaxRetries is the least an idempotent producer accepts,
+	// the upload task retries transient failures with its own backoff
+	DefaultKafkaMaxRetries = 1
+	DefaultKafkaTimeout    = 30 * time.Second
+
+	KafkaHeaderVersion = "version"
+	KafkaHeaderSliceID = "reportSliceId"
+)
+
+// KafkaUploaderConfig is the config.yaml of the rhm-kafka-uploader-secret.
+type KafkaUploaderConfig struct {
+	Brokers []string `json:"brokers" yaml:"brokers"`
+	Topic   string   `json:"topic" yaml:"topic"`
+	// Mode is event, the default, or slice.
+	Mode     KafkaRecordMode `json:"mode,omitempty" yaml:"mode,omitempty"`
+	ClientID string          `json:"clientId,omitempty" yaml:"clientId,omitempty"`
+	// Version of the brokers, idempotent producers require 0.11.0.0 or later.
+	Version    string        `json:"version,omitempty" yaml:"version,omitempty"`
+	Timeout    time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	MaxRetries int           `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty"`
+
+	TLS  KafkaTLSConfig  `json:"tls,omitempty" yaml:"tls,omitempty"`
+	SASL KafkaSASLConfig `json:"sasl,omitempty" yaml:"sasl,omitempty"`
+}
+
+// KafkaTLSConfig holds PEM encoded certificates. A client certificate and key
+// enable mTLS.
+type KafkaTLSConfig struct {
+	Enabled    bool   `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	CACert     string `json:"caCert,omitempty" yaml:"caCert,omitempty"`
+	ClientCert string `json:"clientCert,omitempty" yaml:"clientCert,omitempty"`
+	ClientKey  string `json:"clientKey,omitempty" yaml:"clientKey,omitempty"`
+}
+
+type KafkaSASLConfig struct {
+	// Mechanism is PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512. SASL is disabled when empty.
+	Mechanism string `json:"mechanism,omitempty" yaml:"mechanism,omitempty"`
+	Username  string `json:"username,omitempty" yaml:"username,omitempty"`
+	Password  string `json:"password,omitempty" yaml:"password,omitempty"`
+}
+
+type KafkaUploader struct {
+	KafkaUploaderConfig
+	saramaConfig *sarama.Config
+	newProducer  func(addrs []string, config *sarama.Config) (sarama.SyncProducer, error)
+}
+
+var _ Uploader = &KafkaUploader{}
+
+var UploaderTargetKafka UploaderTarget = &KafkaUploader{}
+
+func (u *KafkaUploader) Name() string {
+	return "kafka"
+}
+
+func init() {
+	Register(Registration{
+		Name:   UploaderTargetKafka.Name(),
+		Target: func() UploaderTarget { return &KafkaUploader{} },
+		Config: func() interface{} { return &KafkaUploaderConfig{} },
+		Factory: func(config interface{}) (Uploader, error) {
+			return NewKafkaUploader(config.(*KafkaUploaderConfig))
+		},
+	})
+}
+
+func NewKafkaUploader(config *KafkaUploaderConfig) (Uploader, error) {
+	if len(config.Brokers) == 0 || config.Topic == "" {
+		return nil, errors.New("kafka uploader requires brokers and a topic")
+	}
+
+	if config.Mode == "" {
+		config.Mode = KafkaRecordModeEvent
+	}
+	if config.Mode != KafkaRecordModeEvent && config.Mode != KafkaRecordModeSlice {
+		return nil, errors.Errorf("kafka uploader mode %s is not event or slice", config.Mode)
+	}
+	if config.ClientID == "" {
+		config.ClientID = DefaultKafkaClientID
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultKafkaTimeout
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = DefaultKafkaMaxRetries
+	}
+
+	cfg := sarama.NewConfig()
+	cfg.ClientID = config.ClientID
+
+	if config.Version != "" {
+		version, err := sarama.ParseKafkaVersion(config.Version)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse kafka version")
+		}
+		cfg.Version = version
+	}
+
+	// idempotent delivery, a retried record is written once per partition
+	cfg.Producer.Idempotent = true
+	cfg.Producer.RequiredAcks = sarama.WaitForAll
+	cfg.Producer.Retry.Max = config.MaxRetries
+	cfg.Producer.Return.Successes = true
+	cfg.Producer.Timeout = config.Timeout
+	cfg.Net.MaxOpenRequests = 1
+	cfg.Net.DialTimeout = config.Timeout
+	cfg.Net.ReadTimeout = config.Timeout
+	cfg.Net.WriteTimeout = config.Timeout
+
+	if config.TLS.Enabled {
+		tlsConfig, err := GeneratePEMTLSConfig(config.TLS.CACert, config.TLS.ClientCert, config.TLS.ClientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "kafka uploader")
+		}
+		cfg.Net.TLS.Enable = true
+		cfg.Net.TLS.Config = tlsConfig
+	}
+
+	if config.SASL.Mechanism != "" {
+		cfg.Net.SASL.Enable = true
+		cfg.Net.SASL.Mechanism = sarama.SASLMechanism(config.SASL.Mechanism)
+		cfg.Net.SASL.User = config.SASL.Username
+		cfg.Net.SASL.Password = config.SASL.Password
+
+		switch config.SASL.Mechanism {
+		case KafkaSASLMechanismPlain:
+		case KafkaSASLMechanismSCRAMSHA256:
+			cfg.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+				return &scramClient{HashGeneratorFcn: scram.SHA256}
+			}
+		case KafkaSASLMechanismSCRAMSHA512:
+			cfg.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+				return &scramClient{HashGeneratorFcn: scram.SHA512}
+			}
+		default:
+			return nil, errors.Errorf("kafka sasl mechanism %s is not supported", config.SASL.Mechanism)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid kafka producer config")
+	}
+
+	return &KafkaUploader{
+		KafkaUploaderConfig: *config,
+		saramaConfig:        cfg,
+		newProducer:         sarama.NewSyncProducer,
+	}, nil
+}
+
+// UploadFile publishes the slices of the report and returns the
+// topic/partition/offset of the first record.
+func (u *KafkaUploader) UploadFile(ctx context.Context, fileName string, reader io.Reader) (string, error) {
+	log := logger.WithValues("uploader", u.Name(), "topic", u.Topic, "file", fileName)
+
+	messages, err := u.messages(reader)
+	if err != nil {
+		return "", errors.WrapIfWithDetails(err, "failed to read report", "file", fileName)
+	}
+
+	if len(messages) == 0 {
+		log.Info("report has no records to publish")
+		return "", nil
+	}
+
+	producer, err := u.newProducer(u.Brokers, u.saramaConfig)
+	if err != nil {
+		return "", kafkaError(errors.Wrap(err, "failed to create kafka producer"))
+	}
+	defer producer.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- producer.SendMessages(messages)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", errors.Wrap(ctx.Err(), "kafka upload cancelled")
+	case err = <-done:
+	}
+
+	if err != nil {
+		details := []interface{}{"records", len(messages)}
+		if perrs, ok := err.(sarama.ProducerErrors); ok {
+			details = append(details, "failed", len(perrs))
+			err = perrs[0].Err
+		}
+		return "", kafkaError(errors.WrapWithDetails(err, "failed to publish report", details...))
+	}
+
+	log.Info("published report", "records", len(messages))
+
+	first := messages[0]
+	return fmt.Sprintf("%s/%d/%d", first.Topic, first.Partition, first.Offset), nil
+}
+
+// kafkaTransientErrors are the broker errors of a cluster changing or
+// recovering, which a later upload may not meet
+var kafkaTransientErrors = []error{
+	sarama.ErrOutOfBrokers,
+	sarama.ErrNotConnected,
+	sarama.ErrBrokerNotAvailable,
+	sarama.ErrLeaderNotAvailable,
+	sarama.ErrNotLeaderForPartition,
+	sarama.ErrRequestTimedOut,
+	sarama.ErrNetworkException,
+	sarama.ErrNotEnoughReplicas,
+	sarama.ErrNotEnoughReplicasAfterAppend,
+	sarama.ErrKafkaStorageError,
+}
+
+// kafkaError marks err transient when it is a network error or a transient
+// broker error
+func kafkaError(err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return Transient(err)
+	}
+
+	for _, transient := range kafkaTransientErrors {
+		if errors.Is(err, transient) {
+			return Transient(err)
+		}
+	}
+
+	return err
+}
+
+func (u *KafkaUploader) messages(reader io.Reader) ([]*sarama.ProducerMessage, error) {
+	gz, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer gz.Close()
+
+	messages := []*sarama.ProducerMessage{}
+	tr := tar.NewReader(gz)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return messages, nil
+		}
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		name := filepath.Base(header.Name)
+		if header.Typeflag != tar.TypeReg || !strings.HasSuffix(name, ".json") || name == "manifest.json" {
+			continue
+		}
+
+		// only v1 reports have a metadata file
+		if name == "metadata.json" {
+			return nil, ErrUnsupportedReportVersion
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		sliceID := strings.TrimSuffix(name, ".json")
+		sliceMessages, err := u.sliceMessages(sliceID, data)
+		if err != nil {
+			return nil, errors.WithDetails(err, "file", name)
+		}
+
+		messages = append(messages, sliceMessages...)
+	}
+}
+
+func (u *KafkaUploader) sliceMessages(sliceID string, data []byte) ([]*sarama.ProducerMessage, error) {
+	slice := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &slice); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if _, ok := slice["data"]; !ok {
+		return nil, ErrUnsupportedReportVersion
+	}
+
+	headers := []sarama.RecordHeader{
+		{Key: []byte(KafkaHeaderVersion), Value: []byte(v2alpha1.Version)},
+		{Key: []byte(KafkaHeaderSliceID), Value: []byte(sliceID)},
+	}
+
+	if u.Mode == KafkaRecordModeSlice {
+		return []*sarama.ProducerMessage{{
+			Topic:   u.Topic,
+			Key:     sarama.StringEncoder(sliceID),
+			Value:   sarama.ByteEncoder(data),
+			Headers: headers,
+		}}, nil
+	}
+
+	events := []*v2alpha1.MarketplaceReportData{}
+	if err := json.Unmarshal(slice["data"], &events); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	messages := make([]*sarama.ProducerMessage, 0, len(events))
+	for _, event := range events {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		messages = append(messages, &sarama.ProducerMessage{
+			Topic:   u.Topic,
+			Key:     sarama.StringEncoder(event.EventID),
+			Value:   sarama.ByteEncoder(value),
+			Headers: headers,
+		})
+	}
+
+	return messages, nil
+}
+
+type scramClient struct {
+	*scram.Client
+	*scram.ClientConversation
+	scram.HashGeneratorFcn
+}
+
+func (x *scramClient) Begin(userName, password, authzID string) (err error) {
+	x.Client, err = x.HashGeneratorFcn.NewClient(userName, password, authzID)
+	if err != nil {
+		return err
+	}
+	x.ClientConversation = x.Client.NewConversation()
+	return nil
+}
+
+func (x *scramClient) Step(challenge string) (string, error) {
+	return x.ClientConversation.Step(challenge)
+}
+
+func (x *scramClient) Done() bool {
+	return x.ClientConversation.Done()
+}
@@ -0,0 +1,223 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package uploaders
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+
+	"emperror.dev/errors"
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/pkg/reporter/schema/v2alpha1"
+)
+
+var _ = Describe("kafka uploader", func() {
+	const topic = "usage"
+
+	var (
+		config *KafkaUploaderConfig
+		report []byte
+	)
+
+	BeforeEach(func() {
+		config = &KafkaUploaderConfig{
+			Brokers: []string{"localhost:9092"},
+			Topic:   topic,
+		}
+
+		report = newTestReport(map[string]interface{}{
+			"manifest.json": v2alpha1.Manifest{Type: v2alpha1.AccountMetrics, Version: "1"},
+			"slice-1.json": v2alpha1.MarketplaceReportSlice{Metrics: []*v2alpha1.MarketplaceReportData{
+				{EventID: "event-1", MeasuredUsage: []v2alpha1.MeasuredUsage{{MetricID: "usage", Value: 1}}},
+				{EventID: "event-2", MeasuredUsage: []v2alpha1.MeasuredUsage{{MetricID: "usage", Value: 2}}},
+			}},
+			"slice-2.json": v2alpha1.MarketplaceReportSlice{Metrics: []*v2alpha1.MarketplaceReportData{
+				{EventID: "event-3", MeasuredUsage: []v2alpha1.MeasuredUsage{{MetricID: "usage", Value: 3}}},
+			}},
+		})
+	})
+
+	newUploader := func() *KafkaUploader {
+		sut, err := NewKafkaUploader(config)
+		Expect(err).To(Succeed())
+		return sut.(*KafkaUploader)
+	}
+
+	// withMockProducer records the messages sent by the uploader
+	withMockProducer := func(sut *KafkaUploader, expected int) *[]*sarama.ProducerMessage {
+		sent := &[]*sarama.ProducerMessage{}
+		producer := mocks.NewSyncProducer(GinkgoT(), sut.saramaConfig)
+		for i := 0; i < expected; i++ {
+			producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+				*sent = append(*sent, msg)
+				return nil
+			})
+		}
+		sut.newProducer = func([]string, *sarama.Config) (sarama.SyncProducer, error) {
+			return producer, nil
+		}
+		return sent
+	}
+
+	It("should configure an idempotent producer", func() {
+		config.SASL = KafkaSASLConfig{Mechanism: KafkaSASLMechanismSCRAMSHA512, Username: "user", Password: "pass"}
+		sut := newUploader()
+
+		Expect(sut.saramaConfig.Producer.Idempotent).To(BeTrue())
+		Expect(sut.saramaConfig.Producer.RequiredAcks).To(Equal(sarama.WaitForAll))
+		Expect(sut.saramaConfig.Net.MaxOpenRequests).To(Equal(1))
+		Expect(sut.saramaConfig.Net.SASL.Enable).To(BeTrue())
+		Expect(sut.saramaConfig.Net.SASL.SCRAMClientGeneratorFunc).ToNot(BeNil())
+
+		config.SASL.Mechanism = "GSSAPI"
+		_, err := NewKafkaUploader(config)
+		Expect(err).To(HaveOccurred())
+
+		_, err = NewUploader("kafka", []byte("brokers: [localhost:9092]\ntopic: usage\nmode: slice\nsasl:\n  mechanism: PLAIN\n  username: user\n  password: pass\n"))
+		Expect(err).To(Succeed())
+	})
+
+	It("should publish every event keyed by its event id", func() {
+		sut := newUploader()
+		sent := withMockProducer(sut, 3)
+
+		_, err := sut.UploadFile(context.Background(), "upload.tar.gz", bytes.NewReader(report))
+		Expect(err).To(Succeed())
+		Expect(*sent).To(HaveLen(3))
+
+		keys := []string{}
+		for _, msg := range *sent {
+			key, _ := msg.Key.Encode()
+			keys = append(keys, string(key))
+
+			value, _ := msg.Value.Encode()
+			event := v2alpha1.MarketplaceReportData{}
+			Expect(json.Unmarshal(value, &event)).To(Succeed())
+			Expect(event.EventID).To(Equal(string(key)))
+			Expect(msg.Headers).To(ContainElement(sarama.RecordHeader{Key: []byte(KafkaHeaderVersion), Value: []byte(v2alpha1.Version)}))
+		}
+		Expect(keys).To(ConsistOf("event-1", "event-2", "event-3"))
+	})
+
+	It("should publish every slice", func() {
+		config.Mode = KafkaRecordModeSlice
+		sut := newUploader()
+		sent := withMockProducer(sut, 2)
+
+		_, err := sut.UploadFile(context.Background(), "upload.tar.gz", bytes.NewReader(report))
+		Expect(err).To(Succeed())
+
+		keys := []string{}
+		for _, msg := range *sent {
+			key, _ := msg.Key.Encode()
+			keys = append(keys, string(key))
+		}
+		Expect(keys).To(ConsistOf("slice-1", "slice-2"))
+	})
+
+	It("should reject v1alpha1 reports", func() {
+		report = newTestReport(map[string]interface{}{
+			"metadata.json": map[string]string{"version": "1"},
+			"slice-1.json":  map[string]interface{}{"metrics": []interface{}{}},
+		})
+
+		_, err := newUploader().UploadFile(context.Background(), "upload.tar.gz", bytes.NewReader(report))
+		Expect(errors.Is(err, ErrUnsupportedReportVersion)).To(BeTrue())
+	})
+
+	Context("with an in-process broker", func() {
+		var broker *sarama.MockBroker
+
+		BeforeEach(func() {
+			broker = sarama.NewMockBroker(GinkgoT(), 1)
+			config.Brokers = []string{broker.Addr()}
+			config.MaxRetries = 1
+		})
+
+		AfterEach(func() {
+			broker.Close()
+		})
+
+		It("should deliver the report", func() {
+			broker.SetHandlerByMap(map[string]sarama.MockResponse{
+				"MetadataRequest": sarama.NewMockMetadataResponse(GinkgoT()).
+					SetBroker(broker.Addr(), broker.BrokerID()).
+					SetLeader(topic, 0, broker.BrokerID()),
+				"InitProducerIDRequest": sarama.NewMockInitProducerIDResponse(GinkgoT()).
+					SetProducerID(1000),
+				"ProduceRequest": sarama.NewMockProduceResponse(GinkgoT()),
+			})
+
+			id, err := newUploader().UploadFile(context.Background(), "upload.tar.gz", bytes.NewReader(report))
+			Expect(err).To(Succeed())
+			Expect(id).To(Equal("usage/0/0"))
+		})
+
+		It("should return the delivery error", func() {
+			broker.SetHandlerByMap(map[string]sarama.MockResponse{
+				"MetadataRequest": sarama.NewMockMetadataResponse(GinkgoT()).
+					SetBroker(broker.Addr(), broker.BrokerID()).
+					SetLeader(topic, 0, broker.BrokerID()),
+				"InitProducerIDRequest": sarama.NewMockInitProducerIDResponse(GinkgoT()).
+					SetProducerID(1000),
+				"ProduceRequest": sarama.NewMockProduceResponse(GinkgoT()).
+					SetError(topic, 0, sarama.ErrTopicAuthorizationFailed),
+			})
+
+			_, err := newUploader().UploadFile(context.Background(), "upload.tar.gz", bytes.NewReader(report))
+			Expect(errors.Is(err, sarama.ErrTopicAuthorizationFailed)).To(BeTrue())
+			Expect(IsTransient(err)).To(BeFalse())
+		})
+
+		It("should report broker errors as transient", func() {
+			broker.SetHandlerByMap(map[string]sarama.MockResponse{
+				"MetadataRequest": sarama.NewMockMetadataResponse(GinkgoT()).
+					SetBroker(broker.Addr(), broker.BrokerID()).
+					SetLeader(topic, 0, broker.BrokerID()),
+				"InitProducerIDRequest": sarama.NewMockInitProducerIDResponse(GinkgoT()).
+					SetProducerID(1000),
+				"ProduceRequest": sarama.NewMockProduceResponse(GinkgoT()).
+					SetError(topic, 0, sarama.ErrNotEnoughReplicas),
+			})
+
+			_, err := newUploader().UploadFile(context.Background(), "upload.tar.gz", bytes.NewReader(report))
+			Expect(errors.Is(err, sarama.ErrNotEnoughReplicas)).To(BeTrue())
+			Expect(IsTransient(err)).To(BeTrue())
+		})
+	})
+})
+
+func newTestReport(files map[string]interface{}) []byte {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	for name, v := range files {
+		data, err := json.Marshal(v)
+		Expect(err).To(Succeed())
+		Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), Typeflag: tar.TypeReg})).To(Succeed())
+		_, err = tw.Write(data)
+		Expect(err).To(Succeed())
+	}
+
+	Expect(tw.Close()).To(Succeed())
+	Expect(gz.Close()).To(Succeed())
+	return buf.Bytes()
+}
@@ -130,6 +130,11 @@ func checkError(resp *http.Response, status MarketplaceUsageResponse, message st
 		return errors.WrapWithDetails(VerificationError, "status", status.Message)
 	}
 
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 ||
+		(status.Details != nil && status.Details.Retryable) {
+		return Transient(err)
+	}
+
 	return err
 }
 
@@ -182,7 +187,7 @@ func (r *MarketplaceUploader) uploadFile(req *http.Request) (string, error) {
 	resp, err := r.client.Do(req)
 	if err != nil {
 		logger.Error(err, "failed to post")
-		return "", errors.Wrap(err, "failed to post")
+		return "", Transient(errors.Wrap(err, "failed to post"))
 	}
 
 	defer resp.Body.Close()
@@ -0,0 +1,126 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package uploaders
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"emperror.dev/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const ErrUnknownUploaderTarget = errors.Sentinel("unknown upload target")
+
+// Registration describes an upload target that can be selected by name.
+type Registration struct {
+	// Name of the target, as passed to --uploadTargets.
+	Name string
+	// Target returns a new, unconfigured instance of the target.
+	Target func() UploaderTarget
+	// Config returns a pointer to the zero value of the target's config schema.
+	// Targets that leave Config and Factory nil are built by the reporter.
+	Config func() interface{}
+	// Factory builds the uploader from the config returned by Config after the
+	// config.yaml of the target's secret has been decoded into it.
+	Factory func(config interface{}) (Uploader, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Registration{}
+)
+
+// Register adds an upload target to the registry. It panics if the name is
+// already registered or the registration is incomplete.
+func Register(r Registration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if r.Name == "" || r.Target == nil {
+		panic(errors.New("uploader registration requires a name and a target"))
+	}
+
+	if (r.Config == nil) != (r.Factory == nil) {
+		panic(errors.Errorf("uploader %s must register both a config and a factory", r.Name))
+	}
+
+	if _, ok := registry[r.Name]; ok {
+		panic(errors.Errorf("uploader %s is already registered", r.Name))
+	}
+
+	registry[r.Name] = r
+}
+
+// Lookup returns the registration of the named target.
+func Lookup(name string) (Registration, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	r, ok := registry[name]
+	return r, ok
+}
+
+// RegisteredTargets returns the sorted names of the registered targets.
+func RegisteredTargets() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ParseUploaderTarget returns a new instance of the named target.
+func ParseUploaderTarget(s string) (UploaderTarget, error) {
+	r, ok := Lookup(s)
+	if !ok {
+		return nil, errors.WithDetails(ErrUnknownUploaderTarget,
+			"target", s, "available", strings.Join(RegisteredTargets(), ","))
+	}
+
+	return r.Target(), nil
+}
+
+// ConfigSecretName is the name of the secret holding the config.yaml of a
+// registered target.
+func ConfigSecretName(name string) string {
+	return fmt.Sprintf("rhm-%s-uploader-secret", name)
+}
+
+// NewUploader decodes the yaml config into the schema of the named target and
+// builds its uploader.
+func NewUploader(name string, configYaml []byte) (Uploader, error) {
+	r, ok := Lookup(name)
+	if !ok {
+		return nil, errors.WithDetails(ErrUnknownUploaderTarget, "target", name)
+	}
+
+	if r.Factory == nil {
+		return nil, errors.Errorf("uploader %s can not be built from a config", name)
+	}
+
+	config := r.Config()
+	if err := yaml.UnmarshalStrict(configYaml, config); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %s config", name)
+	}
+
+	return r.Factory(config)
+}
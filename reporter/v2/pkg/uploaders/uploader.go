@@ -19,8 +19,10 @@ import (
 	"io"
 
 	"emperror.dev/errors"
+
 	"github.com/redhat-marketplace/redhat-marketplace-operator/reporter/v2/pkg/dataservice"
 
+	"k8s.io/apimachinery/pkg/util/wait"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -28,6 +30,37 @@ var (
 	logger = logf.Log.WithName("uploaders")
 )
 
+// TransientError is an upload failure that may succeed when the upload is
+// retried, such as a network error or a server error response. Uploaders
+// do not retry, the upload task retries transient failures with the backoff
+// of a RetryUploader or its own.
+type TransientError interface {
+	error
+	Transient() bool
+}
+
+// IsTransient reports whether err, or an error it wraps, is transient
+func IsTransient(err error) bool {
+	var transient TransientError
+	return errors.As(err, &transient) && transient.Transient()
+}
+
+type transientError struct {
+	error
+}
+
+func (e *transientError) Transient() bool { return true }
+
+func (e *transientError) Unwrap() error { return e.error }
+
+// Transient marks err as a transient failure
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &transientError{err}
+}
+
 type UploaderTargets []UploaderTarget
 
 type UploaderTarget interface {
@@ -63,24 +96,13 @@ func (u *MarketplaceUploader) Name() string {
 	return "redhat-marketplace"
 }
 
-func MustParseUploaderTarget(s string) UploaderTarget {
-	switch s {
-	//  Disable redhat-insights uploader option. Motivation: requires cluster secret read.
-	//	case UploaderTargetRedHatInsights.Name():
-	//		return UploaderTargetRedHatInsights
-	case UploaderTargetLocalPath.Name():
-		return UploaderTargetLocalPath
-	case UploaderTargetNoOp.Name():
-		return UploaderTargetNoOp
-	case UploaderTargetCOSS3.Name():
-		return UploaderTargetCOSS3
-	case UploaderTargetMarketplace.Name():
-		return UploaderTargetMarketplace
-	case UploaderTargetDataService.Name():
-		return UploaderTargetDataService
-	default:
-		panic(errors.Errorf("provided string is not a valid upload target %s", s))
-	}
+func init() {
+	// The redhat-insights target is not registered. Motivation: requires cluster secret read.
+	Register(Registration{Name: UploaderTargetLocalPath.Name(), Target: func() UploaderTarget { return &LocalFilePathUploader{} }})
+	Register(Registration{Name: UploaderTargetNoOp.Name(), Target: func() UploaderTarget { return UploaderTargetNoOp }})
+	Register(Registration{Name: UploaderTargetCOSS3.Name(), Target: func() UploaderTarget { return UploaderTargetCOSS3 }})
+	Register(Registration{Name: UploaderTargetMarketplace.Name(), Target: func() UploaderTarget { return UploaderTargetMarketplace }})
+	Register(Registration{Name: UploaderTargetDataService.Name(), Target: func() UploaderTarget { return UploaderTargetDataService }})
 }
 
 type Uploaders []Uploader
@@ -90,6 +112,14 @@ type Uploader interface {
 	UploadFile(ctx context.Context, fileName string, reader io.Reader) (id string, err error)
 }
 
+// RetryUploader is an Uploader with its own retry settings. The upload task
+// retries its transient failures with Backoff instead of the task's default,
+// Cap is the longest wait between two attempts.
+type RetryUploader interface {
+	Uploader
+	Backoff() wait.Backoff
+}
+
 type NoOpUploader struct{}
 
 var _ Uploader = &NoOpUploader{}
@@ -77,3 +77,26 @@ func GenerateCACertPool(
 		ClientCAs: caCertPool,
 	}, nil
 }
+
+// GeneratePEMTLSConfig adds the PEM encoded CA to the system cert pool and loads
+// the optional client certificate and key for mTLS.
+func GeneratePEMTLSConfig(caCert, clientCert, clientKey string) (*tls.Config, error) {
+	tlsConfig, err := GenerateCACertPool(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if caCert != "" && !tlsConfig.RootCAs.AppendCertsFromPEM([]byte(caCert)) {
+		return nil, errors.New("failed to parse caCert")
+	}
+
+	if clientCert != "" || clientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,238 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package uploaders
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	DefaultWebhookSignatureHeader = "X-Rhm-Signature-256"
+	DefaultWebhookMaxRetries      = 3
+	DefaultWebhookInitialBackoff  = time.Second
+	DefaultWebhookMaxBackoff      = 30 * time.Second
+	DefaultWebhookTimeout         = time.Minute
+)
+
+// WebhookUploaderConfig is the config.yaml of the rhm-webhook-uploader-secret.
+type WebhookUploaderConfig struct {
+	// URL the report is POSTed to.
+	URL string `json:"url" yaml:"url"`
+	// Headers are added to every request.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// Timeout of a single request.
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	TLS   WebhookTLSConfig   `json:"tls,omitempty" yaml:"tls,omitempty"`
+	HMAC  WebhookHMACConfig  `json:"hmac,omitempty" yaml:"hmac,omitempty"`
+	Retry WebhookRetryConfig `json:"retry,omitempty" yaml:"retry,omitempty"`
+}
+
+// WebhookTLSConfig holds PEM encoded certificates. A client certificate and key
+// enable mTLS.
+type WebhookTLSConfig struct {
+	CACert     string `json:"caCert,omitempty" yaml:"caCert,omitempty"`
+	ClientCert string `json:"clientCert,omitempty" yaml:"clientCert,omitempty"`
+	ClientKey  string `json:"clientKey,omitempty" yaml:"clientKey,omitempty"`
+
+	CipherSuites []uint16 `json:"-" yaml:"-"`
+	MinVersion   uint16   `json:"-" yaml:"-"`
+}
+
+// WebhookHMACConfig signs the request body with HMAC-SHA256 when Secret is set.
+// The header value is "sha256=" followed by the hex encoded digest.
+type WebhookHMACConfig struct {
+	Secret string `json:"secret,omitempty" yaml:"secret,omitempty"`
+	Header string `json:"header,omitempty" yaml:"header,omitempty"`
+}
+
+// WebhookRetryConfig is the backoff the upload task retries network errors,
+// 429 and 5xx responses of the webhook with. The wait between two attempts
+// doubles from InitialBackoff up to MaxBackoff.
+type WebhookRetryConfig struct {
+	MaxRetries     *int          `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty"`
+	InitialBackoff time.Duration `json:"initialBackoff,omitempty" yaml:"initialBackoff,omitempty"`
+	MaxBackoff     time.Duration `json:"maxBackoff,omitempty" yaml:"maxBackoff,omitempty"`
+}
+
+func (c *WebhookUploaderConfig) setDefaults() {
+	if c.Timeout == 0 {
+		c.Timeout = DefaultWebhookTimeout
+	}
+	if c.HMAC.Header == "" {
+		c.HMAC.Header = DefaultWebhookSignatureHeader
+	}
+	if c.Retry.MaxRetries == nil {
+		maxRetries := DefaultWebhookMaxRetries
+		c.Retry.MaxRetries = &maxRetries
+	}
+	if c.Retry.InitialBackoff == 0 {
+		c.Retry.InitialBackoff = DefaultWebhookInitialBackoff
+	}
+	if c.Retry.MaxBackoff == 0 {
+		c.Retry.MaxBackoff = DefaultWebhookMaxBackoff
+	}
+}
+
+type WebhookUploader struct {
+	WebhookUploaderConfig
+	client *http.Client
+}
+
+var _ RetryUploader = &WebhookUploader{}
+
+var UploaderTargetWebhook UploaderTarget = &WebhookUploader{}
+
+func (u *WebhookUploader) Name() string {
+	return "webhook"
+}
+
+// Backoff retries the upload MaxRetries times
+func (u *WebhookUploader) Backoff() wait.Backoff {
+	return wait.Backoff{
+		Steps:    *u.Retry.MaxRetries + 1,
+		Duration: u.Retry.InitialBackoff,
+		Factor:   2,
+		Jitter:   0.1,
+		Cap:      u.Retry.MaxBackoff,
+	}
+}
+
+func init() {
+	Register(Registration{
+		Name:   UploaderTargetWebhook.Name(),
+		Target: func() UploaderTarget { return &WebhookUploader{} },
+		Config: func() interface{} { return &WebhookUploaderConfig{} },
+		Factory: func(config interface{}) (Uploader, error) {
+			return NewWebhookUploader(config.(*WebhookUploaderConfig))
+		},
+	})
+}
+
+func NewWebhookUploader(config *WebhookUploaderConfig) (Uploader, error) {
+	if config.URL == "" {
+		return nil, errors.New("webhook uploader requires a url")
+	}
+
+	config.setDefaults()
+
+	tlsConfig, err := GeneratePEMTLSConfig(config.TLS.CACert, config.TLS.ClientCert, config.TLS.ClientKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "webhook uploader")
+	}
+
+	tlsConfig.CipherSuites = config.TLS.CipherSuites
+	tlsConfig.MinVersion = config.TLS.MinVersion
+
+	client := &http.Client{
+		Timeout: config.Timeout,
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+			Proxy:           http.ProxyFromEnvironment,
+		},
+	}
+
+	return &WebhookUploader{
+		WebhookUploaderConfig: *config,
+		client:                client,
+	}, nil
+}
+
+// WebhookResponse is the optional json body of a successful response.
+type WebhookResponse struct {
+	ID string `json:"id,omitempty"`
+}
+
+func (u *WebhookUploader) UploadFile(ctx context.Context, fileName string, reader io.Reader) (string, error) {
+	// the body is read once so it can be signed and resent
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read file")
+	}
+
+	var signature string
+	if u.HMAC.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(u.HMAC.Secret))
+		mac.Write(body)
+		signature = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	id, err := u.post(ctx, fileName, body, signature)
+	if err != nil {
+		return "", errors.WrapIf(err, "file upload failed")
+	}
+
+	return id, nil
+}
+
+// post sends the body once, network errors, 429 and 5xx responses are
+// transient
+func (u *WebhookUploader) post(ctx context.Context, fileName string, body []byte, signature string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+
+	for k, v := range u.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+	req.Header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filepath.Base(fileName)))
+	if signature != "" {
+		req.Header.Set(u.HMAC.Header, signature)
+	}
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", err
+		}
+		return "", Transient(errors.Wrap(err, "failed to post"))
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", Transient(errors.Wrap(err, "failed to read response body"))
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := errors.NewWithDetails("failed to upload", "code", resp.StatusCode, "body", string(respBody))
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return "", Transient(err)
+		}
+		return "", err
+	}
+
+	status := WebhookResponse{}
+	if len(respBody) != 0 {
+		// the id is optional, a non json response is still a success
+		_ = json.Unmarshal(respBody, &status)
+	}
+
+	return status.ID, nil
+}
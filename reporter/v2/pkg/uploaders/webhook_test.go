@@ -0,0 +1,213 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package uploaders
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"time"
+
+	"emperror.dev/errors"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var _ = Describe("uploader registry", func() {
+	It("should parse registered targets", func() {
+		Expect(RegisteredTargets()).To(ContainElements("local-path", "noop", "cos-s3", "redhat-marketplace", "data-service", "webhook"))
+
+		target, err := ParseUploaderTarget("webhook")
+		Expect(err).To(Succeed())
+		Expect(target).To(BeAssignableToTypeOf(&WebhookUploader{}))
+	})
+
+	It("should return an error for unknown targets", func() {
+		_, err := ParseUploaderTarget("foo")
+		Expect(errors.Is(err, ErrUnknownUploaderTarget)).To(BeTrue())
+	})
+
+	It("should not register a target twice", func() {
+		Expect(func() {
+			Register(Registration{Name: "noop", Target: func() UploaderTarget { return UploaderTargetNoOp }})
+		}).To(Panic())
+	})
+
+	It("should build an uploader from its config", func() {
+		uploader, err := NewUploader("webhook", []byte("url: https://example.com/usage\ntimeout: 2s\n"))
+		Expect(err).To(Succeed())
+
+		webhook := uploader.(*WebhookUploader)
+		Expect(webhook.URL).To(Equal("https://example.com/usage"))
+		Expect(webhook.Timeout).To(Equal(2 * time.Second))
+		Expect(webhook.HMAC.Header).To(Equal(DefaultWebhookSignatureHeader))
+		Expect(webhook.Backoff()).To(Equal(wait.Backoff{
+			Steps:    DefaultWebhookMaxRetries + 1,
+			Duration: DefaultWebhookInitialBackoff,
+			Factor:   2,
+			Jitter:   0.1,
+			Cap:      DefaultWebhookMaxBackoff,
+		}))
+
+		uploader, err = NewUploader("webhook", []byte("url: https://example.com\nretry:\n  maxRetries: 0\n  initialBackoff: 2s\n  maxBackoff: 10s\n"))
+		Expect(err).To(Succeed())
+
+		backoff := uploader.(RetryUploader).Backoff()
+		Expect(backoff.Steps).To(Equal(1))
+		Expect(backoff.Duration).To(Equal(2 * time.Second))
+		Expect(backoff.Cap).To(Equal(10 * time.Second))
+
+		_, err = NewUploader("webhook", []byte("url: https://example.com\nunknown: true\n"))
+		Expect(err).To(HaveOccurred())
+
+		_, err = NewUploader("noop", []byte(""))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("webhook uploader", func() {
+	var (
+		server   *ghttp.Server
+		config   *WebhookUploaderConfig
+		fileName string
+		testBody []byte
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewTLSServer()
+		fileName = "/tmp/upload-1234.tar.gz"
+		testBody = []byte("foo")
+
+		config = &WebhookUploaderConfig{
+			URL:     server.URL() + "/usage",
+			Headers: map[string]string{"X-Tenant": "acme"},
+			TLS: WebhookTLSConfig{
+				CACert: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.HTTPTestServer.Certificate().Raw})),
+			},
+			HMAC: WebhookHMACConfig{Secret: "shh"},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	upload := func() (string, error) {
+		sut, err := NewWebhookUploader(config)
+		Expect(err).To(Succeed())
+		return sut.UploadFile(context.Background(), fileName, bytes.NewReader(testBody))
+	}
+
+	It("should post the signed report with the configured headers", func() {
+		mac := hmac.New(sha256.New, []byte("shh"))
+		mac.Write(testBody)
+
+		server.AppendHandlers(ghttp.CombineHandlers(
+			ghttp.VerifyRequest("POST", "/usage"),
+			ghttp.VerifyHeaderKV("X-Tenant", "acme"),
+			ghttp.VerifyHeaderKV("Content-Type", "application/gzip"),
+			ghttp.VerifyHeaderKV("Content-Disposition", `attachment; filename="upload-1234.tar.gz"`),
+			ghttp.VerifyHeaderKV(DefaultWebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil))),
+			ghttp.VerifyBody(testBody),
+			ghttp.RespondWith(http.StatusAccepted, `{"id":"abc"}`),
+		))
+
+		id, err := upload()
+		Expect(err).To(Succeed())
+		Expect(id).To(Equal("abc"))
+	})
+
+	It("should report server errors as transient without retrying", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(ghttp.VerifyBody(testBody), ghttp.RespondWith(http.StatusServiceUnavailable, "")),
+			ghttp.CombineHandlers(ghttp.VerifyBody(testBody), ghttp.RespondWith(http.StatusTooManyRequests, "")),
+		)
+
+		_, err := upload()
+		Expect(IsTransient(err)).To(BeTrue())
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+
+		_, err = upload()
+		Expect(IsTransient(err)).To(BeTrue())
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("should not report client errors as transient", func() {
+		server.AppendHandlers(ghttp.RespondWith(http.StatusBadRequest, "bad"))
+
+		_, err := upload()
+		Expect(err).To(HaveOccurred())
+		Expect(IsTransient(err)).To(BeFalse())
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("should authenticate with a client certificate", func() {
+		certPEM, keyPEM := generateClientCert()
+
+		pool := x509.NewCertPool()
+		Expect(pool.AppendCertsFromPEM(certPEM)).To(BeTrue())
+		server.HTTPTestServer.TLS.ClientCAs = pool
+		server.HTTPTestServer.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+		server.AppendHandlers(
+			ghttp.RespondWith(http.StatusOK, ""),
+			ghttp.RespondWith(http.StatusOK, ""),
+		)
+
+		_, err := upload()
+		Expect(err).To(HaveOccurred())
+
+		config.TLS.ClientCert = string(certPEM)
+		config.TLS.ClientKey = string(keyPEM)
+		_, err = upload()
+		Expect(err).To(Succeed())
+	})
+})
+
+func generateClientCert() ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).To(Succeed())
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "reporter"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).To(Succeed())
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).To(Succeed())
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
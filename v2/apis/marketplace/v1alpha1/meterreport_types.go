@@ -181,6 +181,9 @@ type UploadDetails struct {
 	Status UploadStatus `json:"status"`
 	// Error is present if an error occurred on upload
 	Error string `json:"error,omitempty"`
+	// Attempts is the number of upload runs to the target, each run retries
+	// the upload with a backoff
+	Attempts int `json:"attempts,omitempty"`
 }
 
 func (u UploadDetails) Success() bool {
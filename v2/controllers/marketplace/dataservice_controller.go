@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -71,6 +72,17 @@ func (r *DataServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&appsv1.StatefulSet{},
 			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &marketplacev1alpha1.MeterBase{}, handler.OnlyControllerOwner()),
 			builder.WithPredicates(namespacePredicate)).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, _ client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{
+					Name:      utils.METERBASE_NAME,
+					Namespace: r.Cfg.DeployedNamespace,
+				}}}
+			}),
+			builder.WithPredicates(namespacePredicate, predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetName() == utils.DATA_SERVICE_BLOBSTORE_SECRET
+			}))).
 		Watches(
 			&routev1.Route{},
 			handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &marketplacev1alpha1.MeterBase{}, handler.OnlyControllerOwner()),
@@ -134,6 +146,47 @@ func (r *DataServiceReconciler) Reconcile(ctx context.Context, request reconcile
 			return reconcile.Result{}, err
 		}
 
+		/* DataService page token key Secret
+		The key is generated once and shared by the replicas, it is not updated
+		so page tokens stay valid across restarts
+		*/
+		pageTokenSecret, err := r.Factory.NewDataServicePageTokenSecret()
+		if err != nil {
+			reqLogger.Error(err, "Generate Secret error: ")
+			return reconcile.Result{}, err
+		}
+		if err := r.Factory.SetControllerReference(meterBase, pageTokenSecret); err != nil {
+			return reconcile.Result{}, err
+		}
+
+		err = r.Client.Get(ctx, types.NamespacedName{Name: pageTokenSecret.Name, Namespace: pageTokenSecret.Namespace}, &corev1.Secret{})
+		if err != nil && errors.IsNotFound(err) { // not found: create
+			err = r.Client.Create(ctx, pageTokenSecret)
+			if err != nil {
+				reqLogger.Error(err, "Create Secret error: ")
+				return reconcile.Result{}, err
+			}
+		} else if err != nil {
+			reqLogger.Error(err, "Get Secret error: ")
+			return reconcile.Result{}, err
+		}
+
+		/* DataService S3 blob store Secret
+		Optional, created by the administrator. Without it a single replica stores
+		the files on its volume, which also works on disconnected clusters
+		*/
+		blobStoreSecret := &corev1.Secret{}
+		err = r.Client.Get(ctx, types.NamespacedName{Name: utils.DATA_SERVICE_BLOBSTORE_SECRET, Namespace: r.Cfg.DeployedNamespace}, blobStoreSecret)
+		if err != nil && errors.IsNotFound(err) {
+			blobStoreSecret = nil
+		} else if err != nil {
+			reqLogger.Error(err, "Get Secret error: ")
+			return reconcile.Result{}, err
+		} else if err := manifests.ValidateDataServiceBlobStoreSecret(blobStoreSecret); err != nil {
+			reqLogger.Error(err, "invalid blob store secret")
+			return reconcile.Result{}, err
+		}
+
 		/* DataService Service */
 		if err := r.Factory.CreateOrUpdate(r.Client, meterBase, func() (client.Object, error) {
 			return r.Factory.NewDataServiceService()
@@ -143,7 +196,11 @@ func (r *DataServiceReconciler) Reconcile(ctx context.Context, request reconcile
 
 		/* DataService StatefulSet */
 		if err := r.Factory.CreateOrUpdate(r.Client, meterBase, func() (client.Object, error) {
-			return r.Factory.NewDataServiceStatefulSet()
+			sts, err := r.Factory.NewDataServiceStatefulSet()
+			if err == nil && blobStoreSecret != nil {
+				r.Factory.SetDataServiceS3BlobStore(sts, blobStoreSecret)
+			}
+			return sts, err
 		}); err != nil {
 			return reconcile.Result{}, err
 		}
@@ -203,6 +260,18 @@ func (r *DataServiceReconciler) Reconcile(ctx context.Context, request reconcile
 			reqLogger.Error(err, "Delete Secret error: ")
 			return reconcile.Result{}, err
 		}
+
+		/* DataService page token key Secret */
+		pageTokenSecret, err := r.Factory.NewDataServicePageTokenSecret()
+		if err != nil {
+			reqLogger.Error(err, "data service secret error")
+			return reconcile.Result{}, err
+		}
+
+		if err = r.Client.Delete(ctx, pageTokenSecret); err != nil && !errors.IsNotFound(err) {
+			reqLogger.Error(err, "Delete Secret error: ")
+			return reconcile.Result{}, err
+		}
 	}
 	reqLogger.Info("finished reconciling")
 	return reconcile.Result{}, nil
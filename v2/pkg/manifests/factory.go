@@ -19,6 +19,7 @@ import (
 	"crypto/rand"
 	"crypto/sha1"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
 	mathrand "math/rand"
@@ -94,6 +95,8 @@ const (
 	DataServiceRoute       = "dataservice/route.yaml"
 	DataServiceTLSSecret   = "dataservice/secret.yaml"
 
+	DataServicePageTokenSecret = "dataservice/page-token-secret.yaml"
+
 	MeterdefinitionFileServerDeploymentConfig = "catalog-server/deployment-config.yaml"
 	MeterdefinitionFileServerService          = "catalog-server/service.yaml"
 	MeterdefinitionFileServerImageStream      = "catalog-server/image-stream.yaml"
@@ -1202,6 +1205,70 @@ func (f *Factory) UpdateDataServiceStatefulSet(sts *appsv1.StatefulSet) error {
 	return nil
 }
 
+// DataServiceS3Replicas is the number of data service replicas once they
+// share the file content through an S3 bucket
+const DataServiceS3Replicas = 3
+
+// dataServiceBlobStoreKeys are the keys of the blob store secret and whether
+// they are required
+var dataServiceBlobStoreKeys = []struct {
+	key, env string
+	required bool
+}{
+	{key: "endpoint", env: "AIRGAP_S3_ENDPOINT", required: true},
+	{key: "bucket", env: "AIRGAP_S3_BUCKET", required: true},
+	{key: "accessKey", env: "AIRGAP_S3_ACCESS_KEY", required: true},
+	{key: "secretKey", env: "AIRGAP_S3_SECRET_KEY", required: true},
+	{key: "region", env: "AIRGAP_S3_REGION"},
+	{key: "prefix", env: "AIRGAP_S3_PREFIX"},
+}
+
+// ValidateDataServiceBlobStoreSecret checks that the blob store secret has
+// the keys required to reach the S3 bucket
+func ValidateDataServiceBlobStoreSecret(secret *v1.Secret) error {
+	missing := []string{}
+	for _, k := range dataServiceBlobStoreKeys {
+		if k.required && len(secret.Data[k.key]) == 0 {
+			missing = append(missing, k.key)
+		}
+	}
+
+	if len(missing) != 0 {
+		return fmt.Errorf("secret %s is missing the keys %s", secret.Name, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// SetDataServiceS3BlobStore stores the file content of the data service in
+// the S3 bucket of the secret, shared by DataServiceS3Replicas replicas. By
+// default a single replica stores the content on its volume.
+func (f *Factory) SetDataServiceS3BlobStore(sts *appsv1.StatefulSet, secret *v1.Secret) {
+	replicas := int32(DataServiceS3Replicas)
+	sts.Spec.Replicas = &replicas
+
+	env := []corev1.EnvVar{{Name: "AIRGAP_BLOB_STORE", Value: "s3"}}
+	for _, k := range dataServiceBlobStoreKeys {
+		env = append(env, corev1.EnvVar{
+			Name: k.env,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+					Key:                  k.key,
+					Optional:             ptr.Bool(!k.required),
+				},
+			},
+		})
+	}
+
+	for i := range sts.Spec.Template.Spec.Containers {
+		container := &sts.Spec.Template.Spec.Containers[i]
+		if container.Name == utils.DATA_SERVICE_CONTAINER_NAME {
+			container.Env = append(container.Env, env...)
+		}
+	}
+}
+
 func (f *Factory) NewDataServiceRoute() (*routev1.Route, error) {
 	return f.NewRoute(MustAssetReader(DataServiceRoute))
 }
@@ -1504,6 +1571,28 @@ func (f *Factory) NewDataServiceTLSSecret(commonNamePrefix string) (*v1.Secret,
 	return s, nil
 }
 
+// NewDataServicePageTokenSecret returns a secret with a random key, shared by
+// the data service replicas to sign list page tokens
+func (f *Factory) NewDataServicePageTokenSecret() (*v1.Secret, error) {
+	s, err := f.NewSecret(MustAssetReader(DataServicePageTokenSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	if s.Data == nil {
+		s.Data = make(map[string][]byte)
+	}
+
+	s.Data["key"] = []byte(hex.EncodeToString(key))
+
+	return s, nil
+}
+
 func (f *Factory) NewMOServiceMonitorMetricsReaderSecret() (*v1.Secret, error) {
 	return f.NewSecret(MustAssetReader(MOServiceMonitorMetricsReaderSecret))
 }
@@ -19,8 +19,11 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/v2/assets"
 	"github.com/redhat-marketplace/redhat-marketplace-operator/v2/pkg/config"
+	"github.com/redhat-marketplace/redhat-marketplace-operator/v2/pkg/utils"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var files = []string{
@@ -110,4 +113,39 @@ var _ = Describe("FactoryTest", func() {
 		r = resource.MustParse("600Mi")
 		Expect(container.Resources.Requests.Memory()).To(Equal(&r))
 	})
+
+	It("should require the S3 keys of the data service blob store secret", func() {
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: utils.DATA_SERVICE_BLOBSTORE_SECRET},
+			Data: map[string][]byte{
+				"endpoint": []byte("minio:9000"),
+				"bucket":   []byte("files"),
+			},
+		}
+		Expect(ValidateDataServiceBlobStoreSecret(secret)).To(MatchError(ContainSubstring("accessKey, secretKey")))
+
+		secret.Data["accessKey"] = []byte("access")
+		secret.Data["secretKey"] = []byte("secret")
+		Expect(ValidateDataServiceBlobStoreSecret(secret)).To(Succeed())
+	})
+
+	It("should run the data service replicas on the S3 blob store", func() {
+		factory := Factory{}
+		replicas := int32(1)
+		sts := &appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{
+				{Name: utils.DATA_SERVICE_CONTAINER_NAME, Env: []v1.EnvVar{{Name: "POD_NAME"}}},
+				{Name: "authcheck"},
+			}}},
+		}}
+
+		factory.SetDataServiceS3BlobStore(sts, &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: utils.DATA_SERVICE_BLOBSTORE_SECRET}})
+
+		Expect(*sts.Spec.Replicas).To(Equal(int32(DataServiceS3Replicas)))
+		env := sts.Spec.Template.Spec.Containers[0].Env
+		Expect(env).To(ContainElement(v1.EnvVar{Name: "AIRGAP_BLOB_STORE", Value: "s3"}))
+		Expect(env).To(ContainElement(HaveField("ValueFrom.SecretKeyRef.Key", "secretKey")))
+		Expect(sts.Spec.Template.Spec.Containers[1].Env).To(BeEmpty())
+	})
 })
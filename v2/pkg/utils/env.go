@@ -134,6 +134,10 @@ const (
 	/* Certificate */
 	DQLITE_COMMONNAME_PREFIX = "*.rhm-data-service" // wildcard.ServiceName
 
+	/* DataService */
+	DATA_SERVICE_CONTAINER_NAME   = "rhm-data-service"
+	DATA_SERVICE_BLOBSTORE_SECRET = "rhm-data-service-blobstore" // created by the administrator to store the files in S3
+
 	DeploymentConfigName = "rhm-meterdefinition-file-server"
 	FileServerAudience   = "rhm-meterdefinition-file-server.openshift-redhat-marketplace.svc"
 	ProductionURL        = "https://marketplace.redhat.com"